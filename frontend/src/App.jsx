@@ -5,6 +5,9 @@ import ProductsPage from './pages/ProductsPage';
 import ProductDetailPage from './pages/ProductDetailPage';
 import RegisterPage from './pages/RegisterPage';
 import LoginPage from './pages/LoginPage';
+import VerifyEmailPage from './pages/VerifyEmailPage';
+import ForgotPasswordPage from './pages/ForgotPasswordPage';
+import ResetPasswordPage from './pages/ResetPasswordPage';
 import PurchasesPage from './pages/PurchasesPage';
 import PurchaseDetailPage from './pages/PurchaseDetailPage';
 import CartPage from "./pages/CartPage";
@@ -24,6 +27,9 @@ function App() {
             <Route path="/producto/:id" element={<ProductDetailPage />} />
             <Route path="/registro" element={<RegisterPage />} />
             <Route path="/login" element={<LoginPage />} />
+            <Route path="/verificar-email" element={<VerifyEmailPage />} />
+            <Route path="/olvide-password" element={<ForgotPasswordPage />} />
+            <Route path="/restablecer-password" element={<ResetPasswordPage />} />
             <Route path="/mis-compras" element={<PurchasesPage />} />
             <Route path="/compra/:id" element={<PurchaseDetailPage />} />
             <Route path="/carrito" element={<CartPage />} />
@@ -1,16 +1,23 @@
 import React from 'react';
 import { useNavigate } from 'react-router-dom';
-import { isAuthenticated, isAdmin, removeToken } from '../utils/auth';
+import { isAuthenticated, hasPermission, removeToken, getRefreshToken } from '../utils/auth';
+import { userService } from '../services/userService';
 import { useCart } from '../context/CartContext';
 import './Header.css';
 
 const Header = () => {
   const navigate = useNavigate();
   const authenticated = isAuthenticated();
-  const userIsAdmin = isAdmin();
+  // El panel de productos es para quienes pueden editar el catálogo
+  const userIsAdmin = hasPermission('items:write');
   const { cart, toggleCart} = useCart();
 
-  const handleLogout = () => {
+  const handleLogout = async () => {
+    const refreshToken = getRefreshToken();
+    if (refreshToken) {
+      // Si falla igual se borran los tokens locales
+      await userService.logout(refreshToken).catch(() => {});
+    }
     removeToken();
     navigate('/');
   };
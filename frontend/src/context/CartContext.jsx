@@ -1,5 +1,5 @@
-import React, { createContext, useContext, useState, useEffect } from 'react';
-import { cartService } from '../services/cartService';
+import React, { createContext, useContext, useState, useEffect, useRef } from 'react';
+import { cartService, getGuestCartToken, clearGuestCartToken } from '../services/cartService';
 import { isAuthenticated, getCustomerId, getCustomerIDFromToken } from '../utils/auth';
 
 const CartContext = createContext();
@@ -21,21 +21,26 @@ export const CartProvider = ({ children }) => {
     const [loading, setLoading] = useState(false);
     const [isOpen, setIsOpen] = useState(false);
     const [currentCustomerId, setCurrentCustomerId] = useState(null);
+    const checkoutKeyRef = useRef(null);
 
-    // Solo cargar al montar si hay usuario autenticado
+    // Al montar: carrito del cliente si hay sesión, si no el carrito de invitado (si existe)
     useEffect(() => {
         const customerID = getCustomerIDFromToken();
         if (isAuthenticated() && customerID) {
             setCurrentCustomerId(customerID);
             loadCart();
+        } else if (getGuestCartToken()) {
+            loadGuestCart();
         }
     }, []); // Solo se ejecuta una vez al montar
 
     // Función para inicializar el carrito desde el login
     // Cambiar la firma de initializeCart para recibir customerID
+    // Si había carrito de invitado, users-api ya lo fusionó en cartData
     const initializeCart = async (customerID, cartData) => {
         // Primero resetear el carrito anterior
         resetCart();
+        clearGuestCartToken();
 
         // Actualizar el customerID
         setCurrentCustomerId(customerID);
@@ -90,17 +95,35 @@ export const CartProvider = ({ children }) => {
         }
     };
 
-    // Agregar item al carrito
-    const addItem = async (itemID, quantity = 1) => {
-        if (!isAuthenticated()) {
-            alert('Debes iniciar sesión para agregar productos al carrito');
-            return false;
+    // Cargar el carrito de invitado
+    const loadGuestCart = async () => {
+        try {
+            setLoading(true);
+            const guestCart = await cartService.getGuestCart();
+            if (guestCart) {
+                setCart(guestCart);
+            }
+        } catch (error) {
+            console.error('Error loading guest cart:', error);
+        } finally {
+            setLoading(false);
         }
+    };
 
+    // Agregar item al carrito (sin sesión se usa un carrito de invitado)
+    const addItem = async (itemID, quantity = 1) => {
         try {
             setLoading(true);
-            const customerID = getCustomerIDFromToken();
-            const updatedCart = await cartService.addItem(customerID, itemID, quantity);
+            let updatedCart;
+            if (isAuthenticated()) {
+                const customerID = getCustomerIDFromToken();
+                updatedCart = await cartService.addItem(customerID, itemID, quantity);
+            } else {
+                if (!(await cartService.getGuestCart())) {
+                    await cartService.createGuestCart();
+                }
+                updatedCart = await cartService.addGuestItem(itemID, quantity);
+            }
             setCart(updatedCart);
             return true;
         } catch (error) {
@@ -117,8 +140,9 @@ export const CartProvider = ({ children }) => {
     const updateItem = async (itemID, quantity) => {
         try {
             setLoading(true);
-            const customerID = getCustomerIDFromToken();
-            const updatedCart = await cartService.updateItem(customerID, itemID, quantity);
+            const updatedCart = isAuthenticated()
+                ? await cartService.updateItem(getCustomerIDFromToken(), itemID, quantity)
+                : await cartService.updateGuestItem(itemID, quantity);
             setCart(updatedCart);
         } catch (error) {
             console.error('Error updating item:', error);
@@ -133,8 +157,9 @@ export const CartProvider = ({ children }) => {
     const removeItem = async (itemID) => {
         try {
             setLoading(true);
-            const customerID = getCustomerIDFromToken();
-            const updatedCart = await cartService.removeItem(customerID, itemID);
+            const updatedCart = isAuthenticated()
+                ? await cartService.removeItem(getCustomerIDFromToken(), itemID)
+                : await cartService.removeGuestItem(itemID);
             setCart(updatedCart);
         } catch (error) {
             console.error('Error removing item:', error);
@@ -151,6 +176,8 @@ export const CartProvider = ({ children }) => {
             const customerID = getCustomerIDFromToken();
             if (customerID) {
                 await cartService.clearCart(customerID);
+            } else if (getGuestCartToken()) {
+                await cartService.deleteGuestCart();
             }
             resetCart();
         } catch (error) {
@@ -161,18 +188,87 @@ export const CartProvider = ({ children }) => {
         }
     };
 
+    // Aplicar un cupón de descuento
+    const applyCoupon = async (code) => {
+        try {
+            setLoading(true);
+            const customerID = getCustomerIDFromToken();
+            const updatedCart = await cartService.applyCoupon(customerID, code);
+            setCart(updatedCart);
+        } catch (error) {
+            throw new Error(error.error || 'No se pudo aplicar el cupón');
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    // Quitar el cupón de descuento
+    const removeCoupon = async () => {
+        try {
+            setLoading(true);
+            const customerID = getCustomerIDFromToken();
+            const updatedCart = await cartService.removeCoupon(customerID);
+            setCart(updatedCart);
+        } catch (error) {
+            console.error('Error removing coupon:', error);
+            alert('Error al quitar el cupón');
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    // Aceptar los cambios de precio y stock desde que se agregaron los productos
+    const acknowledgeChanges = async () => {
+        try {
+            setLoading(true);
+            const updatedCart = await cartService.acknowledgeChanges(getCustomerIDFromToken());
+            setCart(updatedCart);
+        } catch (error) {
+            console.error('Error acknowledging cart changes:', error);
+            alert('Error al aceptar los cambios del carrito');
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    // Cotizar el carrito con el costo de envío
+    const quote = async (shipping) => {
+        const customerID = getCustomerIDFromToken();
+        try {
+            return await cartService.quote(customerID, shipping);
+        } catch (error) {
+            throw new Error(error.error || 'Error al calcular el envío');
+        }
+    };
+
     // Procesar checkout
-    const checkout = async () => {
+    const checkout = async (payment, shipping) => {
+        // Si el intento anterior no obtuvo respuesta (timeout, red) se reintenta con la misma clave
+        if (!checkoutKeyRef.current) {
+            checkoutKeyRef.current = crypto.randomUUID();
+        }
         try {
             setLoading(true);
             const customerID = getCustomerIDFromToken();
-            const result = await cartService.checkout(customerID);
+            const result = await cartService.checkout(customerID, payment, shipping, checkoutKeyRef.current);
+            checkoutKeyRef.current = null;
             resetCart();
             return result;
         } catch (error) {
+            // El servidor respondió: el próximo intento es un checkout nuevo
+            if (error && typeof error === 'object') {
+                checkoutKeyRef.current = null;
+            }
             console.error('Error during checkout:', error);
+            // El carrito cambió: se recarga para mostrar qué cambió
+            if (error?.code === 'cart_changed') {
+                await loadCart();
+            }
             const errorMessage = error.error || 'Error al procesar la compra';
-            throw new Error(errorMessage);
+            // El code (ej. email_not_verified) le sirve a la página para ofrecer una salida
+            const checkoutError = new Error(errorMessage);
+            checkoutError.code = error?.code;
+            throw checkoutError;
         } finally {
             setLoading(false);
         }
@@ -194,7 +290,12 @@ export const CartProvider = ({ children }) => {
         removeItem,
         clearCart,
         checkout,
+        quote,
+        applyCoupon,
+        removeCoupon,
+        acknowledgeChanges,
         loadCart,
+        loadGuestCart,
         openCart,
         closeCart,
         toggleCart,
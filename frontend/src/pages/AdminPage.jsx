@@ -1,7 +1,7 @@
 import React, { useState, useEffect } from 'react';
 import { useNavigate } from 'react-router-dom';
 import { searchService } from '../services/searchService';
-import { isAdmin } from '../utils/auth';
+import { hasPermission } from '../utils/auth';
 import Header from '../components/Header';
 import AdminProductCard from '../components/AdminProductCard';
 import './AdminPage.css';
@@ -24,9 +24,9 @@ const AdminPage = () => {
     maxPrice: null,
   });
 
-  // Verificar que sea admin
+  // Verificar que pueda editar el catálogo (admin o catalog manager)
   useEffect(() => {
-    if (!isAdmin()) {
+    if (!hasPermission('items:write')) {
       navigate('/');
     }
   }, [navigate]);
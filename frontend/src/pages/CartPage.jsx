@@ -1,9 +1,20 @@
-import React, { useState } from 'react';
+import React, { useState, useEffect } from 'react';
 import { useNavigate } from 'react-router-dom';
 import { useCart } from '../context/CartContext';
+import { userService } from '../services/userService';
+import { getCustomerIDFromToken } from '../utils/auth';
 import Header from '../components/Header';
 import './CartPage.css';
 
+// Textos de los cambios de una línea desde que se agregó al carrito
+const changeMessages = {
+    price_increased: (item) => `Subió de precio (antes $${item.price_at_add.toFixed(2)})`,
+    price_decreased: (item) => `Bajó de precio (antes $${item.price_at_add.toFixed(2)})`,
+    out_of_stock: () => 'Sin stock',
+    insufficient_stock: (item) => `Solo quedan ${item.stock} unidades`,
+    item_deleted: () => 'El producto ya no está a la venta',
+};
+
 const CartPage = () => {
     const navigate = useNavigate();
     const {
@@ -13,9 +24,76 @@ const CartPage = () => {
         removeItem,
         clearCart,
         checkout,
+        quote,
+        applyCoupon,
+        removeCoupon,
+        acknowledgeChanges,
     } = useCart();
 
     const [processingCheckout, setProcessingCheckout] = useState(false);
+    const [payment, setPayment] = useState({
+        card_number: '',
+        card_holder: '',
+        expiry: '',
+        cvv: '',
+    });
+
+    const handlePaymentChange = (e) => {
+        setPayment({ ...payment, [e.target.name]: e.target.value });
+    };
+
+    const [shippingMethod, setShippingMethod] = useState('standard');
+    const [address, setAddress] = useState({
+        recipient_name: '',
+        street: '',
+        number: '',
+        city: '',
+        province: '',
+        postal_code: '',
+    });
+    const [shippingQuote, setShippingQuote] = useState(null);
+
+    const [couponCode, setCouponCode] = useState('');
+
+    // Si cambia el carrito (o el cupón) hay que volver a cotizar el envío
+    useEffect(() => {
+        setShippingQuote(null);
+    }, [cart.items, cart.coupon_code]);
+
+    const handleApplyCoupon = async () => {
+        if (!couponCode.trim()) return;
+        try {
+            await applyCoupon(couponCode.trim());
+            setCouponCode('');
+        } catch (error) {
+            alert(error.message);
+        }
+    };
+
+    const handleAddressChange = (e) => {
+        setAddress({ ...address, [e.target.name]: e.target.value });
+        setShippingQuote(null);
+    };
+
+    const handleShippingMethodChange = (e) => {
+        setShippingMethod(e.target.value);
+        setShippingQuote(null);
+    };
+
+    const shippingRequest = () => (
+        shippingMethod === 'pickup'
+            ? { shipping_method: shippingMethod }
+            : { shipping_method: shippingMethod, shipping_address: address }
+    );
+
+    const handleQuote = async () => {
+        try {
+            setShippingQuote(await quote(shippingRequest()));
+        } catch (error) {
+            setShippingQuote(null);
+            alert(error.message);
+        }
+    };
 
     const handleQuantityChange = async (itemID, newQuantity) => {
         if (newQuantity < 1) return;
@@ -34,12 +112,32 @@ const CartPage = () => {
         }
     };
 
+    // Para comprar hay que tener el email confirmado: se ofrece reenviar el link
+    const offerVerificationEmail = async () => {
+        const resend = window.confirm(
+            'Para comprar tenés que confirmar tu email con el link que te enviamos al registrarte.\n\n¿Querés que te lo enviemos de nuevo?'
+        );
+        if (!resend) return;
+        try {
+            const user = await userService.getUserById(getCustomerIDFromToken());
+            await userService.resendVerification(user.email);
+            alert(`Te enviamos un nuevo link a ${user.email}`);
+        } catch (error) {
+            alert('No pudimos reenviar el email. Intentá más tarde.');
+        }
+    };
+
     const handleCheckout = async () => {
         if (cart.items.length === 0) {
             alert('El carrito está vacío');
             return;
         }
 
+        if (cart.has_changes) {
+            alert('Algunos productos cambiaron desde que los agregaste. Revisá y aceptá los cambios antes de comprar.');
+            return;
+        }
+
         // Verificar stock antes de procesar
         const insufficientStock = cart.items.find(item => item.quantity > item.stock);
         if (insufficientStock) {
@@ -47,24 +145,48 @@ const CartPage = () => {
             return;
         }
 
+        const [month, year] = payment.expiry.split('/').map(v => parseInt(v, 10));
+        if (!payment.card_number || !month || !year) {
+            alert('Completá los datos de la tarjeta');
+            return;
+        }
+
+        if (!shippingQuote) {
+            alert('Calculá el envío antes de finalizar la compra');
+            return;
+        }
+
         const confirmPurchase = window.confirm(
-            `¿Confirmar compra?\n\nTotal: $${cart.total.toFixed(2)}\nProductos: ${cart.item_count}`
+            `¿Confirmar compra?\n\nTotal: $${shippingQuote.total.toFixed(2)}\nProductos: ${cart.item_count}`
         );
 
         if (!confirmPurchase) return;
 
         try {
             setProcessingCheckout(true);
-            await checkout();
+            await checkout({
+                card_number: payment.card_number.replace(/\s/g, ''),
+                card_holder: payment.card_holder,
+                expiry_month: month,
+                expiry_year: year,
+                cvv: payment.cvv,
+            }, shippingRequest());
             alert('¡Compra realizada con éxito! ✅');
             navigate('/mis-compras');
         } catch (error) {
+            if (error.code === 'email_not_verified') {
+                await offerVerificationEmail();
+                return;
+            }
             alert(error.message || 'Error al procesar la compra');
         } finally {
             setProcessingCheckout(false);
         }
     };
 
+    // IVA de la cotización (incluye el envío) o, si todavía no se cotizó, el del carrito
+    const taxes = shippingQuote ? shippingQuote.taxes : cart.taxes;
+
     return (
         <div className="cart-page">
             <Header />
@@ -96,6 +218,15 @@ const CartPage = () => {
                     </div>
                 )}
 
+                {!loading && cart.has_changes && (
+                    <div className="cart-changes-banner">
+                        <p>⚠️ Algunos productos cambiaron de precio o de stock desde que los agregaste.</p>
+                        <button className="btn-acknowledge-changes" onClick={acknowledgeChanges}>
+                            Aceptar cambios
+                        </button>
+                    </div>
+                )}
+
                 {!loading && cart.items.length > 0 && (
                     <div className="cart-page-content">
                         <div className="cart-items-section">
@@ -110,9 +241,14 @@ const CartPage = () => {
                                     />
 
                                     <div className="cart-page-item-details">
-                                        <h3>{item.name}</h3>
+                                        <h3>{item.name || 'Producto no disponible'}</h3>
                                         <p className="item-description">{item.description}</p>
                                         <p className="item-stock">Stock disponible: {item.stock} unidades</p>
+                                        {(item.changes || []).map(change => (
+                                            <p className="item-change" key={change}>
+                                                ⚠️ {changeMessages[change] ? changeMessages[change](item) : change}
+                                            </p>
+                                        ))}
                                     </div>
 
                                     <div className="cart-page-item-price">
@@ -156,6 +292,9 @@ const CartPage = () => {
                                     <div className="cart-page-item-subtotal">
                                         <p className="subtotal-label">Subtotal</p>
                                         <p className="subtotal-value">${item.subtotal.toFixed(2)}</p>
+                                        {(item.discount_reasons || []).map(reason => (
+                                            <p className="item-discount-reason" key={reason}>🏷️ {reason}</p>
+                                        ))}
                                     </div>
 
                                     <button
@@ -175,19 +314,140 @@ const CartPage = () => {
 
                                 <div className="summary-row">
                                     <span>Productos ({cart.item_count})</span>
-                                    <span>${cart.total.toFixed(2)}</span>
+                                    <span>${(cart.subtotal ?? cart.total).toFixed(2)}</span>
+                                </div>
+
+                                {(cart.discounts || []).map(discount => (
+                                    <div className="summary-row summary-discount" key={discount.promotion_id || discount.code}>
+                                        <span>{discount.type === 'coupon' ? `Cupón ${discount.code}` : discount.description}</span>
+                                        <span>-${discount.amount.toFixed(2)}</span>
+                                    </div>
+                                ))}
+
+                                <div className="coupon-form">
+                                    {cart.coupon_code ? (
+                                        <div className="coupon-applied">
+                                            <span>🎟️ {cart.coupon_code}</span>
+                                            <button type="button" className="btn-remove-coupon" onClick={removeCoupon}>
+                                                Quitar
+                                            </button>
+                                        </div>
+                                    ) : (
+                                        <div className="payment-form-row">
+                                            <input
+                                                placeholder="Código de descuento"
+                                                value={couponCode}
+                                                onChange={(e) => setCouponCode(e.target.value)}
+                                            />
+                                            <button type="button" className="btn-apply-coupon" onClick={handleApplyCoupon}>
+                                                Aplicar
+                                            </button>
+                                        </div>
+                                    )}
+                                    {cart.coupon_error && (
+                                        <p className="coupon-error">{cart.coupon_error}</p>
+                                    )}
+                                </div>
+
+                                <div className="shipping-form">
+                                    <h3>🚚 Envío</h3>
+                                    <select value={shippingMethod} onChange={handleShippingMethodChange}>
+                                        <option value="standard">Envío estándar</option>
+                                        <option value="express">Envío express</option>
+                                        <option value="pickup">Retiro en sucursal</option>
+                                    </select>
+                                    {shippingMethod !== 'pickup' && (
+                                        <>
+                                            <input name="recipient_name" placeholder="Nombre de quien recibe" value={address.recipient_name} onChange={handleAddressChange} />
+                                            <div className="payment-form-row">
+                                                <input name="street" placeholder="Calle" value={address.street} onChange={handleAddressChange} />
+                                                <input name="number" placeholder="Número" value={address.number} onChange={handleAddressChange} />
+                                            </div>
+                                            <input name="city" placeholder="Ciudad" value={address.city} onChange={handleAddressChange} />
+                                            <div className="payment-form-row">
+                                                <input name="province" placeholder="Provincia" value={address.province} onChange={handleAddressChange} />
+                                                <input name="postal_code" placeholder="Código postal" value={address.postal_code} onChange={handleAddressChange} />
+                                            </div>
+                                        </>
+                                    )}
+                                    <button type="button" className="btn-quote-shipping" onClick={handleQuote}>
+                                        Calcular envío
+                                    </button>
                                 </div>
 
                                 <div className="summary-row summary-shipping">
                                     <span>Envío</span>
-                                    <span className="free-shipping">Gratis</span>
+                                    {!shippingQuote ? (
+                                        <span>A calcular</span>
+                                    ) : shippingQuote.shipping.cost === 0 ? (
+                                        <span className="free-shipping">Gratis</span>
+                                    ) : (
+                                        <span>${shippingQuote.shipping.cost.toFixed(2)}</span>
+                                    )}
                                 </div>
+                                {shippingQuote && shippingQuote.shipping.estimated_days > 0 && (
+                                    <div className="summary-row shipping-eta">
+                                        <span>Llega en {shippingQuote.shipping.estimated_days} días hábiles</span>
+                                    </div>
+                                )}
+
+                                {taxes && taxes.tax > 0 && (
+                                    <div className="summary-row summary-tax">
+                                        <span>{taxes.prices_include_tax ? 'IVA incluido' : 'IVA'}</span>
+                                        <span>${taxes.tax.toFixed(2)}</span>
+                                    </div>
+                                )}
 
                                 <div className="summary-divider"></div>
 
                                 <div className="summary-row summary-total">
                                     <span>Total</span>
-                                    <span className="total-amount">${cart.total.toFixed(2)}</span>
+                                    <span className="total-amount">${(shippingQuote ? shippingQuote.total : cart.total).toFixed(2)}</span>
+                                </div>
+
+                                {shippingQuote?.installments?.length > 0 && (
+                                    <ul className="installment-options">
+                                        {shippingQuote.installments.map((option) => (
+                                            <li key={option.installments}>
+                                                {option.installments} x ${option.installment_amount.toFixed(2)}
+                                                {option.interest_free ? ' sin interés' : ` (total $${option.total.toFixed(2)})`}
+                                            </li>
+                                        ))}
+                                    </ul>
+                                )}
+
+                                <div className="payment-form">
+                                    <h3>💳 Pago con tarjeta</h3>
+                                    <input
+                                        name="card_number"
+                                        placeholder="Número de tarjeta"
+                                        value={payment.card_number}
+                                        onChange={handlePaymentChange}
+                                        autoComplete="cc-number"
+                                    />
+                                    <input
+                                        name="card_holder"
+                                        placeholder="Nombre del titular"
+                                        value={payment.card_holder}
+                                        onChange={handlePaymentChange}
+                                        autoComplete="cc-name"
+                                    />
+                                    <div className="payment-form-row">
+                                        <input
+                                            name="expiry"
+                                            placeholder="MM/AA"
+                                            value={payment.expiry}
+                                            onChange={handlePaymentChange}
+                                            autoComplete="cc-exp"
+                                        />
+                                        <input
+                                            name="cvv"
+                                            placeholder="CVV"
+                                            value={payment.cvv}
+                                            onChange={handlePaymentChange}
+                                            autoComplete="cc-csc"
+                                        />
+                                    </div>
                                 </div>
 
                                 <button
@@ -1,7 +1,7 @@
 import React, { useState, useEffect } from 'react';
 import { useParams, useNavigate } from 'react-router-dom';
 import { productService } from '../services/productService';
-import { isAdmin } from '../utils/auth';
+import { hasPermission } from '../utils/auth';
 import Header from '../components/Header';
 import './ProductFormPage.css';
 
@@ -14,15 +14,20 @@ const EditProductPage = () => {
     description: '',
     price: '',
     stock: '',
-    image_url: ''
+    image_url: '',
+    weight_kg: '',
+    tax_class: 'general',
+    length_cm: '',
+    width_cm: '',
+    height_cm: ''
   });
   const [errors, setErrors] = useState({});
   const [loading, setLoading] = useState(false);
   const [loadingProduct, setLoadingProduct] = useState(true);
 
-  // Verificar que sea admin
+  // Verificar que pueda editar el catálogo (admin o catalog manager)
   useEffect(() => {
-    if (!isAdmin()) {
+    if (!hasPermission('items:write')) {
       navigate('/');
     }
   }, [navigate]);
@@ -45,7 +50,12 @@ const EditProductPage = () => {
         description: product.description || '',
         price: product.price || '',
         stock: product.stock || '',
-        image_url: product.image_url || ''
+        image_url: product.image_url || '',
+        weight_kg: product.weight_kg || '',
+        tax_class: product.tax_class || 'general',
+        length_cm: product.dimensions?.length_cm || '',
+        width_cm: product.dimensions?.width_cm || '',
+        height_cm: product.dimensions?.height_cm || ''
       });
     } catch (error) {
       console.error('Error al cargar producto:', error);
@@ -137,7 +147,14 @@ const EditProductPage = () => {
         description: formData.description,
         price: parseFloat(formData.price),
         stock: parseInt(formData.stock),
-        image_url: formData.image_url
+        image_url: formData.image_url,
+        weight_kg: parseFloat(formData.weight_kg) || 0,
+        tax_class: formData.tax_class,
+        dimensions: {
+          length_cm: parseFloat(formData.length_cm) || 0,
+          width_cm: parseFloat(formData.width_cm) || 0,
+          height_cm: parseFloat(formData.height_cm) || 0
+        }
       };
 
       await productService.updateProduct(id, productData);
@@ -254,6 +271,42 @@ const EditProductPage = () => {
               {errors.stock && <span className="error-message">{errors.stock}</span>}
             </div>
 
+            <div className="form-group">
+              <label htmlFor="tax_class">IVA</label>
+              <select
+                id="tax_class"
+                name="tax_class"
+                value={formData.tax_class}
+                onChange={handleChange}
+              >
+                <option value="general">General (21%)</option>
+                <option value="reduced">Reducido (10,5%)</option>
+                <option value="exempt">Exento</option>
+              </select>
+            </div>
+
+            <div className="form-group">
+              <label htmlFor="weight_kg">Peso (kg)</label>
+              <input
+                type="number"
+                id="weight_kg"
+                name="weight_kg"
+                value={formData.weight_kg}
+                onChange={handleChange}
+                step="0.001"
+                min="0"
+              />
+            </div>
+
+            <div className="form-group">
+              <label>Medidas del paquete (cm)</label>
+              <div className="dimensions-inputs">
+                <input type="number" name="length_cm" placeholder="Largo" value={formData.length_cm} onChange={handleChange} min="0" step="0.1" />
+                <input type="number" name="width_cm" placeholder="Ancho" value={formData.width_cm} onChange={handleChange} min="0" step="0.1" />
+                <input type="number" name="height_cm" placeholder="Alto" value={formData.height_cm} onChange={handleChange} min="0" step="0.1" />
+              </div>
+            </div>
+
             <div className="form-group full-width">
               <label htmlFor="image_url">URL de la Imagen *</label>
               <input
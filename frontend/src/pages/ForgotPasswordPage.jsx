@@ -0,0 +1,101 @@
+import React, { useState } from 'react';
+import { useNavigate } from 'react-router-dom';
+import { userService } from '../services/userService';
+import './LoginPage.css';
+
+// Pide el email con el link para elegir otra contraseña
+// users-api responde igual exista o no la cuenta, así que siempre se muestra el mismo mensaje
+const ForgotPasswordPage = () => {
+  const navigate = useNavigate();
+  const [email, setEmail] = useState('');
+  const [loading, setLoading] = useState(false);
+  const [sent, setSent] = useState(false);
+  const [error, setError] = useState(null);
+
+  const handleSubmit = async (e) => {
+    e.preventDefault();
+    setError(null);
+
+    if (!email) {
+      setError('Ingresá tu email');
+      return;
+    }
+
+    try {
+      setLoading(true);
+      await userService.requestPasswordReset(email);
+      setSent(true);
+    } catch (err) {
+      console.error('Error requesting password reset:', err);
+      setError('No pudimos procesar el pedido. Probá de nuevo en unos minutos.');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <div className="login-page">
+      <div className="login-container">
+        <div className="login-header">
+          <img
+            src="/logo-gustoamate.jpg"
+            alt="GustoaMate"
+            className="logo-auth"
+            onClick={() => navigate('/')}
+          />
+        </div>
+        <div className="login-card">
+          <h2 className="login-title">Recuperar contraseña</h2>
+          <p className="login-subtitle">Te mandamos un link para elegir una nueva</p>
+
+          {error && (
+            <div className="error-alert">
+              {error}
+            </div>
+          )}
+
+          {sent ? (
+            <div className="success-alert">
+              Si hay una cuenta con ese email, te llega un link en unos minutos. Vence en 30 minutos y se puede usar una sola vez.
+            </div>
+          ) : (
+            <form onSubmit={handleSubmit} className="login-form">
+              <div className="form-group">
+                <label htmlFor="email">Email *</label>
+                <input
+                  type="email"
+                  id="email"
+                  name="email"
+                  value={email}
+                  onChange={(e) => setEmail(e.target.value)}
+                  required
+                  className="form-input"
+                  placeholder="tu@email.com"
+                  autoComplete="email"
+                />
+              </div>
+
+              <button
+                type="submit"
+                className="btn-submit"
+                disabled={loading}
+              >
+                {loading ? 'Enviando...' : 'Enviar link'}
+              </button>
+            </form>
+          )}
+
+          <button
+            type="button"
+            className="btn-link"
+            onClick={() => navigate('/login')}
+          >
+            Volver a iniciar sesión
+          </button>
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default ForgotPasswordPage;
@@ -1,9 +1,10 @@
 import React, { useState } from 'react';
 import { useNavigate } from 'react-router-dom';
 import { userService } from '../services/userService';
-import { setToken } from '../utils/auth';
+import { setToken, setRefreshToken } from '../utils/auth';
 import { saveCustomerID } from '../utils/auth';
 import { useCart } from '../context/CartContext';
+import { getGuestCartToken } from '../services/cartService';
 import './LoginPage.css';
 
 const LoginPage = () => {
@@ -35,7 +36,11 @@ const LoginPage = () => {
 
     try {
       setLoading(true);
-      const response = await userService.login(formData);
+      // Si compró como invitado, users-api fusiona ese carrito con el suyo
+      const guestCartToken = getGuestCartToken();
+      const response = await userService.login(
+        guestCartToken ? { ...formData, guest_cart_token: guestCartToken } : formData
+      );
 
       // Guardar el token en la cookie
       if (response.token) {
@@ -46,6 +51,7 @@ const LoginPage = () => {
         }
 
         setToken(response.token);
+        setRefreshToken(response.refresh_token, response.refresh_expires_at);
         alert('Inicio de sesión exitoso');
         navigate('/');
       } else {
@@ -53,7 +59,15 @@ const LoginPage = () => {
       }
     } catch (err) {
       console.error('Error during login:', err);
-      setError('Email o contraseña incorrectos');
+      // Demasiados intentos fallidos: users-api bloquea la cuenta (o la IP) por unos minutos
+      if (err?.code === 'account_locked' || err?.code === 'too_many_attempts') {
+        const minutes = Math.max(1, Math.ceil((err.retry_after_seconds || 0) / 60));
+        setError(err.code === 'account_locked'
+          ? `Por seguridad bloqueamos la cuenta después de varios intentos fallidos. Probá de nuevo en ${minutes} minuto(s) o recuperá tu contraseña.`
+          : `Demasiados intentos fallidos desde esta conexión. Probá de nuevo en ${minutes} minuto(s).`);
+      } else {
+        setError('Email o contraseña incorrectos');
+      }
     } finally {
       setLoading(false);
     }
@@ -120,6 +134,14 @@ const LoginPage = () => {
             </button>
           </form>
 
+          <button
+            type="button"
+            className="btn-link"
+            onClick={() => navigate('/olvide-password')}
+          >
+            ¿Olvidaste tu contraseña?
+          </button>
+
           <div className="login-footer">
             <p>¿No tienes una cuenta?</p>
             <button
@@ -1,7 +1,7 @@
 import React, { useState, useEffect } from 'react';
 import { useNavigate } from 'react-router-dom';
 import { productService } from '../services/productService';
-import { isAdmin } from '../utils/auth';
+import { hasPermission } from '../utils/auth';
 import Header from '../components/Header';
 import './ProductFormPage.css';
 
@@ -13,14 +13,19 @@ const NewProductPage = () => {
     description: '',
     price: '',
     stock: '',
-    image_url: ''
+    image_url: '',
+    weight_kg: '',
+    tax_class: 'general',
+    length_cm: '',
+    width_cm: '',
+    height_cm: ''
   });
   const [errors, setErrors] = useState({});
   const [loading, setLoading] = useState(false);
 
-  // Verificar que sea admin
+  // Verificar que pueda editar el catálogo (admin o catalog manager)
   useEffect(() => {
-    if (!isAdmin()) {
+    if (!hasPermission('items:write')) {
       navigate('/');
     }
   }, [navigate]);
@@ -111,7 +116,14 @@ const NewProductPage = () => {
         description: formData.description,
         price: parseFloat(formData.price),
         stock: parseInt(formData.stock),
-        image_url: formData.image_url
+        image_url: formData.image_url,
+        weight_kg: parseFloat(formData.weight_kg) || 0,
+        tax_class: formData.tax_class,
+        dimensions: {
+          length_cm: parseFloat(formData.length_cm) || 0,
+          width_cm: parseFloat(formData.width_cm) || 0,
+          height_cm: parseFloat(formData.height_cm) || 0
+        }
       };
 
       await productService.createProduct(productData);
@@ -217,6 +229,42 @@ const NewProductPage = () => {
               {errors.stock && <span className="error-message">{errors.stock}</span>}
             </div>
 
+            <div className="form-group">
+              <label htmlFor="tax_class">IVA</label>
+              <select
+                id="tax_class"
+                name="tax_class"
+                value={formData.tax_class}
+                onChange={handleChange}
+              >
+                <option value="general">General (21%)</option>
+                <option value="reduced">Reducido (10,5%)</option>
+                <option value="exempt">Exento</option>
+              </select>
+            </div>
+
+            <div className="form-group">
+              <label htmlFor="weight_kg">Peso (kg)</label>
+              <input
+                type="number"
+                id="weight_kg"
+                name="weight_kg"
+                value={formData.weight_kg}
+                onChange={handleChange}
+                step="0.001"
+                min="0"
+              />
+            </div>
+
+            <div className="form-group">
+              <label>Medidas del paquete (cm)</label>
+              <div className="dimensions-inputs">
+                <input type="number" name="length_cm" placeholder="Largo" value={formData.length_cm} onChange={handleChange} min="0" step="0.1" />
+                <input type="number" name="width_cm" placeholder="Ancho" value={formData.width_cm} onChange={handleChange} min="0" step="0.1" />
+                <input type="number" name="height_cm" placeholder="Alto" value={formData.height_cm} onChange={handleChange} min="0" step="0.1" />
+              </div>
+            </div>
+
             <div className="form-group full-width">
               <label htmlFor="image_url">URL de la Imagen *</label>
               <input
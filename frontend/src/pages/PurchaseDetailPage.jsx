@@ -3,12 +3,15 @@ import { useLocation, useNavigate } from 'react-router-dom';
 import Header from '../components/Header';
 import './PurchaseDetailPage.css';
 import { salesService } from '../services/salesService';
+import { hasPermission } from '../utils/auth';
 
 const PurchaseDetailPage = () => {
   const location = useLocation();
   const navigate = useNavigate();
   const purchase = location.state?.purchase;
   const [isCancelling, setIsCancelling] = useState(false);
+  // Solo un operador con sales:write cancela ventas; las de una orden las maneja el checkout
+  const canCancel = hasPermission('sales:write') && !purchase?.order_id;
 
   if (!purchase) {
     return (
@@ -124,7 +127,7 @@ const PurchaseDetailPage = () => {
               <div className="price-summary">
                 <div className="summary-row">
                   <span>Precio por unidad:</span>
-                  <span>${(purchase.total_price / purchase.quantity).toFixed(2)}</span>
+                  <span>${(purchase.unit_price ?? purchase.total_price / purchase.quantity).toFixed(2)}</span>
                 </div>
                 <div className="summary-row">
                   <span>Cantidad:</span>
@@ -137,12 +140,14 @@ const PurchaseDetailPage = () => {
               </div>
             </div>
           </div>
-          <button
-            className="btn-cancel"
+          {canCancel && (
+            <button
+              className="btn-cancel"
               onClick={handleCancel}
               disabled={isCancelling}
-             >{isCancelling ? 'Cancelando...' : 'Cancelar compra'}
-          </button>
+            >{isCancelling ? 'Cancelando...' : 'Cancelar compra'}
+            </button>
+          )}
         </div>
       </div>
     </div>
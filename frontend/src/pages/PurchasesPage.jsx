@@ -1,7 +1,6 @@
 import React, { useState, useEffect } from 'react';
 import { useNavigate } from 'react-router-dom';
 import { salesService } from '../services/salesService';
-import { productService } from '../services/productService';
 import { getCustomerId, getCustomerIDFromToken } from '../utils/auth';
 import { isAuthenticated } from '../utils/auth';
 import Header from '../components/Header';
@@ -44,27 +43,12 @@ const PurchasesPage = () => {
         salesData = response;
       }
 
-      // Obtener información de los productos para cada compra
-      const purchasesWithProducts = await Promise.all(
-        salesData.map(async (sale) => {
-          try {
-            const productResponse = await productService.getProductById(sale.item_id);
-            const product = productResponse.item || productResponse;
-            return {
-              ...sale,
-              productName: product.name,
-              productImage: product.image_url
-            };
-          } catch (err) {
-            console.error('Error fetching product:', err);
-            return {
-              ...sale,
-              productName: 'Producto no disponible',
-              productImage: null
-            };
-          }
-        })
-      );
+      // Cada venta trae el snapshot del producto al momento de la compra
+      const purchasesWithProducts = salesData.map((sale) => ({
+        ...sale,
+        productName: sale.item_name || 'Producto no disponible',
+        productImage: sale.image_url || null
+      }));
 
       setPurchases(purchasesWithProducts);
     } catch (err) {
@@ -31,6 +31,12 @@ const RegisterPage = () => {
       return;
     }
 
+    // Misma regla que users-api
+    if (formData.password.length < 8) {
+      setError('La contraseña tiene que tener al menos 8 caracteres');
+      return;
+    }
+
     // Confirmar registro
     const confirmRegister = window.confirm('¿Estás seguro de registrarte con estos datos?');
     if (!confirmRegister) return;
@@ -40,7 +46,7 @@ const RegisterPage = () => {
       const response = await userService.register(formData);
       
       // Si el registro fue exitoso (status 201)
-      alert('Registro realizado correctamente. Ahora inicia sesión.');
+      alert('Registro realizado correctamente. Te enviamos un email para confirmar tu cuenta (lo necesitás para comprar). Ahora inicia sesión.');
       navigate('/login');
     } catch (err) {
       console.error('Error during registration:', err);
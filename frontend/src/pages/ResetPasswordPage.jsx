@@ -0,0 +1,135 @@
+import React, { useState } from 'react';
+import { useNavigate, useSearchParams } from 'react-router-dom';
+import { userService } from '../services/userService';
+import { removeToken } from '../utils/auth';
+import './LoginPage.css';
+
+// Página del link del email de recuperación: elige la contraseña nueva con el token del query string
+const ResetPasswordPage = () => {
+  const navigate = useNavigate();
+  const [searchParams] = useSearchParams();
+  const token = searchParams.get('token');
+  const [formData, setFormData] = useState({
+    password: '',
+    confirmPassword: ''
+  });
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState(token ? null : 'El link de recuperación no es válido.');
+
+  const handleChange = (e) => {
+    setFormData({
+      ...formData,
+      [e.target.name]: e.target.value
+    });
+  };
+
+  const handleSubmit = async (e) => {
+    e.preventDefault();
+    setError(null);
+
+    if (!formData.password || !formData.confirmPassword) {
+      setError('Todos los campos son obligatorios');
+      return;
+    }
+    // Misma regla que en el registro
+    if (formData.password.length < 8) {
+      setError('La contraseña tiene que tener al menos 8 caracteres');
+      return;
+    }
+    if (formData.password !== formData.confirmPassword) {
+      setError('Las contraseñas no coinciden');
+      return;
+    }
+
+    try {
+      setLoading(true);
+      await userService.confirmPasswordReset(token, formData.password);
+      // users-api cerró todas las sesiones: la de este navegador tampoco sirve más
+      removeToken();
+      alert('Listo, tu contraseña cambió. Iniciá sesión con la nueva.');
+      navigate('/login');
+    } catch (err) {
+      console.error('Error confirming password reset:', err);
+      setError('El link venció o ya se usó. Pedí uno nuevo.');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <div className="login-page">
+      <div className="login-container">
+        <div className="login-header">
+          <img
+            src="/logo-gustoamate.jpg"
+            alt="GustoaMate"
+            className="logo-auth"
+            onClick={() => navigate('/')}
+          />
+        </div>
+        <div className="login-card">
+          <h2 className="login-title">Nueva contraseña</h2>
+          <p className="login-subtitle">Elegí la contraseña con la que vas a iniciar sesión</p>
+
+          {error && (
+            <div className="error-alert">
+              {error}
+            </div>
+          )}
+
+          {token && (
+            <form onSubmit={handleSubmit} className="login-form">
+              <div className="form-group">
+                <label htmlFor="password">Contraseña nueva *</label>
+                <input
+                  type="password"
+                  id="password"
+                  name="password"
+                  value={formData.password}
+                  onChange={handleChange}
+                  required
+                  className="form-input"
+                  placeholder="••••••••"
+                  autoComplete="new-password"
+                />
+              </div>
+
+              <div className="form-group">
+                <label htmlFor="confirmPassword">Repetir contraseña *</label>
+                <input
+                  type="password"
+                  id="confirmPassword"
+                  name="confirmPassword"
+                  value={formData.confirmPassword}
+                  onChange={handleChange}
+                  required
+                  className="form-input"
+                  placeholder="••••••••"
+                  autoComplete="new-password"
+                />
+              </div>
+
+              <button
+                type="submit"
+                className="btn-submit"
+                disabled={loading}
+              >
+                {loading ? 'Guardando...' : 'Cambiar contraseña'}
+              </button>
+            </form>
+          )}
+
+          <button
+            type="button"
+            className="btn-link"
+            onClick={() => navigate('/olvide-password')}
+          >
+            Pedir otro link
+          </button>
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default ResetPasswordPage;
@@ -0,0 +1,74 @@
+import React, { useEffect, useRef, useState } from 'react';
+import { useNavigate, useSearchParams } from 'react-router-dom';
+import { userService } from '../services/userService';
+import { refreshAccessToken } from '../services/api';
+import { getRefreshToken } from '../utils/auth';
+import './RegisterPage.css';
+
+// Página del link del email de bienvenida: confirma el email con el token del query string
+const VerifyEmailPage = () => {
+  const navigate = useNavigate();
+  const [searchParams] = useSearchParams();
+  const [status, setStatus] = useState('loading');
+  const [message, setMessage] = useState('Confirmando tu email...');
+  const requested = useRef(false);
+
+  useEffect(() => {
+    // En modo estricto React corre el efecto dos veces: se confirma una sola
+    if (requested.current) return;
+    requested.current = true;
+
+    const token = searchParams.get('token');
+    if (!token) {
+      setStatus('error');
+      setMessage('El link de verificación no es válido.');
+      return;
+    }
+
+    const verify = async () => {
+      try {
+        await userService.verifyEmail(token);
+        // Si hay una sesión abierta se renueva el token para que ya diga que el email está verificado
+        if (getRefreshToken()) {
+          await refreshAccessToken().catch(() => null);
+        }
+        setStatus('success');
+        setMessage('¡Listo! Tu email quedó confirmado. Ya podés comprar.');
+      } catch (error) {
+        setStatus('error');
+        setMessage('El link venció o no es válido. Podés pedir uno nuevo al finalizar tu compra.');
+      }
+    };
+    verify();
+  }, [searchParams]);
+
+  return (
+    <div className="register-page">
+      <div className="register-container">
+        <div className="register-header">
+          <img
+            src="/logo-gustoamate.jpg"
+            alt="GustoaMate"
+            className="logo-auth"
+            onClick={() => navigate('/')}
+          />
+        </div>
+        <div className="register-card">
+          <h2 className="register-title">Confirmar email</h2>
+          {status === 'error' ? (
+            <div className="error-alert">{message}</div>
+          ) : (
+            <p className="register-subtitle">{message}</p>
+          )}
+          {status !== 'loading' && (
+            <button className="btn-submit" onClick={() => navigate('/')}>
+              Ir a la tienda
+            </button>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default VerifyEmailPage;
@@ -1,5 +1,5 @@
 import axios from 'axios';
-import { getToken } from '../utils/auth';
+import { getToken, getRefreshToken, setToken, setRefreshToken, removeToken } from '../utils/auth';
 
 const USERS_SERVICE_URL = process.env.REACT_APP_USERS_SERVICE_URL || 'http://localhost:8082';
 const ITEMS_SERVICE_URL = process.env.REACT_APP_ITEMS_SERVICE_URL || 'http://localhost:8080';
@@ -34,9 +34,53 @@ const addAuthInterceptor = (apiInstance) => {
   );
 };
 
+// Renovación del access token: un solo /auth/refresh a la vez, aunque fallen varios requests juntos
+let refreshPromise = null;
+
+const refreshAccessToken = () => {
+  if (!refreshPromise) {
+    refreshPromise = axios
+      .post(`${USERS_SERVICE_URL}/auth/refresh`, { refresh_token: getRefreshToken() })
+      .then((response) => {
+        setToken(response.data.token);
+        setRefreshToken(response.data.refresh_token, response.data.refresh_expires_at);
+        return response.data.token;
+      })
+      .catch((error) => {
+        // Refresh token vencido, revocado o reusado: hay que volver a loguearse
+        removeToken();
+        throw error;
+      })
+      .finally(() => {
+        refreshPromise = null;
+      });
+  }
+  return refreshPromise;
+};
+
+// Interceptor que ante un 401 renueva el token y reintenta el request una vez
+const addRefreshInterceptor = (apiInstance) => {
+  apiInstance.interceptors.response.use(
+    (response) => response,
+    async (error) => {
+      const original = error.config;
+      if (error.response?.status !== 401 || !original || original._retried || !getRefreshToken()) {
+        return Promise.reject(error);
+      }
+      original._retried = true;
+      const token = await refreshAccessToken();
+      original.headers.Authorization = `Bearer ${token}`;
+      return apiInstance(original);
+    }
+  );
+};
+
 // Agregar interceptores a todas las instancias
 addAuthInterceptor(usersAPI);
 addAuthInterceptor(itemsAPI);
 addAuthInterceptor(searchAPI);
+addRefreshInterceptor(usersAPI);
+addRefreshInterceptor(itemsAPI);
+addRefreshInterceptor(searchAPI);
 
-export { usersAPI, itemsAPI, searchAPI };
+export { usersAPI, itemsAPI, searchAPI, refreshAccessToken };
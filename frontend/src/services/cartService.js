@@ -1,5 +1,14 @@
 import { itemsAPI } from './api';
 
+// Token del carrito de invitado (se crea al agregar el primer producto sin sesión iniciada)
+const GUEST_CART_KEY = 'guestCartToken';
+
+export const getGuestCartToken = () => localStorage.getItem(GUEST_CART_KEY);
+export const setGuestCartToken = (token) => localStorage.setItem(GUEST_CART_KEY, token);
+export const clearGuestCartToken = () => localStorage.removeItem(GUEST_CART_KEY);
+
+const guestHeaders = () => ({ headers: { 'X-Cart-Session': getGuestCartToken() } });
+
 export const cartService = {
     // Obtener carrito del usuario
     getCart: async (customerID) => {
@@ -75,15 +84,148 @@ export const cartService = {
         }
     },
 
+    // Aplicar un cupón de descuento al carrito
+    applyCoupon: async (customerID, code) => {
+        try {
+            const response = await itemsAPI.post(
+                `http://localhost:8080/cart/${customerID}/coupon`,
+                { code }
+            );
+            return response.data;
+        } catch (error) {
+            throw error.response?.data || error.message;
+        }
+    },
+
+    // Quitar el cupón del carrito
+    removeCoupon: async (customerID) => {
+        try {
+            const response = await itemsAPI.delete(
+                `http://localhost:8080/cart/${customerID}/coupon`
+            );
+            return response.data;
+        } catch (error) {
+            throw error.response?.data || error.message;
+        }
+    },
+
+    // Aceptar los cambios de precio y stock del carrito
+    acknowledgeChanges: async (customerID) => {
+        try {
+            const response = await itemsAPI.post(`http://localhost:8080/cart/${customerID}/acknowledge`);
+            return response.data;
+        } catch (error) {
+            throw error.response?.data || error.message;
+        }
+    },
+
+    // Cotizar el carrito con el envío (shipping_method + shipping_address o address_id)
+    quote: async (customerID, shipping) => {
+        try {
+            const response = await itemsAPI.post(
+                `http://localhost:8080/cart/${customerID}/quote`,
+                shipping
+            );
+            return response.data;
+        } catch (error) {
+            throw error.response?.data || error.message;
+        }
+    },
+
     // Procesar checkout
-    checkout: async (customerID) => {
+    // idempotencyKey se reutiliza en los reintentos para que no se cobre dos veces
+    checkout: async (customerID, payment, shipping, idempotencyKey) => {
         try {
             const response = await itemsAPI.post(
-                `http://localhost:8080/cart/${customerID}/checkout`
+                `http://localhost:8080/cart/${customerID}/checkout`,
+                { payment, ...shipping },
+                { headers: { 'Idempotency-Key': idempotencyKey } }
+            );
+            return response.data;
+        } catch (error) {
+            throw error.response?.data || error.message;
+        }
+    },
+
+    // Abrir un carrito de invitado y guardar su token
+    createGuestCart: async () => {
+        try {
+            const response = await itemsAPI.post('http://localhost:8080/guest-cart');
+            setGuestCartToken(response.data.session_token);
+            return response.data;
+        } catch (error) {
+            throw error.response?.data || error.message;
+        }
+    },
+
+    // Obtener el carrito de invitado (null si no hay o ya venció)
+    getGuestCart: async () => {
+        if (!getGuestCartToken()) {
+            return null;
+        }
+        try {
+            const response = await itemsAPI.get('http://localhost:8080/guest-cart', guestHeaders());
+            return response.data;
+        } catch (error) {
+            if (error.response?.status === 404) {
+                clearGuestCartToken();
+                return null;
+            }
+            throw error.response?.data || error.message;
+        }
+    },
+
+    // Descartar el carrito de invitado
+    deleteGuestCart: async () => {
+        try {
+            await itemsAPI.delete('http://localhost:8080/guest-cart', guestHeaders());
+        } catch (error) {
+            if (error.response?.status !== 404) {
+                throw error.response?.data || error.message;
+            }
+        } finally {
+            clearGuestCartToken();
+        }
+    },
+
+    // Agregar item al carrito de invitado
+    addGuestItem: async (itemID, quantity = 1) => {
+        try {
+            const response = await itemsAPI.post(
+                'http://localhost:8080/guest-cart/items',
+                { item_id: itemID, quantity },
+                guestHeaders()
+            );
+            return response.data;
+        } catch (error) {
+            throw error.response?.data || error.message;
+        }
+    },
+
+    // Actualizar cantidad de un item del carrito de invitado
+    updateGuestItem: async (itemID, quantity) => {
+        try {
+            const response = await itemsAPI.put(
+                `http://localhost:8080/guest-cart/items/${itemID}`,
+                { quantity },
+                guestHeaders()
+            );
+            return response.data;
+        } catch (error) {
+            throw error.response?.data || error.message;
+        }
+    },
+
+    // Eliminar item del carrito de invitado
+    removeGuestItem: async (itemID) => {
+        try {
+            const response = await itemsAPI.delete(
+                `http://localhost:8080/guest-cart/items/${itemID}`,
+                guestHeaders()
             );
             return response.data;
         } catch (error) {
             throw error.response?.data || error.message;
         }
     }
-};
\ No newline at end of file
+};
@@ -21,6 +21,66 @@ export const userService = {
     }
   },
 
+  // Confirmar el email con el token del link de verificación
+  verifyEmail: async (token) => {
+    try {
+      const response = await usersAPI.get('http://localhost:8082/auth/verify-email', { params: { token } });
+      return response.data;
+    } catch (error) {
+      throw error.response?.data || error.message;
+    }
+  },
+
+  // Pedir el email con el link para elegir otra contraseña (responde igual exista o no el email)
+  requestPasswordReset: async (email) => {
+    try {
+      const response = await usersAPI.post('http://localhost:8082/auth/password-reset/request', { email });
+      return response.data;
+    } catch (error) {
+      throw error.response?.data || error.message;
+    }
+  },
+
+  // Elegir la contraseña nueva con el token del link (cierra todas las sesiones)
+  confirmPasswordReset: async (token, password) => {
+    try {
+      const response = await usersAPI.post('http://localhost:8082/auth/password-reset/confirm', { token, password });
+      return response.data;
+    } catch (error) {
+      throw error.response?.data || error.message;
+    }
+  },
+
+  // Pedir otro link de verificación
+  resendVerification: async (email) => {
+    try {
+      const response = await usersAPI.post('http://localhost:8082/auth/resend-verification', { email });
+      return response.data;
+    } catch (error) {
+      throw error.response?.data || error.message;
+    }
+  },
+
+  // Cerrar la sesión del refresh token (el access token vence solo)
+  logout: async (refreshToken) => {
+    try {
+      const response = await usersAPI.post('http://localhost:8082/auth/logout', { refresh_token: refreshToken });
+      return response.data;
+    } catch (error) {
+      throw error.response?.data || error.message;
+    }
+  },
+
+  // Cerrar todas las sesiones del usuario (en todos los dispositivos)
+  logoutAll: async (userId) => {
+    try {
+      const response = await usersAPI.post(`http://localhost:8082/users/${userId}/logout-all`);
+      return response.data;
+    } catch (error) {
+      throw error.response?.data || error.message;
+    }
+  },
+
   // Obtener usuario por ID
   getUserById: async (userId) => {
     try {
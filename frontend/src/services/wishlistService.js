@@ -0,0 +1,53 @@
+import { itemsAPI } from './api';
+
+const BASE_URL = 'http://localhost:8080';
+
+const request = async (call) => {
+    try {
+        const response = await call();
+        return response.data;
+    } catch (error) {
+        throw error.response?.data || error.message;
+    }
+};
+
+export const wishlistService = {
+    // Listas de deseos del cliente, con precio y stock actuales
+    getWishlists: (customerID) =>
+        request(() => itemsAPI.get(`${BASE_URL}/wishlists/${customerID}`)),
+
+    getWishlist: (customerID, wishlistID) =>
+        request(() => itemsAPI.get(`${BASE_URL}/wishlists/${customerID}/${wishlistID}`)),
+
+    createWishlist: (customerID, name) =>
+        request(() => itemsAPI.post(`${BASE_URL}/wishlists/${customerID}`, { name })),
+
+    renameWishlist: (customerID, wishlistID, name) =>
+        request(() => itemsAPI.put(`${BASE_URL}/wishlists/${customerID}/${wishlistID}`, { name })),
+
+    deleteWishlist: (customerID, wishlistID) =>
+        request(() => itemsAPI.delete(`${BASE_URL}/wishlists/${customerID}/${wishlistID}`)),
+
+    addItem: (customerID, wishlistID, itemID) =>
+        request(() => itemsAPI.post(`${BASE_URL}/wishlists/${customerID}/${wishlistID}/items`, { item_id: itemID })),
+
+    removeItem: (customerID, wishlistID, itemID) =>
+        request(() => itemsAPI.delete(`${BASE_URL}/wishlists/${customerID}/${wishlistID}/items/${itemID}`)),
+
+    // Devuelve { cart, wishlist } como quedaron después de mover el producto
+    moveToCart: (customerID, wishlistID, itemID, quantity = 1) =>
+        request(() => itemsAPI.post(`${BASE_URL}/wishlists/${customerID}/${wishlistID}/items/${itemID}/move-to-cart`, { quantity })),
+
+    moveFromCart: (customerID, itemID, wishlistID) =>
+        request(() => itemsAPI.post(`${BASE_URL}/cart/${customerID}/items/${itemID}/move-to-wishlist`, { wishlist_id: wishlistID })),
+
+    // El enlace compartido es /shared-wishlists/<share_token>, de solo lectura
+    share: (customerID, wishlistID) =>
+        request(() => itemsAPI.post(`${BASE_URL}/wishlists/${customerID}/${wishlistID}/share`)),
+
+    unshare: (customerID, wishlistID) =>
+        request(() => itemsAPI.delete(`${BASE_URL}/wishlists/${customerID}/${wishlistID}/share`)),
+
+    getShared: (token) =>
+        request(() => itemsAPI.get(`${BASE_URL}/shared-wishlists/${token}`)),
+};
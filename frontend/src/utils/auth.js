@@ -30,6 +30,16 @@ export const setToken = (token) => {
 
 export const removeToken = () => {
   Cookies.remove("token");
+  Cookies.remove("refresh_token");
+};
+
+// El refresh token permite pedir otro access token (dura 10 minutos) sin volver a loguearse
+export const getRefreshToken = () => {
+  return Cookies.get("refresh_token");
+};
+
+export const setRefreshToken = (token, expiresAt) => {
+  Cookies.set("refresh_token", token, { expires: expiresAt ? new Date(expiresAt) : 30 });
 };
 
 export const getCustomerId = () => {
@@ -44,8 +54,9 @@ export const isAuthenticated = () => {
 
   try {
     const decoded = jwtDecode(token);
-    // Verificar si el token no ha expirado
+    // Verificar si el token no ha expirado (si hay refresh token se renueva en el próximo request)
     if (decoded.exp && decoded.exp * 1000 < Date.now()) {
+      if (getRefreshToken()) return true;
       removeToken();
       return false;
     }
@@ -90,3 +101,16 @@ export const isAdmin = () => {
     return false;
   }
 };
+
+// Permisos del rol que vienen en el token (ej. 'items:write'); los super admin los tienen todos
+export const hasPermission = (permission) => {
+  const token = getToken();
+  if (!token) return false;
+
+  try {
+    const decoded = jwtDecode(token);
+    return decoded.is_admin === true || (decoded.permissions || []).includes(permission);
+  } catch (error) {
+    return false;
+  }
+};
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"notifications-service/internal/clients"
+	"notifications-service/internal/config"
+	"notifications-service/internal/controllers"
+	"notifications-service/internal/middleware"
+	"notifications-service/internal/repository"
+	"notifications-service/internal/services"
+	"notifications-service/internal/templates"
+
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	// Cargar configuracion desde las variables de entorno
+	cfg := config.Load()
+
+	// Inicializar capas de la aplicacion (Dependency Injection)
+	// Patron: Repository -> Service -> Controller
+
+	// Context
+	ctx := context.Background()
+
+	// Capa de datos: log de entregas
+	deliveriesRepo := repository.NewMongoDeliveriesRepository(ctx, cfg.Mongo.URI, cfg.Mongo.DB, "deliveries")
+
+	// Templates (embebidos en el binario) por idioma
+	renderer, err := services.NewTemplateRenderer(templates.FS, cfg.DefaultLocale)
+	if err != nil {
+		log.Fatalf("Error loading templates: %v", err)
+	}
+
+	// Sender: SMTP real o archivos .eml en una carpeta (desarrollo)
+	var sender services.Sender
+	switch cfg.Sender {
+	case "smtp":
+		sender = clients.NewSMTPSender(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.From)
+	default:
+		fileSender, err := clients.NewFileSender(cfg.OutboxDir, cfg.SMTP.From)
+		if err != nil {
+			log.Fatalf("Error creating outbox: %v", err)
+		}
+		sender = fileSender
+	}
+	log.Printf("📮 Using %s sender", sender.Name())
+
+	usersClient := clients.NewUsersAPIClient(cfg.UsersAPI)
+
+	// Capa de lógica de negocio
+	notificationsService := services.NewNotificationsService(renderer, sender, deliveriesRepo, usersClient)
+
+	// Consumer de eventos: órdenes, usuarios y catálogo
+	consumer := clients.NewRabbitMQConsumer(
+		cfg.RabbitMQ.Username,
+		cfg.RabbitMQ.Password,
+		cfg.RabbitMQ.Host,
+		cfg.RabbitMQ.Port,
+		cfg.RabbitMQ.QueueName,
+		clients.DefaultBindings(cfg.RabbitMQ.OrdersExchange, cfg.RabbitMQ.UsersExchange),
+		cfg.MaxAttempts,
+		cfg.RetryDelay,
+	)
+	defer consumer.Close()
+
+	go func() {
+		if err := consumer.Consume(ctx, notificationsService.Handle, notificationsService.DeadLetter); err != nil {
+			log.Fatalf("consumer error: %v", err)
+		}
+	}()
+
+	// Capa de controladores
+	authController := controllers.NewAuthController(usersClient)
+	deliveriesController := controllers.NewDeliveriesController(notificationsService)
+
+	router := gin.Default()
+
+	// Middleware: funciones que se ejecutan en cada request
+	router.Use(middleware.CORSMiddleware)
+
+	// Health check endpoint
+	router.GET("/healthz", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	// GET /deliveries - log de entregas con filtros (solo admin)
+	router.GET("/deliveries", authController.VerifyAdminToken, deliveriesController.List)
+
+	// GET /deliveries/:id - entrega de un evento (solo admin)
+	router.GET("/deliveries/:id", authController.VerifyAdminToken, deliveriesController.GetByID)
+
+	// Configuracion del server HTTP
+	srv := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           router,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("Notifications service listening on port %s", cfg.Port)
+	log.Printf("Health check: http://localhost:%s/healthz", cfg.Port)
+	log.Printf("Deliveries API: http://localhost:%s/deliveries", cfg.Port)
+
+	// Iniciar servidor (bloquea hasta que se pare el servidor)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatalf("server error: %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"notifications-service/internal/domain"
+)
+
+// FileSender escribe cada email como un archivo .eml en una carpeta (outbox)
+// Sirve para correr en local sin servidor de correo y para los tests
+type FileSender struct {
+	dir  string
+	from string
+	seq  atomic.Int64
+}
+
+// NewFileSender crea la carpeta outbox si no existe
+func NewFileSender(dir, from string) (*FileSender, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating outbox dir %s: %w", dir, err)
+	}
+	return &FileSender{dir: dir, from: from}, nil
+}
+
+func (s *FileSender) Name() string {
+	return "file"
+}
+
+// Send escribe el email en <outbox>/<timestamp>-<n>-<destinatario>.eml
+func (s *FileSender) Send(ctx context.Context, message domain.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	body, err := buildMIME(s.from, message, now)
+	if err != nil {
+		return err
+	}
+
+	recipient := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_").Replace(message.To.Email)
+	name := fmt.Sprintf("%s-%d-%s.eml", now.UTC().Format("20060102T150405"), s.seq.Add(1), recipient)
+	if err := os.WriteFile(filepath.Join(s.dir, name), body, 0o644); err != nil {
+		return fmt.Errorf("error writing email to outbox: %w", err)
+	}
+	return nil
+}
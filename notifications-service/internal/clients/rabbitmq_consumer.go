@@ -0,0 +1,212 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"notifications-service/internal/domain"
+	"notifications-service/internal/services"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	headerAttempt   = "x-attempt"
+	headerEventType = "x-event-type"
+)
+
+// EventHandler procesa un evento; attempt empieza en 1
+type EventHandler func(ctx context.Context, eventType string, body []byte, attempt int) error
+
+// DeadLetterHandler se llama cuando un evento termina en la DLQ
+type DeadLetterHandler func(ctx context.Context, eventType string, body []byte, attempts int, cause error)
+
+// RabbitMQConsumer consume los eventos que generan notificaciones
+// Topología:
+//   - <queue>: cola durable bindeada a los exchanges topic de órdenes y usuarios
+//   - <queue>.retry: los mensajes que fallaron esperan retryDelay (TTL) y vuelven a <queue> por dead-letter
+//   - <queue>.dlq: los que agotaron los reintentos o no se pueden procesar nunca
+type RabbitMQConsumer struct {
+	connection  *amqp091.Connection
+	channel     *amqp091.Channel
+	queue       string
+	retryQueue  string
+	deadQueue   string
+	maxAttempts int
+}
+
+// Binding es un exchange topic con las routing keys que interesan de él
+type Binding struct {
+	Exchange    string
+	RoutingKeys []string
+}
+
+// DefaultBindings son los eventos que hoy generan notificaciones
+// La confirmación de compra sale con order.paid: order.created se publica antes de cobrar y la orden todavía puede cancelarse
+func DefaultBindings(ordersExchange, usersExchange string) []Binding {
+	return []Binding{
+		{Exchange: ordersExchange, RoutingKeys: []string{domain.EventOrderPaid, domain.EventOrderShipped}},
+		{Exchange: usersExchange, RoutingKeys: []string{domain.EventUserRegistered, domain.EventPasswordReset}},
+	}
+}
+
+// NewRabbitMQConsumer se conecta a RabbitMQ y declara exchanges, colas y bindings
+func NewRabbitMQConsumer(user, password, host, port, queue string, bindings []Binding, maxAttempts int, retryDelay time.Duration) *RabbitMQConsumer {
+	connStr := fmt.Sprintf("amqp://%s:%s@%s:%s/", user, password, host, port)
+	connection, err := amqp091.Dial(connStr)
+	if err != nil {
+		log.Fatalf("failed to connect to RabbitMQ: %v", err)
+	}
+	channel, err := connection.Channel()
+	if err != nil {
+		log.Fatalf("failed to open a channel: %v", err)
+	}
+
+	c := &RabbitMQConsumer{
+		connection:  connection,
+		channel:     channel,
+		queue:       queue,
+		retryQueue:  queue + ".retry",
+		deadQueue:   queue + ".dlq",
+		maxAttempts: maxAttempts,
+	}
+
+	if _, err := channel.QueueDeclare(c.queue, true, false, false, false, nil); err != nil {
+		log.Fatalf("failed to declare queue %s: %v", c.queue, err)
+	}
+	if _, err := channel.QueueDeclare(c.retryQueue, true, false, false, false, amqp091.Table{
+		"x-message-ttl":             retryDelay.Milliseconds(),
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": c.queue,
+	}); err != nil {
+		log.Fatalf("failed to declare queue %s: %v", c.retryQueue, err)
+	}
+	if _, err := channel.QueueDeclare(c.deadQueue, true, false, false, false, nil); err != nil {
+		log.Fatalf("failed to declare queue %s: %v", c.deadQueue, err)
+	}
+
+	for _, binding := range bindings {
+		if err := channel.ExchangeDeclare(binding.Exchange, amqp091.ExchangeTopic, true, false, false, false, nil); err != nil {
+			log.Fatalf("failed to declare exchange %s: %v", binding.Exchange, err)
+		}
+		for _, key := range binding.RoutingKeys {
+			if err := channel.QueueBind(c.queue, key, binding.Exchange, false, nil); err != nil {
+				log.Fatalf("failed to bind %s to %s: %v", key, binding.Exchange, err)
+			}
+		}
+	}
+
+	if err := channel.Qos(10, 0, false); err != nil {
+		log.Fatalf("failed to set prefetch: %v", err)
+	}
+	return c
+}
+
+// Consume procesa los mensajes hasta que se cancela el context o se cierra la conexión
+// Cada mensaje se confirma (ack) recién después de enviarlo, reprogramarlo o mandarlo a la DLQ
+func (c *RabbitMQConsumer) Consume(ctx context.Context, handle EventHandler, deadLetter DeadLetterHandler) error {
+	msgs, err := c.channel.Consume(c.queue, "", false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to register consumer: %w", err)
+	}
+
+	log.Printf("🎯 Consumer registered for queue: %s", c.queue)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("🛑 Consumer context cancelled")
+			return ctx.Err()
+
+		case msg, ok := <-msgs:
+			if !ok {
+				return errors.New("rabbitmq channel closed")
+			}
+			c.process(ctx, msg, handle, deadLetter)
+		}
+	}
+}
+
+func (c *RabbitMQConsumer) process(ctx context.Context, msg amqp091.Delivery, handle EventHandler, deadLetter DeadLetterHandler) {
+	eventType := eventTypeOf(msg)
+	attempt := attemptOf(msg)
+
+	err := handle(ctx, eventType, msg.Body, attempt)
+	switch {
+	case err == nil:
+	case errors.Is(err, services.ErrPermanent) || attempt >= c.maxAttempts:
+		log.Printf("❌ Error handling %s (attempt %d/%d), sending to DLQ: %v", eventType, attempt, c.maxAttempts, err)
+		if pubErr := c.republish(ctx, c.deadQueue, msg, eventType, attempt, err); pubErr != nil {
+			log.Printf("❌ Error sending message to DLQ, requeueing: %v", pubErr)
+			_ = msg.Nack(false, true)
+			return
+		}
+		deadLetter(ctx, eventType, msg.Body, attempt, err)
+	default:
+		log.Printf("🔁 Error handling %s (attempt %d/%d), retrying later: %v", eventType, attempt, c.maxAttempts, err)
+		if pubErr := c.republish(ctx, c.retryQueue, msg, eventType, attempt+1, err); pubErr != nil {
+			log.Printf("❌ Error scheduling retry, requeueing: %v", pubErr)
+			_ = msg.Nack(false, true)
+			return
+		}
+	}
+
+	if err := msg.Ack(false); err != nil {
+		log.Printf("⚠️ Error acking message: %v", err)
+	}
+}
+
+// republish copia el mensaje a otra cola con el intento y el tipo de evento en los headers
+func (c *RabbitMQConsumer) republish(ctx context.Context, queue string, msg amqp091.Delivery, eventType string, attempt int, cause error) error {
+	headers := amqp091.Table{}
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	headers[headerAttempt] = int32(attempt)
+	headers[headerEventType] = eventType
+	headers["x-last-error"] = cause.Error()
+
+	return c.channel.PublishWithContext(ctx, "", queue, false, false, amqp091.Publishing{
+		ContentType:     msg.ContentType,
+		ContentEncoding: msg.ContentEncoding,
+		DeliveryMode:    amqp091.Persistent,
+		MessageId:       msg.MessageId,
+		Type:            eventType,
+		Timestamp:       msg.Timestamp,
+		AppId:           msg.AppId,
+		Headers:         headers,
+		Body:            msg.Body,
+	})
+}
+
+// eventTypeOf toma el tipo del header (reintentos), del campo Type o de la routing key original
+func eventTypeOf(msg amqp091.Delivery) string {
+	if eventType, ok := msg.Headers[headerEventType].(string); ok && eventType != "" {
+		return eventType
+	}
+	if msg.Type != "" {
+		return msg.Type
+	}
+	return msg.RoutingKey
+}
+
+func attemptOf(msg amqp091.Delivery) int {
+	switch v := msg.Headers[headerAttempt].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 1
+}
+
+// Close cierra el canal y la conexión
+func (c *RabbitMQConsumer) Close() {
+	_ = c.channel.Close()
+	_ = c.connection.Close()
+}
@@ -0,0 +1,61 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+
+	"notifications-service/internal/domain"
+)
+
+// SMTPSender envía los emails por SMTP
+// Si no hay usuario configurado se envía sin autenticación (Mailpit/MailHog en local)
+type SMTPSender struct {
+	addr string
+	host string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPSender crea el sender SMTP
+func NewSMTPSender(host, port, username, password, from string) *SMTPSender {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPSender{
+		addr: net.JoinHostPort(host, port),
+		host: host,
+		auth: auth,
+		from: from,
+	}
+}
+
+func (s *SMTPSender) Name() string {
+	return "smtp"
+}
+
+// Send envía el email; smtp.SendMail no recibe context, así que se respeta la cancelación antes de empezar
+func (s *SMTPSender) Send(ctx context.Context, message domain.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	from, err := mail.ParseAddress(s.from)
+	if err != nil {
+		return fmt.Errorf("invalid from address %q: %w", s.from, err)
+	}
+
+	body, err := buildMIME(s.from, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := smtp.SendMail(s.addr, s.auth, from.Address, []string{message.To.Email}, body); err != nil {
+		return fmt.Errorf("error sending email via smtp: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"notifications-service/internal/domain"
+	"notifications-service/internal/services"
+)
+
+// UsersAPIClient consulta los datos de contacto de los usuarios en users-api
+type UsersAPIClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewUsersAPIClient crea el cliente HTTP de users-api
+func NewUsersAPIClient(baseURL string) *UsersAPIClient {
+	return &UsersAPIClient{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// user es el usuario tal como lo devuelve users-api
+type user struct {
+	ID        int    `json:"id"`
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// GetRecipient obtiene el email y el nombre de un usuario
+// Un usuario inexistente es un error permanente: reintentar no lo va a crear
+func (c *UsersAPIClient) GetRecipient(ctx context.Context, userID int) (domain.Recipient, error) {
+	url := fmt.Sprintf("%s/users/%d", c.baseURL, userID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return domain.Recipient{}, fmt.Errorf("error creating request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return domain.Recipient{}, fmt.Errorf("error calling users-api: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return domain.Recipient{}, fmt.Errorf("%w: user %d not found", services.ErrPermanent, userID)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return domain.Recipient{}, fmt.Errorf("users-api responded with status %d", resp.StatusCode)
+	}
+
+	var u user
+	if err := json.NewDecoder(resp.Body).Decode(&u); err != nil {
+		return domain.Recipient{}, fmt.Errorf("error decoding user: %w", err)
+	}
+
+	return domain.Recipient{
+		Email: u.Email,
+		Name:  strings.TrimSpace(u.FirstName),
+	}, nil
+}
+
+// VerifyAdminToken verifica con users-api que el token sea de un admin
+func (c *UsersAPIClient) VerifyAdminToken(ctx context.Context, token string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/auth/verify-admin-token", nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling users-api: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("invalid admin token or insufficient permissions")
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+type Config struct {
+	Port          string
+	Mongo         MongoConfig
+	RabbitMQ      RabbitMQConfig
+	SMTP          SMTPConfig
+	Sender        string // smtp o file
+	OutboxDir     string // Carpeta donde el sender file deja los emails (.eml)
+	DefaultLocale string
+	MaxAttempts   int
+	RetryDelay    time.Duration
+	UsersAPI      string
+}
+
+type MongoConfig struct {
+	URI string
+	DB  string
+}
+
+type RabbitMQConfig struct {
+	Username string
+	Password string
+	Host     string
+	Port     string
+	// Exchanges topic de los que se consumen eventos
+	OrdersExchange string
+	UsersExchange  string
+	// Cola de notificaciones; de ella salen <queue>.retry y <queue>.dlq
+	QueueName string
+}
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func Load() Config {
+	// Load .env file
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found or error loading .env file")
+	}
+
+	maxAttempts, err := strconv.Atoi(getEnv("NOTIFICATIONS_MAX_ATTEMPTS", "5"))
+	if err != nil || maxAttempts < 1 {
+		maxAttempts = 5
+	}
+	retryDelaySeconds, err := strconv.Atoi(getEnv("NOTIFICATIONS_RETRY_DELAY_SECONDS", "30"))
+	if err != nil || retryDelaySeconds < 1 {
+		retryDelaySeconds = 30
+	}
+	return Config{
+		Port: getEnv("PORT", "8083"),
+		Mongo: MongoConfig{
+			URI: getEnv("MONGO_URI", "mongodb://localhost:27017"),
+			DB:  getEnv("MONGO_DB", "notifications"),
+		},
+		RabbitMQ: RabbitMQConfig{
+			Username:       getEnv("RABBITMQ_USER", "admin"),
+			Password:       getEnv("RABBITMQ_PASS", "admin"),
+			Host:           getEnv("RABBITMQ_HOST", "localhost"),
+			Port:           getEnv("RABBITMQ_PORT", "5672"),
+			OrdersExchange: getEnv("RABBITMQ_ORDERS_EXCHANGE", "orders"),
+			UsersExchange:  getEnv("RABBITMQ_USERS_EXCHANGE", "users"),
+			QueueName:      getEnv("RABBITMQ_QUEUE_NAME", "notifications"),
+		},
+		SMTP: SMTPConfig{
+			Host:     getEnv("SMTP_HOST", "localhost"),
+			Port:     getEnv("SMTP_PORT", "1025"),
+			Username: getEnv("SMTP_USER", ""),
+			Password: getEnv("SMTP_PASS", ""),
+			From:     getEnv("SMTP_FROM", "Mates <no-reply@mates.local>"),
+		},
+		Sender:        getEnv("NOTIFICATIONS_SENDER", "file"),
+		OutboxDir:     getEnv("NOTIFICATIONS_OUTBOX_DIR", "./outbox"),
+		DefaultLocale: getEnv("NOTIFICATIONS_DEFAULT_LOCALE", "es"),
+		MaxAttempts:   maxAttempts,
+		RetryDelay:    time.Duration(retryDelaySeconds) * time.Second,
+		UsersAPI:      getEnv("USERS_API_URL", "http://users-api:8082"),
+	}
+}
+
+func getEnv(k, def string) string {
+	if v := os.Getenv(k); v != "" {
+		return v
+	}
+	return def
+}
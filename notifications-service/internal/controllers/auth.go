@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminVerifier verifica con users-api que el token sea de un admin
+type AdminVerifier interface {
+	VerifyAdminToken(ctx context.Context, token string) error
+}
+
+// AuthController protege las rutas de administración
+type AuthController struct {
+	verifier AdminVerifier
+}
+
+// NewAuthController crea una nueva instancia del controller de autenticación
+func NewAuthController(verifier AdminVerifier) *AuthController {
+	return &AuthController{
+		verifier: verifier,
+	}
+}
+
+// VerifyAdminToken deja pasar solo a los admins
+func (c *AuthController) VerifyAdminToken(ctx *gin.Context) {
+	token := ctx.GetHeader("Authorization")
+	if token == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Token is required"})
+		ctx.Abort()
+		return
+	}
+
+	tokenString := strings.TrimPrefix(token, "Bearer ")
+	if tokenString == token {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token format"})
+		ctx.Abort()
+		return
+	}
+
+	if err := c.verifier.VerifyAdminToken(ctx.Request.Context(), tokenString); err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Invalid admin token",
+			"details": err.Error(),
+		})
+		ctx.Abort()
+		return
+	}
+
+	ctx.Next()
+}
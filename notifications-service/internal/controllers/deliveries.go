@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"notifications-service/internal/domain"
+	"notifications-service/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeliveriesService define las consultas del log de entregas
+type DeliveriesService interface {
+	List(ctx context.Context, filters domain.DeliveryFilters) (domain.DeliveriesPaginatedResponse, error)
+	GetByID(ctx context.Context, id string) (domain.Delivery, error)
+}
+
+// DeliveriesController expone el log de entregas (solo admin)
+type DeliveriesController struct {
+	service DeliveriesService
+}
+
+// NewDeliveriesController crea una nueva instancia del controller
+func NewDeliveriesController(service DeliveriesService) *DeliveriesController {
+	return &DeliveriesController{
+		service: service,
+	}
+}
+
+// List obtiene las entregas con filtros y paginación
+// GET /deliveries?status=&event_type=&recipient=&page=&count=
+func (c *DeliveriesController) List(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	count, _ := strconv.Atoi(ctx.DefaultQuery("count", "20"))
+
+	filters := domain.DeliveryFilters{
+		Status:    ctx.Query("status"),
+		EventType: ctx.Query("event_type"),
+		Recipient: ctx.Query("recipient"),
+		Page:      page,
+		Count:     count,
+	}
+
+	deliveries, err := c.service.List(ctx.Request.Context(), filters)
+	if err != nil {
+		log.Printf("❌ Error listing deliveries: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error listing deliveries"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+// GetByID obtiene la entrega de un evento
+// GET /deliveries/:id
+func (c *DeliveriesController) GetByID(ctx *gin.Context) {
+	delivery, err := c.service.GetByID(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrDeliveryNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+			return
+		}
+		log.Printf("❌ Error getting delivery: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error getting delivery"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"delivery": delivery})
+}
@@ -0,0 +1,30 @@
+package dao
+
+import (
+	"notifications-service/internal/domain"
+	"time"
+)
+
+// Delivery usa el ID del evento como _id: un evento tiene una sola entrega
+type Delivery struct {
+	ID        string     `bson:"_id"`
+	EventType string     `bson:"event_type"`
+	Recipient string     `bson:"recipient"`
+	Subject   string     `bson:"subject"`
+	Locale    string     `bson:"locale"`
+	Sender    string     `bson:"sender"`
+	Status    string     `bson:"status"`
+	Attempts  int        `bson:"attempts"`
+	Error     string     `bson:"error,omitempty"`
+	SentAt    *time.Time `bson:"sent_at,omitempty"`
+	CreatedAt time.Time  `bson:"created_at"`
+	UpdatedAt time.Time  `bson:"updated_at"`
+}
+
+func (d Delivery) ToDomain() domain.Delivery {
+	return domain.Delivery(d)
+}
+
+func FromDomainDelivery(delivery domain.Delivery) Delivery {
+	return Delivery(delivery)
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"time"
+)
+
+// Tipos de eventos que generan notificaciones (son las routing keys de RabbitMQ)
+const (
+	EventOrderPaid      = "order.paid"          // exchange orders (products-api)
+	EventOrderShipped   = "order.shipped"       // exchange orders (products-api)
+	EventUserRegistered = "user.registered"     // exchange users (users-api)
+	EventPasswordReset  = "user.password_reset" // exchange users (users-api)
+)
+
+// OrderEvent es el evento de orden que publica products-api
+// Solo se leen los campos que usan los templates
+type OrderEvent struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Order      Order     `json:"order"`
+}
+
+type Order struct {
+	ID              string           `json:"id"`
+	CustomerID      int              `json:"customer_id"`
+	Status          string           `json:"status"`
+	Items           []OrderLine      `json:"items"`
+	Subtotal        float64          `json:"subtotal"`
+	DiscountTotal   float64          `json:"discount_total"`
+	Shipping        OrderShipping    `json:"shipping"`
+	ShippingAddress *ShippingAddress `json:"shipping_address,omitempty"`
+	Total           float64          `json:"total"`
+	TrackingNumber  string           `json:"tracking_number,omitempty"`
+	ShippedAt       *time.Time       `json:"shipped_at,omitempty"`
+}
+
+type OrderLine struct {
+	ItemID    string  `json:"item_id"`
+	ItemName  string  `json:"item_name"`
+	UnitPrice float64 `json:"unit_price"`
+	Quantity  int     `json:"quantity"`
+	Subtotal  float64 `json:"subtotal"`
+}
+
+type OrderShipping struct {
+	Method        string  `json:"method"`
+	Cost          float64 `json:"cost"`
+	EstimatedDays int     `json:"estimated_days"`
+}
+
+type ShippingAddress struct {
+	RecipientName string `json:"recipient_name"`
+	Street        string `json:"street"`
+	Number        string `json:"number"`
+	Apartment     string `json:"apartment,omitempty"`
+	City          string `json:"city"`
+	Province      string `json:"province"`
+	PostalCode    string `json:"postal_code"`
+}
+
+// UserEvent es el evento de cuenta que publica users-api (registro, recuperación de contraseña)
+// ActionURL es el link que tiene que seguir el usuario (verificar el email, elegir una contraseña nueva)
+type UserEvent struct {
+	ID         string     `json:"id"`
+	Type       string     `json:"type"`
+	OccurredAt time.Time  `json:"occurred_at"`
+	Locale     string     `json:"locale,omitempty"`
+	User       User       `json:"user"`
+	ActionURL  string     `json:"action_url,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
+
+type User struct {
+	ID        int    `json:"id"`
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
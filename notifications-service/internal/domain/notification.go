@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"time"
+)
+
+// Recipient es el destinatario de una notificación
+type Recipient struct {
+	Email  string `json:"email"`
+	Name   string `json:"name"`
+	Locale string `json:"locale,omitempty"`
+}
+
+// Message es un email ya renderizado, listo para enviar
+type Message struct {
+	To      Recipient
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Estados de una entrega
+const (
+	DeliveryStatusSent   = "sent"   // Enviada
+	DeliveryStatusFailed = "failed" // Falló y se va a reintentar
+	DeliveryStatusDead   = "dead"   // Se agotaron los reintentos (o el evento es inválido): quedó en la DLQ
+)
+
+// Delivery es el registro de la notificación que generó un evento
+// El ID es el del evento: un evento repetido no vuelve a enviar el email
+type Delivery struct {
+	ID        string     `json:"id"`
+	EventType string     `json:"event_type"`
+	Recipient string     `json:"recipient"`
+	Subject   string     `json:"subject"`
+	Locale    string     `json:"locale"`
+	Sender    string     `json:"sender"`
+	Status    string     `json:"status"`
+	Attempts  int        `json:"attempts"`
+	Error     string     `json:"error,omitempty"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// DeliveryFilters son los filtros del log de entregas
+type DeliveryFilters struct {
+	Status    string
+	EventType string
+	Recipient string
+	Page      int
+	Count     int
+}
+
+type DeliveriesPaginatedResponse struct {
+	Page    int        `json:"page"`
+	Count   int        `json:"count"`
+	Total   int        `json:"total"`
+	Results []Delivery `json:"results"`
+}
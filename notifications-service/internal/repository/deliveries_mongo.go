@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"notifications-service/internal/dao"
+	"notifications-service/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoDeliveriesRepository guarda el log de entregas en MongoDB (_id = ID del evento)
+type MongoDeliveriesRepository struct {
+	col *mongo.Collection
+}
+
+// NewMongoDeliveriesRepository crea una nueva instancia del repository y se conecta a MongoDB
+func NewMongoDeliveriesRepository(ctx context.Context, uri, dbName, collectionName string) *MongoDeliveriesRepository {
+	opt := options.Client().ApplyURI(uri)
+	opt.SetServerSelectionTimeout(10 * time.Second)
+
+	client, err := mongo.Connect(ctx, opt)
+	if err != nil {
+		log.Fatalf("Error connecting to DB: %v", err)
+		return nil
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := client.Ping(pingCtx, nil); err != nil {
+		log.Fatalf("Error pinging DB: %v", err)
+		return nil
+	}
+
+	col := client.Database(dbName).Collection(collectionName)
+
+	// El log se consulta por estado y por destinatario, siempre de lo más reciente a lo más viejo
+	indexModels := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "updated_at", Value: -1}}},
+		{Keys: bson.D{{Key: "recipient", Value: 1}, {Key: "updated_at", Value: -1}}},
+	}
+	if _, err := col.Indexes().CreateMany(ctx, indexModels); err != nil {
+		log.Printf("Warning: Could not create delivery indexes: %v", err)
+	}
+
+	return &MongoDeliveriesRepository{
+		col: col,
+	}
+}
+
+// Save crea o reemplaza la entrega de un evento
+func (r *MongoDeliveriesRepository) Save(ctx context.Context, delivery domain.Delivery) (domain.Delivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	delivery.UpdatedAt = time.Now().UTC()
+	deliveryDAO := dao.FromDomainDelivery(delivery)
+
+	_, err := r.col.ReplaceOne(ctx, bson.M{"_id": deliveryDAO.ID}, deliveryDAO, options.Replace().SetUpsert(true))
+	if err != nil {
+		return domain.Delivery{}, err
+	}
+	return delivery, nil
+}
+
+// GetByID obtiene la entrega de un evento
+func (r *MongoDeliveriesRepository) GetByID(ctx context.Context, id string) (domain.Delivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var deliveryDAO dao.Delivery
+	if err := r.col.FindOne(ctx, bson.M{"_id": id}).Decode(&deliveryDAO); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Delivery{}, errors.New("delivery not found")
+		}
+		return domain.Delivery{}, err
+	}
+	return deliveryDAO.ToDomain(), nil
+}
+
+// List obtiene las entregas filtradas, de la más reciente a la más vieja
+func (r *MongoDeliveriesRepository) List(ctx context.Context, filters domain.DeliveryFilters) (domain.DeliveriesPaginatedResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if filters.Status != "" {
+		filter["status"] = filters.Status
+	}
+	if filters.EventType != "" {
+		filter["event_type"] = filters.EventType
+	}
+	if filters.Recipient != "" {
+		filter["recipient"] = filters.Recipient
+	}
+
+	total, err := r.col.CountDocuments(ctx, filter)
+	if err != nil {
+		return domain.DeliveriesPaginatedResponse{}, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updated_at", Value: -1}}).
+		SetSkip(int64((filters.Page - 1) * filters.Count)).
+		SetLimit(int64(filters.Count))
+
+	cur, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return domain.DeliveriesPaginatedResponse{}, err
+	}
+	defer cur.Close(ctx)
+
+	var deliveriesDAO []dao.Delivery
+	if err := cur.All(ctx, &deliveriesDAO); err != nil {
+		return domain.DeliveriesPaginatedResponse{}, err
+	}
+
+	results := make([]domain.Delivery, 0, len(deliveriesDAO))
+	for _, d := range deliveriesDAO {
+		results = append(results, d.ToDomain())
+	}
+
+	return domain.DeliveriesPaginatedResponse{
+		Page:    filters.Page,
+		Count:   len(results),
+		Total:   int(total),
+		Results: results,
+	}, nil
+}
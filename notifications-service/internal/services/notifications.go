@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"notifications-service/internal/domain"
+)
+
+// Sender envía un email ya renderizado (SMTP o archivos en una carpeta outbox)
+type Sender interface {
+	Name() string
+	Send(ctx context.Context, message domain.Message) error
+}
+
+// DeliveriesRepository persiste el log de entregas
+type DeliveriesRepository interface {
+	Save(ctx context.Context, delivery domain.Delivery) (domain.Delivery, error)
+	GetByID(ctx context.Context, id string) (domain.Delivery, error)
+	List(ctx context.Context, filters domain.DeliveryFilters) (domain.DeliveriesPaginatedResponse, error)
+}
+
+// UsersClient busca los datos de contacto de un cliente (los eventos de órdenes solo traen el customer_id)
+type UsersClient interface {
+	GetRecipient(ctx context.Context, userID int) (domain.Recipient, error)
+}
+
+var (
+	// ErrPermanent marca los errores que no se arreglan reintentando (evento inválido, sin template, usuario inexistente)
+	// El consumer manda esos mensajes directo a la DLQ
+	ErrPermanent        = errors.New("permanent notification error")
+	ErrDeliveryNotFound = errors.New("delivery not found")
+)
+
+// templateNames asocia cada tipo de evento con su template
+var templateNames = map[string]string{
+	domain.EventOrderPaid:      "order_paid",
+	domain.EventOrderShipped:   "order_shipped",
+	domain.EventUserRegistered: "user_registered",
+	domain.EventPasswordReset:  "password_reset",
+}
+
+// notification es lo que se arma a partir de un evento antes de renderizar
+type notification struct {
+	eventID  string
+	template string
+	locale   string
+	data     TemplateData
+}
+
+// NotificationsServiceImpl transforma los eventos en emails, los envía y registra cada entrega
+type NotificationsServiceImpl struct {
+	renderer   *TemplateRenderer
+	sender     Sender
+	deliveries DeliveriesRepository
+	users      UsersClient
+}
+
+// NewNotificationsService crea una nueva instancia del service
+func NewNotificationsService(renderer *TemplateRenderer, sender Sender, deliveries DeliveriesRepository, users UsersClient) *NotificationsServiceImpl {
+	return &NotificationsServiceImpl{
+		renderer:   renderer,
+		sender:     sender,
+		deliveries: deliveries,
+		users:      users,
+	}
+}
+
+// Handle procesa un evento: arma el email, lo envía y lo registra en el log de entregas
+// attempt empieza en 1. Si devuelve un error que no es ErrPermanent el consumer lo reintenta
+func (s *NotificationsServiceImpl) Handle(ctx context.Context, eventType string, body []byte, attempt int) error {
+	n, err := s.buildNotification(ctx, eventType, body)
+	if err != nil {
+		return err
+	}
+
+	// Un evento repetido (reintento del productor, redelivery) no vuelve a mandar el email
+	previous, err := s.deliveries.GetByID(ctx, n.eventID)
+	if err == nil && previous.Status == domain.DeliveryStatusSent {
+		log.Printf("⏭️ Event %s already delivered to %s", n.eventID, previous.Recipient)
+		return nil
+	}
+	createdAt := time.Now().UTC()
+	if err == nil {
+		createdAt = previous.CreatedAt
+	}
+
+	message, locale, err := s.renderer.Render(n.template, n.locale, n.data)
+	if err != nil {
+		return err
+	}
+
+	delivery := domain.Delivery{
+		ID:        n.eventID,
+		EventType: eventType,
+		Recipient: message.To.Email,
+		Subject:   message.Subject,
+		Locale:    locale,
+		Sender:    s.sender.Name(),
+		Attempts:  attempt,
+		CreatedAt: createdAt,
+	}
+
+	if err := s.sender.Send(ctx, message); err != nil {
+		delivery.Status = domain.DeliveryStatusFailed
+		delivery.Error = err.Error()
+		s.saveDelivery(ctx, delivery)
+		return fmt.Errorf("error sending %s to %s: %w", eventType, message.To.Email, err)
+	}
+
+	now := time.Now().UTC()
+	delivery.Status = domain.DeliveryStatusSent
+	delivery.SentAt = &now
+	s.saveDelivery(ctx, delivery)
+
+	log.Printf("📧 %s sent to %s (event %s)", eventType, message.To.Email, n.eventID)
+	return nil
+}
+
+// DeadLetter registra que el evento se mandó a la DLQ (reintentos agotados o evento inválido)
+func (s *NotificationsServiceImpl) DeadLetter(ctx context.Context, eventType string, body []byte, attempts int, cause error) {
+	var envelope struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(body, &envelope)
+	if envelope.ID == "" {
+		log.Printf("☠️ Unidentified %s event sent to the DLQ: %v", eventType, cause)
+		return
+	}
+
+	delivery, err := s.deliveries.GetByID(ctx, envelope.ID)
+	if err != nil {
+		delivery = domain.Delivery{ID: envelope.ID, EventType: eventType, Sender: s.sender.Name(), CreatedAt: time.Now().UTC()}
+	}
+	delivery.Status = domain.DeliveryStatusDead
+	delivery.Attempts = attempts
+	delivery.Error = cause.Error()
+	s.saveDelivery(ctx, delivery)
+
+	log.Printf("☠️ Event %s (%s) sent to the DLQ after %d attempts: %v", envelope.ID, eventType, attempts, cause)
+}
+
+// List obtiene el log de entregas con filtros y paginación
+func (s *NotificationsServiceImpl) List(ctx context.Context, filters domain.DeliveryFilters) (domain.DeliveriesPaginatedResponse, error) {
+	if filters.Page < 1 {
+		filters.Page = 1
+	}
+	if filters.Count < 1 || filters.Count > 100 {
+		filters.Count = 20
+	}
+	return s.deliveries.List(ctx, filters)
+}
+
+// GetByID obtiene la entrega de un evento
+func (s *NotificationsServiceImpl) GetByID(ctx context.Context, id string) (domain.Delivery, error) {
+	delivery, err := s.deliveries.GetByID(ctx, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "delivery not found") {
+			return domain.Delivery{}, ErrDeliveryNotFound
+		}
+		return domain.Delivery{}, err
+	}
+	return delivery, nil
+}
+
+// buildNotification lee el evento según su tipo y resuelve el destinatario
+func (s *NotificationsServiceImpl) buildNotification(ctx context.Context, eventType string, body []byte) (notification, error) {
+	template, ok := templateNames[eventType]
+	if !ok {
+		return notification{}, fmt.Errorf("%w: unknown event type %q", ErrPermanent, eventType)
+	}
+
+	switch eventType {
+	case domain.EventOrderPaid, domain.EventOrderShipped:
+		var event domain.OrderEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return notification{}, fmt.Errorf("%w: invalid order event: %v", ErrPermanent, err)
+		}
+		recipient, err := s.users.GetRecipient(ctx, event.Order.CustomerID)
+		if err != nil {
+			return notification{}, err
+		}
+		return validateNotification(notification{
+			eventID:  event.ID,
+			template: template,
+			locale:   recipient.Locale,
+			data:     TemplateData{Recipient: recipient, Order: &event.Order},
+		})
+
+	default:
+		var event domain.UserEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return notification{}, fmt.Errorf("%w: invalid user event: %v", ErrPermanent, err)
+		}
+		recipient := domain.Recipient{Email: event.User.Email, Name: event.User.FirstName, Locale: event.Locale}
+		return validateNotification(notification{
+			eventID:  event.ID,
+			template: template,
+			locale:   event.Locale,
+			data:     TemplateData{Recipient: recipient, User: &event.User, ActionURL: event.ActionURL, ExpiresAt: event.ExpiresAt},
+		})
+	}
+}
+
+func validateNotification(n notification) (notification, error) {
+	if n.eventID == "" {
+		return notification{}, fmt.Errorf("%w: event id is required", ErrPermanent)
+	}
+	if !strings.Contains(n.data.Recipient.Email, "@") {
+		return notification{}, fmt.Errorf("%w: event %s has no valid recipient email", ErrPermanent, n.eventID)
+	}
+	return n, nil
+}
+
+// saveDelivery registra la entrega; si falla solo se loguea para no reenviar un email ya enviado
+func (s *NotificationsServiceImpl) saveDelivery(ctx context.Context, delivery domain.Delivery) {
+	if _, err := s.deliveries.Save(ctx, delivery); err != nil {
+		log.Printf("⚠️ Error saving delivery %s: %v", delivery.ID, err)
+	}
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"notifications-service/internal/domain"
+	"testing"
+)
+
+type fakeSender struct {
+	sent []domain.Message
+	err  error
+}
+
+func (s *fakeSender) Name() string { return "fake" }
+
+func (s *fakeSender) Send(ctx context.Context, message domain.Message) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, message)
+	return nil
+}
+
+type fakeDeliveries struct {
+	deliveries map[string]domain.Delivery
+}
+
+func (r *fakeDeliveries) Save(ctx context.Context, delivery domain.Delivery) (domain.Delivery, error) {
+	r.deliveries[delivery.ID] = delivery
+	return delivery, nil
+}
+
+func (r *fakeDeliveries) GetByID(ctx context.Context, id string) (domain.Delivery, error) {
+	delivery, ok := r.deliveries[id]
+	if !ok {
+		return domain.Delivery{}, errors.New("delivery not found")
+	}
+	return delivery, nil
+}
+
+func (r *fakeDeliveries) List(ctx context.Context, filters domain.DeliveryFilters) (domain.DeliveriesPaginatedResponse, error) {
+	return domain.DeliveriesPaginatedResponse{}, nil
+}
+
+type fakeUsers struct {
+	recipients map[int]domain.Recipient
+}
+
+func (c *fakeUsers) GetRecipient(ctx context.Context, userID int) (domain.Recipient, error) {
+	recipient, ok := c.recipients[userID]
+	if !ok {
+		return domain.Recipient{}, ErrPermanent
+	}
+	return recipient, nil
+}
+
+func newTestNotificationsService(t *testing.T, sender *fakeSender) (*NotificationsServiceImpl, *fakeDeliveries) {
+	deliveries := &fakeDeliveries{deliveries: map[string]domain.Delivery{}}
+	users := &fakeUsers{recipients: map[int]domain.Recipient{7: {Email: "ana@mail.com", Name: "Ana"}}}
+	return NewNotificationsService(newTestRenderer(t), sender, deliveries, users), deliveries
+}
+
+func orderEventBody(t *testing.T, id string, customerID int) []byte {
+	body, err := json.Marshal(domain.OrderEvent{ID: id, Type: domain.EventOrderPaid, Order: domain.Order{ID: "ord-1", CustomerID: customerID, Total: 1500}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestHandle_SendsOnceAndLogsDelivery(t *testing.T) {
+	sender := &fakeSender{}
+	service, deliveries := newTestNotificationsService(t, sender)
+	body := orderEventBody(t, "ord-1:order.created", 7)
+
+	if err := service.Handle(context.Background(), domain.EventOrderPaid, body, 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// El mismo evento repetido no vuelve a enviar el email
+	if err := service.Handle(context.Background(), domain.EventOrderPaid, body, 1); err != nil {
+		t.Fatalf("Expected no error on redelivery, got %v", err)
+	}
+
+	if len(sender.sent) != 1 || sender.sent[0].To.Email != "ana@mail.com" {
+		t.Fatalf("Expected exactly one email to ana@mail.com, got %+v", sender.sent)
+	}
+	delivery := deliveries.deliveries["ord-1:order.created"]
+	if delivery.Status != domain.DeliveryStatusSent || delivery.SentAt == nil || delivery.Locale != "es" {
+		t.Errorf("Expected a sent delivery in spanish, got %+v", delivery)
+	}
+}
+
+func TestHandle_SendErrorIsRetryable(t *testing.T) {
+	sender := &fakeSender{err: errors.New("smtp unavailable")}
+	service, deliveries := newTestNotificationsService(t, sender)
+
+	err := service.Handle(context.Background(), domain.EventOrderPaid, orderEventBody(t, "ev-1", 7), 2)
+	if err == nil || errors.Is(err, ErrPermanent) {
+		t.Fatalf("Expected a retryable error, got %v", err)
+	}
+	if delivery := deliveries.deliveries["ev-1"]; delivery.Status != domain.DeliveryStatusFailed || delivery.Attempts != 2 {
+		t.Errorf("Expected a failed delivery with 2 attempts, got %+v", delivery)
+	}
+
+	service.DeadLetter(context.Background(), domain.EventOrderPaid, orderEventBody(t, "ev-1", 7), 5, err)
+	if delivery := deliveries.deliveries["ev-1"]; delivery.Status != domain.DeliveryStatusDead || delivery.Attempts != 5 {
+		t.Errorf("Expected a dead delivery with 5 attempts, got %+v", delivery)
+	}
+}
+
+func TestHandle_PermanentErrors(t *testing.T) {
+	service, _ := newTestNotificationsService(t, &fakeSender{})
+	ctx := context.Background()
+
+	if err := service.Handle(ctx, "order.unknown", []byte(`{}`), 1); !errors.Is(err, ErrPermanent) {
+		t.Errorf("Expected ErrPermanent for an unknown event, got %v", err)
+	}
+	if err := service.Handle(ctx, domain.EventOrderPaid, []byte(`not json`), 1); !errors.Is(err, ErrPermanent) {
+		t.Errorf("Expected ErrPermanent for an invalid body, got %v", err)
+	}
+	if err := service.Handle(ctx, domain.EventOrderPaid, orderEventBody(t, "ev-2", 99), 1); !errors.Is(err, ErrPermanent) {
+		t.Errorf("Expected ErrPermanent for an unknown customer, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"notifications-service/internal/domain"
+)
+
+// templateFuncs son las funciones disponibles en los templates
+var templateFuncs = map[string]any{
+	"money": func(amount float64) string { return fmt.Sprintf("$%.2f", amount) },
+	"date": func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format("02/01/2006 15:04")
+	},
+}
+
+// TemplateData son los datos que recibe cada template; cada evento completa lo que le corresponde
+type TemplateData struct {
+	Recipient domain.Recipient
+	Order     *domain.Order
+	User      *domain.User
+	ActionURL string
+	ExpiresAt *time.Time
+}
+
+// TemplateRenderer renderiza los emails desde los templates de cada idioma
+// Si un idioma no tiene el template se usa el idioma por defecto
+type TemplateRenderer struct {
+	defaultLocale string
+	text          map[string]*texttemplate.Template
+	html          map[string]*htmltemplate.Template
+}
+
+// NewTemplateRenderer parsea todos los templates al arrancar, así un template roto falla en el deploy y no en un envío
+func NewTemplateRenderer(files fs.FS, defaultLocale string) (*TemplateRenderer, error) {
+	r := &TemplateRenderer{
+		defaultLocale: defaultLocale,
+		text:          map[string]*texttemplate.Template{},
+		html:          map[string]*htmltemplate.Template{},
+	}
+
+	paths, err := fs.Glob(files, "*/*.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("error listing templates: %w", err)
+	}
+	for _, p := range paths {
+		locale := path.Dir(p)
+		base := path.Base(p)
+		switch {
+		case strings.HasSuffix(base, ".txt.tmpl"):
+			key := locale + "/" + strings.TrimSuffix(base, ".txt.tmpl")
+			tmpl, err := texttemplate.New(base).Funcs(templateFuncs).ParseFS(files, p)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing template %s: %w", p, err)
+			}
+			if tmpl.Lookup("subject") == nil {
+				return nil, fmt.Errorf("template %s has no subject block", p)
+			}
+			r.text[key] = tmpl
+		case strings.HasSuffix(base, ".html.tmpl"):
+			key := locale + "/" + strings.TrimSuffix(base, ".html.tmpl")
+			tmpl, err := htmltemplate.New(base).Funcs(templateFuncs).ParseFS(files, p)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing template %s: %w", p, err)
+			}
+			r.html[key] = tmpl
+		}
+	}
+	return r, nil
+}
+
+// Render arma el asunto, el HTML y el texto plano de una notificación
+// Devuelve el idioma que terminó usando
+func (r *TemplateRenderer) Render(name, locale string, data TemplateData) (domain.Message, string, error) {
+	locale = r.resolveLocale(name, locale)
+	key := locale + "/" + name
+
+	text, ok := r.text[key]
+	if !ok {
+		return domain.Message{}, "", fmt.Errorf("%w: template %s not found", ErrPermanent, key)
+	}
+
+	var subject, body, html bytes.Buffer
+	if err := text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return domain.Message{}, "", fmt.Errorf("%w: error rendering subject of %s: %v", ErrPermanent, key, err)
+	}
+	if err := text.Execute(&body, data); err != nil {
+		return domain.Message{}, "", fmt.Errorf("%w: error rendering %s: %v", ErrPermanent, key, err)
+	}
+	if tmpl, ok := r.html[key]; ok {
+		if err := tmpl.Execute(&html, data); err != nil {
+			return domain.Message{}, "", fmt.Errorf("%w: error rendering html of %s: %v", ErrPermanent, key, err)
+		}
+	}
+
+	return domain.Message{
+		To:      data.Recipient,
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(body.String()) + "\n",
+		HTML:    html.String(),
+	}, locale, nil
+}
+
+// resolveLocale normaliza el idioma ("es-AR" -> "es") y cae al idioma por defecto si no hay template
+func (r *TemplateRenderer) resolveLocale(name, locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locale = locale[:i]
+	}
+	if _, ok := r.text[locale+"/"+name]; ok {
+		return locale
+	}
+	return r.defaultLocale
+}
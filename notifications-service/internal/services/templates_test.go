@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+	"notifications-service/internal/domain"
+	"notifications-service/internal/templates"
+	"strings"
+	"testing"
+)
+
+func newTestRenderer(t *testing.T) *TemplateRenderer {
+	t.Helper()
+	renderer, err := NewTemplateRenderer(templates.FS, "es")
+	if err != nil {
+		t.Fatalf("Expected templates to parse, got %v", err)
+	}
+	return renderer
+}
+
+func TestRender_Locales(t *testing.T) {
+	renderer := newTestRenderer(t)
+	data := TemplateData{
+		Recipient: domain.Recipient{Email: "ana@mail.com", Name: "Ana"},
+		Order:     &domain.Order{ID: "ord-1", Total: 1500},
+	}
+
+	message, locale, err := renderer.Render("order_paid", "en-US", data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if locale != "en" || message.Subject != "Your order #ord-1 is confirmed" {
+		t.Errorf("Expected the english template, got %s / %q", locale, message.Subject)
+	}
+	if !strings.Contains(message.HTML, "ord-1") || !strings.Contains(message.Text, "Ana") {
+		t.Errorf("Expected html and text bodies with the order data")
+	}
+
+	// Un idioma sin templates cae al idioma por defecto
+	message, locale, err = renderer.Render("order_paid", "pt-BR", data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if locale != "es" || message.Subject != "Tu pedido #ord-1 está confirmado" {
+		t.Errorf("Expected the spanish template, got %s / %q", locale, message.Subject)
+	}
+}
+
+func TestRender_AllEventsHaveTemplates(t *testing.T) {
+	renderer := newTestRenderer(t)
+	data := TemplateData{
+		Recipient: domain.Recipient{Email: "ana@mail.com", Name: "Ana"},
+		Order:     &domain.Order{ID: "ord-1"},
+		User:      &domain.User{ID: 1, Email: "ana@mail.com", FirstName: "Ana"},
+		ActionURL: "https://mates.local/action",
+	}
+	for _, locale := range []string{"es", "en"} {
+		for eventType, name := range templateNames {
+			if _, used, err := renderer.Render(name, locale, data); err != nil || used != locale {
+				t.Errorf("Expected %s (%s) to render in %s, got %s / %v", name, eventType, locale, used, err)
+			}
+		}
+	}
+}
+
+func TestRender_UnknownTemplateIsPermanent(t *testing.T) {
+	renderer := newTestRenderer(t)
+	if _, _, err := renderer.Render("missing", "es", TemplateData{}); !errors.Is(err, ErrPermanent) {
+		t.Errorf("Expected ErrPermanent, got %v", err)
+	}
+}
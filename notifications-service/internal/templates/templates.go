@@ -0,0 +1,8 @@
+// Package templates contiene los templates de los emails, uno por tipo de evento y por idioma
+// Cada notificación tiene <idioma>/<nombre>.txt.tmpl (con el asunto en el bloque "subject") y <idioma>/<nombre>.html.tmpl
+package templates
+
+import "embed"
+
+//go:embed */*.tmpl
+var FS embed.FS
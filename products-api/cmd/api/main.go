@@ -7,6 +7,7 @@ import (
 	"products-api/internal/clients"
 	"products-api/internal/config"
 	"products-api/internal/controllers"
+	"products-api/internal/domain"
 	"products-api/internal/middleware"
 	"products-api/internal/repository"
 	"products-api/internal/services"
@@ -65,14 +66,43 @@ func main() {
 	salesLocalCacheRepo := repository.NewSalesLocalCacheRepository(30 * time.Second)
 
 	// Capa de logica de negocio para Sales (inyectamos itemService para calcular precios)
-	salesService := services.NewSalesService(salesMongoRepo, salesLocalCacheRepo, &itemService)
+	// IVA: alícuota por clase de producto, con precios finales o netos según la configuración
+	taxService := services.NewTaxService(cfg.Tax.PricesIncludeTax)
+
+	salesService := services.NewSalesService(salesMongoRepo, salesLocalCacheRepo, &itemService, taxService)
 
 	// Capa de controladores para Sales
 	salesController := controllers.NewSalesController(&salesService)
 
+	// Repositorio de reportes: aggregation pipelines sobre la coleccion sales (solo cuentan las ordenes pagadas o despachadas)
+	reportsMongoRepo := repository.NewMongoSalesReportsRepository(ctx, cfg.Mongo.URI, cfg.Mongo.DB, "sales", "items", "orders")
+
+	// Capa de logica de negocio y controlador para los reportes de ventas
+	reportsService := services.NewReportsService(reportsMongoRepo)
+	reportsController := controllers.NewReportsController(reportsService)
+
+	// Claves de idempotencia: evitan procesar dos veces una venta o un checkout reintentado
+	idempotencyMongoRepo := repository.NewMongoIdempotencyRepository(ctx, cfg.Mongo.URI, cfg.Mongo.DB, "idempotency_keys")
+	idempotencyService := services.NewIdempotencyService(idempotencyMongoRepo, 24*time.Hour)
+	idempotencyController := controllers.NewIdempotencyController(idempotencyService)
+
 	// Capa de logica de negocio para Auth y controlador
-	authService := services.NewAuthService("http://users-api:8082")
-	authController := controllers.NewAuthController(authService)
+	// Los tokens se verifican localmente con las claves públicas (JWKS) de users-api
+	jwksCache := services.NewJWKSCache(cfg.Auth.JWKSURL, time.Duration(cfg.Auth.JWKSCacheMinutes)*time.Minute)
+	authService := services.NewAuthService(cfg.UsersAPI, jwksCache, cfg.Auth.IntrospectionFallback)
+
+	// Registro de auditoría: los accesos denegados quedan guardados en MongoDB
+	auditMongoRepo := repository.NewMongoAuditRepository(ctx, cfg.Mongo.URI, cfg.Mongo.DB, "audit_log")
+	auditService := services.NewAuditService(auditMongoRepo)
+	authController := controllers.NewAuthController(authService, auditService)
+
+	// Cada cliente solo accede a su carrito y a sus ventas (los admins a todos)
+	ownerOrAdmin := authController.RequireOwnerOrAdmin("customerID")
+
+	// Permisos por rol (vienen en el token): catálogo, precios (cupones y promociones) y reportes
+	catalogWriter := authController.RequirePermission(domain.PermItemsWrite)
+	pricingWriter := authController.RequirePermission(domain.PermPricingWrite)
+	reportsReader := authController.RequirePermission(domain.PermReportsRead)
 
 	// ========================================
 	// CART - Configuracion
@@ -84,12 +114,147 @@ func main() {
 	// Repositorio de cache local para Cart
 	cartLocalCacheRepo := repository.NewCartLocalCacheRepository(30 * time.Second)
 
+	// Repositorio MongoDB para los carritos de invitados (vencen solos por un índice TTL)
+	guestCartMongoRepo := repository.NewMongoGuestCartRepository(ctx, cfg.Mongo.URI, cfg.Mongo.DB, "guest_carts")
+
+	// Repositorio MongoDB para las listas de deseos
+	wishlistsMongoRepo := repository.NewMongoWishlistsRepository(ctx, cfg.Mongo.URI, cfg.Mongo.DB, "wishlists")
+
+	// ========================================
+	// CHECKOUT - Configuracion (saga persistida)
+	// ========================================
+
+	// Repositorio MongoDB para las ordenes generadas por el checkout
+	ordersMongoRepo := repository.NewMongoOrdersRepository(ctx, cfg.Mongo.URI, cfg.Mongo.DB, "orders")
+
+	// Repositorio MongoDB para el estado de cada saga de checkout
+	checkoutSagaRepo := repository.NewMongoCheckoutSagaRepository(ctx, cfg.Mongo.URI, cfg.Mongo.DB, "checkout_sagas")
+
+	// Eventos del ciclo de vida de las ordenes (order.created, order.paid...) en un exchange topic de RabbitMQ
+	orderEvents := clients.NewRabbitMQEventsPublisher(
+		cfg.RabbitMQ.Username,
+		cfg.RabbitMQ.Password,
+		cfg.RabbitMQ.Host,
+		cfg.RabbitMQ.Port,
+		cfg.RabbitMQ.OrdersExchange,
+	)
+
+	// Capa de logica de negocio y controlador para Orders (consulta y despacho)
+	ordersService := services.NewOrdersService(ordersMongoRepo, orderEvents)
+	ordersController := controllers.NewOrdersController(ordersService)
+
+	// ========================================
+	// PAYMENTS - Configuracion
+	// ========================================
+
+	// Proveedor de pagos: por ahora solo el gateway falso, que guarda los pagos en memoria
+	// Sin un proveedor configurado la API no arranca, y el falso hay que habilitarlo a mano (PAYMENTS_ALLOW_FAKE)
+	if cfg.Payments.WebhookSecret == "" {
+		log.Fatalf("PAYMENTS_WEBHOOK_SECRET is required")
+	}
+	var paymentGateway services.PaymentGateway
+	switch cfg.Payments.Provider {
+	case "":
+		log.Fatalf("no payments provider configured (PAYMENTS_PROVIDER)")
+	case "fake":
+		if !cfg.Payments.AllowFake {
+			log.Fatalf("the fake payments provider keeps payments in memory and is only for local development: set PAYMENTS_ALLOW_FAKE=true to use it")
+		}
+		log.Println("⚠️ Using the fake payments provider: payments are lost on restart")
+		paymentGateway = clients.NewFakePaymentGateway(cfg.Payments.WebhookSecret)
+	default:
+		log.Fatalf("unknown payments provider: %s", cfg.Payments.Provider)
+	}
+
+	// Repositorio MongoDB para los pagos de las ordenes
+	paymentsMongoRepo := repository.NewMongoPaymentsRepository(ctx, cfg.Mongo.URI, cfg.Mongo.DB, "payments")
+
+	// Capa de logica de negocio y controlador para Payments
+	paymentsService := services.NewPaymentsService(paymentGateway, paymentsMongoRepo, ordersMongoRepo, orderEvents)
+	paymentsController := controllers.NewPaymentsController(paymentsService)
+
+	// ========================================
+	// SHIPPING - Configuracion
+	// ========================================
+
+	// Tabla de tarifas por zona y peso: la de por defecto o la de un archivo JSON
+	var shippingRates services.ShippingRateTable = services.DefaultShippingRateTable()
+	if cfg.Shipping.RatesFile != "" {
+		rates, err := services.LoadZoneRateTable(cfg.Shipping.RatesFile)
+		if err != nil {
+			log.Fatalf("error loading shipping rates: %v", err)
+		}
+		shippingRates = rates
+	}
+
+	// Las direcciones guardadas de cada usuario viven en users-api
+	usersAPIClient := clients.NewUsersAPIClient(cfg.UsersAPI)
+	shippingService := services.NewShippingService(shippingRates, usersAPIClient)
+
+	// ========================================
+	// COUPONS - Configuracion
+	// ========================================
+
+	// Repositorio MongoDB para los cupones (y sus redenciones)
+	couponsMongoRepo := repository.NewMongoCouponsRepository(ctx, cfg.Mongo.URI, cfg.Mongo.DB, "coupons")
+
+	// Capa de logica de negocio y controlador para Coupons
+	couponsService := services.NewCouponsService(couponsMongoRepo)
+	couponsController := controllers.NewCouponsController(couponsService)
+
+	// ========================================
+	// PROMOTIONS - Configuracion
+	// ========================================
+
+	// Repositorio MongoDB para las reglas de promociones automaticas (2x1, % off por categoria...)
+	promotionsMongoRepo := repository.NewMongoPromotionsRepository(ctx, cfg.Mongo.URI, cfg.Mongo.DB, "promotions")
+
+	// Capa de logica de negocio y controlador para Promotions
+	promotionsService := services.NewPromotionsService(promotionsMongoRepo)
+	promotionsController := controllers.NewPromotionsController(promotionsService)
+
+	// Precios: promociones + cupon, el mismo calculo para carrito, cotizacion y checkout
+	// Planes de cuotas que se ofrecen en la cotización
+	installmentPlans, err := services.ParseInstallmentPlans(cfg.Payments.InstallmentPlans)
+	if err != nil {
+		log.Fatalf("error loading installment plans: %v", err)
+	}
+	pricingService := services.NewPricingService(promotionsService, couponsService, taxService, installmentPlans)
+
+	// Orquestador del checkout: reservar stock -> crear orden -> redimir cupon -> autorizar y cobrar -> vaciar carrito
+	checkoutSaga := services.NewCheckoutSagaService(checkoutSagaRepo, ordersMongoRepo, &itemService, &salesService, cartMongoRepo, cartLocalCacheRepo, paymentsService, shippingService, pricingService, couponsService, orderEvents)
+
+	// Worker que termina o revierte las sagas que quedaron a medias por un crash
+	checkoutSaga.StartRecoveryWorker(ctx, 30*time.Second)
+
 	// Capa de logica de negocio para Cart
-	cartService := services.NewCartService(cartMongoRepo, cartLocalCacheRepo, &itemService, &salesService)
+	cartService := services.NewCartService(cartMongoRepo, cartLocalCacheRepo, &itemService, checkoutSaga, shippingService, pricingService, guestCartMongoRepo, time.Duration(cfg.Cart.GuestTTLHours)*time.Hour)
+
+	// Eventos de carritos (cart.abandoned) en su propio exchange topic
+	cartEvents := clients.NewRabbitMQEventsPublisher(
+		cfg.RabbitMQ.Username,
+		cfg.RabbitMQ.Password,
+		cfg.RabbitMQ.Host,
+		cfg.RabbitMQ.Port,
+		cfg.RabbitMQ.CartsExchange,
+	)
+
+	// Worker que publica cart.abandoned para los carritos sin modificaciones y elimina los carritos vacíos viejos
+	abandonedCartsService := services.NewAbandonedCartsService(cartMongoRepo, cartService, cartEvents, services.AbandonedCartsPolicy{
+		IdleAfter:        time.Duration(cfg.Cart.AbandonedAfterHours) * time.Hour,
+		ReminderInterval: time.Duration(cfg.Cart.ReminderIntervalHours) * time.Hour,
+		MaxReminders:     cfg.Cart.MaxReminders,
+		PurgeEmptyAfter:  time.Duration(cfg.Cart.PurgeEmptyAfterDays) * 24 * time.Hour,
+	})
+	abandonedCartsService.StartWorker(ctx, time.Duration(cfg.Cart.AbandonedCheckMinutes)*time.Minute)
 
 	// Capa de controladores para Cart
 	cartController := controllers.NewCartController(cartService)
 
+	// Listas de deseos: se enriquecen y se mueven al carrito a través del service de Cart
+	wishlistsService := services.NewWishlistsService(wishlistsMongoRepo, cartService)
+	wishlistsController := controllers.NewWishlistsController(wishlistsService)
+
 	// Configurar router HTTP con Gin
 	router := gin.Default()
 
@@ -103,16 +268,16 @@ func main() {
 
 	// 📚 Rutas de Items API
 
-	router.POST("/items", authController.VerifyAdminToken, itemController.CreateItem)
+	router.POST("/items", authController.VerifyToken, catalogWriter, itemController.CreateItem)
 
 	// GET /items/:id - obtener item por ID
 	router.GET("/items/:id", itemController.GetItemByID)
 
 	// PUT /items/:id - actualizar item existente
-	router.PUT("/items/:id", authController.VerifyAdminToken, itemController.UpdateItem)
+	router.PUT("/items/:id", authController.VerifyToken, catalogWriter, itemController.UpdateItem)
 
 	// DELETE /items/:id - eliminar item
-	router.DELETE("/items/:id", authController.VerifyAdminToken, itemController.DeleteItem)
+	router.DELETE("/items/:id", authController.VerifyToken, catalogWriter, itemController.DeleteItem)
 
 	// ========================================
 	// SALES - Rutas
@@ -122,44 +287,194 @@ func main() {
 	//router.GET("/sales", salesController.List)
 
 	// POST /sales - crear nueva venta
-	router.POST("/sales", authController.VerifyToken, salesController.CreateSale)
+	// Acepta el header Idempotency-Key para que un reintento no duplique la venta
+	router.POST("/sales", authController.VerifyToken, authController.RequireVerifiedEmail, idempotencyController.Handle, salesController.CreateSale)
 
 	// GET /sales/:id - obtener venta por ID (MongoDB ObjectID)
 	router.GET("/sales/:id", authController.VerifyToken, salesController.GetSaleByID)
 
 	// GET /sales/customer/:customerID - obtener todas las ventas de un cliente
-	router.GET("/sales/customer/:customerID", authController.VerifyToken, salesController.GetSalesByCustomerID)
+	router.GET("/sales/customer/:customerID", authController.VerifyToken, authController.RequireOwnerOrPermission("customerID", domain.PermSalesRead), salesController.GetSalesByCustomerID)
 
-	// PUT /sales/:id - actualizar venta existente
-	router.PUT("/sales/:id", authController.VerifyToken, salesController.UpdateSale)
+	// PUT /sales/:id - actualizar venta existente (solo operadores; las ventas de una orden responden 409)
+	router.PUT("/sales/:id", authController.VerifyToken, authController.RequirePermission(domain.PermSalesWrite), salesController.UpdateSale)
 
-	// DELETE /sales/:id - eliminar venta
-	router.DELETE("/sales/:id", authController.VerifyToken, salesController.DeleteSale)
+	// DELETE /sales/:id - eliminar venta (solo operadores; las ventas de una orden responden 409)
+	router.DELETE("/sales/:id", authController.VerifyToken, authController.RequirePermission(domain.PermSalesWrite), salesController.DeleteSale)
+
+	// ========================================
+	// REPORTS - Rutas (reports:read)
+	// ========================================
+
+	// GET /reports/sales/revenue - facturacion y unidades por dia/semana/mes
+	router.GET("/reports/sales/revenue", authController.VerifyToken, reportsReader, reportsController.RevenueByPeriod)
+
+	// GET /reports/sales/top-products - productos mas vendidos
+	router.GET("/reports/sales/top-products", authController.VerifyToken, reportsReader, reportsController.TopProducts)
+
+	// GET /reports/sales/by-category - facturacion por categoria
+	router.GET("/reports/sales/by-category", authController.VerifyToken, reportsReader, reportsController.RevenueByCategory)
+
+	// GET /reports/sales/summary - ticket promedio y tasa de clientes recurrentes
+	router.GET("/reports/sales/summary", authController.VerifyToken, reportsReader, reportsController.Summary)
 
 	// ========================================
 	// CART - Rutas
 	// ========================================
 
 	// Crear un carrito para un cliente (si no existe)
-	router.POST("/cart/:customerID", authController.VerifyToken, cartController.CreateCart)
+	router.POST("/cart/:customerID", authController.VerifyToken, ownerOrAdmin, cartController.CreateCart)
 
 	// GET /cart/:customerID - obtener carrito del cliente
-	router.GET("/cart/:customerID", authController.VerifyToken, cartController.GetCart)
+	router.GET("/cart/:customerID", authController.VerifyToken, ownerOrAdmin, cartController.GetCart)
 
 	// POST /cart/:customerID/items - agregar item al carrito
-	router.POST("/cart/:customerID/items", authController.VerifyToken, cartController.AddItem)
+	router.POST("/cart/:customerID/items", authController.VerifyToken, ownerOrAdmin, cartController.AddItem)
 
 	// PUT /cart/:customerID/items/:itemID - actualizar cantidad de un item
-	router.PUT("/cart/:customerID/items/:itemID", authController.VerifyToken, cartController.UpdateItemCart)
+	router.PUT("/cart/:customerID/items/:itemID", authController.VerifyToken, ownerOrAdmin, cartController.UpdateItemCart)
 
 	// DELETE /cart/:customerID/items/:itemID - eliminar item del carrito
-	router.DELETE("/cart/:customerID/items/:itemID", authController.VerifyToken, cartController.RemoveItem)
+	router.DELETE("/cart/:customerID/items/:itemID", authController.VerifyToken, ownerOrAdmin, cartController.RemoveItem)
 
 	// DELETE /cart/:customerID - vaciar carrito completamente
-	router.DELETE("/cart/:customerID", authController.VerifyToken, cartController.ClearCart)
+	router.DELETE("/cart/:customerID", authController.VerifyToken, ownerOrAdmin, cartController.ClearCart)
+
+	// POST /cart/:customerID/quote - cotizar el carrito con el envío a un destino
+	router.POST("/cart/:customerID/quote", authController.VerifyToken, ownerOrAdmin, cartController.Quote)
+
+	// POST /cart/:customerID/coupon - aplicar un codigo de descuento al carrito
+	router.POST("/cart/:customerID/coupon", authController.VerifyToken, ownerOrAdmin, cartController.ApplyCoupon)
+
+	// DELETE /cart/:customerID/coupon - quitar el codigo de descuento del carrito
+	router.DELETE("/cart/:customerID/coupon", authController.VerifyToken, ownerOrAdmin, cartController.RemoveCoupon)
+
+	// POST /cart/:customerID/acknowledge - aceptar los cambios de precio y stock desde que se agregaron los productos
+	router.POST("/cart/:customerID/acknowledge", authController.VerifyToken, ownerOrAdmin, cartController.AcknowledgeChanges)
 
 	// POST /cart/:customerID/checkout - procesar compra del carrito
-	router.POST("/cart/:customerID/checkout", authController.VerifyToken, cartController.Checkout)
+	// Acepta el header Idempotency-Key para que un reintento no cobre ni descuente stock dos veces
+	router.POST("/cart/:customerID/checkout", authController.VerifyToken, ownerOrAdmin, authController.RequireVerifiedEmail, idempotencyController.Handle, cartController.Checkout)
+
+	// POST /cart/:customerID/merge - pasar el carrito de invitado al del cliente (al iniciar sesión)
+	router.POST("/cart/:customerID/merge", authController.VerifyToken, ownerOrAdmin, cartController.MergeGuestCart)
+
+	// POST /cart/:customerID/items/:itemID/move-to-wishlist - guardar una línea del carrito en una lista de deseos
+	router.POST("/cart/:customerID/items/:itemID/move-to-wishlist", authController.VerifyToken, ownerOrAdmin, wishlistsController.MoveToWishlist)
+
+	// ========================================
+	// GUEST CART - Rutas (sin login, el token de sesión va en el header X-Cart-Session)
+	// ========================================
+
+	// POST /guest-cart - abrir un carrito de invitado (devuelve session_token)
+	router.POST("/guest-cart", cartController.CreateGuestCart)
+
+	// GET /guest-cart - obtener el carrito de invitado
+	router.GET("/guest-cart", cartController.GetGuestCart)
+
+	// DELETE /guest-cart - descartar el carrito de invitado
+	router.DELETE("/guest-cart", cartController.DeleteGuestCart)
+
+	// POST /guest-cart/items - agregar item al carrito de invitado
+	router.POST("/guest-cart/items", cartController.AddGuestItem)
+
+	// PUT /guest-cart/items/:itemID - actualizar cantidad de un item
+	router.PUT("/guest-cart/items/:itemID", cartController.UpdateGuestItem)
+
+	// DELETE /guest-cart/items/:itemID - eliminar item del carrito de invitado
+	router.DELETE("/guest-cart/items/:itemID", cartController.RemoveGuestItem)
+
+	// ========================================
+	// WISHLISTS - Rutas
+	// ========================================
+
+	// GET /wishlists/:customerID - listar las listas de deseos del cliente
+	router.GET("/wishlists/:customerID", authController.VerifyToken, ownerOrAdmin, wishlistsController.List)
+
+	// POST /wishlists/:customerID - crear una lista con nombre
+	router.POST("/wishlists/:customerID", authController.VerifyToken, ownerOrAdmin, wishlistsController.Create)
+
+	// GET /wishlists/:customerID/:wishlistID - obtener una lista con precio y stock actuales
+	router.GET("/wishlists/:customerID/:wishlistID", authController.VerifyToken, ownerOrAdmin, wishlistsController.Get)
+
+	// PUT /wishlists/:customerID/:wishlistID - renombrar una lista
+	router.PUT("/wishlists/:customerID/:wishlistID", authController.VerifyToken, ownerOrAdmin, wishlistsController.Rename)
+
+	// DELETE /wishlists/:customerID/:wishlistID - eliminar una lista
+	router.DELETE("/wishlists/:customerID/:wishlistID", authController.VerifyToken, ownerOrAdmin, wishlistsController.Delete)
+
+	// POST /wishlists/:customerID/:wishlistID/items - guardar un producto en la lista
+	router.POST("/wishlists/:customerID/:wishlistID/items", authController.VerifyToken, ownerOrAdmin, wishlistsController.AddItem)
+
+	// DELETE /wishlists/:customerID/:wishlistID/items/:itemID - sacar un producto de la lista
+	router.DELETE("/wishlists/:customerID/:wishlistID/items/:itemID", authController.VerifyToken, ownerOrAdmin, wishlistsController.RemoveItem)
+
+	// POST /wishlists/:customerID/:wishlistID/items/:itemID/move-to-cart - pasar un producto de la lista al carrito
+	router.POST("/wishlists/:customerID/:wishlistID/items/:itemID/move-to-cart", authController.VerifyToken, ownerOrAdmin, wishlistsController.MoveToCart)
+
+	// POST /wishlists/:customerID/:wishlistID/share - generar el enlace de solo lectura
+	router.POST("/wishlists/:customerID/:wishlistID/share", authController.VerifyToken, ownerOrAdmin, wishlistsController.Share)
+
+	// DELETE /wishlists/:customerID/:wishlistID/share - dejar de compartir la lista
+	router.DELETE("/wishlists/:customerID/:wishlistID/share", authController.VerifyToken, ownerOrAdmin, wishlistsController.Unshare)
+
+	// GET /shared-wishlists/:token - ver una lista compartida (sin login, solo lectura)
+	router.GET("/shared-wishlists/:token", wishlistsController.GetShared)
+
+	// ========================================
+	// COUPONS - Rutas (pricing:write)
+	// ========================================
+
+	// GET /coupons - listar cupones
+	router.GET("/coupons", authController.VerifyToken, pricingWriter, couponsController.List)
+
+	// GET /coupons/:id - obtener cupon por ID
+	router.GET("/coupons/:id", authController.VerifyToken, pricingWriter, couponsController.GetByID)
+
+	// POST /coupons - crear cupon
+	router.POST("/coupons", authController.VerifyToken, pricingWriter, couponsController.Create)
+
+	// PUT /coupons/:id - actualizar cupon
+	router.PUT("/coupons/:id", authController.VerifyToken, pricingWriter, couponsController.Update)
+
+	// DELETE /coupons/:id - eliminar cupon
+	router.DELETE("/coupons/:id", authController.VerifyToken, pricingWriter, couponsController.Delete)
+
+	// ========================================
+	// PROMOTIONS - Rutas (pricing:write)
+	// ========================================
+
+	// GET /promotions - listar promociones
+	router.GET("/promotions", authController.VerifyToken, pricingWriter, promotionsController.List)
+
+	// GET /promotions/:id - obtener promocion por ID
+	router.GET("/promotions/:id", authController.VerifyToken, pricingWriter, promotionsController.GetByID)
+
+	// POST /promotions - crear promocion
+	router.POST("/promotions", authController.VerifyToken, pricingWriter, promotionsController.Create)
+
+	// PUT /promotions/:id - actualizar promocion
+	router.PUT("/promotions/:id", authController.VerifyToken, pricingWriter, promotionsController.Update)
+
+	// DELETE /promotions/:id - eliminar promocion
+	router.DELETE("/promotions/:id", authController.VerifyToken, pricingWriter, promotionsController.Delete)
+
+	// ========================================
+	// ORDERS - Rutas
+	// ========================================
+
+	// GET /orders/:id - obtener una orden (su cliente o quien tenga sales:read)
+	router.GET("/orders/:id", authController.VerifyToken, ordersController.GetByID)
+
+	// POST /orders/:id/ship - marcar una orden pagada como despachada
+	router.POST("/orders/:id/ship", authController.VerifyToken, authController.RequirePermission(domain.PermOrdersWrite), ordersController.Ship)
+
+	// ========================================
+	// PAYMENTS - Rutas
+	// ========================================
+
+	// POST /payments/webhook - notificaciones del proveedor de pagos (validadas por firma)
+	router.POST("/payments/webhook", paymentsController.Webhook)
 
 	// Configuracion del server HTTP
 	srv := &http.Server{
@@ -173,6 +488,7 @@ func main() {
 	log.Printf("Items API: http://localhost:%s/items", cfg.Port)
 	log.Printf("Sales API: http://localhost:%s/sales", cfg.Port)
 	log.Printf("Cart API: http://localhost:%s/cart", cfg.Port)
+	log.Printf("Reports API: http://localhost:%s/reports/sales", cfg.Port)
 
 	// Iniciar servidor (bloquea hasta que se pare el servidor)
 	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -0,0 +1,278 @@
+package clients
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"products-api/internal/domain"
+	"products-api/internal/services"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tarjetas de prueba del gateway falso: el resultado depende solo del número
+// Cualquier otro número que pase el algoritmo de Luhn se aprueba
+const (
+	FakeCardApproved          = "4242424242424242"
+	FakeCardDeclined          = "4000000000000002"
+	FakeCardInsufficientFunds = "4000000000009995"
+	FakeCardTimeout           = "4000000000000119"
+	FakeCardCaptureDeclined   = "4000000000000341"
+)
+
+const fakeGatewayName = "fake"
+
+// fakeWebhookTolerance es cuánto puede tener un webhook firmado antes de rechazarlo (evita que se reenvíe uno capturado)
+const fakeWebhookTolerance = 5 * time.Minute
+
+// fakePayment es un pago guardado en memoria por el gateway falso
+type fakePayment struct {
+	payment      domain.Payment
+	captureFails bool
+}
+
+// FakePaymentGateway simula un proveedor de pagos en memoria para desarrollo local y tests
+// Los pagos se pierden al reiniciar, por eso la API solo lo acepta con PAYMENTS_ALLOW_FAKE=true
+// Es determinístico: el número de tarjeta decide si el pago se aprueba, se rechaza o no responde
+type FakePaymentGateway struct {
+	webhookSecret []byte
+	mu            sync.Mutex
+	payments      map[string]*fakePayment
+	now           func() time.Time
+}
+
+// NewFakePaymentGateway crea el gateway falso. webhookSecret firma los webhooks que genera
+func NewFakePaymentGateway(webhookSecret string) *FakePaymentGateway {
+	return &FakePaymentGateway{
+		webhookSecret: []byte(webhookSecret),
+		payments:      make(map[string]*fakePayment),
+		now:           time.Now,
+	}
+}
+
+// Name devuelve el nombre del proveedor
+func (g *FakePaymentGateway) Name() string {
+	return fakeGatewayName
+}
+
+// Authorize valida la tarjeta y reserva el monto
+func (g *FakePaymentGateway) Authorize(ctx context.Context, req domain.PaymentRequest) (domain.Payment, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.Payment{}, err
+	}
+
+	cardNumber := normalizeCardNumber(req.Method.CardNumber)
+	if !validLuhn(cardNumber) {
+		return domain.Payment{}, fmt.Errorf("%w: invalid card number", services.ErrInvalidPaymentMethod)
+	}
+	if !validExpiry(req.Method.ExpiryMonth, req.Method.ExpiryYear, time.Now()) {
+		return domain.Payment{}, fmt.Errorf("%w: card expired or invalid expiry date", services.ErrInvalidPaymentMethod)
+	}
+	if req.Amount <= 0 {
+		return domain.Payment{}, fmt.Errorf("%w: amount must be greater than zero", services.ErrInvalidPaymentMethod)
+	}
+
+	switch cardNumber {
+	case FakeCardDeclined:
+		return domain.Payment{}, fmt.Errorf("%w: card declined", services.ErrPaymentDeclined)
+	case FakeCardInsufficientFunds:
+		return domain.Payment{}, fmt.Errorf("%w: insufficient funds", services.ErrPaymentDeclined)
+	case FakeCardTimeout:
+		return domain.Payment{}, services.ErrPaymentTimeout
+	}
+
+	now := time.Now().UTC()
+	payment := domain.Payment{
+		ID:         "fake_pay_" + uuid.New().String(),
+		OrderID:    req.OrderID,
+		CustomerID: req.CustomerID,
+		Amount:     req.Amount,
+		Currency:   req.Currency,
+		Status:     domain.PaymentStatusAuthorized,
+		Provider:   fakeGatewayName,
+		CardLast4:  cardNumber[len(cardNumber)-4:],
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	g.mu.Lock()
+	g.payments[payment.ID] = &fakePayment{
+		payment:      payment,
+		captureFails: cardNumber == FakeCardCaptureDeclined,
+	}
+	g.mu.Unlock()
+
+	return payment, nil
+}
+
+// Capture cobra un pago autorizado
+func (g *FakePaymentGateway) Capture(ctx context.Context, paymentID string, amount float64) (domain.Payment, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.Payment{}, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	stored, ok := g.payments[paymentID]
+	if !ok {
+		return domain.Payment{}, services.ErrPaymentNotFound
+	}
+
+	switch stored.payment.Status {
+	case domain.PaymentStatusCaptured:
+		return stored.payment, nil
+	case domain.PaymentStatusAuthorized:
+	default:
+		return domain.Payment{}, fmt.Errorf("cannot capture a payment in status %s", stored.payment.Status)
+	}
+
+	if amount > stored.payment.Amount {
+		return domain.Payment{}, fmt.Errorf("cannot capture more than the authorized amount")
+	}
+	if stored.captureFails {
+		stored.payment.Status = domain.PaymentStatusFailed
+		stored.payment.UpdatedAt = time.Now().UTC()
+		return domain.Payment{}, fmt.Errorf("%w: capture rejected", services.ErrPaymentDeclined)
+	}
+
+	stored.payment.Status = domain.PaymentStatusCaptured
+	stored.payment.UpdatedAt = time.Now().UTC()
+	return stored.payment, nil
+}
+
+// Refund reintegra un pago cobrado o anula uno que solo estaba autorizado
+func (g *FakePaymentGateway) Refund(ctx context.Context, paymentID string, amount float64) (domain.Payment, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.Payment{}, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	stored, ok := g.payments[paymentID]
+	if !ok {
+		return domain.Payment{}, services.ErrPaymentNotFound
+	}
+
+	switch stored.payment.Status {
+	case domain.PaymentStatusCaptured:
+		stored.payment.Status = domain.PaymentStatusRefunded
+	case domain.PaymentStatusAuthorized, domain.PaymentStatusFailed:
+		stored.payment.Status = domain.PaymentStatusVoided
+	}
+	stored.payment.UpdatedAt = time.Now().UTC()
+	return stored.payment, nil
+}
+
+// ParseWebhook valida la firma y la antigüedad del webhook y lo decodifica
+// La firma tiene el formato "t=<unix>,v1=<hex>": el HMAC-SHA256 cubre el timestamp y el payload,
+// así un webhook capturado no se puede reenviar pasada la tolerancia ni con otro timestamp
+func (g *FakePaymentGateway) ParseWebhook(payload []byte, signature string) (domain.PaymentEvent, error) {
+	timestamp, err := parseWebhookTimestamp(signature)
+	if err != nil {
+		return domain.PaymentEvent{}, err
+	}
+	expected := g.SignWebhook(payload, timestamp)
+	if !hmac.Equal([]byte(expected), []byte(strings.TrimSpace(signature))) {
+		return domain.PaymentEvent{}, fmt.Errorf("invalid signature")
+	}
+	if age := g.now().Sub(timestamp); age > fakeWebhookTolerance || age < -fakeWebhookTolerance {
+		return domain.PaymentEvent{}, fmt.Errorf("webhook timestamp outside the tolerance window")
+	}
+
+	var event domain.PaymentEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return domain.PaymentEvent{}, fmt.Errorf("invalid payload: %w", err)
+	}
+	if event.ID == "" || event.PaymentID == "" || event.Type == "" {
+		return domain.PaymentEvent{}, fmt.Errorf("missing event fields")
+	}
+	return event, nil
+}
+
+// SignWebhook calcula la firma de un payload enviado en timestamp con el secreto del gateway
+func (g *FakePaymentGateway) SignWebhook(payload []byte, timestamp time.Time) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, g.webhookSecret)
+	mac.Write([]byte(unix + "."))
+	mac.Write(payload)
+	return "t=" + unix + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// parseWebhookTimestamp lee el "t=<unix>" de la firma
+func parseWebhookTimestamp(signature string) (time.Time, error) {
+	for _, part := range strings.Split(strings.TrimSpace(signature), ",") {
+		if value, ok := strings.CutPrefix(part, "t="); ok {
+			unix, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("invalid signature timestamp")
+			}
+			return time.Unix(unix, 0), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("signature has no timestamp")
+}
+
+// NewWebhook arma un webhook firmado como lo enviaría el proveedor (útil en tests y pruebas locales)
+func (g *FakePaymentGateway) NewWebhook(eventType, paymentID string, amount float64) ([]byte, string, error) {
+	payload, err := json.Marshal(domain.PaymentEvent{
+		ID:        "fake_evt_" + uuid.New().String(),
+		Type:      eventType,
+		PaymentID: paymentID,
+		Amount:    amount,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, g.SignWebhook(payload, g.now()), nil
+}
+
+func normalizeCardNumber(cardNumber string) string {
+	cardNumber = strings.ReplaceAll(cardNumber, " ", "")
+	return strings.ReplaceAll(cardNumber, "-", "")
+}
+
+// validLuhn verifica el dígito de control de un número de tarjeta
+func validLuhn(number string) bool {
+	if len(number) < 12 || len(number) > 19 {
+		return false
+	}
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		digit := int(number[i] - '0')
+		if digit < 0 || digit > 9 {
+			return false
+		}
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// validExpiry verifica que la tarjeta no esté vencida (vence al final del mes indicado)
+func validExpiry(month, year int, now time.Time) bool {
+	if month < 1 || month > 12 || year <= 0 {
+		return false
+	}
+	if year < 100 {
+		year += 2000
+	}
+	expiresAt := time.Date(year, time.Month(month)+1, 1, 0, 0, 0, 0, time.UTC)
+	return now.Before(expiresAt)
+}
@@ -0,0 +1,175 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"products-api/internal/domain"
+	"products-api/internal/services"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newFakePaymentRequest(cardNumber string) domain.PaymentRequest {
+	return domain.PaymentRequest{
+		OrderID:    "order-1",
+		CustomerID: 1,
+		Amount:     1500,
+		Currency:   "ARS",
+		Method: domain.PaymentMethod{
+			CardNumber:  cardNumber,
+			CardHolder:  "Juan Perez",
+			ExpiryMonth: 12,
+			ExpiryYear:  time.Now().Year() + 2,
+			CVV:         "123",
+		},
+	}
+}
+
+func TestFakeGateway_ApprovedCardAuthorizeCaptureRefund(t *testing.T) {
+	gateway := NewFakePaymentGateway("secret")
+	ctx := context.Background()
+
+	payment, err := gateway.Authorize(ctx, newFakePaymentRequest(FakeCardApproved))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if payment.Status != domain.PaymentStatusAuthorized {
+		t.Errorf("Expected status %s, got %s", domain.PaymentStatusAuthorized, payment.Status)
+	}
+	if payment.CardLast4 != "4242" {
+		t.Errorf("Expected last4 4242, got %s", payment.CardLast4)
+	}
+
+	captured, err := gateway.Capture(ctx, payment.ID, payment.Amount)
+	if err != nil {
+		t.Fatalf("Expected no error on capture, got %v", err)
+	}
+	if captured.Status != domain.PaymentStatusCaptured {
+		t.Errorf("Expected status %s, got %s", domain.PaymentStatusCaptured, captured.Status)
+	}
+
+	refunded, err := gateway.Refund(ctx, payment.ID, payment.Amount)
+	if err != nil {
+		t.Fatalf("Expected no error on refund, got %v", err)
+	}
+	if refunded.Status != domain.PaymentStatusRefunded {
+		t.Errorf("Expected status %s, got %s", domain.PaymentStatusRefunded, refunded.Status)
+	}
+}
+
+func TestFakeGateway_RefundBeforeCaptureVoids(t *testing.T) {
+	gateway := NewFakePaymentGateway("secret")
+	ctx := context.Background()
+
+	payment, err := gateway.Authorize(ctx, newFakePaymentRequest(FakeCardApproved))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	voided, err := gateway.Refund(ctx, payment.ID, payment.Amount)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if voided.Status != domain.PaymentStatusVoided {
+		t.Errorf("Expected status %s, got %s", domain.PaymentStatusVoided, voided.Status)
+	}
+}
+
+func TestFakeGateway_MagicCards(t *testing.T) {
+	cases := []struct {
+		name       string
+		cardNumber string
+		want       error
+	}{
+		{"declined", FakeCardDeclined, services.ErrPaymentDeclined},
+		{"insufficient funds", FakeCardInsufficientFunds, services.ErrPaymentDeclined},
+		{"timeout", FakeCardTimeout, services.ErrPaymentTimeout},
+		{"invalid luhn", "4242424242424241", services.ErrInvalidPaymentMethod},
+	}
+
+	gateway := NewFakePaymentGateway("secret")
+	for _, tc := range cases {
+		_, err := gateway.Authorize(context.Background(), newFakePaymentRequest(tc.cardNumber))
+		if !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestFakeGateway_CaptureDeclined(t *testing.T) {
+	gateway := NewFakePaymentGateway("secret")
+	ctx := context.Background()
+
+	payment, err := gateway.Authorize(ctx, newFakePaymentRequest(FakeCardCaptureDeclined))
+	if err != nil {
+		t.Fatalf("Authorization should succeed, got %v", err)
+	}
+
+	if _, err := gateway.Capture(ctx, payment.ID, payment.Amount); !errors.Is(err, services.ErrPaymentDeclined) {
+		t.Errorf("Expected ErrPaymentDeclined, got %v", err)
+	}
+}
+
+func TestFakeGateway_ExpiredCard(t *testing.T) {
+	gateway := NewFakePaymentGateway("secret")
+	req := newFakePaymentRequest(FakeCardApproved)
+	req.Method.ExpiryYear = 2020
+
+	if _, err := gateway.Authorize(context.Background(), req); !errors.Is(err, services.ErrInvalidPaymentMethod) {
+		t.Errorf("Expected ErrInvalidPaymentMethod, got %v", err)
+	}
+}
+
+func TestFakeGateway_Webhook(t *testing.T) {
+	gateway := NewFakePaymentGateway("secret")
+
+	payload, signature, err := gateway.NewWebhook(domain.PaymentEventRefunded, "fake_pay_1", 1500)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	event, err := gateway.ParseWebhook(payload, signature)
+	if err != nil {
+		t.Fatalf("Expected valid webhook, got %v", err)
+	}
+	if event.Type != domain.PaymentEventRefunded || event.PaymentID != "fake_pay_1" {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+
+	other := NewFakePaymentGateway("other-secret")
+	if _, err := other.ParseWebhook(payload, signature); err == nil {
+		t.Errorf("Expected invalid signature error")
+	}
+}
+
+// Un webhook firmado no sirve pasada la tolerancia, ni cambiándole el timestamp a la firma
+func TestFakeGateway_WebhookReplay(t *testing.T) {
+	gateway := NewFakePaymentGateway("secret")
+	sentAt := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	gateway.now = func() time.Time { return sentAt }
+
+	payload, signature, err := gateway.NewWebhook(domain.PaymentEventCaptured, "fake_pay_1", 1500)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	gateway.now = func() time.Time { return sentAt.Add(4 * time.Minute) }
+	if _, err := gateway.ParseWebhook(payload, signature); err != nil {
+		t.Errorf("Expected a webhook within the tolerance to be valid, got %v", err)
+	}
+
+	gateway.now = func() time.Time { return sentAt.Add(6 * time.Minute) }
+	if _, err := gateway.ParseWebhook(payload, signature); err == nil {
+		t.Error("Expected an old webhook to be rejected")
+	}
+
+	forged := strings.Replace(signature, fmt.Sprintf("t=%d", sentAt.Unix()), fmt.Sprintf("t=%d", sentAt.Add(6*time.Minute).Unix()), 1)
+	if _, err := gateway.ParseWebhook(payload, forged); err == nil {
+		t.Error("Expected a webhook with a changed timestamp to be rejected")
+	}
+	if _, err := gateway.ParseWebhook(payload, strings.TrimPrefix(signature, fmt.Sprintf("t=%d,", sentAt.Unix()))); err == nil {
+		t.Error("Expected a signature without timestamp to be rejected")
+	}
+}
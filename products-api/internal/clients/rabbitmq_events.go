@@ -0,0 +1,69 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"products-api/internal/domain"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// RabbitMQEventsPublisher publica eventos de dominio en un exchange topic
+// Cada consumidor (notificaciones, analítica, contabilidad) declara su propia cola y la bindea con las routing keys que le interesan
+type RabbitMQEventsPublisher struct {
+	connection *amqp091.Connection
+	channel    *amqp091.Channel
+	exchange   string
+}
+
+// NewRabbitMQEventsPublisher se conecta a RabbitMQ y declara el exchange topic (durable)
+func NewRabbitMQEventsPublisher(user, password, host, port, exchange string) *RabbitMQEventsPublisher {
+	connStr := fmt.Sprintf("amqp://%s:%s@%s:%s/", user, password, host, port)
+	connection, err := amqp091.Dial(connStr)
+	if err != nil {
+		log.Fatalf("failed to connect to RabbitMQ: %v", err)
+	}
+	channel, err := connection.Channel()
+	if err != nil {
+		log.Fatalf("failed to open a channel: %v", err)
+	}
+	if err := channel.ExchangeDeclare(exchange, amqp091.ExchangeTopic, true, false, false, false, nil); err != nil {
+		log.Fatalf("failed to declare exchange %s: %v", exchange, err)
+	}
+	return &RabbitMQEventsPublisher{connection: connection, channel: channel, exchange: exchange}
+}
+
+// PublishOrderEvent publica el evento con su tipo como routing key (order.created, order.paid...)
+func (r *RabbitMQEventsPublisher) PublishOrderEvent(ctx context.Context, event domain.OrderEvent) error {
+	return r.publish(ctx, event.ID, event.Type, event.OccurredAt, event)
+}
+
+// PublishCartEvent publica el evento con su tipo como routing key (cart.abandoned)
+func (r *RabbitMQEventsPublisher) PublishCartEvent(ctx context.Context, event domain.CartEvent) error {
+	return r.publish(ctx, event.ID, event.Type, event.OccurredAt, event)
+}
+
+// publish serializa el evento y lo publica como mensaje persistente
+func (r *RabbitMQEventsPublisher) publish(ctx context.Context, id, eventType string, occurredAt time.Time, event any) error {
+	bytes, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error marshalling event to JSON: %w", err)
+	}
+
+	if err := r.channel.PublishWithContext(ctx, r.exchange, eventType, false, false, amqp091.Publishing{
+		ContentType:     encodingJSON,
+		ContentEncoding: encodingUTF8,
+		DeliveryMode:    amqp091.Persistent,
+		MessageId:       id,
+		Type:            eventType,
+		Timestamp:       occurredAt,
+		AppId:           "products-api",
+		Body:            bytes,
+	}); err != nil {
+		return fmt.Errorf("error publishing event to RabbitMQ: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"products-api/internal/domain"
+	"products-api/internal/services"
+	"time"
+)
+
+// UsersAPIClient consulta datos de los usuarios en users-api
+type UsersAPIClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewUsersAPIClient crea el cliente HTTP de users-api
+func NewUsersAPIClient(baseURL string) *UsersAPIClient {
+	return &UsersAPIClient{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// userAddress es la dirección tal como la devuelve users-api
+type userAddress struct {
+	ID            int    `json:"id"`
+	UserID        int    `json:"user_id"`
+	RecipientName string `json:"recipient_name"`
+	Street        string `json:"street"`
+	Number        string `json:"number"`
+	Apartment     string `json:"apartment"`
+	City          string `json:"city"`
+	Province      string `json:"province"`
+	PostalCode    string `json:"postal_code"`
+	Phone         string `json:"phone"`
+}
+
+// GetAddress obtiene una dirección guardada del usuario
+// Las direcciones son datos personales: se piden con el token de quien hace la compra (users-api verifica que sea suyo)
+func (c *UsersAPIClient) GetAddress(ctx context.Context, customerID, addressID int) (domain.ShippingAddress, error) {
+	token := domain.BearerTokenFrom(ctx)
+	if token == "" {
+		return domain.ShippingAddress{}, fmt.Errorf("no caller token to read the address book of customer %d", customerID)
+	}
+
+	url := fmt.Sprintf("%s/users/%d/addresses/%d", c.baseURL, customerID, addressID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return domain.ShippingAddress{}, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return domain.ShippingAddress{}, fmt.Errorf("error calling users-api: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Una dirección de otro usuario se trata igual que una que no existe
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusForbidden {
+		return domain.ShippingAddress{}, fmt.Errorf("%w: %d", services.ErrAddressNotFound, addressID)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return domain.ShippingAddress{}, fmt.Errorf("users-api responded with status %d", resp.StatusCode)
+	}
+
+	var address userAddress
+	if err := json.NewDecoder(resp.Body).Decode(&address); err != nil {
+		return domain.ShippingAddress{}, fmt.Errorf("error decoding address: %w", err)
+	}
+
+	return domain.ShippingAddress{
+		RecipientName: address.RecipientName,
+		Street:        address.Street,
+		Number:        address.Number,
+		Apartment:     address.Apartment,
+		City:          address.City,
+		Province:      address.Province,
+		PostalCode:    address.PostalCode,
+		Phone:         address.Phone,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"products-api/internal/domain"
+	"products-api/internal/services"
+	"testing"
+)
+
+// ctxWithToken simula el context que deja VerifyToken (el gin context resuelve las claves string así)
+type ctxWithToken struct {
+	context.Context
+	token string
+}
+
+func (c ctxWithToken) Value(key any) any {
+	if key == domain.ContextBearerToken {
+		return c.token
+	}
+	return c.Context.Value(key)
+}
+
+// TestUsersAPIClient_GetAddressForwardsToken verifica que la dirección se pide con el token de quien compra
+func TestUsersAPIClient_GetAddressForwardsToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer customer-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/users/7/addresses/3" {
+			w.Write([]byte(`{"id":3,"user_id":7,"recipient_name":"Ana Paz","street":"Colón","number":"123","city":"Córdoba","province":"Córdoba","postal_code":"X5000"}`))
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	client := NewUsersAPIClient(server.URL)
+	ctx := ctxWithToken{Context: context.Background(), token: "customer-token"}
+
+	address, err := client.GetAddress(ctx, 7, 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if address.RecipientName != "Ana Paz" || address.PostalCode != "X5000" {
+		t.Errorf("Unexpected address %+v", address)
+	}
+
+	// La dirección de otro usuario (403 en users-api) no se distingue de una inexistente
+	if _, err := client.GetAddress(ctx, 8, 3); !errors.Is(err, services.ErrAddressNotFound) {
+		t.Errorf("Expected ErrAddressNotFound, got %v", err)
+	}
+
+	// Sin token no se llama a users-api
+	if _, err := client.GetAddress(context.Background(), 7, 3); err == nil {
+		t.Error("Expected an error without a caller token")
+	}
+}
@@ -14,6 +14,12 @@ type Config struct {
 	Memcached MemcachedConfig
 	RabbitMQ  RabbitMQConfig
 	Solr      SolrConfig
+	Payments  PaymentsConfig
+	Shipping  ShippingConfig
+	Tax       TaxConfig
+	Cart      CartConfig
+	Auth      AuthConfig
+	UsersAPI  string
 }
 
 type MongoConfig struct {
@@ -33,6 +39,10 @@ type RabbitMQConfig struct {
 	QueueName string
 	Host      string
 	Port      string
+	// OrdersExchange es el exchange topic donde se publican los eventos de las ordenes
+	OrdersExchange string
+	// CartsExchange es el exchange topic de los eventos de carritos (cart.abandoned)
+	CartsExchange string
 }
 
 type SolrConfig struct {
@@ -41,6 +51,49 @@ type SolrConfig struct {
 	Core string
 }
 
+type PaymentsConfig struct {
+	// Provider es el proveedor de pagos; no tiene valor por defecto para no arrancar cobrando con el gateway falso
+	Provider      string
+	WebhookSecret string
+	// AllowFake habilita el gateway falso (guarda los pagos en memoria): solo para desarrollo local
+	AllowFake bool
+	// InstallmentPlans son los planes de cuotas que se ofrecen, "cuotas:recargo%" separados por coma
+	// Por defecto 1, 3 y 6 cuotas sin interés y 12 cuotas con 25% de recargo
+	InstallmentPlans string
+}
+
+type ShippingConfig struct {
+	// RatesFile es un JSON con zonas y tarifas; si está vacío se usa la tabla por defecto
+	RatesFile string
+}
+
+type TaxConfig struct {
+	// PricesIncludeTax indica si los precios del catálogo ya incluyen el IVA (precio final)
+	PricesIncludeTax bool
+}
+
+type CartConfig struct {
+	// GuestTTLHours es cuánto vive un carrito de invitado desde su última modificación
+	GuestTTLHours int
+	// Carritos abandonados: horas sin modificaciones, horas entre recordatorios y máximo de recordatorios
+	AbandonedAfterHours   int
+	ReminderIntervalHours int
+	MaxReminders          int
+	// PurgeEmptyAfterDays elimina los carritos vacíos que no se modifican hace esa cantidad de días
+	PurgeEmptyAfterDays int
+	// AbandonedCheckMinutes es cada cuánto corre el job de carritos abandonados
+	AbandonedCheckMinutes int
+}
+
+type AuthConfig struct {
+	// JWKSURL es de donde se traen las claves públicas de users-api para verificar los tokens localmente
+	JWKSURL string
+	// JWKSCacheMinutes es cada cuánto se vuelven a pedir las claves (antes, si llega un kid desconocido)
+	JWKSCacheMinutes int
+	// IntrospectionFallback verifica el token llamando a users-api si no se pudieron traer las claves
+	IntrospectionFallback bool
+}
+
 func Load() Config {
 	// Load .env file
 	if err := godotenv.Load(); err != nil {
@@ -51,6 +104,22 @@ func Load() Config {
 	if err != nil {
 		memcachedTTL = 60
 	}
+	pricesIncludeTax, err := strconv.ParseBool(getEnv("TAX_PRICES_INCLUDE_TAX", "true"))
+	if err != nil {
+		pricesIncludeTax = true
+	}
+	allowFakePayments, err := strconv.ParseBool(getEnv("PAYMENTS_ALLOW_FAKE", "false"))
+	if err != nil {
+		allowFakePayments = false
+	}
+	introspectionFallback, err := strconv.ParseBool(getEnv("AUTH_INTROSPECTION_FALLBACK", "false"))
+	if err != nil {
+		introspectionFallback = false
+	}
+	guestCartTTL, err := strconv.Atoi(getEnv("GUEST_CART_TTL_HOURS", "72"))
+	if err != nil || guestCartTTL < 1 {
+		guestCartTTL = 72
+	}
 	return Config{
 		Port: getEnv("PORT", "8080"),
 		Mongo: MongoConfig{
@@ -68,12 +137,42 @@ func Load() Config {
 			QueueName: getEnv("RABBITMQ_QUEUE_NAME", "items-news"),
 			Host:      getEnv("RABBITMQ_HOST", "localhost"),
 			Port:      getEnv("RABBITMQ_PORT", "5672"),
+
+			OrdersExchange: getEnv("RABBITMQ_ORDERS_EXCHANGE", "orders"),
+			CartsExchange:  getEnv("RABBITMQ_CARTS_EXCHANGE", "carts"),
 		},
 		Solr: SolrConfig{
 			Host: getEnv("SOLR_HOST", "localhost"),
 			Port: getEnv("SOLR_PORT", "8983"),
 			Core: getEnv("SOLR_CORE", "demo"),
 		},
+		Payments: PaymentsConfig{
+			Provider:      getEnv("PAYMENTS_PROVIDER", ""),
+			WebhookSecret: getEnv("PAYMENTS_WEBHOOK_SECRET", ""),
+			AllowFake:     allowFakePayments,
+
+			InstallmentPlans: getEnv("PAYMENTS_INSTALLMENT_PLANS", "1:0,3:0,6:0,12:25"),
+		},
+		Shipping: ShippingConfig{
+			RatesFile: getEnv("SHIPPING_RATES_FILE", ""),
+		},
+		Tax: TaxConfig{
+			PricesIncludeTax: pricesIncludeTax,
+		},
+		Cart: CartConfig{
+			GuestTTLHours:         guestCartTTL,
+			AbandonedAfterHours:   getEnvInt("CART_ABANDONED_AFTER_HOURS", 24),
+			ReminderIntervalHours: getEnvInt("CART_REMINDER_INTERVAL_HOURS", 48),
+			MaxReminders:          getEnvInt("CART_MAX_REMINDERS", 2),
+			PurgeEmptyAfterDays:   getEnvInt("CART_PURGE_EMPTY_AFTER_DAYS", 30),
+			AbandonedCheckMinutes: getEnvInt("CART_ABANDONED_CHECK_MINUTES", 15),
+		},
+		Auth: AuthConfig{
+			JWKSURL:               getEnv("AUTH_JWKS_URL", getEnv("USERS_API_URL", "http://users-api:8082")+"/.well-known/jwks.json"),
+			JWKSCacheMinutes:      getEnvInt("AUTH_JWKS_CACHE_MINUTES", 10),
+			IntrospectionFallback: introspectionFallback,
+		},
+		UsersAPI: getEnv("USERS_API_URL", "http://users-api:8082"),
 	}
 }
 
@@ -83,3 +182,12 @@ func getEnv(k, def string) string {
 	}
 	return def
 }
+
+// getEnvInt lee un entero positivo; si falta o es inválido usa el valor por defecto
+func getEnvInt(k string, def int) int {
+	v, err := strconv.Atoi(getEnv(k, strconv.Itoa(def)))
+	if err != nil || v < 1 {
+		return def
+	}
+	return v
+}
@@ -2,26 +2,49 @@ package controllers
 
 import (
 	"context"
+	"log"
 	"net/http"
+	"products-api/internal/domain"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AuthService interface {
-	VerifyToken(ctx context.Context, token string) error
-	VerifyAdminToken(ctx context.Context, token string) error
+	VerifyToken(ctx context.Context, token string) (domain.AuthClaims, error)
+	VerifyAdminToken(ctx context.Context, token string) (domain.AuthClaims, error)
 }
 
+// AuditLog guarda los accesos denegados en el registro de auditoría
+type AuditLog interface {
+	RecordAccessDenied(ctx context.Context, event domain.AuditEvent) error
+}
+
+// Claves del gin context donde VerifyToken y VerifyAdminToken dejan los claims verificados
+const (
+	ContextUserID        = "user_id"
+	ContextIsAdmin       = "is_admin"
+	ContextRole          = "role"
+	ContextPermissions   = "permissions"
+	ContextEmailVerified = "email_verified"
+
+	// contextAuditLog es donde VerifyToken deja el registro de auditoría para AuthorizeCustomer y los demás chequeos
+	contextAuditLog = "audit_log"
+)
+
 // AuthController maneja la autenticación sin depender de otros servicios
 type AuthController struct {
 	service AuthService
+	audit   AuditLog
 }
 
 // NewAuthController crea una nueva instancia del controller de autenticación
-func NewAuthController(authService AuthService) *AuthController {
+// audit guarda los accesos denegados (ver AuthorizeCustomer y RequirePermission)
+func NewAuthController(authService AuthService, audit AuditLog) *AuthController {
 	return &AuthController{
 		service: authService,
+		audit:   audit,
 	}
 }
 
@@ -40,7 +63,8 @@ func (c *AuthController) VerifyToken(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.service.VerifyToken(ctx.Request.Context(), tokenString); err != nil {
+	claims, err := c.service.VerifyToken(ctx.Request.Context(), tokenString)
+	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, gin.H{
 			"error":   "Invalid token",
 			"details": err.Error(),
@@ -49,6 +73,9 @@ func (c *AuthController) VerifyToken(ctx *gin.Context) {
 		return
 	}
 
+	setClaims(ctx, claims)
+	ctx.Set(domain.ContextBearerToken, tokenString)
+	ctx.Set(contextAuditLog, c.audit)
 	ctx.Next()
 }
 
@@ -70,12 +97,157 @@ func (c *AuthController) VerifyAdminToken(ctx *gin.Context) {
 		return
 	}
 	// Llamar al servicio de verify admin token
-	err := c.service.VerifyAdminToken(ctx.Request.Context(), tokenString)
+	claims, err := c.service.VerifyAdminToken(ctx.Request.Context(), tokenString)
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid admin token or insufficient permissions"})
 		ctx.Abort()
 		return
 	}
 	// Token válido, continuar con la siguiente función
+	setClaims(ctx, claims)
+	ctx.Set(domain.ContextBearerToken, tokenString)
+	ctx.Set(contextAuditLog, c.audit)
 	ctx.Next()
 }
+
+// RequirePermission solo deja pasar a quien tenga el permiso en su token (los super admin pasan siempre)
+// Va después de VerifyToken, que es quien deja los claims en el context
+func (c *AuthController) RequirePermission(permission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		claims, ok := GetClaims(ctx)
+		if !ok {
+			abortUnauthenticated(ctx)
+			return
+		}
+		if claims.HasPermission(permission) {
+			ctx.Next()
+			return
+		}
+
+		recordAccessDenied(ctx, claims, 0, permission)
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions", "required_permission": permission})
+		ctx.Abort()
+	}
+}
+
+// RequireVerifiedEmail solo deja pasar a quien ya confirmó su email (comprar, por ejemplo)
+// Va después de VerifyToken; el frontend usa el code para ofrecer reenviar el link
+func (c *AuthController) RequireVerifiedEmail(ctx *gin.Context) {
+	claims, ok := GetClaims(ctx)
+	if !ok {
+		abortUnauthenticated(ctx)
+		return
+	}
+	if claims.EmailVerified {
+		ctx.Next()
+		return
+	}
+	ctx.JSON(http.StatusForbidden, gin.H{"error": "You need to verify your email first", "code": "email_not_verified"})
+	ctx.Abort()
+}
+
+// RequireOwnerOrAdmin solo deja pasar si el cliente del path param es quien hace la request o si es admin
+// Va después de VerifyToken, que es quien deja los claims en el context
+func (c *AuthController) RequireOwnerOrAdmin(param string) gin.HandlerFunc {
+	return c.RequireOwnerOrPermission(param, "")
+}
+
+// RequireOwnerOrPermission es como RequireOwnerOrAdmin pero también deja pasar a quien tenga el permiso
+// (por ejemplo un operador de órdenes viendo las ventas de un cliente)
+func (c *AuthController) RequireOwnerOrPermission(param, permission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		customerID, err := strconv.Atoi(ctx.Param(param))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer ID"})
+			ctx.Abort()
+			return
+		}
+		if !AuthorizeCustomerOrPermission(ctx, customerID, permission) {
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// AuthorizeCustomer verifica que quien hace la request pueda operar sobre los datos del cliente
+// Si no puede, deja registrado el intento y responde 403
+func AuthorizeCustomer(ctx *gin.Context, customerID int) bool {
+	return AuthorizeCustomerOrPermission(ctx, customerID, "")
+}
+
+// AuthorizeCustomerOrPermission es como AuthorizeCustomer pero también acepta a quien tenga el permiso
+// Con permission vacío solo pasan el cliente y los admins
+func AuthorizeCustomerOrPermission(ctx *gin.Context, customerID int, permission string) bool {
+	claims, ok := GetClaims(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return false
+	}
+	if claims.CanAccessCustomer(customerID) || (permission != "" && claims.HasPermission(permission)) {
+		return true
+	}
+
+	recordAccessDenied(ctx, claims, customerID, permission)
+	ctx.JSON(http.StatusForbidden, gin.H{"error": "You can only access your own resources"})
+	return false
+}
+
+// recordAccessDenied guarda el intento en el registro de auditoría que dejó VerifyToken
+// Si no se pudo guardar igual queda en el log del proceso: la respuesta no cambia
+func recordAccessDenied(ctx *gin.Context, claims domain.AuthClaims, customerID int, permission string) {
+	event := domain.AuditEvent{
+		UserID:     claims.UserID,
+		IsAdmin:    claims.IsAdmin,
+		Role:       claims.Role,
+		Method:     ctx.Request.Method,
+		Path:       ctx.Request.URL.Path,
+		CustomerID: customerID,
+		Permission: permission,
+		ClientIP:   ctx.ClientIP(),
+	}
+
+	value, _ := ctx.Get(contextAuditLog)
+	audit, ok := value.(AuditLog)
+	if !ok || audit == nil {
+		log.Printf("🚨 AUDIT access denied (no audit log): %+v", event)
+		return
+	}
+	if err := audit.RecordAccessDenied(context.Background(), event); err != nil {
+		log.Printf("⚠️ %v", err)
+	}
+}
+
+// abortUnauthenticated responde 401 cuando el context no tiene claims válidos (falta VerifyToken antes)
+func abortUnauthenticated(ctx *gin.Context) {
+	ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+	ctx.Abort()
+}
+
+// GetClaims devuelve los claims verificados del token que dejó VerifyToken en el context
+// Devuelve false si no hay claims o si no tienen el formato esperado
+func GetClaims(ctx *gin.Context) (domain.AuthClaims, bool) {
+	value, ok := ctx.Get(ContextUserID)
+	if !ok {
+		return domain.AuthClaims{}, false
+	}
+	userID, ok := value.(int)
+	if !ok {
+		return domain.AuthClaims{}, false
+	}
+	return domain.AuthClaims{
+		UserID:        userID,
+		IsAdmin:       ctx.GetBool(ContextIsAdmin),
+		Role:          ctx.GetString(ContextRole),
+		Permissions:   ctx.GetStringSlice(ContextPermissions),
+		EmailVerified: ctx.GetBool(ContextEmailVerified),
+	}, true
+}
+
+func setClaims(ctx *gin.Context, claims domain.AuthClaims) {
+	ctx.Set(ContextUserID, claims.UserID)
+	ctx.Set(ContextIsAdmin, claims.IsAdmin)
+	ctx.Set(ContextRole, claims.Role)
+	ctx.Set(ContextPermissions, claims.Permissions)
+	ctx.Set(ContextEmailVerified, claims.EmailVerified)
+}
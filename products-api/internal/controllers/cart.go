@@ -2,9 +2,12 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"io"
 	"log"
 	"net/http"
 	"products-api/internal/domain"
+	"products-api/internal/services"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -18,7 +21,19 @@ type CartService interface {
 	UpdateItemCart(ctx context.Context, customerID int, itemID string, req domain.UpdateItemRequest) (domain.CartResponse, error)
 	RemoveItem(ctx context.Context, customerID int, itemID string) (domain.CartResponse, error)
 	ClearCart(ctx context.Context, customerID int) error
-	Checkout(ctx context.Context, customerID int) ([]domain.Sales, error)
+	Checkout(ctx context.Context, customerID int, req domain.CheckoutRequest) (domain.CheckoutResult, error)
+	Quote(ctx context.Context, customerID int, req domain.CartQuoteRequest) (domain.CartQuote, error)
+	ApplyCoupon(ctx context.Context, customerID int, req domain.ApplyCouponRequest) (domain.CartResponse, error)
+	RemoveCoupon(ctx context.Context, customerID int) (domain.CartResponse, error)
+	MergeGuestCart(ctx context.Context, customerID int, token string) (domain.CartResponse, error)
+	AcknowledgeChanges(ctx context.Context, customerID int) (domain.CartResponse, error)
+
+	CreateGuestCart(ctx context.Context) (domain.CartResponse, error)
+	GetGuestCart(ctx context.Context, token string) (domain.CartResponse, error)
+	AddGuestItem(ctx context.Context, token string, req domain.AddItemRequest) (domain.CartResponse, error)
+	UpdateGuestItem(ctx context.Context, token, itemID string, req domain.UpdateItemRequest) (domain.CartResponse, error)
+	RemoveGuestItem(ctx context.Context, token, itemID string) (domain.CartResponse, error)
+	DeleteGuestCart(ctx context.Context, token string) error
 }
 
 // CartController maneja las peticiones HTTP relacionadas con carritos
@@ -226,7 +241,16 @@ func (c *CartController) Checkout(ctx *gin.Context) {
 		return
 	}
 
-	sales, err := c.service.Checkout(ctx, customerID)
+	// Sin body el service responde que falta el medio de pago
+	var req domain.CheckoutRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	result, err := c.service.Checkout(ctx, customerID, req)
 	if err != nil {
 		log.Printf("❌ Error processing checkout: %v", err)
 
@@ -237,6 +261,27 @@ func (c *CartController) Checkout(ctx *gin.Context) {
 			return
 		}
 
+		if respondShippingError(ctx, err) || respondCouponError(ctx, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, services.ErrCartChanged):
+			// El frontend vuelve a pedir el carrito para mostrar qué cambió
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": "cart_changed"})
+			return
+		case errors.Is(err, services.ErrPaymentMethodRequired), errors.Is(err, services.ErrInvalidPaymentMethod):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, services.ErrPaymentDeclined):
+			ctx.JSON(http.StatusPaymentRequired, gin.H{"error": "Payment declined"})
+			return
+		case errors.Is(err, services.ErrPaymentTimeout):
+			// 504 libera la Idempotency-Key: el cliente puede reintentar
+			ctx.JSON(http.StatusGatewayTimeout, gin.H{"error": "Payment provider did not respond, please retry"})
+			return
+		}
+
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
 		})
@@ -245,6 +290,179 @@ func (c *CartController) Checkout(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{
 		"message": "Checkout completed successfully",
-		"sales":   sales,
+		"order":   result.Order,
+		"sales":   result.Sales,
 	})
 }
+
+// AcknowledgeChanges acepta los cambios de precio y stock del carrito
+// POST /cart/:customerID/acknowledge
+func (c *CartController) AcknowledgeChanges(ctx *gin.Context) {
+	customerIDStr := ctx.Param("customerID")
+	customerID, err := strconv.Atoi(customerIDStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid customer_id format",
+		})
+		return
+	}
+
+	cart, err := c.service.AcknowledgeChanges(ctx.Request.Context(), customerID)
+	if err != nil {
+		log.Printf("❌ Error acknowledging cart changes: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error acknowledging cart changes",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+// ApplyCoupon aplica un código de descuento al carrito
+// POST /cart/:customerID/coupon
+func (c *CartController) ApplyCoupon(ctx *gin.Context) {
+	customerIDStr := ctx.Param("customerID")
+	customerID, err := strconv.Atoi(customerIDStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid customer_id format",
+		})
+		return
+	}
+
+	var req domain.ApplyCouponRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	cart, err := c.service.ApplyCoupon(ctx.Request.Context(), customerID, req)
+	if err != nil {
+		log.Printf("❌ Error applying coupon: %v", err)
+
+		if err.Error() == "cart is empty" {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "Cannot apply a coupon to an empty cart",
+			})
+			return
+		}
+		if respondCouponError(ctx, err) {
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error applying coupon",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+// RemoveCoupon quita el código de descuento del carrito
+// DELETE /cart/:customerID/coupon
+func (c *CartController) RemoveCoupon(ctx *gin.Context) {
+	customerIDStr := ctx.Param("customerID")
+	customerID, err := strconv.Atoi(customerIDStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid customer_id format",
+		})
+		return
+	}
+
+	cart, err := c.service.RemoveCoupon(ctx.Request.Context(), customerID)
+	if err != nil {
+		log.Printf("❌ Error removing coupon: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error removing coupon",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+// Quote cotiza el carrito con el costo de envío a un destino
+// POST /cart/:customerID/quote
+func (c *CartController) Quote(ctx *gin.Context) {
+	customerIDStr := ctx.Param("customerID")
+	customerID, err := strconv.Atoi(customerIDStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid customer_id format",
+		})
+		return
+	}
+
+	var req domain.CartQuoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	quote, err := c.service.Quote(ctx, customerID, req)
+	if err != nil {
+		log.Printf("❌ Error quoting cart: %v", err)
+
+		if err.Error() == "cart is empty" {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "Cannot quote an empty cart",
+			})
+			return
+		}
+		if respondShippingError(ctx, err) {
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error quoting cart",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, quote)
+}
+
+// respondShippingError responde los errores de envío; devuelve false si err no es de envío
+func respondShippingError(ctx *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrInvalidShippingMethod),
+		errors.Is(err, services.ErrShippingAddressRequired),
+		errors.Is(err, services.ErrInvalidShippingAddress):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAddressNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrShippingNotAvailable):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
+
+// respondCouponError responde los errores de cupones; devuelve false si err no es de cupones
+func respondCouponError(ctx *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrCouponNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCouponUsageLimit),
+		errors.Is(err, services.ErrCouponCustomerLimit):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCouponInactive),
+		errors.Is(err, services.ErrCouponNotStarted),
+		errors.Is(err, services.ErrCouponExpired),
+		errors.Is(err, services.ErrCouponMinTotal),
+		errors.Is(err, services.ErrCouponNotApplicable):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
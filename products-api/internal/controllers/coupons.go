@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"products-api/internal/domain"
+	"products-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CouponsService define las operaciones de administración de cupones
+type CouponsService interface {
+	List(ctx context.Context) ([]domain.Coupon, error)
+	GetByID(ctx context.Context, id string) (domain.Coupon, error)
+	Create(ctx context.Context, coupon domain.Coupon) (domain.Coupon, error)
+	Update(ctx context.Context, id string, coupon domain.Coupon) (domain.Coupon, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// CouponsController maneja el ABM de cupones (solo admin)
+type CouponsController struct {
+	service CouponsService
+}
+
+// NewCouponsController crea una nueva instancia del controller
+func NewCouponsController(service CouponsService) *CouponsController {
+	return &CouponsController{
+		service: service,
+	}
+}
+
+// List obtiene todos los cupones
+// GET /coupons
+func (c *CouponsController) List(ctx *gin.Context) {
+	coupons, err := c.service.List(ctx.Request.Context())
+	if err != nil {
+		log.Printf("❌ Error listing coupons: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error listing coupons"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"coupons": coupons})
+}
+
+// GetByID obtiene un cupón por ID
+// GET /coupons/:id
+func (c *CouponsController) GetByID(ctx *gin.Context) {
+	coupon, err := c.service.GetByID(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		respondCouponAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"coupon": coupon})
+}
+
+// Create crea un cupón
+// POST /coupons
+func (c *CouponsController) Create(ctx *gin.Context) {
+	var coupon domain.Coupon
+	if err := ctx.ShouldBindJSON(&coupon); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	created, err := c.service.Create(ctx.Request.Context(), coupon)
+	if err != nil {
+		respondCouponAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"coupon": created})
+}
+
+// Update actualiza un cupón
+// PUT /coupons/:id
+func (c *CouponsController) Update(ctx *gin.Context) {
+	var coupon domain.Coupon
+	if err := ctx.ShouldBindJSON(&coupon); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	updated, err := c.service.Update(ctx.Request.Context(), ctx.Param("id"), coupon)
+	if err != nil {
+		respondCouponAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"coupon": updated})
+}
+
+// Delete elimina un cupón
+// DELETE /coupons/:id
+func (c *CouponsController) Delete(ctx *gin.Context) {
+	if err := c.service.Delete(ctx.Request.Context(), ctx.Param("id")); err != nil {
+		respondCouponAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "coupon deleted successfully"})
+}
+
+func respondCouponAdminError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidCoupon):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCouponNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCouponCodeTaken):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("❌ Error managing coupon: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error managing coupon"})
+	}
+}
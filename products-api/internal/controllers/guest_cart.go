@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"products-api/internal/domain"
+	"products-api/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GuestCartHeader es el header donde el frontend manda el token de sesión del carrito de invitado
+// Va en un header y no en la URL para que no quede en los logs de acceso
+const GuestCartHeader = "X-Cart-Session"
+
+// CreateGuestCart abre un carrito de invitado
+// POST /guest-cart
+func (c *CartController) CreateGuestCart(ctx *gin.Context) {
+	cart, err := c.service.CreateGuestCart(ctx.Request.Context())
+	if err != nil {
+		log.Printf("❌ Error creating guest cart: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating guest cart"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, cart)
+}
+
+// GetGuestCart obtiene el carrito de invitado
+// GET /guest-cart
+func (c *CartController) GetGuestCart(ctx *gin.Context) {
+	cart, err := c.service.GetGuestCart(ctx.Request.Context(), ctx.GetHeader(GuestCartHeader))
+	if err != nil {
+		respondGuestCartError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+// AddGuestItem agrega un item al carrito de invitado
+// POST /guest-cart/items
+func (c *CartController) AddGuestItem(ctx *gin.Context) {
+	var req domain.AddItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cart, err := c.service.AddGuestItem(ctx.Request.Context(), ctx.GetHeader(GuestCartHeader), req)
+	if err != nil {
+		respondGuestCartError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+// UpdateGuestItem actualiza la cantidad de un item del carrito de invitado
+// PUT /guest-cart/items/:itemID
+func (c *CartController) UpdateGuestItem(ctx *gin.Context) {
+	var req domain.UpdateItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	cart, err := c.service.UpdateGuestItem(ctx.Request.Context(), ctx.GetHeader(GuestCartHeader), ctx.Param("itemID"), req)
+	if err != nil {
+		respondGuestCartError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+// RemoveGuestItem elimina un item del carrito de invitado
+// DELETE /guest-cart/items/:itemID
+func (c *CartController) RemoveGuestItem(ctx *gin.Context) {
+	cart, err := c.service.RemoveGuestItem(ctx.Request.Context(), ctx.GetHeader(GuestCartHeader), ctx.Param("itemID"))
+	if err != nil {
+		respondGuestCartError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+// DeleteGuestCart descarta el carrito de invitado
+// DELETE /guest-cart
+func (c *CartController) DeleteGuestCart(ctx *gin.Context) {
+	if err := c.service.DeleteGuestCart(ctx.Request.Context(), ctx.GetHeader(GuestCartHeader)); err != nil {
+		respondGuestCartError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Guest cart deleted successfully"})
+}
+
+// MergeGuestCart pasa el carrito de invitado al carrito del cliente (lo llama users-api en el login)
+// POST /cart/:customerID/merge
+func (c *CartController) MergeGuestCart(ctx *gin.Context) {
+	customerID, err := strconv.Atoi(ctx.Param("customerID"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid customer_id format",
+		})
+		return
+	}
+
+	var req domain.MergeCartRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	cart, err := c.service.MergeGuestCart(ctx.Request.Context(), customerID, req.GuestToken)
+	if err != nil {
+		respondGuestCartError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+// respondGuestCartError responde los errores de los carritos de invitado
+func respondGuestCartError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrGuestCartNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Guest cart not found or expired"})
+	case err.Error() == "item not found in cart":
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Item not found in cart"})
+	default:
+		log.Printf("❌ Error in guest cart: %v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"products-api/internal/domain"
+	"products-api/internal/services"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+)
+
+// IdempotencyService define las operaciones para procesar un request una sola vez
+type IdempotencyService interface {
+	Begin(ctx context.Context, key, fingerprint string) (domain.IdempotencyRecord, bool, error)
+	KeepAlive(key string) (stop func())
+	Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error
+	Release(ctx context.Context, key string) error
+}
+
+// IdempotencyController implementa el header Idempotency-Key como middleware de gin
+// Se usa en las rutas que crean ventas o cobran (POST /sales y el checkout)
+type IdempotencyController struct {
+	service IdempotencyService
+}
+
+// NewIdempotencyController crea una nueva instancia del controller
+func NewIdempotencyController(service IdempotencyService) *IdempotencyController {
+	return &IdempotencyController{
+		service: service,
+	}
+}
+
+// responseRecorder copia la respuesta del handler para poder guardarla
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Handle procesa el request una sola vez por Idempotency-Key
+// Va después de VerifyToken: la clave es de quien hace la request, así otro usuario con la misma clave no recibe su respuesta
+// Sin header el request pasa normalmente. Con header:
+// - la primera vez se procesa y se guarda la respuesta
+// - los reintentos con el mismo body reciben la respuesta guardada
+// - un reintento con otro body recibe 422 y uno concurrente que no termina a tiempo recibe 409
+// - si el request original se cayó sin terminar (venció su lease), el reintento se procesa de nuevo
+func (c *IdempotencyController) Handle(ctx *gin.Context) {
+	key := strings.TrimSpace(ctx.GetHeader(idempotencyKeyHeader))
+	if key == "" {
+		ctx.Next()
+		return
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key is too long"})
+		ctx.Abort()
+		return
+	}
+
+	claims, ok := GetClaims(ctx)
+	if !ok {
+		abortUnauthenticated(ctx)
+		return
+	}
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		ctx.Abort()
+		return
+	}
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	// La clave se guarda por ruta (incluye el cliente del carrito en el checkout) y por usuario
+	caller := "user:" + strconv.Itoa(claims.UserID)
+	scopedKey := ctx.Request.Method + " " + ctx.Request.URL.Path + " " + caller + " " + key
+	fingerprint := requestFingerprint(ctx.Request.Method, ctx.Request.URL.Path, caller, body)
+
+	record, replay, err := c.service.Begin(ctx.Request.Context(), scopedKey, fingerprint)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrIdempotencyKeyReused):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrIdempotencyInProgress):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			log.Printf("❌ Error checking idempotency key: %v", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		ctx.Abort()
+		return
+	}
+
+	if replay {
+		log.Printf("🔁 Replaying response for idempotency key: %s", scopedKey)
+		ctx.Header(idempotencyReplayedHeader, "true")
+		ctx.Data(record.StatusCode, record.ContentType, record.ResponseBody)
+		ctx.Abort()
+		return
+	}
+
+	recorder := &responseRecorder{ResponseWriter: ctx.Writer}
+	ctx.Writer = recorder
+
+	// Mientras el handler corre se renueva el lease; si el proceso se cae, un reintento puede tomar la clave
+	stop := c.service.KeepAlive(scopedKey)
+	defer stop()
+
+	ctx.Next()
+
+	// Los errores del servidor no se guardan: el cliente tiene que poder reintentar
+	status := recorder.Status()
+	if status >= http.StatusInternalServerError {
+		if err := c.service.Release(context.Background(), scopedKey); err != nil {
+			log.Printf("⚠️ %v", err)
+		}
+		return
+	}
+
+	if err := c.service.Complete(context.Background(), scopedKey, status, recorder.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+		log.Printf("⚠️ %v", err)
+	}
+}
+
+// requestFingerprint identifica el request (quién lo hace y con qué contenido) para detectar claves reutilizadas
+func requestFingerprint(method, path, caller string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + " " + caller + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"products-api/internal/domain"
+	"products-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrdersService define las operaciones de negocio sobre órdenes
+type OrdersService interface {
+	GetByID(ctx context.Context, id string) (domain.Order, error)
+	Ship(ctx context.Context, id string, req domain.ShipOrderRequest) (domain.Order, error)
+}
+
+// OrdersController maneja las peticiones HTTP de órdenes
+type OrdersController struct {
+	service OrdersService
+}
+
+// NewOrdersController crea una nueva instancia del controller
+func NewOrdersController(service OrdersService) *OrdersController {
+	return &OrdersController{
+		service: service,
+	}
+}
+
+// GetByID obtiene una orden (solo su cliente o quien puede ver ventas)
+// GET /orders/:id
+func (c *OrdersController) GetByID(ctx *gin.Context) {
+	order, err := c.service.GetByID(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		respondOrderError(ctx, err)
+		return
+	}
+	if !AuthorizeCustomerOrPermission(ctx, order.CustomerID, domain.PermSalesRead) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"order": order})
+}
+
+// Ship marca una orden pagada como despachada (solo con orders:write)
+// POST /orders/:id/ship
+func (c *OrdersController) Ship(ctx *gin.Context) {
+	var req domain.ShipOrderRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid JSON format",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	order, err := c.service.Ship(ctx.Request.Context(), ctx.Param("id"), req)
+	if err != nil {
+		respondOrderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"order": order})
+}
+
+func respondOrderError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrOrderNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+	case errors.Is(err, services.ErrInvalidOrderStatus):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("❌ Error processing order: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing order"})
+	}
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"products-api/internal/domain"
+	"products-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const paymentSignatureHeader = "X-Payment-Signature"
+
+// PaymentsService define las operaciones de negocio para Payments
+type PaymentsService interface {
+	HandleWebhook(ctx context.Context, payload []byte, signature string) (domain.PaymentEvent, error)
+}
+
+// PaymentsController maneja las notificaciones del proveedor de pagos
+type PaymentsController struct {
+	service PaymentsService
+}
+
+// NewPaymentsController crea una nueva instancia del controller
+func NewPaymentsController(service PaymentsService) *PaymentsController {
+	return &PaymentsController{
+		service: service,
+	}
+}
+
+// Webhook recibe los eventos del proveedor de pagos (cobros, reintegros, rechazos)
+// POST /payments/webhook
+func (c *PaymentsController) Webhook(ctx *gin.Context) {
+	payload, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	event, err := c.service.HandleWebhook(ctx.Request.Context(), payload, ctx.GetHeader(paymentSignatureHeader))
+	if err != nil {
+		log.Printf("❌ Error processing payment webhook: %v", err)
+
+		switch {
+		case errors.Is(err, services.ErrInvalidWebhook):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook"})
+		case errors.Is(err, services.ErrPaymentNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+		default:
+			// El proveedor reintenta los webhooks que no responden 2xx
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing webhook"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"received": true,
+		"event_id": event.ID,
+	})
+}
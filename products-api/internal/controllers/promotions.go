@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"products-api/internal/domain"
+	"products-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PromotionsService define las operaciones de administración de promociones
+type PromotionsService interface {
+	List(ctx context.Context) ([]domain.Promotion, error)
+	GetByID(ctx context.Context, id string) (domain.Promotion, error)
+	Create(ctx context.Context, promotion domain.Promotion) (domain.Promotion, error)
+	Update(ctx context.Context, id string, promotion domain.Promotion) (domain.Promotion, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// PromotionsController maneja el ABM de promociones (solo admin)
+type PromotionsController struct {
+	service PromotionsService
+}
+
+// NewPromotionsController crea una nueva instancia del controller
+func NewPromotionsController(service PromotionsService) *PromotionsController {
+	return &PromotionsController{
+		service: service,
+	}
+}
+
+// List obtiene todas las promociones
+// GET /promotions
+func (c *PromotionsController) List(ctx *gin.Context) {
+	promotions, err := c.service.List(ctx.Request.Context())
+	if err != nil {
+		log.Printf("❌ Error listing promotions: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error listing promotions"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"promotions": promotions})
+}
+
+// GetByID obtiene una promoción por ID
+// GET /promotions/:id
+func (c *PromotionsController) GetByID(ctx *gin.Context) {
+	promotion, err := c.service.GetByID(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		respondPromotionAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"promotion": promotion})
+}
+
+// Create crea una promoción
+// POST /promotions
+func (c *PromotionsController) Create(ctx *gin.Context) {
+	var promotion domain.Promotion
+	if err := ctx.ShouldBindJSON(&promotion); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	created, err := c.service.Create(ctx.Request.Context(), promotion)
+	if err != nil {
+		respondPromotionAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"promotion": created})
+}
+
+// Update actualiza una promoción
+// PUT /promotions/:id
+func (c *PromotionsController) Update(ctx *gin.Context) {
+	var promotion domain.Promotion
+	if err := ctx.ShouldBindJSON(&promotion); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	updated, err := c.service.Update(ctx.Request.Context(), ctx.Param("id"), promotion)
+	if err != nil {
+		respondPromotionAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"promotion": updated})
+}
+
+// Delete elimina una promoción
+// DELETE /promotions/:id
+func (c *PromotionsController) Delete(ctx *gin.Context) {
+	if err := c.service.Delete(ctx.Request.Context(), ctx.Param("id")); err != nil {
+		respondPromotionAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "promotion deleted successfully"})
+}
+
+func respondPromotionAdminError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidPromotion):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrPromotionNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		log.Printf("❌ Error managing promotion: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error managing promotion"})
+	}
+}
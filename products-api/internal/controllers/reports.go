@@ -0,0 +1,246 @@
+package controllers
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"products-api/internal/domain"
+	"products-api/internal/services"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportsService define los reportes de ventas disponibles para admins
+type ReportsService interface {
+	RevenueByPeriod(ctx context.Context, filters domain.ReportFilters) ([]domain.RevenuePoint, error)
+	TopProducts(ctx context.Context, filters domain.ReportFilters) ([]domain.TopProduct, error)
+	RevenueByCategory(ctx context.Context, filters domain.ReportFilters) ([]domain.CategoryRevenue, error)
+	Summary(ctx context.Context, filters domain.ReportFilters) (domain.SalesSummary, error)
+}
+
+// ReportsController maneja las peticiones HTTP de reportes de ventas
+// Todos los endpoints aceptan ?from=YYYY-MM-DD&to=YYYY-MM-DD y ?format=csv
+type ReportsController struct {
+	service ReportsService
+}
+
+// NewReportsController crea una nueva instancia del controller
+func NewReportsController(service ReportsService) *ReportsController {
+	return &ReportsController{
+		service: service,
+	}
+}
+
+// RevenueByPeriod maneja GET /reports/sales/revenue?granularity=day|week|month
+func (c *ReportsController) RevenueByPeriod(ctx *gin.Context) {
+	filters, ok := parseReportFilters(ctx)
+	if !ok {
+		return
+	}
+	filters.Granularity = ctx.Query("granularity")
+
+	points, err := c.service.RevenueByPeriod(ctx.Request.Context(), filters)
+	if err != nil {
+		respondReportError(ctx, err)
+		return
+	}
+
+	if wantsCSV(ctx) {
+		rows := [][]string{{"period", "revenue", "units", "orders"}}
+		for _, p := range points {
+			rows = append(rows, []string{
+				p.Period.Format("2006-01-02"),
+				formatMoney(p.Revenue),
+				strconv.Itoa(p.Units),
+				strconv.Itoa(p.Orders),
+			})
+		}
+		writeCSV(ctx, "revenue.csv", rows)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"from":        filters.From,
+		"to":          filters.To,
+		"granularity": filters.Granularity,
+		"results":     points,
+	})
+}
+
+// TopProducts maneja GET /reports/sales/top-products?limit=N
+func (c *ReportsController) TopProducts(ctx *gin.Context) {
+	filters, ok := parseReportFilters(ctx)
+	if !ok {
+		return
+	}
+	if limit := ctx.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a valid integer"})
+			return
+		}
+		filters.Limit = n
+	}
+
+	products, err := c.service.TopProducts(ctx.Request.Context(), filters)
+	if err != nil {
+		respondReportError(ctx, err)
+		return
+	}
+
+	if wantsCSV(ctx) {
+		rows := [][]string{{"item_id", "name", "units", "revenue"}}
+		for _, p := range products {
+			rows = append(rows, []string{p.ItemID, p.Name, strconv.Itoa(p.Units), formatMoney(p.Revenue)})
+		}
+		writeCSV(ctx, "top-products.csv", rows)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"from":    filters.From,
+		"to":      filters.To,
+		"results": products,
+	})
+}
+
+// RevenueByCategory maneja GET /reports/sales/by-category
+func (c *ReportsController) RevenueByCategory(ctx *gin.Context) {
+	filters, ok := parseReportFilters(ctx)
+	if !ok {
+		return
+	}
+
+	categories, err := c.service.RevenueByCategory(ctx.Request.Context(), filters)
+	if err != nil {
+		respondReportError(ctx, err)
+		return
+	}
+
+	if wantsCSV(ctx) {
+		rows := [][]string{{"category", "revenue", "units"}}
+		for _, cat := range categories {
+			rows = append(rows, []string{cat.Category, formatMoney(cat.Revenue), strconv.Itoa(cat.Units)})
+		}
+		writeCSV(ctx, "revenue-by-category.csv", rows)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"from":    filters.From,
+		"to":      filters.To,
+		"results": categories,
+	})
+}
+
+// Summary maneja GET /reports/sales/summary - ticket promedio y clientes recurrentes
+func (c *ReportsController) Summary(ctx *gin.Context) {
+	filters, ok := parseReportFilters(ctx)
+	if !ok {
+		return
+	}
+
+	summary, err := c.service.Summary(ctx.Request.Context(), filters)
+	if err != nil {
+		respondReportError(ctx, err)
+		return
+	}
+
+	if wantsCSV(ctx) {
+		rows := [][]string{
+			{"from", "to", "revenue", "units", "orders", "average_order_value", "customers", "repeat_customers", "repeat_customer_rate"},
+			{
+				summary.From.Format(time.RFC3339),
+				summary.To.Format(time.RFC3339),
+				formatMoney(summary.Revenue),
+				strconv.Itoa(summary.Units),
+				strconv.Itoa(summary.Orders),
+				formatMoney(summary.AverageOrderValue),
+				strconv.Itoa(summary.Customers),
+				strconv.Itoa(summary.RepeatCustomers),
+				strconv.FormatFloat(summary.RepeatCustomerRate, 'f', 4, 64),
+			},
+		}
+		writeCSV(ctx, "summary.csv", rows)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, summary)
+}
+
+// parseReportFilters lee el rango de fechas de la query
+// Acepta fechas YYYY-MM-DD (el "to" se toma inclusive) o RFC3339
+func parseReportFilters(ctx *gin.Context) (domain.ReportFilters, bool) {
+	var filters domain.ReportFilters
+
+	if from := ctx.Query("from"); from != "" {
+		t, _, err := parseReportDate(from)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "from must be YYYY-MM-DD or RFC3339"})
+			return filters, false
+		}
+		filters.From = t
+	}
+
+	if to := ctx.Query("to"); to != "" {
+		t, dateOnly, err := parseReportDate(to)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "to must be YYYY-MM-DD or RFC3339"})
+			return filters, false
+		}
+		if dateOnly {
+			// Incluir el día completo
+			t = t.AddDate(0, 0, 1)
+		}
+		filters.To = t
+	}
+
+	return filters, true
+}
+
+func parseReportDate(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t.UTC(), true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t.UTC(), false, nil
+}
+
+// wantsCSV indica si el cliente pidió la respuesta en CSV
+func wantsCSV(ctx *gin.Context) bool {
+	return strings.EqualFold(ctx.Query("format"), "csv") || strings.Contains(ctx.GetHeader("Accept"), "text/csv")
+}
+
+func writeCSV(ctx *gin.Context, filename string, rows [][]string) {
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.Status(http.StatusOK)
+
+	w := csv.NewWriter(ctx.Writer)
+	if err := w.WriteAll(rows); err != nil {
+		_ = ctx.Error(err)
+	}
+}
+
+func formatMoney(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+func respondReportError(ctx *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidReportFilters) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "failed to build report",
+		"details": err.Error(),
+	})
+}
@@ -61,6 +61,16 @@ func (c *SalesController) CreateSale(ctx *gin.Context) {
 		return
 	}
 
+	// Un cliente solo puede comprar a su nombre
+	customerID, err := strconv.Atoi(sale.CustomerID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "customer_id must be a valid integer"})
+		return
+	}
+	if !AuthorizeCustomer(ctx, customerID) {
+		return
+	}
+
 	created, err := c.service.Create(ctx.Request.Context(), sale)
 	if err != nil {
 		// Error interno del servidor o validación
@@ -96,6 +106,9 @@ func (c *SalesController) GetSaleByID(ctx *gin.Context) {
 		})
 		return
 	}
+	if !AuthorizeCustomerOrPermission(ctx, sale.CustomerID, domain.PermSalesRead) {
+		return
+	}
 
 	ctx.JSON(http.StatusOK, gin.H{
 		"sale": sale,
@@ -161,6 +174,10 @@ func (c *SalesController) UpdateSale(ctx *gin.Context) {
 
 	sale, err := c.service.Update(ctx.Request.Context(), ctx.Param("id"), updatedSale)
 	if err != nil {
+		if errors.Is(err, services.ErrSaleBelongsToOrder) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "failed to update sale",
 			"details": err.Error(),
@@ -187,6 +204,10 @@ func (c *SalesController) DeleteSale(ctx *gin.Context) {
 
 	err := c.service.Delete(ctx.Request.Context(), id)
 	if err != nil {
+		if errors.Is(err, services.ErrSaleBelongsToOrder) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "failed to delete sale",
 			"details": err.Error(),
@@ -0,0 +1,300 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"products-api/internal/domain"
+	"products-api/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WishlistsService define las operaciones de negocio de las listas de deseos
+type WishlistsService interface {
+	List(ctx context.Context, customerID int) ([]domain.WishlistResponse, error)
+	Create(ctx context.Context, customerID int, req domain.WishlistRequest) (domain.WishlistResponse, error)
+	Get(ctx context.Context, customerID int, wishlistID string) (domain.WishlistResponse, error)
+	Rename(ctx context.Context, customerID int, wishlistID string, req domain.WishlistRequest) (domain.WishlistResponse, error)
+	Delete(ctx context.Context, customerID int, wishlistID string) error
+	AddItem(ctx context.Context, customerID int, wishlistID string, req domain.AddWishlistItemRequest) (domain.WishlistResponse, error)
+	RemoveItem(ctx context.Context, customerID int, wishlistID, itemID string) (domain.WishlistResponse, error)
+	MoveToCart(ctx context.Context, customerID int, wishlistID, itemID string, req domain.MoveToCartRequest) (domain.WishlistMoveResult, error)
+	MoveFromCart(ctx context.Context, customerID int, itemID string, req domain.MoveToWishlistRequest) (domain.WishlistMoveResult, error)
+	Share(ctx context.Context, customerID int, wishlistID string) (domain.WishlistResponse, error)
+	Unshare(ctx context.Context, customerID int, wishlistID string) (domain.WishlistResponse, error)
+	GetShared(ctx context.Context, token string) (domain.WishlistResponse, error)
+}
+
+// WishlistsController maneja las peticiones HTTP de las listas de deseos
+type WishlistsController struct {
+	service WishlistsService
+}
+
+// NewWishlistsController crea una nueva instancia del controller
+func NewWishlistsController(service WishlistsService) *WishlistsController {
+	return &WishlistsController{
+		service: service,
+	}
+}
+
+// List obtiene las listas del cliente
+// GET /wishlists/:customerID
+func (c *WishlistsController) List(ctx *gin.Context) {
+	customerID, ok := wishlistCustomerID(ctx)
+	if !ok {
+		return
+	}
+
+	wishlists, err := c.service.List(ctx.Request.Context(), customerID)
+	if err != nil {
+		respondWishlistError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"wishlists": wishlists, "count": len(wishlists)})
+}
+
+// Create crea una lista con nombre
+// POST /wishlists/:customerID
+func (c *WishlistsController) Create(ctx *gin.Context) {
+	customerID, ok := wishlistCustomerID(ctx)
+	if !ok {
+		return
+	}
+
+	var req domain.WishlistRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	wishlist, err := c.service.Create(ctx.Request.Context(), customerID, req)
+	if err != nil {
+		respondWishlistError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, wishlist)
+}
+
+// Get obtiene una lista del cliente con el precio y el stock actuales
+// GET /wishlists/:customerID/:wishlistID
+func (c *WishlistsController) Get(ctx *gin.Context) {
+	customerID, ok := wishlistCustomerID(ctx)
+	if !ok {
+		return
+	}
+
+	wishlist, err := c.service.Get(ctx.Request.Context(), customerID, ctx.Param("wishlistID"))
+	if err != nil {
+		respondWishlistError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, wishlist)
+}
+
+// Rename cambia el nombre de una lista
+// PUT /wishlists/:customerID/:wishlistID
+func (c *WishlistsController) Rename(ctx *gin.Context) {
+	customerID, ok := wishlistCustomerID(ctx)
+	if !ok {
+		return
+	}
+
+	var req domain.WishlistRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	wishlist, err := c.service.Rename(ctx.Request.Context(), customerID, ctx.Param("wishlistID"), req)
+	if err != nil {
+		respondWishlistError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, wishlist)
+}
+
+// Delete elimina una lista
+// DELETE /wishlists/:customerID/:wishlistID
+func (c *WishlistsController) Delete(ctx *gin.Context) {
+	customerID, ok := wishlistCustomerID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.Delete(ctx.Request.Context(), customerID, ctx.Param("wishlistID")); err != nil {
+		respondWishlistError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "wishlist deleted successfully"})
+}
+
+// AddItem guarda un producto en la lista
+// POST /wishlists/:customerID/:wishlistID/items
+func (c *WishlistsController) AddItem(ctx *gin.Context) {
+	customerID, ok := wishlistCustomerID(ctx)
+	if !ok {
+		return
+	}
+
+	var req domain.AddWishlistItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	wishlist, err := c.service.AddItem(ctx.Request.Context(), customerID, ctx.Param("wishlistID"), req)
+	if err != nil {
+		respondWishlistError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, wishlist)
+}
+
+// RemoveItem saca un producto de la lista
+// DELETE /wishlists/:customerID/:wishlistID/items/:itemID
+func (c *WishlistsController) RemoveItem(ctx *gin.Context) {
+	customerID, ok := wishlistCustomerID(ctx)
+	if !ok {
+		return
+	}
+
+	wishlist, err := c.service.RemoveItem(ctx.Request.Context(), customerID, ctx.Param("wishlistID"), ctx.Param("itemID"))
+	if err != nil {
+		respondWishlistError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, wishlist)
+}
+
+// MoveToCart pasa un producto de la lista al carrito
+// POST /wishlists/:customerID/:wishlistID/items/:itemID/move-to-cart
+func (c *WishlistsController) MoveToCart(ctx *gin.Context) {
+	customerID, ok := wishlistCustomerID(ctx)
+	if !ok {
+		return
+	}
+
+	// El body es opcional: sin cantidad se mueve 1 unidad
+	var req domain.MoveToCartRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	result, err := c.service.MoveToCart(ctx.Request.Context(), customerID, ctx.Param("wishlistID"), ctx.Param("itemID"), req)
+	if err != nil {
+		respondWishlistError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// MoveToWishlist pasa una línea del carrito a una lista de deseos
+// POST /cart/:customerID/items/:itemID/move-to-wishlist
+func (c *WishlistsController) MoveToWishlist(ctx *gin.Context) {
+	customerID, ok := wishlistCustomerID(ctx)
+	if !ok {
+		return
+	}
+
+	var req domain.MoveToWishlistRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := c.service.MoveFromCart(ctx.Request.Context(), customerID, ctx.Param("itemID"), req)
+	if err != nil {
+		respondWishlistError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// Share genera el enlace de solo lectura de la lista
+// POST /wishlists/:customerID/:wishlistID/share
+func (c *WishlistsController) Share(ctx *gin.Context) {
+	customerID, ok := wishlistCustomerID(ctx)
+	if !ok {
+		return
+	}
+
+	wishlist, err := c.service.Share(ctx.Request.Context(), customerID, ctx.Param("wishlistID"))
+	if err != nil {
+		respondWishlistError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, wishlist)
+}
+
+// Unshare deja de compartir la lista
+// DELETE /wishlists/:customerID/:wishlistID/share
+func (c *WishlistsController) Unshare(ctx *gin.Context) {
+	customerID, ok := wishlistCustomerID(ctx)
+	if !ok {
+		return
+	}
+
+	wishlist, err := c.service.Unshare(ctx.Request.Context(), customerID, ctx.Param("wishlistID"))
+	if err != nil {
+		respondWishlistError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, wishlist)
+}
+
+// GetShared muestra una lista compartida sin login
+// GET /shared-wishlists/:token
+func (c *WishlistsController) GetShared(ctx *gin.Context) {
+	wishlist, err := c.service.GetShared(ctx.Request.Context(), ctx.Param("token"))
+	if err != nil {
+		respondWishlistError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, wishlist)
+}
+
+func wishlistCustomerID(ctx *gin.Context) (int, bool) {
+	customerID, err := strconv.Atoi(ctx.Param("customerID"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer_id format"})
+		return 0, false
+	}
+	return customerID, true
+}
+
+func respondWishlistError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidWishlist):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrWishlistNotFound),
+		errors.Is(err, services.ErrWishlistItemNotFound),
+		errors.Is(err, services.ErrCartItemNotFound),
+		errors.Is(err, services.ErrItemNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrWishlistNameTaken),
+		errors.Is(err, services.ErrTooManyWishlists),
+		errors.Is(err, services.ErrInsufficientStock):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("❌ Error managing wishlist: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error managing wishlist"})
+	}
+}
@@ -0,0 +1,54 @@
+package dao
+
+import (
+	"products-api/internal/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditEvent representa un evento de auditoría en MongoDB
+type AuditEvent struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	Action     string             `bson:"action"`
+	UserID     int                `bson:"user_id"`
+	IsAdmin    bool               `bson:"is_admin"`
+	Role       string             `bson:"role"`
+	Method     string             `bson:"method"`
+	Path       string             `bson:"path"`
+	CustomerID int                `bson:"customer_id,omitempty"`
+	Permission string             `bson:"permission,omitempty"`
+	ClientIP   string             `bson:"client_ip"`
+	CreatedAt  time.Time          `bson:"created_at"`
+}
+
+func (e AuditEvent) ToDomain() domain.AuditEvent {
+	return domain.AuditEvent{
+		ID:         e.ID.Hex(),
+		Action:     e.Action,
+		UserID:     e.UserID,
+		IsAdmin:    e.IsAdmin,
+		Role:       e.Role,
+		Method:     e.Method,
+		Path:       e.Path,
+		CustomerID: e.CustomerID,
+		Permission: e.Permission,
+		ClientIP:   e.ClientIP,
+		CreatedAt:  e.CreatedAt,
+	}
+}
+
+func FromDomainAuditEvent(event domain.AuditEvent) AuditEvent {
+	return AuditEvent{
+		Action:     event.Action,
+		UserID:     event.UserID,
+		IsAdmin:    event.IsAdmin,
+		Role:       event.Role,
+		Method:     event.Method,
+		Path:       event.Path,
+		CustomerID: event.CustomerID,
+		Permission: event.Permission,
+		ClientIP:   event.ClientIP,
+		CreatedAt:  event.CreatedAt,
+	}
+}
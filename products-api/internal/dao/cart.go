@@ -8,8 +8,9 @@ import (
 
 // CartItemDAO representa un ítem del carrito en la base de datos
 type CartItemDAO struct {
-	ItemID   string `bson:"item_id"`
-	Quantity int    `bson:"quantity"`
+	ItemID     string  `bson:"item_id"`
+	Quantity   int     `bson:"quantity"`
+	PriceAtAdd float64 `bson:"price_at_add,omitempty"`
 }
 
 // CartDAO representa el carrito en la base de datos MongoDB
@@ -18,6 +19,21 @@ type CartDAO struct {
 	CustomerID int                `bson:"customer_id"`
 	Items      []CartItemDAO      `bson:"items"`
 	Total      float64            `bson:"total"`
+	CouponCode string             `bson:"coupon_code,omitempty"`
 	CreatedAt  time.Time          `bson:"created_at"`
 	UpdatedAt  time.Time          `bson:"updated_at"`
+	// Recordatorios de carrito abandonado; se reinician cada vez que el cliente modifica el carrito
+	ReminderCount  int        `bson:"reminder_count"`
+	LastReminderAt *time.Time `bson:"last_reminder_at,omitempty"`
+}
+
+// GuestCartDAO es el carrito de un invitado (_id = token de sesión)
+// MongoDB lo borra solo al pasar expires_at (índice TTL)
+type GuestCartDAO struct {
+	Token      string        `bson:"_id"`
+	Items      []CartItemDAO `bson:"items"`
+	CouponCode string        `bson:"coupon_code,omitempty"`
+	ExpiresAt  time.Time     `bson:"expires_at"`
+	CreatedAt  time.Time     `bson:"created_at"`
+	UpdatedAt  time.Time     `bson:"updated_at"`
 }
@@ -0,0 +1,149 @@
+package dao
+
+import (
+	"products-api/internal/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Coupon struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	Code           string             `bson:"code"`
+	Description    string             `bson:"description"`
+	Type           string             `bson:"type"`
+	Value          float64            `bson:"value"`
+	MinCartTotal   float64            `bson:"min_cart_total"`
+	Categories     []string           `bson:"categories"`
+	ItemIDs        []string           `bson:"item_ids"`
+	StartsAt       *time.Time         `bson:"starts_at,omitempty"`
+	EndsAt         *time.Time         `bson:"ends_at,omitempty"`
+	MaxRedemptions int                `bson:"max_redemptions"`
+	MaxPerCustomer int                `bson:"max_per_customer"`
+	Redemptions    int                `bson:"redemptions"`
+	Active         bool               `bson:"active"`
+	CreatedAt      time.Time          `bson:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at"`
+}
+
+// CouponRedemption usa el ID de la orden como _id: un cupón por orden y redención idempotente
+// CouponRedemption es el uso de un cupón en una orden
+// CustomerCounted y CouponCounted indican qué contadores ya se sumaron (ver MongoCouponsRepository.Redeem)
+type CouponRedemption struct {
+	OrderID         string    `bson:"_id"`
+	CouponID        string    `bson:"coupon_id"`
+	Code            string    `bson:"code"`
+	CustomerID      int       `bson:"customer_id"`
+	Discount        float64   `bson:"discount"`
+	CustomerCounted bool      `bson:"customer_counted"`
+	CouponCounted   bool      `bson:"coupon_counted"`
+	CreatedAt       time.Time `bson:"created_at"`
+}
+
+type LineDiscount struct {
+	ItemID string  `bson:"item_id"`
+	Amount float64 `bson:"amount"`
+	Reason string  `bson:"reason"`
+}
+
+// Discount es un descuento aplicado a una orden
+type Discount struct {
+	Type        string         `bson:"type"`
+	Code        string         `bson:"code,omitempty"`
+	PromotionID string         `bson:"promotion_id,omitempty"`
+	Description string         `bson:"description"`
+	Amount      float64        `bson:"amount"`
+	Lines       []LineDiscount `bson:"lines"`
+}
+
+func (c Coupon) ToDomain() domain.Coupon {
+	return domain.Coupon{
+		ID:             c.ID.Hex(),
+		Code:           c.Code,
+		Description:    c.Description,
+		Type:           c.Type,
+		Value:          c.Value,
+		MinCartTotal:   c.MinCartTotal,
+		Categories:     c.Categories,
+		ItemIDs:        c.ItemIDs,
+		StartsAt:       c.StartsAt,
+		EndsAt:         c.EndsAt,
+		MaxRedemptions: c.MaxRedemptions,
+		MaxPerCustomer: c.MaxPerCustomer,
+		Redemptions:    c.Redemptions,
+		Active:         c.Active,
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+	}
+}
+
+func FromDomainCoupon(coupon domain.Coupon) Coupon {
+	var objectID primitive.ObjectID
+	if coupon.ID != "" {
+		objectID, _ = primitive.ObjectIDFromHex(coupon.ID)
+	}
+	return Coupon{
+		ID:             objectID,
+		Code:           coupon.Code,
+		Description:    coupon.Description,
+		Type:           coupon.Type,
+		Value:          coupon.Value,
+		MinCartTotal:   coupon.MinCartTotal,
+		Categories:     coupon.Categories,
+		ItemIDs:        coupon.ItemIDs,
+		StartsAt:       coupon.StartsAt,
+		EndsAt:         coupon.EndsAt,
+		MaxRedemptions: coupon.MaxRedemptions,
+		MaxPerCustomer: coupon.MaxPerCustomer,
+		Redemptions:    coupon.Redemptions,
+		Active:         coupon.Active,
+		CreatedAt:      coupon.CreatedAt,
+		UpdatedAt:      coupon.UpdatedAt,
+	}
+}
+
+func (r CouponRedemption) ToDomain() domain.CouponRedemption {
+	return domain.CouponRedemption{
+		OrderID:    r.OrderID,
+		CouponID:   r.CouponID,
+		Code:       r.Code,
+		CustomerID: r.CustomerID,
+		Discount:   r.Discount,
+		CreatedAt:  r.CreatedAt,
+	}
+}
+
+func FromDomainCouponRedemption(redemption domain.CouponRedemption) CouponRedemption {
+	return CouponRedemption{
+		OrderID:    redemption.OrderID,
+		CouponID:   redemption.CouponID,
+		Code:       redemption.Code,
+		CustomerID: redemption.CustomerID,
+		Discount:   redemption.Discount,
+		CreatedAt:  redemption.CreatedAt,
+	}
+}
+
+func toDomainDiscounts(discounts []Discount) []domain.Discount {
+	result := make([]domain.Discount, len(discounts))
+	for i, discount := range discounts {
+		lines := make([]domain.LineDiscount, len(discount.Lines))
+		for j, line := range discount.Lines {
+			lines[j] = domain.LineDiscount(line)
+		}
+		result[i] = domain.Discount{Type: discount.Type, Code: discount.Code, PromotionID: discount.PromotionID, Description: discount.Description, Amount: discount.Amount, Lines: lines}
+	}
+	return result
+}
+
+func fromDomainDiscounts(discounts []domain.Discount) []Discount {
+	result := make([]Discount, len(discounts))
+	for i, discount := range discounts {
+		lines := make([]LineDiscount, len(discount.Lines))
+		for j, line := range discount.Lines {
+			lines[j] = LineDiscount(line)
+		}
+		result[i] = Discount{Type: discount.Type, Code: discount.Code, PromotionID: discount.PromotionID, Description: discount.Description, Amount: discount.Amount, Lines: lines}
+	}
+	return result
+}
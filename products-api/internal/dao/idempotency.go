@@ -0,0 +1,46 @@
+package dao
+
+import (
+	"products-api/internal/domain"
+	"time"
+)
+
+type IdempotencyRecord struct {
+	Key          string    `bson:"_id"`
+	Fingerprint  string    `bson:"fingerprint"`
+	Status       string    `bson:"status"`
+	StatusCode   int       `bson:"status_code"`
+	ContentType  string    `bson:"content_type"`
+	ResponseBody []byte    `bson:"response_body"`
+	LockedUntil  time.Time `bson:"locked_until"`
+	CreatedAt    time.Time `bson:"created_at"`
+	ExpiresAt    time.Time `bson:"expires_at"`
+}
+
+func (r IdempotencyRecord) ToDomain() domain.IdempotencyRecord {
+	return domain.IdempotencyRecord{
+		Key:          r.Key,
+		Fingerprint:  r.Fingerprint,
+		Status:       r.Status,
+		StatusCode:   r.StatusCode,
+		ContentType:  r.ContentType,
+		ResponseBody: r.ResponseBody,
+		LockedUntil:  r.LockedUntil,
+		CreatedAt:    r.CreatedAt,
+		ExpiresAt:    r.ExpiresAt,
+	}
+}
+
+func FromDomainIdempotencyRecord(record domain.IdempotencyRecord) IdempotencyRecord {
+	return IdempotencyRecord{
+		Key:          record.Key,
+		Fingerprint:  record.Fingerprint,
+		Status:       record.Status,
+		StatusCode:   record.StatusCode,
+		ContentType:  record.ContentType,
+		ResponseBody: record.ResponseBody,
+		LockedUntil:  record.LockedUntil,
+		CreatedAt:    record.CreatedAt,
+		ExpiresAt:    record.ExpiresAt,
+	}
+}
@@ -15,10 +15,19 @@ type Item struct {
 	Price       float64            `bson:"price"`
 	Stock       int                `bson:"stock"`
 	ImageURL    string             `bson:"image_url"`
+	WeightKg    float64            `bson:"weight_kg"`
+	Dimensions  Dimensions         `bson:"dimensions"`
+	TaxClass    string             `bson:"tax_class,omitempty"`
 	CreatedAt   time.Time          `bson:"created_at"`
 	UpdatedAt   time.Time          `bson:"updated_at"`
 }
 
+type Dimensions struct {
+	LengthCm float64 `bson:"length_cm"`
+	WidthCm  float64 `bson:"width_cm"`
+	HeightCm float64 `bson:"height_cm"`
+}
+
 func (i Item) ToDomain() domain.Item {
 	return domain.Item{
 		ID:          i.ID.Hex(),
@@ -28,6 +37,9 @@ func (i Item) ToDomain() domain.Item {
 		Price:       i.Price,
 		Stock:       i.Stock,
 		ImageURL:    i.ImageURL,
+		WeightKg:    i.WeightKg,
+		Dimensions:  domain.Dimensions(i.Dimensions),
+		TaxClass:    i.TaxClass,
 		CreatedAt:   i.CreatedAt,
 		UpdatedAt:   i.UpdatedAt,
 	}
@@ -50,6 +62,9 @@ func FromDomain(domainItem domain.Item) Item {
 		Price:       domainItem.Price,
 		Stock:       domainItem.Stock,
 		ImageURL:    domainItem.ImageURL,
+		WeightKg:    domainItem.WeightKg,
+		Dimensions:  Dimensions(domainItem.Dimensions),
+		TaxClass:    domainItem.TaxClass,
 		CreatedAt:   domainItem.CreatedAt,
 		UpdatedAt:   domainItem.UpdatedAt,
 	}
@@ -0,0 +1,304 @@
+package dao
+
+import (
+	"products-api/internal/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrderLine struct {
+	ItemID    string  `bson:"item_id"`
+	ItemName  string  `bson:"item_name"`
+	Category  string  `bson:"category"`
+	ImageURL  string  `bson:"image_url"`
+	UnitPrice float64 `bson:"unit_price"`
+	Quantity  int     `bson:"quantity"`
+	Subtotal  float64 `bson:"subtotal"`
+
+	TaxClass    string  `bson:"tax_class,omitempty"`
+	TaxRate     float64 `bson:"tax_rate"`
+	Discount    float64 `bson:"discount"`
+	NetAmount   float64 `bson:"net_amount"`
+	TaxAmount   float64 `bson:"tax_amount"`
+	GrossAmount float64 `bson:"gross_amount"`
+}
+
+// TaxBreakdown tiene los mismos campos que domain.TaxBreakdown para poder convertirlos directamente
+type TaxBreakdown struct {
+	TaxClass string  `bson:"tax_class"`
+	Rate     float64 `bson:"rate"`
+	Net      float64 `bson:"net"`
+	Tax      float64 `bson:"tax"`
+	Gross    float64 `bson:"gross"`
+}
+
+type TaxSummary struct {
+	PricesIncludeTax bool           `bson:"prices_include_tax"`
+	Net              float64        `bson:"net"`
+	Tax              float64        `bson:"tax"`
+	Gross            float64        `bson:"gross"`
+	ByClass          []TaxBreakdown `bson:"by_class"`
+}
+
+// ShippingAddress tiene los mismos campos que domain.ShippingAddress para poder convertirlos directamente
+type ShippingAddress struct {
+	RecipientName string `bson:"recipient_name"`
+	Street        string `bson:"street"`
+	Number        string `bson:"number"`
+	Apartment     string `bson:"apartment,omitempty"`
+	City          string `bson:"city"`
+	Province      string `bson:"province"`
+	PostalCode    string `bson:"postal_code"`
+	Phone         string `bson:"phone,omitempty"`
+}
+
+type ShippingQuote struct {
+	Method        string  `bson:"method"`
+	Zone          string  `bson:"zone,omitempty"`
+	WeightKg      float64 `bson:"weight_kg"`
+	Cost          float64 `bson:"cost"`
+	EstimatedDays int     `bson:"estimated_days"`
+}
+
+type Order struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty"`
+	CustomerID      int                `bson:"customer_id"`
+	Status          string             `bson:"status"`
+	Items           []OrderLine        `bson:"items"`
+	Subtotal        float64            `bson:"subtotal"`
+	CouponCode      string             `bson:"coupon_code,omitempty"`
+	Discounts       []Discount         `bson:"discounts"`
+	DiscountTotal   float64            `bson:"discount_total"`
+	Shipping        ShippingQuote      `bson:"shipping"`
+	ShippingAddress *ShippingAddress   `bson:"shipping_address,omitempty"`
+	Taxes           TaxSummary         `bson:"taxes"`
+	Total           float64            `bson:"total"`
+	SaleIDs         []string           `bson:"sale_ids"`
+	PaymentID       string             `bson:"payment_id,omitempty"`
+	TrackingNumber  string             `bson:"tracking_number,omitempty"`
+	ShippedAt       *time.Time         `bson:"shipped_at,omitempty"`
+	CreatedAt       time.Time          `bson:"created_at"`
+	UpdatedAt       time.Time          `bson:"updated_at"`
+}
+
+func (l OrderLine) ToDomain() domain.OrderLine {
+	return domain.OrderLine{
+		ItemID:    l.ItemID,
+		ItemName:  l.ItemName,
+		Category:  l.Category,
+		ImageURL:  l.ImageURL,
+		UnitPrice: l.UnitPrice,
+		Quantity:  l.Quantity,
+		Subtotal:  l.Subtotal,
+
+		TaxClass:    l.TaxClass,
+		TaxRate:     l.TaxRate,
+		Discount:    l.Discount,
+		NetAmount:   l.NetAmount,
+		TaxAmount:   l.TaxAmount,
+		GrossAmount: l.GrossAmount,
+	}
+}
+
+func FromDomainOrderLine(line domain.OrderLine) OrderLine {
+	return OrderLine{
+		ItemID:    line.ItemID,
+		ItemName:  line.ItemName,
+		Category:  line.Category,
+		ImageURL:  line.ImageURL,
+		UnitPrice: line.UnitPrice,
+		Quantity:  line.Quantity,
+		Subtotal:  line.Subtotal,
+
+		TaxClass:    line.TaxClass,
+		TaxRate:     line.TaxRate,
+		Discount:    line.Discount,
+		NetAmount:   line.NetAmount,
+		TaxAmount:   line.TaxAmount,
+		GrossAmount: line.GrossAmount,
+	}
+}
+
+func (o Order) ToDomain() domain.Order {
+	items := make([]domain.OrderLine, len(o.Items))
+	for i, line := range o.Items {
+		items[i] = line.ToDomain()
+	}
+	return domain.Order{
+		ID:              o.ID.Hex(),
+		CustomerID:      o.CustomerID,
+		Status:          o.Status,
+		Items:           items,
+		Subtotal:        o.Subtotal,
+		CouponCode:      o.CouponCode,
+		Discounts:       toDomainDiscounts(o.Discounts),
+		DiscountTotal:   o.DiscountTotal,
+		Shipping:        domain.ShippingQuote(o.Shipping),
+		ShippingAddress: toDomainShippingAddress(o.ShippingAddress),
+		Taxes:           o.Taxes.ToDomain(),
+		Total:           o.Total,
+		SaleIDs:         o.SaleIDs,
+		PaymentID:       o.PaymentID,
+		TrackingNumber:  o.TrackingNumber,
+		ShippedAt:       o.ShippedAt,
+		CreatedAt:       o.CreatedAt,
+		UpdatedAt:       o.UpdatedAt,
+	}
+}
+
+func FromDomainOrder(order domain.Order) Order {
+	var objectID primitive.ObjectID
+	if order.ID != "" {
+		objectID, _ = primitive.ObjectIDFromHex(order.ID)
+	}
+	items := make([]OrderLine, len(order.Items))
+	for i, line := range order.Items {
+		items[i] = FromDomainOrderLine(line)
+	}
+	return Order{
+		ID:              objectID,
+		CustomerID:      order.CustomerID,
+		Status:          order.Status,
+		Items:           items,
+		Subtotal:        order.Subtotal,
+		CouponCode:      order.CouponCode,
+		Discounts:       fromDomainDiscounts(order.Discounts),
+		DiscountTotal:   order.DiscountTotal,
+		Shipping:        ShippingQuote(order.Shipping),
+		ShippingAddress: fromDomainShippingAddress(order.ShippingAddress),
+		Taxes:           FromDomainTaxSummary(order.Taxes),
+		Total:           order.Total,
+		SaleIDs:         order.SaleIDs,
+		PaymentID:       order.PaymentID,
+		TrackingNumber:  order.TrackingNumber,
+		ShippedAt:       order.ShippedAt,
+		CreatedAt:       order.CreatedAt,
+		UpdatedAt:       order.UpdatedAt,
+	}
+}
+
+type SagaLine struct {
+	OrderLine     `bson:",inline"`
+	WeightKg      float64 `bson:"weight_kg"`
+	ReservationID string  `bson:"reservation_id,omitempty"`
+	Reserved      bool    `bson:"reserved"`
+}
+
+// CheckoutSaga representa el registro de la saga de checkout en MongoDB
+type CheckoutSaga struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty"`
+	CustomerID      int                `bson:"customer_id"`
+	Status          string             `bson:"status"`
+	Lines           []SagaLine         `bson:"lines"`
+	CompletedSteps  []string           `bson:"completed_steps"`
+	OrderID         string             `bson:"order_id"`
+	SaleIDs         []string           `bson:"sale_ids"`
+	PaymentID       string             `bson:"payment_id,omitempty"`
+	ShippingMethod  string             `bson:"shipping_method"`
+	ShippingAddress *ShippingAddress   `bson:"shipping_address,omitempty"`
+	CouponCode      string             `bson:"coupon_code,omitempty"`
+	Error           string             `bson:"error,omitempty"`
+	LockedUntil     time.Time          `bson:"locked_until"`
+	Version         int                `bson:"version"`
+	CreatedAt       time.Time          `bson:"created_at"`
+	UpdatedAt       time.Time          `bson:"updated_at"`
+}
+
+func (s CheckoutSaga) ToDomain() domain.CheckoutSaga {
+	lines := make([]domain.SagaLine, len(s.Lines))
+	for i, line := range s.Lines {
+		lines[i] = domain.SagaLine{OrderLine: line.OrderLine.ToDomain(), WeightKg: line.WeightKg, ReservationID: line.ReservationID, Reserved: line.Reserved}
+	}
+	return domain.CheckoutSaga{
+		ID:              s.ID.Hex(),
+		CustomerID:      s.CustomerID,
+		Status:          s.Status,
+		Lines:           lines,
+		CompletedSteps:  s.CompletedSteps,
+		OrderID:         s.OrderID,
+		SaleIDs:         s.SaleIDs,
+		PaymentID:       s.PaymentID,
+		ShippingMethod:  s.ShippingMethod,
+		ShippingAddress: toDomainShippingAddress(s.ShippingAddress),
+		CouponCode:      s.CouponCode,
+		Error:           s.Error,
+		LockedUntil:     s.LockedUntil,
+		Version:         s.Version,
+		CreatedAt:       s.CreatedAt,
+		UpdatedAt:       s.UpdatedAt,
+	}
+}
+
+func FromDomainCheckoutSaga(saga domain.CheckoutSaga) CheckoutSaga {
+	var objectID primitive.ObjectID
+	if saga.ID != "" {
+		objectID, _ = primitive.ObjectIDFromHex(saga.ID)
+	}
+	lines := make([]SagaLine, len(saga.Lines))
+	for i, line := range saga.Lines {
+		lines[i] = SagaLine{OrderLine: FromDomainOrderLine(line.OrderLine), WeightKg: line.WeightKg, ReservationID: line.ReservationID, Reserved: line.Reserved}
+	}
+	return CheckoutSaga{
+		ID:              objectID,
+		CustomerID:      saga.CustomerID,
+		Status:          saga.Status,
+		Lines:           lines,
+		CompletedSteps:  saga.CompletedSteps,
+		OrderID:         saga.OrderID,
+		SaleIDs:         saga.SaleIDs,
+		PaymentID:       saga.PaymentID,
+		ShippingMethod:  saga.ShippingMethod,
+		ShippingAddress: fromDomainShippingAddress(saga.ShippingAddress),
+		CouponCode:      saga.CouponCode,
+		Error:           saga.Error,
+		LockedUntil:     saga.LockedUntil,
+		Version:         saga.Version,
+		CreatedAt:       saga.CreatedAt,
+		UpdatedAt:       saga.UpdatedAt,
+	}
+}
+
+func toDomainShippingAddress(address *ShippingAddress) *domain.ShippingAddress {
+	if address == nil {
+		return nil
+	}
+	result := domain.ShippingAddress(*address)
+	return &result
+}
+
+func fromDomainShippingAddress(address *domain.ShippingAddress) *ShippingAddress {
+	if address == nil {
+		return nil
+	}
+	result := ShippingAddress(*address)
+	return &result
+}
+
+func (t TaxSummary) ToDomain() domain.TaxSummary {
+	byClass := make([]domain.TaxBreakdown, len(t.ByClass))
+	for i, total := range t.ByClass {
+		byClass[i] = domain.TaxBreakdown(total)
+	}
+	return domain.TaxSummary{
+		PricesIncludeTax: t.PricesIncludeTax,
+		Net:              t.Net,
+		Tax:              t.Tax,
+		Gross:            t.Gross,
+		ByClass:          byClass,
+	}
+}
+
+func FromDomainTaxSummary(summary domain.TaxSummary) TaxSummary {
+	byClass := make([]TaxBreakdown, len(summary.ByClass))
+	for i, total := range summary.ByClass {
+		byClass[i] = TaxBreakdown(total)
+	}
+	return TaxSummary{
+		PricesIncludeTax: summary.PricesIncludeTax,
+		Net:              summary.Net,
+		Tax:              summary.Tax,
+		Gross:            summary.Gross,
+		ByClass:          byClass,
+	}
+}
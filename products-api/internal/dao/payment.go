@@ -0,0 +1,50 @@
+package dao
+
+import (
+	"products-api/internal/domain"
+	"time"
+)
+
+// Payment guarda el pago usando el ID del proveedor como _id
+type Payment struct {
+	ID         string    `bson:"_id"`
+	OrderID    string    `bson:"order_id"`
+	CustomerID int       `bson:"customer_id"`
+	Amount     float64   `bson:"amount"`
+	Currency   string    `bson:"currency"`
+	Status     string    `bson:"status"`
+	Provider   string    `bson:"provider"`
+	CardLast4  string    `bson:"card_last4"`
+	CreatedAt  time.Time `bson:"created_at"`
+	UpdatedAt  time.Time `bson:"updated_at"`
+}
+
+func (p Payment) ToDomain() domain.Payment {
+	return domain.Payment{
+		ID:         p.ID,
+		OrderID:    p.OrderID,
+		CustomerID: p.CustomerID,
+		Amount:     p.Amount,
+		Currency:   p.Currency,
+		Status:     p.Status,
+		Provider:   p.Provider,
+		CardLast4:  p.CardLast4,
+		CreatedAt:  p.CreatedAt,
+		UpdatedAt:  p.UpdatedAt,
+	}
+}
+
+func FromDomainPayment(payment domain.Payment) Payment {
+	return Payment{
+		ID:         payment.ID,
+		OrderID:    payment.OrderID,
+		CustomerID: payment.CustomerID,
+		Amount:     payment.Amount,
+		Currency:   payment.Currency,
+		Status:     payment.Status,
+		Provider:   payment.Provider,
+		CardLast4:  payment.CardLast4,
+		CreatedAt:  payment.CreatedAt,
+		UpdatedAt:  payment.UpdatedAt,
+	}
+}
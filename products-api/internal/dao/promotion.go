@@ -0,0 +1,76 @@
+package dao
+
+import (
+	"products-api/internal/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Promotion struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	Name        string             `bson:"name"`
+	Description string             `bson:"description"`
+	Type        string             `bson:"type"`
+	Percentage  float64            `bson:"percentage"`
+	BuyQuantity int                `bson:"buy_quantity"`
+	GetQuantity int                `bson:"get_quantity"`
+	Categories  []string           `bson:"categories"`
+	ItemIDs     []string           `bson:"item_ids"`
+	Weekdays    []int              `bson:"weekdays"`
+	StartsAt    *time.Time         `bson:"starts_at,omitempty"`
+	EndsAt      *time.Time         `bson:"ends_at,omitempty"`
+	Priority    int                `bson:"priority"`
+	Stackable   bool               `bson:"stackable"`
+	Active      bool               `bson:"active"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+}
+
+func (p Promotion) ToDomain() domain.Promotion {
+	return domain.Promotion{
+		ID:          p.ID.Hex(),
+		Name:        p.Name,
+		Description: p.Description,
+		Type:        p.Type,
+		Percentage:  p.Percentage,
+		BuyQuantity: p.BuyQuantity,
+		GetQuantity: p.GetQuantity,
+		Categories:  p.Categories,
+		ItemIDs:     p.ItemIDs,
+		Weekdays:    p.Weekdays,
+		StartsAt:    p.StartsAt,
+		EndsAt:      p.EndsAt,
+		Priority:    p.Priority,
+		Stackable:   p.Stackable,
+		Active:      p.Active,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+}
+
+func FromDomainPromotion(promotion domain.Promotion) Promotion {
+	var objectID primitive.ObjectID
+	if promotion.ID != "" {
+		objectID, _ = primitive.ObjectIDFromHex(promotion.ID)
+	}
+	return Promotion{
+		ID:          objectID,
+		Name:        promotion.Name,
+		Description: promotion.Description,
+		Type:        promotion.Type,
+		Percentage:  promotion.Percentage,
+		BuyQuantity: promotion.BuyQuantity,
+		GetQuantity: promotion.GetQuantity,
+		Categories:  promotion.Categories,
+		ItemIDs:     promotion.ItemIDs,
+		Weekdays:    promotion.Weekdays,
+		StartsAt:    promotion.StartsAt,
+		EndsAt:      promotion.EndsAt,
+		Priority:    promotion.Priority,
+		Stackable:   promotion.Stackable,
+		Active:      promotion.Active,
+		CreatedAt:   promotion.CreatedAt,
+		UpdatedAt:   promotion.UpdatedAt,
+	}
+}
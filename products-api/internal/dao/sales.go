@@ -14,6 +14,18 @@ type Sales struct {
 	TotalPrice float64            `bson:"total_price"`
 	SaleDate   time.Time          `bson:"sale_date"`
 	CustomerID int                `bson:"customer_id"`
+	OrderID    string             `bson:"order_id,omitempty"`
+	ItemName   string             `bson:"item_name,omitempty"`
+	Category   string             `bson:"category,omitempty"`
+	ImageURL   string             `bson:"image_url,omitempty"`
+	UnitPrice  float64            `bson:"unit_price,omitempty"`
+
+	TaxClass    string  `bson:"tax_class,omitempty"`
+	TaxRate     float64 `bson:"tax_rate"`
+	Discount    float64 `bson:"discount"`
+	NetAmount   float64 `bson:"net_amount"`
+	TaxAmount   float64 `bson:"tax_amount"`
+	GrossAmount float64 `bson:"gross_amount"`
 }
 
 type SalesList []Sales
@@ -27,6 +39,11 @@ func (sl SalesList) ToDomainList() []domain.Sales {
 }
 
 func (s Sales) ToDomain() domain.Sales {
+	// Las ventas anteriores al snapshot no tienen precio unitario guardado
+	unitPrice := s.UnitPrice
+	if unitPrice == 0 && s.Quantity > 0 {
+		unitPrice = s.TotalPrice / float64(s.Quantity)
+	}
 	return domain.Sales{
 		ID:         s.ID.Hex(),
 		ItemID:     s.ItemID,
@@ -34,6 +51,18 @@ func (s Sales) ToDomain() domain.Sales {
 		TotalPrice: s.TotalPrice,
 		SaleDate:   s.SaleDate,
 		CustomerID: s.CustomerID,
+		OrderID:    s.OrderID,
+		ItemName:   s.ItemName,
+		Category:   s.Category,
+		ImageURL:   s.ImageURL,
+		UnitPrice:  unitPrice,
+
+		TaxClass:    s.TaxClass,
+		TaxRate:     s.TaxRate,
+		Discount:    s.Discount,
+		NetAmount:   s.NetAmount,
+		TaxAmount:   s.TaxAmount,
+		GrossAmount: s.GrossAmount,
 	}
 }
 
@@ -53,5 +82,17 @@ func FromDomainSales(domainSales domain.Sales) Sales {
 		TotalPrice: domainSales.TotalPrice,
 		SaleDate:   domainSales.SaleDate,
 		CustomerID: domainSales.CustomerID,
+		OrderID:    domainSales.OrderID,
+		ItemName:   domainSales.ItemName,
+		Category:   domainSales.Category,
+		ImageURL:   domainSales.ImageURL,
+		UnitPrice:  domainSales.UnitPrice,
+
+		TaxClass:    domainSales.TaxClass,
+		TaxRate:     domainSales.TaxRate,
+		Discount:    domainSales.Discount,
+		NetAmount:   domainSales.NetAmount,
+		TaxAmount:   domainSales.TaxAmount,
+		GrossAmount: domainSales.GrossAmount,
 	}
 }
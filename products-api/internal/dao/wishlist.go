@@ -0,0 +1,58 @@
+package dao
+
+import (
+	"products-api/internal/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WishlistItem struct {
+	ItemID     string    `bson:"item_id"`
+	PriceAtAdd float64   `bson:"price_at_add,omitempty"`
+	AddedAt    time.Time `bson:"added_at"`
+}
+
+// Wishlist es una lista de deseos; el nombre es único por cliente
+// share_token se omite mientras la lista no está compartida (índice único sparse)
+type Wishlist struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	CustomerID int                `bson:"customer_id"`
+	Name       string             `bson:"name"`
+	Items      []WishlistItem     `bson:"items"`
+	ShareToken string             `bson:"share_token,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at"`
+	UpdatedAt  time.Time          `bson:"updated_at"`
+}
+
+func (w Wishlist) ToDomain() domain.Wishlist {
+	items := make([]domain.WishlistItem, len(w.Items))
+	for i, item := range w.Items {
+		items[i] = domain.WishlistItem{
+			ItemID:     item.ItemID,
+			PriceAtAdd: item.PriceAtAdd,
+			AddedAt:    item.AddedAt,
+		}
+	}
+	return domain.Wishlist{
+		ID:         w.ID.Hex(),
+		CustomerID: w.CustomerID,
+		Name:       w.Name,
+		Items:      items,
+		ShareToken: w.ShareToken,
+		CreatedAt:  w.CreatedAt,
+		UpdatedAt:  w.UpdatedAt,
+	}
+}
+
+func FromDomainWishlistItems(items []domain.WishlistItem) []WishlistItem {
+	itemsDAO := make([]WishlistItem, len(items))
+	for i, item := range items {
+		itemsDAO[i] = WishlistItem{
+			ItemID:     item.ItemID,
+			PriceAtAdd: item.PriceAtAdd,
+			AddedAt:    item.AddedAt,
+		}
+	}
+	return itemsDAO
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// Acciones que quedan en el registro de auditoría
+const (
+	AuditActionAccessDenied = "access_denied"
+)
+
+// AuditEvent es un intento de acceso registrado en el log de auditoría
+// CustomerID es el cliente cuyos datos se intentaron leer o modificar; Permission el permiso que faltó
+type AuditEvent struct {
+	ID         string    `json:"id"`
+	Action     string    `json:"action"`
+	UserID     int       `json:"user_id"`
+	IsAdmin    bool      `json:"is_admin"`
+	Role       string    `json:"role"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	CustomerID int       `json:"customer_id,omitempty"`
+	Permission string    `json:"permission,omitempty"`
+	ClientIP   string    `json:"client_ip"`
+	CreatedAt  time.Time `json:"created_at"`
+}
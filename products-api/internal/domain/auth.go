@@ -0,0 +1,53 @@
+package domain
+
+import "context"
+
+// ContextBearerToken es la clave del gin context donde VerifyToken deja el token de quien hace la request
+// Los clientes de otros servicios (users-api) lo reenvían para llamar en su nombre
+const ContextBearerToken = "bearer_token"
+
+// BearerTokenFrom devuelve el token de quien hace la request ("" si el context no viene de un request autenticado)
+// Funciona con el gin context y con cualquier context derivado de él
+func BearerTokenFrom(ctx context.Context) string {
+	token, _ := ctx.Value(ContextBearerToken).(string)
+	return token
+}
+
+// Permisos que users-api pone en el token según el rol de quien llama
+// sales:read y sales:write cubren ventas y órdenes; orders:write es despachar
+const (
+	PermItemsWrite   = "items:write"
+	PermPricingWrite = "pricing:write" // cupones y promociones
+	PermSalesRead    = "sales:read"
+	PermSalesWrite   = "sales:write"
+	PermOrdersWrite  = "orders:write"
+	PermReportsRead  = "reports:read"
+)
+
+// AuthClaims son los datos verificados del token de quien hace la request
+type AuthClaims struct {
+	UserID        int      `json:"user_id"`
+	IsAdmin       bool     `json:"is_admin"`
+	Role          string   `json:"role"`
+	Permissions   []string `json:"permissions"`
+	EmailVerified bool     `json:"email_verified"`
+}
+
+// HasPermission indica si quien llama tiene el permiso (los super admin los tienen todos)
+func (c AuthClaims) HasPermission(permission string) bool {
+	if c.IsAdmin {
+		return true
+	}
+	for _, p := range c.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccessCustomer indica si quien llama puede operar sobre los datos (carrito, ventas) de un cliente
+// Cada cliente solo accede a lo suyo; los admins acceden a todo
+func (c AuthClaims) CanAccessCustomer(customerID int) bool {
+	return c.IsAdmin || (c.UserID != 0 && c.UserID == customerID)
+}
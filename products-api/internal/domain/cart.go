@@ -5,19 +5,38 @@ import (
 )
 
 // CartItem representa un ítem individual dentro del carrito
+// PriceAtAdd es el precio que vio el cliente al agregarlo (o al aceptar los últimos cambios)
 type CartItem struct {
-	ItemID   string `json:"item_id" bson:"item_id"`
-	Quantity int    `json:"quantity" bson:"quantity"`
+	ItemID     string  `json:"item_id" bson:"item_id"`
+	Quantity   int     `json:"quantity" bson:"quantity"`
+	PriceAtAdd float64 `json:"price_at_add,omitempty" bson:"price_at_add,omitempty"`
 }
 
+// Cambios de una línea del carrito desde que el cliente agregó el producto
+// Mientras haya alguno el checkout se rechaza hasta que el cliente los acepte
+const (
+	CartLinePriceIncreased    = "price_increased"
+	CartLinePriceDecreased    = "price_decreased"
+	CartLineOutOfStock        = "out_of_stock"
+	CartLineInsufficientStock = "insufficient_stock" // Hay stock, pero menos que la cantidad del carrito
+	CartLineItemDeleted       = "item_deleted"
+)
+
 // Cart representa el carrito de compras de un usuario
+// Los carritos de invitados no tienen CustomerID: se identifican por SessionToken y vencen en ExpiresAt
 type Cart struct {
-	ID         string     `json:"id" bson:"_id,omitempty"`
-	CustomerID int        `json:"customer_id" bson:"customer_id"`
-	Items      []CartItem `json:"items" bson:"items"`
-	Total      float64    `json:"total" bson:"total"`
-	CreatedAt  time.Time  `json:"created_at" bson:"created_at"`
-	UpdatedAt  time.Time  `json:"updated_at" bson:"updated_at"`
+	ID           string     `json:"id" bson:"_id,omitempty"`
+	CustomerID   int        `json:"customer_id" bson:"customer_id"`
+	SessionToken string     `json:"session_token,omitempty" bson:"-"`
+	Items        []CartItem `json:"items" bson:"items"`
+	Total        float64    `json:"total" bson:"total"`
+	CouponCode   string     `json:"coupon_code,omitempty" bson:"coupon_code,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty" bson:"-"`
+	CreatedAt    time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" bson:"updated_at"`
+	// Recordatorios de carrito abandonado enviados desde la última modificación
+	ReminderCount  int        `json:"reminder_count,omitempty" bson:"reminder_count"`
+	LastReminderAt *time.Time `json:"last_reminder_at,omitempty" bson:"last_reminder_at,omitempty"`
 }
 
 // AddItemRequest representa la request para agregar un ítem al carrito
@@ -26,35 +45,90 @@ type AddItemRequest struct {
 	Quantity int    `json:"quantity" binding:"required,min=1"`
 }
 
+// MergeCartRequest pide pasar el carrito de invitado al carrito del cliente (al iniciar sesión)
+type MergeCartRequest struct {
+	GuestToken string `json:"guest_token" binding:"required"`
+}
+
 // UpdateItemRequest representa la request para actualizar un ítem del carrito
 type UpdateItemRequest struct {
 	Quantity int `json:"quantity" binding:"required,min=0"`
 }
 
 // CartResponse representa la respuesta del carrito con información enriquecida
+// Total es el subtotal menos los descuentos, con IVA (Taxes.Gross)
 type CartResponse struct {
-	ID         string                `json:"id"`
-	CustomerID int                   `json:"customer_id"`
-	Items      []CartItemWithDetails `json:"items"`
-	Total      float64               `json:"total"`
-	ItemCount  int                   `json:"item_count"`
-	CreatedAt  time.Time             `json:"created_at"`
-	UpdatedAt  time.Time             `json:"updated_at"`
+	ID            string                `json:"id"`
+	CustomerID    int                   `json:"customer_id"`
+	SessionToken  string                `json:"session_token,omitempty"` // Solo en los carritos de invitado
+	ExpiresAt     *time.Time            `json:"expires_at,omitempty"`    // Cuándo se borra el carrito de invitado si no se usa
+	Items         []CartItemWithDetails `json:"items"`
+	Subtotal      float64               `json:"subtotal"`
+	Discounts     []Discount            `json:"discounts"`
+	DiscountTotal float64               `json:"discount_total"`
+	Taxes         TaxSummary            `json:"taxes"`
+	Total         float64               `json:"total"`
+	ItemCount     int                   `json:"item_count"`
+	CouponCode    string                `json:"coupon_code,omitempty"`
+	CouponError   string                `json:"coupon_error,omitempty"` // Por qué el cupón aplicado no descuenta (vencido, mínimo no alcanzado...)
+	HasChanges    bool                  `json:"has_changes"`            // Alguna línea tiene cambios sin aceptar: el checkout se rechaza
+	CreatedAt     time.Time             `json:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at"`
 }
 
 // CartItemWithDetails incluye la información completa del producto
 type CartItemWithDetails struct {
-	ItemID      string  `json:"item_id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	ImageURL    string  `json:"image_url"`
-	Price       float64 `json:"price"`
-	Quantity    int     `json:"quantity"`
-	Subtotal    float64 `json:"subtotal"`
-	Stock       int     `json:"stock"` // Stock disponible del producto
+	ItemID          string       `json:"item_id"`
+	Name            string       `json:"name"`
+	Description     string       `json:"description"`
+	Category        string       `json:"category"`
+	ImageURL        string       `json:"image_url"`
+	Price           float64      `json:"price"`
+	Quantity        int          `json:"quantity"`
+	Subtotal        float64      `json:"subtotal"`
+	Discount        float64      `json:"discount"`                   // Descuento total que recibe esta línea
+	DiscountReasons []string     `json:"discount_reasons,omitempty"` // Qué promoción o cupón explica cada parte del descuento
+	Stock           int          `json:"stock"`                      // Stock disponible del producto
+	WeightKg        float64      `json:"weight_kg"`                  // Peso facturable por unidad, para el envío
+	TaxClass        string       `json:"tax_class"`
+	Tax             TaxBreakdown `json:"tax"`                    // Neto, IVA y bruto de la línea ya descontada
+	PriceAtAdd      float64      `json:"price_at_add,omitempty"` // Precio cuando se agregó al carrito
+	Changes         []string     `json:"changes,omitempty"`      // Cambios desde que se agregó (CartLine*)
+}
+
+// CartQuoteRequest pide cotizar el carrito para un destino y método de envío
+// CouponCode es opcional: sin el campo se usa el cupón aplicado al carrito, con "" se cotiza sin cupón,
+// con un código se cotiza con ese cupón sin aplicarlo al carrito
+type CartQuoteRequest struct {
+	ShippingRequest
+	CouponCode *string `json:"coupon_code,omitempty"`
+}
+
+// CartQuote es el detalle de lo que costaría el carrito: productos - descuentos + envío, con IVA
+// Se calcula con el mismo pipeline de precios que el checkout (promociones, cupón, IVA por línea, envío),
+// así Total es exactamente lo que se cobraría en un pago
+// El envío tributa a la alícuota general
+type CartQuote struct {
+	CustomerID      int                   `json:"customer_id"`
+	Items           []CartItemWithDetails `json:"items"` // Subtotal, descuento e IVA de cada línea
+	ItemCount       int                   `json:"item_count"`
+	Subtotal        float64               `json:"subtotal"`
+	Discounts       []Discount            `json:"discounts"`
+	DiscountTotal   float64               `json:"discount_total"`
+	CouponCode      string                `json:"coupon_code,omitempty"`
+	CouponError     string                `json:"coupon_error,omitempty"` // Por qué el cupón no aplica: con este error el checkout se rechaza
+	Shipping        ShippingQuote         `json:"shipping"`
+	ShippingTax     TaxBreakdown          `json:"shipping_tax"`
+	ShippingAddress *ShippingAddress      `json:"shipping_address,omitempty"`
+	Taxes           TaxSummary            `json:"taxes"`
+	Total           float64               `json:"total"`
+	Installments    []InstallmentOption   `json:"installments"`
+	HasChanges      bool                  `json:"has_changes"` // Hay cambios sin aceptar: el checkout se rechaza hasta aceptarlos
 }
 
 // CheckoutRequest representa la request para finalizar una compra
+// Los campos de envío (shipping_method, address_id, shipping_address) van en el primer nivel del body
 type CheckoutRequest struct {
-	// Podemos agregar más campos en el futuro (dirección, método de pago, etc.)
+	Payment PaymentMethod `json:"payment"`
+	ShippingRequest
 }
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"time"
+)
+
+// Tipos de eventos de carritos (routing keys del exchange topic de carritos)
+const (
+	CartEventAbandoned = "cart.abandoned"
+)
+
+// CartEvent es el mensaje que se publica cuando un carrito queda abandonado
+// ID es el carrito + el número de recordatorio, para que los consumidores descarten duplicados
+type CartEvent struct {
+	ID             string       `json:"id"`
+	Type           string       `json:"type"`
+	OccurredAt     time.Time    `json:"occurred_at"`
+	CustomerID     int          `json:"customer_id"`
+	ReminderNumber int          `json:"reminder_number"` // 1 para el primer recordatorio
+	IdleSince      time.Time    `json:"idle_since"`      // Última modificación del carrito
+	Cart           CartResponse `json:"cart"`
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"time"
+)
+
+// Tipos de descuento de un cupón
+const (
+	CouponTypePercentage = "percentage"
+	CouponTypeFixed      = "fixed"
+)
+
+// Coupon es un código de descuento (por ejemplo "MATE10": 10% off)
+// Los límites en 0 significan "sin límite". Categories e ItemIDs vacíos: aplica a todo el carrito
+type Coupon struct {
+	ID             string     `json:"id"`
+	Code           string     `json:"code"`
+	Description    string     `json:"description"`
+	Type           string     `json:"type"`
+	Value          float64    `json:"value"`
+	MinCartTotal   float64    `json:"min_cart_total"`
+	Categories     []string   `json:"categories"`
+	ItemIDs        []string   `json:"item_ids"`
+	StartsAt       *time.Time `json:"starts_at,omitempty"`
+	EndsAt         *time.Time `json:"ends_at,omitempty"`
+	MaxRedemptions int        `json:"max_redemptions"`
+	MaxPerCustomer int        `json:"max_per_customer"`
+	Redemptions    int        `json:"redemptions"`
+	Active         bool       `json:"active"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// CouponRedemption registra el uso de un cupón en una orden
+type CouponRedemption struct {
+	OrderID    string    `json:"order_id"`
+	CouponID   string    `json:"coupon_id"`
+	Code       string    `json:"code"`
+	CustomerID int       `json:"customer_id"`
+	Discount   float64   `json:"discount"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ApplyCouponRequest es el body para aplicar un cupón al carrito
+type ApplyCouponRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// Origen de un descuento
+const (
+	DiscountTypeCoupon    = "coupon"
+	DiscountTypePromotion = "promotion"
+)
+
+// Discount es un descuento aplicado a un carrito u orden: un cupón o una promoción automática
+// Lines indica cuánto del descuento corresponde a cada ítem y por qué
+type Discount struct {
+	Type        string         `json:"type"`
+	Code        string         `json:"code,omitempty"`
+	PromotionID string         `json:"promotion_id,omitempty"`
+	Description string         `json:"description"`
+	Amount      float64        `json:"amount"`
+	Lines       []LineDiscount `json:"lines"`
+}
+
+// LineDiscount es la parte de un descuento que corresponde a un ítem
+type LineDiscount struct {
+	ItemID string  `json:"item_id"`
+	Amount float64 `json:"amount"`
+	Reason string  `json:"reason"`
+}
+
+// DiscountLine es la información mínima de una línea que necesita el motor de descuentos
+// Subtotal es lo que queda por pagar de la línea: los cupones se calculan después de las promociones
+type DiscountLine struct {
+	ItemID    string
+	Category  string
+	UnitPrice float64
+	Quantity  int
+	Subtotal  float64
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+)
+
+// Estados de una clave de idempotencia
+const (
+	IdempotencyStatusInProgress = "in_progress"
+	IdempotencyStatusCompleted  = "completed"
+)
+
+// IdempotencyRecord guarda el resultado de un request identificado por su Idempotency-Key
+// para poder devolver la misma respuesta si el cliente reintenta
+// LockedUntil es el lease del request en curso: si vence sin que termine, un reintento puede tomar la clave
+type IdempotencyRecord struct {
+	Key          string    `json:"key"`
+	Fingerprint  string    `json:"fingerprint"` // hash del request original (método, ruta y body)
+	Status       string    `json:"status"`
+	StatusCode   int       `json:"status_code"`
+	ContentType  string    `json:"content_type"`
+	ResponseBody []byte    `json:"response_body"`
+	LockedUntil  time.Time `json:"locked_until"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
@@ -5,15 +5,37 @@ import (
 )
 
 type Item struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	Category    string    `json:"category"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
-	Stock       int       `json:"stock"`
-	ImageURL    string    `json:"image_url"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Category    string     `json:"category"`
+	Description string     `json:"description"`
+	Price       float64    `json:"price"`
+	Stock       int        `json:"stock"`
+	ImageURL    string     `json:"image_url"`
+	WeightKg    float64    `json:"weight_kg"`
+	Dimensions  Dimensions `json:"dimensions"`
+	TaxClass    string     `json:"tax_class"` // general (21%), reduced (10,5%) o exempt; vacío es general
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Dimensions son las medidas del producto embalado, en centímetros
+type Dimensions struct {
+	LengthCm float64 `json:"length_cm"`
+	WidthCm  float64 `json:"width_cm"`
+	HeightCm float64 `json:"height_cm"`
+}
+
+// volumetricDivisor es el divisor que usan los correos para el peso volumétrico (cm³/kg)
+const volumetricDivisor = 5000
+
+// BillableWeightKg es el peso que se cobra en el envío: el mayor entre el real y el volumétrico
+func (i Item) BillableWeightKg() float64 {
+	volumetric := i.Dimensions.LengthCm * i.Dimensions.WidthCm * i.Dimensions.HeightCm / volumetricDivisor
+	if volumetric > i.WeightKg {
+		return volumetric
+	}
+	return i.WeightKg
 }
 
 type PaginatedResponse struct {
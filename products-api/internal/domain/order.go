@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Estados posibles de una orden
+const (
+	OrderStatusPendingPayment = "pending_payment"
+	OrderStatusPaid           = "paid"
+	OrderStatusShipped        = "shipped"
+	OrderStatusCancelled      = "cancelled"
+	OrderStatusRefunded       = "refunded"
+)
+
+// orderTransitions son los cambios de estado permitidos de una orden
+// cancelled y refunded son finales. Una orden cobrada que la saga no llegó a marcar como pagada puede reintegrarse directo
+var orderTransitions = map[string][]string{
+	OrderStatusPendingPayment: {OrderStatusPaid, OrderStatusCancelled, OrderStatusRefunded},
+	OrderStatusPaid:           {OrderStatusShipped, OrderStatusCancelled, OrderStatusRefunded},
+	OrderStatusShipped:        {OrderStatusRefunded},
+}
+
+// CanMoveOrder indica si una orden puede pasar del estado from al estado to
+func CanMoveOrder(from, to string) bool {
+	return slices.Contains(orderTransitions[from], to)
+}
+
+// OrderLine representa una línea de la orden con el snapshot del item al momento de la compra
+type OrderLine struct {
+	ItemID    string  `json:"item_id"`
+	ItemName  string  `json:"item_name"`
+	Category  string  `json:"category"`
+	ImageURL  string  `json:"image_url"`
+	UnitPrice float64 `json:"unit_price"`
+	Quantity  int     `json:"quantity"`
+	Subtotal  float64 `json:"subtotal"`
+
+	// IVA de la línea, calculado sobre el subtotal menos los descuentos
+	TaxClass    string  `json:"tax_class"`
+	TaxRate     float64 `json:"tax_rate"`
+	Discount    float64 `json:"discount"`
+	NetAmount   float64 `json:"net_amount"`
+	TaxAmount   float64 `json:"tax_amount"`
+	GrossAmount float64 `json:"gross_amount"`
+}
+
+// Order agrupa las ventas generadas por un checkout
+// Total es el subtotal de las líneas menos los descuentos más el costo de envío, con IVA (Taxes.Gross)
+type Order struct {
+	ID              string           `json:"id"`
+	CustomerID      int              `json:"customer_id"`
+	Status          string           `json:"status"`
+	Items           []OrderLine      `json:"items"`
+	Subtotal        float64          `json:"subtotal"`
+	CouponCode      string           `json:"coupon_code,omitempty"`
+	Discounts       []Discount       `json:"discounts"`
+	DiscountTotal   float64          `json:"discount_total"`
+	Shipping        ShippingQuote    `json:"shipping"`
+	ShippingAddress *ShippingAddress `json:"shipping_address,omitempty"`
+	Taxes           TaxSummary       `json:"taxes"`
+	Total           float64          `json:"total"`
+	SaleIDs         []string         `json:"sale_ids"`
+	PaymentID       string           `json:"payment_id,omitempty"`
+	TrackingNumber  string           `json:"tracking_number,omitempty"`
+	ShippedAt       *time.Time       `json:"shipped_at,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+// ShipOrderRequest marca una orden pagada como despachada
+type ShipOrderRequest struct {
+	TrackingNumber string `json:"tracking_number"`
+}
+
+// CheckoutResult es la respuesta de un checkout exitoso
+type CheckoutResult struct {
+	Order Order   `json:"order"`
+	Sales []Sales `json:"sales"`
+}
+
+// Estados posibles de la saga de checkout
+const (
+	SagaStatusRunning      = "running"
+	SagaStatusCompleted    = "completed"
+	SagaStatusCompensating = "compensating"
+	SagaStatusCompensated  = "compensated"
+)
+
+// Pasos de la saga de checkout, en el orden en que se ejecutan
+const (
+	SagaStepReserveStock   = "reserve_stock"
+	SagaStepCreateOrder    = "create_order"
+	SagaStepRedeemCoupon   = "redeem_coupon"
+	SagaStepCapturePayment = "capture_payment"
+	SagaStepClearCart      = "clear_cart"
+	SagaStepConfirmStock   = "confirm_stock"
+)
+
+// SagaLine representa un ítem del carrito dentro de la saga
+// ReservationID se guarda antes de descontar el stock: con esa clave reservar y liberar son idempotentes
+// Reserved indica si ya se confirmó el descuento del stock de esta línea
+// WeightKg es el peso facturable de una unidad, para calcular el envío
+type SagaLine struct {
+	OrderLine
+	WeightKg      float64 `json:"weight_kg"`
+	ReservationID string  `json:"reservation_id,omitempty"`
+	Reserved      bool    `json:"reserved"`
+}
+
+// DiscountLine devuelve la línea en el formato del motor de descuentos
+func (l SagaLine) DiscountLine() DiscountLine {
+	return DiscountLine{ItemID: l.ItemID, Category: l.Category, UnitPrice: l.UnitPrice, Quantity: l.Quantity, Subtotal: l.Subtotal}
+}
+
+// CheckoutSaga es el registro persistido de un checkout en curso o terminado
+// Version aumenta en cada escritura: una instancia que perdió el lease no puede pisar el estado de otra
+type CheckoutSaga struct {
+	ID              string           `json:"id"`
+	CustomerID      int              `json:"customer_id"`
+	Status          string           `json:"status"`
+	Lines           []SagaLine       `json:"lines"`
+	CompletedSteps  []string         `json:"completed_steps"`
+	OrderID         string           `json:"order_id"`
+	SaleIDs         []string         `json:"sale_ids"`
+	PaymentID       string           `json:"payment_id,omitempty"`
+	ShippingMethod  string           `json:"shipping_method"`
+	ShippingAddress *ShippingAddress `json:"shipping_address,omitempty"`
+	CouponCode      string           `json:"coupon_code,omitempty"`
+	Error           string           `json:"error,omitempty"`
+	LockedUntil     time.Time        `json:"locked_until"`
+	Version         int              `json:"version"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+// HasCompleted indica si el paso ya se ejecutó correctamente
+func (s CheckoutSaga) HasCompleted(step string) bool {
+	for _, done := range s.CompletedSteps {
+		if done == step {
+			return true
+		}
+	}
+	return false
+}
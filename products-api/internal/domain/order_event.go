@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"time"
+)
+
+// Tipos de eventos del ciclo de vida de una orden
+// Se usan como routing key en el exchange topic de órdenes: los consumidores se suscriben con "order.*" o a uno puntual
+const (
+	OrderEventCreated   = "order.created"
+	OrderEventPaid      = "order.paid"
+	OrderEventCancelled = "order.cancelled"
+	OrderEventShipped   = "order.shipped"
+	OrderEventRefunded  = "order.refunded"
+)
+
+// OrderEvent es el mensaje que se publica en cada cambio de estado de una orden
+// ID es el mismo en cada reintento de publicación (orden + tipo), para que los consumidores puedan descartar duplicados
+type OrderEvent struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Order      Order     `json:"order"`
+}
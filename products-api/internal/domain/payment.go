@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Estados posibles de un pago
+const (
+	PaymentStatusAuthorized = "authorized"
+	PaymentStatusCaptured   = "captured"
+	PaymentStatusRefunded   = "refunded"
+	PaymentStatusVoided     = "voided"
+	PaymentStatusFailed     = "failed"
+)
+
+// paymentTransitions son los cambios de estado permitidos de un pago
+// refunded, voided y failed son finales: un webhook atrasado o repetido no los cambia
+var paymentTransitions = map[string][]string{
+	PaymentStatusAuthorized: {PaymentStatusCaptured, PaymentStatusVoided, PaymentStatusFailed},
+	PaymentStatusCaptured:   {PaymentStatusRefunded},
+}
+
+// CanMovePayment indica si un pago puede pasar del estado from al estado to
+func CanMovePayment(from, to string) bool {
+	return slices.Contains(paymentTransitions[from], to)
+}
+
+// Tipos de eventos que el proveedor de pagos envía por webhook
+const (
+	PaymentEventCaptured = "payment.captured"
+	PaymentEventRefunded = "payment.refunded"
+	PaymentEventFailed   = "payment.failed"
+)
+
+// PaymentMethod son los datos de pago que envía el cliente en el checkout
+// Nunca se persisten: solo viajan al proveedor de pagos
+type PaymentMethod struct {
+	CardNumber  string `json:"card_number"`
+	CardHolder  string `json:"card_holder"`
+	ExpiryMonth int    `json:"expiry_month"`
+	ExpiryYear  int    `json:"expiry_year"`
+	CVV         string `json:"cvv"`
+}
+
+// PaymentRequest es lo que se le pide autorizar al proveedor de pagos
+type PaymentRequest struct {
+	OrderID    string        `json:"order_id"`
+	CustomerID int           `json:"customer_id"`
+	Amount     float64       `json:"amount"`
+	Currency   string        `json:"currency"`
+	Method     PaymentMethod `json:"-"`
+}
+
+// Payment representa un pago registrado en el proveedor
+type Payment struct {
+	ID         string    `json:"id"`
+	OrderID    string    `json:"order_id"`
+	CustomerID int       `json:"customer_id"`
+	Amount     float64   `json:"amount"`
+	Currency   string    `json:"currency"`
+	Status     string    `json:"status"`
+	Provider   string    `json:"provider"`
+	CardLast4  string    `json:"card_last4"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// PaymentEvent es una notificación asíncrona del proveedor de pagos (webhook)
+type PaymentEvent struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	PaymentID string    `json:"payment_id"`
+	Amount    float64   `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// InstallmentPlan es un plan de cuotas con tarjeta: cantidad de cuotas y recargo sobre el total (0.10 = 10%)
+type InstallmentPlan struct {
+	Installments int     `json:"installments"`
+	InterestRate float64 `json:"interest_rate"`
+}
+
+// InstallmentOption es lo que pagaría el cliente con un plan de cuotas para un total dado
+// Total incluye el recargo; la última cuota ajusta los centavos del redondeo de InstallmentAmount
+type InstallmentOption struct {
+	Installments      int     `json:"installments"`
+	InterestRate      float64 `json:"interest_rate"`
+	InterestFree      bool    `json:"interest_free"`
+	InstallmentAmount float64 `json:"installment_amount"`
+	Total             float64 `json:"total"`
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+)
+
+// Tipos de promoción automática
+const (
+	PromotionTypePercentage = "percentage"  // X% off en los productos elegibles
+	PromotionTypeBuyXGetY   = "buy_x_get_y" // Llevando BuyQuantity, GetQuantity unidades más salen gratis (o con Percentage off)
+)
+
+// Promotion es una regla de descuento que se aplica sola, sin código (por ejemplo "2x1 en bombillas")
+// Se evalúan de mayor a menor Priority. Una promoción no acumulable (Stackable=false) no se aplica
+// sobre líneas que ya tienen otra promoción, y las líneas que descuenta no reciben más promociones
+// Weekdays usa 0=domingo ... 6=sábado; vacío significa todos los días
+type Promotion struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Type        string     `json:"type"`
+	Percentage  float64    `json:"percentage"`
+	BuyQuantity int        `json:"buy_quantity"`
+	GetQuantity int        `json:"get_quantity"`
+	Categories  []string   `json:"categories"`
+	ItemIDs     []string   `json:"item_ids"`
+	Weekdays    []int      `json:"weekdays"`
+	StartsAt    *time.Time `json:"starts_at,omitempty"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+	Priority    int        `json:"priority"`
+	Stackable   bool       `json:"stackable"`
+	Active      bool       `json:"active"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+)
+
+// ReportFilters representa los parámetros comunes de los reportes de ventas
+type ReportFilters struct {
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	Granularity string    `json:"granularity"` // "day", "week", "month"
+	Limit       int       `json:"limit"`
+}
+
+// RevenuePoint representa la facturación y unidades vendidas en un período
+type RevenuePoint struct {
+	Period  time.Time `json:"period"`
+	Revenue float64   `json:"revenue"`
+	Units   int       `json:"units"`
+	Orders  int       `json:"orders"`
+}
+
+// TopProduct representa un producto del ranking de más vendidos
+type TopProduct struct {
+	ItemID  string  `json:"item_id"`
+	Name    string  `json:"name"`
+	Units   int     `json:"units"`
+	Revenue float64 `json:"revenue"`
+}
+
+// CategoryRevenue representa la facturación agrupada por categoría
+type CategoryRevenue struct {
+	Category string  `json:"category"`
+	Revenue  float64 `json:"revenue"`
+	Units    int     `json:"units"`
+}
+
+// SalesSummary resume las métricas generales de un rango de fechas
+type SalesSummary struct {
+	From               time.Time `json:"from"`
+	To                 time.Time `json:"to"`
+	Revenue            float64   `json:"revenue"`
+	Units              int       `json:"units"`
+	Orders             int       `json:"orders"`
+	AverageOrderValue  float64   `json:"average_order_value"`
+	Customers          int       `json:"customers"`
+	RepeatCustomers    int       `json:"repeat_customers"`
+	RepeatCustomerRate float64   `json:"repeat_customer_rate"`
+}
@@ -11,6 +11,21 @@ type Sales struct {
 	TotalPrice float64   `json:"total_price"`
 	SaleDate   time.Time `json:"sale_date"`
 	CustomerID int       `json:"customer_id"`
+	OrderID    string    `json:"order_id,omitempty"` // Orden del checkout que generó la venta (vacío en ventas directas)
+
+	// Snapshot del item al momento de la compra (no cambia si el item se edita o se elimina)
+	ItemName  string  `json:"item_name"`
+	Category  string  `json:"category"`
+	ImageURL  string  `json:"image_url"`
+	UnitPrice float64 `json:"unit_price"`
+
+	// IVA de la venta para conciliar con contabilidad: NetAmount + TaxAmount = GrossAmount
+	TaxClass    string  `json:"tax_class"`
+	TaxRate     float64 `json:"tax_rate"`
+	Discount    float64 `json:"discount"`
+	NetAmount   float64 `json:"net_amount"`
+	TaxAmount   float64 `json:"tax_amount"`
+	GrossAmount float64 `json:"gross_amount"`
 }
 
 type ValidationResult struct {
@@ -0,0 +1,59 @@
+package domain
+
+// Métodos de envío disponibles
+const (
+	ShippingMethodStandard = "standard"
+	ShippingMethodExpress  = "express"
+	ShippingMethodPickup   = "pickup"
+)
+
+// ShippingAddress es la dirección de entrega de una orden
+type ShippingAddress struct {
+	RecipientName string `json:"recipient_name"`
+	Street        string `json:"street"`
+	Number        string `json:"number"`
+	Apartment     string `json:"apartment,omitempty"`
+	City          string `json:"city"`
+	Province      string `json:"province"`
+	PostalCode    string `json:"postal_code"`
+	Phone         string `json:"phone,omitempty"`
+}
+
+// ShippingRequest indica a dónde y cómo se envía una compra
+// Se puede mandar la dirección completa o el ID de una dirección guardada del usuario
+type ShippingRequest struct {
+	Method    string           `json:"shipping_method"`
+	AddressID int              `json:"address_id,omitempty"`
+	Address   *ShippingAddress `json:"shipping_address,omitempty"`
+}
+
+// ShippingQuote es el costo de envío calculado para una compra
+type ShippingQuote struct {
+	Method        string  `json:"method"`
+	Zone          string  `json:"zone,omitempty"`
+	WeightKg      float64 `json:"weight_kg"`
+	Cost          float64 `json:"cost"`
+	EstimatedDays int     `json:"estimated_days"`
+}
+
+// ShippingRate es una fila de la tabla de tarifas: costo por zona, método y peso máximo
+type ShippingRate struct {
+	Zone          string  `json:"zone"`
+	Method        string  `json:"method"`
+	MaxWeightKg   float64 `json:"max_weight_kg"`
+	Cost          float64 `json:"cost"`
+	EstimatedDays int     `json:"estimated_days"`
+}
+
+// ShippingZone agrupa provincias y rangos de códigos postales bajo una misma tarifa
+type ShippingZone struct {
+	Name        string            `json:"name"`
+	Provinces   []string          `json:"provinces"`
+	PostalCodes []PostalCodeRange `json:"postal_codes"`
+}
+
+// PostalCodeRange es un rango de códigos postales numéricos (inclusive)
+type PostalCodeRange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
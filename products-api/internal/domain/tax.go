@@ -0,0 +1,28 @@
+package domain
+
+// Clases de IVA de un producto
+const (
+	TaxClassGeneral = "general" // 21%
+	TaxClassReduced = "reduced" // 10,5%
+	TaxClassExempt  = "exempt"  // Exento
+)
+
+// TaxBreakdown separa un importe en neto, IVA y total (bruto)
+// Se usa por línea y también para los totales por alícuota
+type TaxBreakdown struct {
+	TaxClass string  `json:"tax_class"`
+	Rate     float64 `json:"rate"`
+	Net      float64 `json:"net"`
+	Tax      float64 `json:"tax"`
+	Gross    float64 `json:"gross"`
+}
+
+// TaxSummary es el resumen de IVA de un carrito u orden
+// PricesIncludeTax indica si los precios del catálogo ya tenían el IVA incluido
+type TaxSummary struct {
+	PricesIncludeTax bool           `json:"prices_include_tax"`
+	Net              float64        `json:"net"`
+	Tax              float64        `json:"tax"`
+	Gross            float64        `json:"gross"`
+	ByClass          []TaxBreakdown `json:"by_class"`
+}
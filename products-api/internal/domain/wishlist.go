@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+// WishlistItem es un producto guardado en una lista de deseos
+// PriceAtAdd es el precio cuando se guardó, para avisar si bajó o subió
+type WishlistItem struct {
+	ItemID     string    `json:"item_id"`
+	PriceAtAdd float64   `json:"price_at_add,omitempty"`
+	AddedAt    time.Time `json:"added_at"`
+}
+
+// Wishlist es una lista de deseos con nombre de un cliente (puede tener varias)
+// ShareToken permite verla sin login y en solo lectura; vacío si no está compartida
+type Wishlist struct {
+	ID         string         `json:"id"`
+	CustomerID int            `json:"customer_id"`
+	Name       string         `json:"name"`
+	Items      []WishlistItem `json:"items"`
+	ShareToken string         `json:"share_token,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// WishlistRequest crea o renombra una lista de deseos
+type WishlistRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// AddWishlistItemRequest guarda un producto en una lista de deseos
+type AddWishlistItemRequest struct {
+	ItemID string `json:"item_id" binding:"required"`
+}
+
+// MoveToCartRequest pasa un producto de la lista al carrito (por defecto 1 unidad)
+type MoveToCartRequest struct {
+	Quantity int `json:"quantity" binding:"omitempty,min=1"`
+}
+
+// MoveToWishlistRequest pasa una línea del carrito a una lista de deseos
+type MoveToWishlistRequest struct {
+	WishlistID string `json:"wishlist_id" binding:"required"`
+}
+
+// WishlistResponse es la lista con el precio y el stock actuales de cada producto
+// Los ítems se enriquecen igual que las líneas del carrito (cantidad 1); los productos eliminados quedan marcados con item_deleted
+type WishlistResponse struct {
+	ID         string                `json:"id"`
+	CustomerID int                   `json:"customer_id,omitempty"` // No se muestra en la vista compartida
+	Name       string                `json:"name"`
+	Items      []CartItemWithDetails `json:"items"`
+	ItemCount  int                   `json:"item_count"`
+	ShareToken string                `json:"share_token,omitempty"` // No se muestra en la vista compartida
+	Shared     bool                  `json:"shared"`
+	ReadOnly   bool                  `json:"read_only"`
+	CreatedAt  time.Time             `json:"created_at"`
+	UpdatedAt  time.Time             `json:"updated_at"`
+}
+
+// WishlistMoveResult devuelve cómo quedaron el carrito y la lista después de mover un producto
+type WishlistMoveResult struct {
+	Cart     CartResponse     `json:"cart"`
+	Wishlist WishlistResponse `json:"wishlist"`
+}
@@ -9,7 +9,7 @@ import (
 func CORSMiddleware(ctx *gin.Context) {
 	ctx.Header("Access-Control-Allow-Origin", "*")
 	ctx.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-	ctx.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	ctx.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key, X-Cart-Session")
 
 	if ctx.Request.Method == http.MethodOptions {
 		ctx.Status(http.StatusNoContent)
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"products-api/internal/dao"
+	"products-api/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoAuditRepository guarda el registro de auditoría en MongoDB
+// Los eventos solo se insertan: no hay update ni delete
+type MongoAuditRepository struct {
+	col *mongo.Collection
+}
+
+// NewMongoAuditRepository crea una nueva instancia del repository y se conecta a MongoDB
+func NewMongoAuditRepository(ctx context.Context, uri, dbName, collectionName string) *MongoAuditRepository {
+	opt := options.Client().ApplyURI(uri)
+	opt.SetServerSelectionTimeout(10 * time.Second)
+
+	client, err := mongo.Connect(ctx, opt)
+	if err != nil {
+		log.Fatalf("Error connecting to DB: %v", err)
+		return nil
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := client.Ping(pingCtx, nil); err != nil {
+		log.Fatalf("Error pinging DB: %v", err)
+		return nil
+	}
+
+	col := client.Database(dbName).Collection(collectionName)
+
+	// Índice para buscar los intentos de un usuario ordenados por fecha
+	indexModel := mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}}
+	if _, err := col.Indexes().CreateOne(ctx, indexModel); err != nil {
+		log.Printf("Warning: Could not create index on audit log: %v", err)
+	}
+
+	return &MongoAuditRepository{col: col}
+}
+
+// Create inserta un evento de auditoría
+func (r *MongoAuditRepository) Create(ctx context.Context, event domain.AuditEvent) (domain.AuditEvent, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	eventDAO := dao.FromDomainAuditEvent(event)
+	eventDAO.ID = primitive.NewObjectID()
+	if _, err := r.col.InsertOne(ctx, eventDAO); err != nil {
+		return domain.AuditEvent{}, err
+	}
+	return eventDAO.ToDomain(), nil
+}
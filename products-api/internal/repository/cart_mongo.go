@@ -94,9 +94,13 @@ func (r *MongoCartRepository) Update(ctx context.Context, customerID int, cart d
 	filter := bson.M{"customer_id": customerID}
 	update := bson.M{
 		"$set": bson.M{
-			"items":      cartDAO.Items,
-			"total":      cartDAO.Total,
-			"updated_at": cartDAO.UpdatedAt,
+			"items":       cartDAO.Items,
+			"total":       cartDAO.Total,
+			"coupon_code": cartDAO.CouponCode,
+			"updated_at":  cartDAO.UpdatedAt,
+			// El cliente volvió al carrito: si lo abandona de nuevo los recordatorios empiezan de cero
+			"reminder_count":   0,
+			"last_reminder_at": nil,
 		},
 	}
 
@@ -129,9 +133,13 @@ func (r *MongoCartRepository) Upsert(ctx context.Context, cart domain.Cart) (dom
 	filter := bson.M{"customer_id": cart.CustomerID}
 	update := bson.M{
 		"$set": bson.M{
-			"items":      cartDAO.Items,
-			"total":      cartDAO.Total,
-			"updated_at": cartDAO.UpdatedAt,
+			"items":       cartDAO.Items,
+			"total":       cartDAO.Total,
+			"coupon_code": cartDAO.CouponCode,
+			"updated_at":  cartDAO.UpdatedAt,
+			// El cliente volvió al carrito: si lo abandona de nuevo los recordatorios empiezan de cero
+			"reminder_count":   0,
+			"last_reminder_at": nil,
 		},
 		"$setOnInsert": bson.M{
 			"customer_id": cart.CustomerID,
@@ -157,8 +165,9 @@ func (r *MongoCartRepository) daoToDomain(cartDAO dao.CartDAO) domain.Cart {
 	items := make([]domain.CartItem, len(cartDAO.Items))
 	for i, item := range cartDAO.Items {
 		items[i] = domain.CartItem{
-			ItemID:   item.ItemID,
-			Quantity: item.Quantity,
+			ItemID:     item.ItemID,
+			Quantity:   item.Quantity,
+			PriceAtAdd: item.PriceAtAdd,
 		}
 	}
 
@@ -167,8 +176,12 @@ func (r *MongoCartRepository) daoToDomain(cartDAO dao.CartDAO) domain.Cart {
 		CustomerID: cartDAO.CustomerID,
 		Items:      items,
 		Total:      cartDAO.Total,
+		CouponCode: cartDAO.CouponCode,
 		CreatedAt:  cartDAO.CreatedAt,
 		UpdatedAt:  cartDAO.UpdatedAt,
+
+		ReminderCount:  cartDAO.ReminderCount,
+		LastReminderAt: cartDAO.LastReminderAt,
 	}
 }
 
@@ -177,8 +190,9 @@ func (r *MongoCartRepository) domainToDAO(cart domain.Cart) dao.CartDAO {
 	items := make([]dao.CartItemDAO, len(cart.Items))
 	for i, item := range cart.Items {
 		items[i] = dao.CartItemDAO{
-			ItemID:   item.ItemID,
-			Quantity: item.Quantity,
+			ItemID:     item.ItemID,
+			Quantity:   item.Quantity,
+			PriceAtAdd: item.PriceAtAdd,
 		}
 	}
 
@@ -192,7 +206,79 @@ func (r *MongoCartRepository) domainToDAO(cart domain.Cart) dao.CartDAO {
 		CustomerID: cart.CustomerID,
 		Items:      items,
 		Total:      cart.Total,
+		CouponCode: cart.CouponCode,
 		CreatedAt:  cart.CreatedAt,
 		UpdatedAt:  cart.UpdatedAt,
+
+		ReminderCount:  cart.ReminderCount,
+		LastReminderAt: cart.LastReminderAt,
+	}
+}
+
+// FindAbandoned busca los carritos con productos que no se modifican desde idleSince
+// y que todavía pueden recibir un recordatorio (menos de maxReminders y el último antes de remindBefore)
+func (r *MongoCartRepository) FindAbandoned(ctx context.Context, idleSince, remindBefore time.Time, maxReminders, limit int) ([]domain.Cart, error) {
+	filter := bson.M{
+		"customer_id": bson.M{"$gt": 0},
+		"items.0":     bson.M{"$exists": true},
+		"updated_at":  bson.M{"$lt": idleSince},
+		// $not también incluye los carritos anteriores a los recordatorios (sin reminder_count)
+		"reminder_count": bson.M{"$not": bson.M{"$gte": maxReminders}},
+		"$or": bson.A{
+			bson.M{"last_reminder_at": nil},
+			bson.M{"last_reminder_at": bson.M{"$lt": remindBefore}},
+		},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updated_at", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cur, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding abandoned carts: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	var cartsDAO []dao.CartDAO
+	if err := cur.All(ctx, &cartsDAO); err != nil {
+		return nil, fmt.Errorf("error decoding abandoned carts: %w", err)
+	}
+
+	carts := make([]domain.Cart, len(cartsDAO))
+	for i, cartDAO := range cartsDAO {
+		carts[i] = r.daoToDomain(cartDAO)
+	}
+	return carts, nil
+}
+
+// MarkReminded registra un recordatorio enviado sin tocar updated_at
+// Solo actualiza si el carrito no cambió desde que se leyó; devuelve false si el cliente lo modificó en el medio
+func (r *MongoCartRepository) MarkReminded(ctx context.Context, cart domain.Cart, at time.Time) (bool, error) {
+	filter := bson.M{
+		"customer_id":    cart.CustomerID,
+		"updated_at":     cart.UpdatedAt,
+		"reminder_count": bson.M{"$not": bson.M{"$gt": cart.ReminderCount}},
+	}
+	update := bson.M{
+		"$set": bson.M{"reminder_count": cart.ReminderCount + 1, "last_reminder_at": at},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("error marking cart reminder: %w", err)
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// PurgeEmpty elimina los carritos vacíos que no se modifican desde before
+func (r *MongoCartRepository) PurgeEmpty(ctx context.Context, before time.Time) (int64, error) {
+	filter := bson.M{
+		"items":      bson.M{"$size": 0},
+		"updated_at": bson.M{"$lt": before},
+	}
+	result, err := r.collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("error purging empty carts: %w", err)
 	}
+	return result.DeletedCount, nil
 }
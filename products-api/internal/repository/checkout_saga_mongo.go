@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"products-api/internal/dao"
+	"products-api/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoCheckoutSagaRepository persiste el estado de las sagas de checkout
+type MongoCheckoutSagaRepository struct {
+	col *mongo.Collection
+}
+
+// NewMongoCheckoutSagaRepository crea una nueva instancia del repository y se conecta a MongoDB
+func NewMongoCheckoutSagaRepository(ctx context.Context, uri, dbName, collectionName string) *MongoCheckoutSagaRepository {
+	opt := options.Client().ApplyURI(uri)
+	opt.SetServerSelectionTimeout(10 * time.Second)
+
+	client, err := mongo.Connect(ctx, opt)
+	if err != nil {
+		log.Fatalf("Error connecting to DB: %v", err)
+		return nil
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := client.Ping(pingCtx, nil); err != nil {
+		log.Fatalf("Error pinging DB: %v", err)
+		return nil
+	}
+
+	col := client.Database(dbName).Collection(collectionName)
+
+	// Índice para que el worker de recuperación encuentre rápido las sagas pendientes
+	indexModel := mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: "locked_until", Value: 1}}}
+	if _, err := col.Indexes().CreateOne(ctx, indexModel); err != nil {
+		log.Printf("Warning: Could not create index on checkout sagas: %v", err)
+	}
+
+	return &MongoCheckoutSagaRepository{col: col}
+}
+
+// Create inserta una nueva saga
+func (r *MongoCheckoutSagaRepository) Create(ctx context.Context, saga domain.CheckoutSaga) (domain.CheckoutSaga, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	sagaDAO := dao.FromDomainCheckoutSaga(saga)
+	sagaDAO.ID = primitive.NewObjectID()
+	now := time.Now().UTC()
+	sagaDAO.CreatedAt = now
+	sagaDAO.UpdatedAt = now
+	sagaDAO.Version = 1
+
+	if _, err := r.col.InsertOne(ctx, sagaDAO); err != nil {
+		return domain.CheckoutSaga{}, err
+	}
+
+	return sagaDAO.ToDomain(), nil
+}
+
+// Update guarda el estado actual de la saga si nadie la modificó desde que se leyó (control optimista por version)
+// Si otra instancia la tomó (ClaimStale) o la escribió, devuelve un error "modified concurrently"
+func (r *MongoCheckoutSagaRepository) Update(ctx context.Context, saga domain.CheckoutSaga) (domain.CheckoutSaga, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	sagaDAO := dao.FromDomainCheckoutSaga(saga)
+	if sagaDAO.ID.IsZero() {
+		return domain.CheckoutSaga{}, errors.New("invalid ObjectID format")
+	}
+	sagaDAO.UpdatedAt = time.Now().UTC()
+	sagaDAO.Version = saga.Version + 1
+
+	result, err := r.col.ReplaceOne(ctx, bson.M{"_id": sagaDAO.ID, "version": saga.Version}, sagaDAO)
+	if err != nil {
+		return domain.CheckoutSaga{}, err
+	}
+	if result.MatchedCount == 0 {
+		count, err := r.col.CountDocuments(ctx, bson.M{"_id": sagaDAO.ID})
+		if err != nil {
+			return domain.CheckoutSaga{}, err
+		}
+		if count == 0 {
+			return domain.CheckoutSaga{}, errors.New("checkout saga not found")
+		}
+		return domain.CheckoutSaga{}, errors.New("checkout saga modified concurrently")
+	}
+
+	return sagaDAO.ToDomain(), nil
+}
+
+// ClaimStale toma una saga sin terminar cuyo lease haya vencido y la bloquea por leaseDuration
+// Sube la version, así la instancia que tenía el lease ya no puede guardar cambios
+// Devuelve found=false si no hay sagas pendientes
+func (r *MongoCheckoutSagaRepository) ClaimStale(ctx context.Context, leaseDuration time.Duration) (domain.CheckoutSaga, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	now := time.Now().UTC()
+	filter := bson.M{
+		"status":       bson.M{"$in": bson.A{domain.SagaStatusRunning, domain.SagaStatusCompensating}},
+		"locked_until": bson.M{"$lt": now},
+	}
+	update := bson.M{
+		"$set": bson.M{"locked_until": now.Add(leaseDuration), "updated_at": now},
+		"$inc": bson.M{"version": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetSort(bson.M{"locked_until": 1})
+
+	var sagaDAO dao.CheckoutSaga
+	err := r.col.FindOneAndUpdate(ctx, filter, update, opts).Decode(&sagaDAO)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.CheckoutSaga{}, false, nil
+		}
+		return domain.CheckoutSaga{}, false, err
+	}
+
+	return sagaDAO.ToDomain(), true, nil
+}
@@ -0,0 +1,330 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"products-api/internal/dao"
+	"products-api/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoCouponsRepository guarda los cupones, sus redenciones y el uso por cliente en MongoDB
+type MongoCouponsRepository struct {
+	col         *mongo.Collection
+	redemptions *mongo.Collection
+	usage       *mongo.Collection
+}
+
+// couponUsage cuenta los usos de un cupón por cliente (_id = "<couponID>:<customerID>")
+type couponUsage struct {
+	ID    string `bson:"_id"`
+	Count int    `bson:"count"`
+}
+
+// NewMongoCouponsRepository crea una nueva instancia del repository y se conecta a MongoDB
+func NewMongoCouponsRepository(ctx context.Context, uri, dbName, collectionName string) *MongoCouponsRepository {
+	opt := options.Client().ApplyURI(uri)
+	opt.SetServerSelectionTimeout(10 * time.Second)
+
+	client, err := mongo.Connect(ctx, opt)
+	if err != nil {
+		log.Fatalf("Error connecting to DB: %v", err)
+		return nil
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := client.Ping(pingCtx, nil); err != nil {
+		log.Fatalf("Error pinging DB: %v", err)
+		return nil
+	}
+
+	db := client.Database(dbName)
+	col := db.Collection(collectionName)
+
+	// Los códigos son únicos
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "code", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := col.Indexes().CreateOne(ctx, indexModel); err != nil {
+		log.Printf("Warning: Could not create unique index on coupon code: %v", err)
+	}
+
+	return &MongoCouponsRepository{
+		col:         col,
+		redemptions: db.Collection(collectionName + "_redemptions"),
+		usage:       db.Collection(collectionName + "_usage"),
+	}
+}
+
+// List obtiene todos los cupones
+func (r *MongoCouponsRepository) List(ctx context.Context) ([]domain.Coupon, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	cur, err := r.col.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var couponsDAO []dao.Coupon
+	if err := cur.All(ctx, &couponsDAO); err != nil {
+		return nil, err
+	}
+
+	coupons := make([]domain.Coupon, len(couponsDAO))
+	for i, couponDAO := range couponsDAO {
+		coupons[i] = couponDAO.ToDomain()
+	}
+	return coupons, nil
+}
+
+// Create inserta un nuevo cupón
+func (r *MongoCouponsRepository) Create(ctx context.Context, coupon domain.Coupon) (domain.Coupon, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	couponDAO := dao.FromDomainCoupon(coupon)
+	couponDAO.ID = primitive.NewObjectID()
+	now := time.Now().UTC()
+	couponDAO.CreatedAt = now
+	couponDAO.UpdatedAt = now
+	couponDAO.Redemptions = 0
+
+	if _, err := r.col.InsertOne(ctx, couponDAO); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.Coupon{}, errors.New("coupon code already exists")
+		}
+		return domain.Coupon{}, err
+	}
+	return couponDAO.ToDomain(), nil
+}
+
+// GetByID obtiene un cupón por su ID
+func (r *MongoCouponsRepository) GetByID(ctx context.Context, id string) (domain.Coupon, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Coupon{}, errors.New("invalid ObjectID format")
+	}
+	return r.findOne(ctx, bson.M{"_id": objectID})
+}
+
+// GetByCode obtiene un cupón por su código
+func (r *MongoCouponsRepository) GetByCode(ctx context.Context, code string) (domain.Coupon, error) {
+	return r.findOne(ctx, bson.M{"code": code})
+}
+
+func (r *MongoCouponsRepository) findOne(ctx context.Context, filter bson.M) (domain.Coupon, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var couponDAO dao.Coupon
+	if err := r.col.FindOne(ctx, filter).Decode(&couponDAO); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Coupon{}, errors.New("coupon not found")
+		}
+		return domain.Coupon{}, err
+	}
+	return couponDAO.ToDomain(), nil
+}
+
+// Update reemplaza la configuración de un cupón (el contador de usos no se toca)
+func (r *MongoCouponsRepository) Update(ctx context.Context, id string, coupon domain.Coupon) (domain.Coupon, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Coupon{}, errors.New("invalid ObjectID format")
+	}
+
+	couponDAO := dao.FromDomainCoupon(coupon)
+	update := bson.M{"$set": bson.M{
+		"code":             couponDAO.Code,
+		"description":      couponDAO.Description,
+		"type":             couponDAO.Type,
+		"value":            couponDAO.Value,
+		"min_cart_total":   couponDAO.MinCartTotal,
+		"categories":       couponDAO.Categories,
+		"item_ids":         couponDAO.ItemIDs,
+		"starts_at":        couponDAO.StartsAt,
+		"ends_at":          couponDAO.EndsAt,
+		"max_redemptions":  couponDAO.MaxRedemptions,
+		"max_per_customer": couponDAO.MaxPerCustomer,
+		"active":           couponDAO.Active,
+		"updated_at":       time.Now().UTC(),
+	}}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated dao.Coupon
+	if err := r.col.FindOneAndUpdate(ctx, bson.M{"_id": objectID}, update, opts).Decode(&updated); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Coupon{}, errors.New("coupon not found")
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.Coupon{}, errors.New("coupon code already exists")
+		}
+		return domain.Coupon{}, err
+	}
+	return updated.ToDomain(), nil
+}
+
+// Delete elimina un cupón
+func (r *MongoCouponsRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid ObjectID format")
+	}
+	result, err := r.col.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("coupon not found")
+	}
+	return nil
+}
+
+// CustomerUsage devuelve cuántas veces usó el cliente el cupón
+func (r *MongoCouponsRepository) CustomerUsage(ctx context.Context, couponID string, customerID int) (int, error) {
+	var usage couponUsage
+	err := r.usage.FindOne(ctx, bson.M{"_id": usageKey(couponID, customerID)}).Decode(&usage)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return usage.Count, nil
+}
+
+// Redeem registra el uso del cupón en una orden de forma atómica
+// Cada contador se incrementa con un update condicional, así dos checkouts concurrentes no pueden pasarse del límite
+// Es idempotente por orden: la redención marca qué contadores ya se sumaron y un reintento solo suma los que faltan.
+// Si el proceso se cae entre un $inc y su marca, el reintento vuelve a sumar ese contador: se prefiere contar de más
+// (el cupón se agota un uso antes) a dejar un uso sin contar
+func (r *MongoCouponsRepository) Redeem(ctx context.Context, coupon domain.Coupon, redemption domain.CouponRedemption) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	redemptionDAO := dao.FromDomainCouponRedemption(redemption)
+	if _, err := r.redemptions.InsertOne(ctx, redemptionDAO); err != nil {
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+		// Reintento de la misma orden: se retoman los contadores que quedaron pendientes
+		if err := r.redemptions.FindOne(ctx, bson.M{"_id": redemption.OrderID}).Decode(&redemptionDAO); err != nil {
+			return err
+		}
+		if redemptionDAO.CustomerCounted && redemptionDAO.CouponCounted {
+			return nil
+		}
+	}
+
+	// 1. Límite por cliente: si ya llegó al máximo el filtro no matchea, el upsert intenta insertar y choca con el _id
+	// Sin límite por cliente igual se cuenta, para poder consultarlo
+	if !redemptionDAO.CustomerCounted {
+		filter := bson.M{"_id": usageKey(coupon.ID, redemption.CustomerID)}
+		if coupon.MaxPerCustomer > 0 {
+			filter["count"] = bson.M{"$lt": coupon.MaxPerCustomer}
+		}
+		_, err := r.usage.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"count": 1}}, options.Update().SetUpsert(true))
+		if err != nil {
+			r.undoRedemption(redemption.OrderID)
+			if coupon.MaxPerCustomer > 0 && mongo.IsDuplicateKeyError(err) {
+				return errors.New("customer usage limit reached")
+			}
+			return err
+		}
+		if err := r.markCounted(ctx, redemption.OrderID, "customer_counted"); err != nil {
+			return err
+		}
+	}
+
+	// 2. Límite global
+	if !redemptionDAO.CouponCounted {
+		objectID, err := primitive.ObjectIDFromHex(coupon.ID)
+		if err != nil {
+			r.undoRedemption(redemption.OrderID)
+			return errors.New("invalid ObjectID format")
+		}
+		filter := bson.M{"_id": objectID}
+		if coupon.MaxRedemptions > 0 {
+			filter["redemptions"] = bson.M{"$lt": coupon.MaxRedemptions}
+		}
+		result, err := r.col.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"redemptions": 1}})
+		if err != nil {
+			r.undoRedemption(redemption.OrderID)
+			return err
+		}
+		if result.MatchedCount == 0 {
+			r.undoRedemption(redemption.OrderID)
+			return errors.New("coupon usage limit reached")
+		}
+		if err := r.markCounted(ctx, redemption.OrderID, "coupon_counted"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Release deshace la redención de una orden (por ejemplo, si el checkout se compensa)
+// Solo resta los contadores que la redención llegó a sumar
+func (r *MongoCouponsRepository) Release(ctx context.Context, orderID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var redemptionDAO dao.CouponRedemption
+	err := r.redemptions.FindOneAndDelete(ctx, bson.M{"_id": orderID}).Decode(&redemptionDAO)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		return err
+	}
+
+	if redemptionDAO.CustomerCounted {
+		if _, err := r.usage.UpdateOne(ctx, bson.M{"_id": usageKey(redemptionDAO.CouponID, redemptionDAO.CustomerID), "count": bson.M{"$gt": 0}}, bson.M{"$inc": bson.M{"count": -1}}); err != nil {
+			return fmt.Errorf("error releasing customer usage: %w", err)
+		}
+	}
+	if objectID, err := primitive.ObjectIDFromHex(redemptionDAO.CouponID); err == nil && redemptionDAO.CouponCounted {
+		if _, err := r.col.UpdateOne(ctx, bson.M{"_id": objectID, "redemptions": bson.M{"$gt": 0}}, bson.M{"$inc": bson.M{"redemptions": -1}}); err != nil {
+			return fmt.Errorf("error releasing coupon usage: %w", err)
+		}
+	}
+	return nil
+}
+
+// markCounted marca en la redención que un contador ya se sumó
+func (r *MongoCouponsRepository) markCounted(ctx context.Context, orderID, field string) error {
+	if _, err := r.redemptions.UpdateOne(ctx, bson.M{"_id": orderID}, bson.M{"$set": bson.M{field: true}}); err != nil {
+		return fmt.Errorf("error saving coupon redemption progress: %w", err)
+	}
+	return nil
+}
+
+// undoRedemption deshace una redención que no pudo completarse (restando lo que ya se había contado)
+func (r *MongoCouponsRepository) undoRedemption(orderID string) {
+	if err := r.Release(context.Background(), orderID); err != nil {
+		log.Printf("⚠️ Error rolling back coupon redemption for order %s: %v", orderID, err)
+	}
+}
+
+func usageKey(couponID string, customerID int) string {
+	return fmt.Sprintf("%s:%d", couponID, customerID)
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"products-api/internal/dao"
+	"products-api/internal/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoGuestCartRepository guarda los carritos de invitados en MongoDB
+// Un índice TTL sobre expires_at hace que MongoDB borre solos los carritos vencidos
+type MongoGuestCartRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoGuestCartRepository crea una nueva instancia del repositorio
+func NewMongoGuestCartRepository(ctx context.Context, uri, dbName, collectionName string) *MongoGuestCartRepository {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := client.Ping(ctx, nil); err != nil {
+		log.Fatal(err)
+	}
+
+	collection := client.Database(dbName).Collection(collectionName)
+
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+	if _, err := collection.Indexes().CreateOne(ctx, indexModel); err != nil {
+		log.Printf("Warning: Could not create TTL index on expires_at: %v", err)
+	}
+
+	log.Printf("✓ Connected to MongoDB - Guest Cart Collection: %s", collectionName)
+	return &MongoGuestCartRepository{collection: collection}
+}
+
+// GetByToken obtiene el carrito de un invitado
+// El TTL de MongoDB corre cada minuto, así que también se filtran los que vencieron hace poco
+func (r *MongoGuestCartRepository) GetByToken(ctx context.Context, token string) (domain.Cart, error) {
+	var cartDAO dao.GuestCartDAO
+	filter := bson.M{"_id": token, "expires_at": bson.M{"$gt": time.Now()}}
+
+	if err := r.collection.FindOne(ctx, filter).Decode(&cartDAO); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Cart{}, errors.New("guest cart not found")
+		}
+		return domain.Cart{}, fmt.Errorf("error finding guest cart: %w", err)
+	}
+
+	return guestCartToDomain(cartDAO), nil
+}
+
+// Upsert crea o actualiza el carrito de un invitado y extiende su vencimiento
+func (r *MongoGuestCartRepository) Upsert(ctx context.Context, cart domain.Cart, expiresAt time.Time) (domain.Cart, error) {
+	items := make([]dao.CartItemDAO, len(cart.Items))
+	for i, item := range cart.Items {
+		items[i] = dao.CartItemDAO{ItemID: item.ItemID, Quantity: item.Quantity, PriceAtAdd: item.PriceAtAdd}
+	}
+
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"items":       items,
+			"coupon_code": cart.CouponCode,
+			"expires_at":  expiresAt,
+			"updated_at":  now,
+		},
+		"$setOnInsert": bson.M{
+			"created_at": now,
+		},
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After)
+
+	var updatedDAO dao.GuestCartDAO
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": cart.SessionToken}, update, opts).Decode(&updatedDAO)
+	if err != nil {
+		return domain.Cart{}, fmt.Errorf("error upserting guest cart: %w", err)
+	}
+
+	return guestCartToDomain(updatedDAO), nil
+}
+
+// Delete elimina el carrito de un invitado (por ejemplo, después de pasarlo al carrito del cliente)
+func (r *MongoGuestCartRepository) Delete(ctx context.Context, token string) error {
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"_id": token}); err != nil {
+		return fmt.Errorf("error deleting guest cart: %w", err)
+	}
+	return nil
+}
+
+func guestCartToDomain(cartDAO dao.GuestCartDAO) domain.Cart {
+	items := make([]domain.CartItem, len(cartDAO.Items))
+	for i, item := range cartDAO.Items {
+		items[i] = domain.CartItem{ItemID: item.ItemID, Quantity: item.Quantity, PriceAtAdd: item.PriceAtAdd}
+	}
+
+	expiresAt := cartDAO.ExpiresAt
+	return domain.Cart{
+		SessionToken: cartDAO.Token,
+		Items:        items,
+		CouponCode:   cartDAO.CouponCode,
+		ExpiresAt:    &expiresAt,
+		CreatedAt:    cartDAO.CreatedAt,
+		UpdatedAt:    cartDAO.UpdatedAt,
+	}
+}
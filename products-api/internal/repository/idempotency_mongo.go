@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"products-api/internal/dao"
+	"products-api/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoIdempotencyRepository guarda las claves de idempotencia en MongoDB
+// El _id del documento es la clave, así el índice único evita duplicados concurrentes
+type MongoIdempotencyRepository struct {
+	col *mongo.Collection
+}
+
+// NewMongoIdempotencyRepository crea una nueva instancia del repository y se conecta a MongoDB
+func NewMongoIdempotencyRepository(ctx context.Context, uri, dbName, collectionName string) *MongoIdempotencyRepository {
+	opt := options.Client().ApplyURI(uri)
+	opt.SetServerSelectionTimeout(10 * time.Second)
+
+	client, err := mongo.Connect(ctx, opt)
+	if err != nil {
+		log.Fatalf("Error connecting to DB: %v", err)
+		return nil
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := client.Ping(pingCtx, nil); err != nil {
+		log.Fatalf("Error pinging DB: %v", err)
+		return nil
+	}
+
+	col := client.Database(dbName).Collection(collectionName)
+
+	// Índice TTL: MongoDB borra las claves vencidas automáticamente
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+	if _, err := col.Indexes().CreateOne(ctx, indexModel); err != nil {
+		log.Printf("Warning: Could not create TTL index on idempotency keys: %v", err)
+	}
+
+	return &MongoIdempotencyRepository{col: col}
+}
+
+// Acquire intenta registrar la clave. Si ya existía devuelve el registro guardado y acquired=false
+func (r *MongoIdempotencyRepository) Acquire(ctx context.Context, record domain.IdempotencyRecord) (domain.IdempotencyRecord, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	recordDAO := dao.FromDomainIdempotencyRecord(record)
+
+	_, err := r.col.InsertOne(ctx, recordDAO)
+	if err == nil {
+		return record, true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return domain.IdempotencyRecord{}, false, err
+	}
+
+	var existing dao.IdempotencyRecord
+	if err := r.col.FindOne(ctx, bson.M{"_id": record.Key}).Decode(&existing); err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+
+	// El monitor TTL de MongoDB corre cada 60s: una clave vencida puede seguir en la colección
+	if existing.ExpiresAt.Before(time.Now().UTC()) {
+		filter := bson.M{"_id": record.Key, "expires_at": existing.ExpiresAt}
+		result, err := r.col.ReplaceOne(ctx, filter, recordDAO)
+		if err != nil {
+			return domain.IdempotencyRecord{}, false, err
+		}
+		if result.ModifiedCount == 1 {
+			return record, true, nil
+		}
+		if err := r.col.FindOne(ctx, bson.M{"_id": record.Key}).Decode(&existing); err != nil {
+			return domain.IdempotencyRecord{}, false, err
+		}
+	}
+
+	return existing.ToDomain(), false, nil
+}
+
+// TakeOver toma una clave en curso cuyo lease venció (el request original se cayó sin terminar)
+// Es atómico: si dos reintentos llegan juntos, solo uno la toma. Devuelve taken=false si el lease sigue vigente
+func (r *MongoIdempotencyRepository) TakeOver(ctx context.Context, key string, lockedUntil time.Time) (domain.IdempotencyRecord, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"_id":          key,
+		"status":       domain.IdempotencyStatusInProgress,
+		"locked_until": bson.M{"$lt": time.Now().UTC()},
+	}
+	update := bson.M{"$set": bson.M{"locked_until": lockedUntil}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var recordDAO dao.IdempotencyRecord
+	if err := r.col.FindOneAndUpdate(ctx, filter, update, opts).Decode(&recordDAO); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.IdempotencyRecord{}, false, nil
+		}
+		return domain.IdempotencyRecord{}, false, err
+	}
+	return recordDAO.ToDomain(), true, nil
+}
+
+// ExtendLease extiende el lease de una clave mientras su request sigue en curso
+func (r *MongoIdempotencyRepository) ExtendLease(ctx context.Context, key string, lockedUntil time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": key, "status": domain.IdempotencyStatusInProgress}
+	_, err := r.col.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"locked_until": lockedUntil}})
+	return err
+}
+
+// GetByKey obtiene el registro de una clave
+func (r *MongoIdempotencyRepository) GetByKey(ctx context.Context, key string) (domain.IdempotencyRecord, error) {
+	var recordDAO dao.IdempotencyRecord
+	if err := r.col.FindOne(ctx, bson.M{"_id": key}).Decode(&recordDAO); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.IdempotencyRecord{}, errors.New("idempotency key not found")
+		}
+		return domain.IdempotencyRecord{}, err
+	}
+	return recordDAO.ToDomain(), nil
+}
+
+// Complete guarda la respuesta del request original
+func (r *MongoIdempotencyRepository) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{
+		"status":        domain.IdempotencyStatusCompleted,
+		"status_code":   statusCode,
+		"content_type":  contentType,
+		"response_body": body,
+	}}
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": key}, update)
+	return err
+}
+
+// Delete libera una clave (por ejemplo, si el request terminó con un error del servidor)
+func (r *MongoIdempotencyRepository) Delete(ctx context.Context, key string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := r.col.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
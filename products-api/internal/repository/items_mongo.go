@@ -113,6 +113,9 @@ func (r *MongoItemsRepository) Update(ctx context.Context, id string, item domai
 		"stock":       item.Stock,
 		"category":    item.Category,
 		"image_url":   item.ImageURL,
+		"weight_kg":   item.WeightKg,
+		"dimensions":  dao.Dimensions(item.Dimensions),
+		"tax_class":   item.TaxClass,
 		"updated_at":  time.Now().UTC().Truncate(time.Millisecond), // Solo actualizar updated_at
 	}
 
@@ -201,3 +204,72 @@ func (r *MongoItemsRepository) IncrementStock(ctx context.Context, itemID string
 	log.Printf("✅ Stock incremented for item %s", itemID)
 	return nil
 }
+
+// ReserveStock descuenta stock guardando la clave de la reserva en el item (stock_reservations)
+// Es idempotente: si la reserva ya se aplicó devuelve true sin volver a descontar
+// Devuelve false si no hay stock suficiente
+func (r *MongoItemsRepository) ReserveStock(ctx context.Context, itemID string, quantity int, reservationID string) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(itemID)
+	if err != nil {
+		log.Printf("❌ Invalid ObjectID format: %s", itemID)
+		return false, errors.New("invalid ObjectID format")
+	}
+
+	filter := bson.M{
+		"_id":                objID,
+		"stock":              bson.M{"$gte": quantity},
+		"stock_reservations": bson.M{"$ne": reservationID},
+	}
+	update := bson.M{
+		"$inc":  bson.M{"stock": -quantity},
+		"$push": bson.M{"stock_reservations": reservationID},
+	}
+	result, err := r.col.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	if result.MatchedCount == 1 {
+		return true, nil
+	}
+
+	// No se aplicó: o la reserva ya estaba hecha (reintento) o no hay stock
+	count, err := r.col.CountDocuments(ctx, bson.M{"_id": objID, "stock_reservations": reservationID})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ReleaseStock devuelve el stock de una reserva y borra su clave
+// Si la reserva no existe (nunca se aplicó o ya se liberó) no hace nada
+func (r *MongoItemsRepository) ReleaseStock(ctx context.Context, itemID string, quantity int, reservationID string) error {
+	objID, err := primitive.ObjectIDFromHex(itemID)
+	if err != nil {
+		log.Printf("❌ Invalid ObjectID format: %s", itemID)
+		return errors.New("invalid ObjectID format")
+	}
+
+	filter := bson.M{"_id": objID, "stock_reservations": reservationID}
+	update := bson.M{
+		"$inc":  bson.M{"stock": quantity},
+		"$pull": bson.M{"stock_reservations": reservationID},
+	}
+	if _, err := r.col.UpdateOne(ctx, filter, update); err != nil {
+		log.Printf("❌ Error releasing stock: %v", err)
+		return err
+	}
+	return nil
+}
+
+// ConfirmStockReservation borra la clave de una reserva que ya no se va a liberar (la compra se completó)
+func (r *MongoItemsRepository) ConfirmStockReservation(ctx context.Context, itemID string, reservationID string) error {
+	objID, err := primitive.ObjectIDFromHex(itemID)
+	if err != nil {
+		log.Printf("❌ Invalid ObjectID format: %s", itemID)
+		return errors.New("invalid ObjectID format")
+	}
+
+	update := bson.M{"$pull": bson.M{"stock_reservations": reservationID}}
+	_, err = r.col.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	return err
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"products-api/internal/dao"
+	"products-api/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoOrdersRepository maneja las órdenes de checkout en MongoDB
+type MongoOrdersRepository struct {
+	col *mongo.Collection
+}
+
+// NewMongoOrdersRepository crea una nueva instancia del repository y se conecta a MongoDB
+func NewMongoOrdersRepository(ctx context.Context, uri, dbName, collectionName string) *MongoOrdersRepository {
+	opt := options.Client().ApplyURI(uri)
+	opt.SetServerSelectionTimeout(10 * time.Second)
+
+	client, err := mongo.Connect(ctx, opt)
+	if err != nil {
+		log.Fatalf("Error connecting to DB: %v", err)
+		return nil
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := client.Ping(pingCtx, nil); err != nil {
+		log.Fatalf("Error pinging DB: %v", err)
+		return nil
+	}
+
+	col := client.Database(dbName).Collection(collectionName)
+
+	indexModel := mongo.IndexModel{Keys: bson.D{{Key: "customer_id", Value: 1}, {Key: "created_at", Value: -1}}}
+	if _, err := col.Indexes().CreateOne(ctx, indexModel); err != nil {
+		log.Printf("Warning: Could not create index on orders: %v", err)
+	}
+
+	return &MongoOrdersRepository{col: col}
+}
+
+// Save crea o reemplaza una orden (el ID lo genera la saga antes de crearla)
+func (r *MongoOrdersRepository) Save(ctx context.Context, order domain.Order) (domain.Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	orderDAO := dao.FromDomainOrder(order)
+	if orderDAO.ID.IsZero() {
+		return domain.Order{}, errors.New("invalid ObjectID format")
+	}
+
+	now := time.Now().UTC()
+	if orderDAO.CreatedAt.IsZero() {
+		orderDAO.CreatedAt = now
+	}
+	orderDAO.UpdatedAt = now
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.col.ReplaceOne(ctx, bson.M{"_id": orderDAO.ID}, orderDAO, opts); err != nil {
+		return domain.Order{}, err
+	}
+
+	return orderDAO.ToDomain(), nil
+}
+
+// GetByID obtiene una orden por su ID
+func (r *MongoOrdersRepository) GetByID(ctx context.Context, id string) (domain.Order, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Order{}, errors.New("invalid ObjectID format")
+	}
+
+	var orderDAO dao.Order
+	if err := r.col.FindOne(ctx, bson.M{"_id": objectID}).Decode(&orderDAO); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Order{}, errors.New("order not found")
+		}
+		return domain.Order{}, err
+	}
+
+	return orderDAO.ToDomain(), nil
+}
+
+// UpdateStatus cambia el estado de una orden
+func (r *MongoOrdersRepository) UpdateStatus(ctx context.Context, id string, status string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid ObjectID format")
+	}
+
+	update := bson.M{"$set": bson.M{"status": status, "updated_at": time.Now().UTC()}}
+	result, err := r.col.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("order not found")
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"products-api/internal/dao"
+	"products-api/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoPaymentsRepository guarda los pagos de las órdenes en MongoDB
+// En <collection>_webhook_events quedan los IDs de los webhooks ya procesados
+type MongoPaymentsRepository struct {
+	col    *mongo.Collection
+	events *mongo.Collection
+}
+
+// paymentWebhookEvent registra un webhook procesado (_id = ID del evento del proveedor, único)
+type paymentWebhookEvent struct {
+	ID         string    `bson:"_id"`
+	Type       string    `bson:"type"`
+	PaymentID  string    `bson:"payment_id"`
+	ReceivedAt time.Time `bson:"received_at"`
+}
+
+// webhookEventsRetention es cuánto se guarda cada evento procesado
+// Alcanza con que supere la tolerancia de la firma: un webhook más viejo ya se rechaza por el timestamp
+const webhookEventsRetention = 7 * 24 * time.Hour
+
+// NewMongoPaymentsRepository crea una nueva instancia del repository y se conecta a MongoDB
+func NewMongoPaymentsRepository(ctx context.Context, uri, dbName, collectionName string) *MongoPaymentsRepository {
+	opt := options.Client().ApplyURI(uri)
+	opt.SetServerSelectionTimeout(10 * time.Second)
+
+	client, err := mongo.Connect(ctx, opt)
+	if err != nil {
+		log.Fatalf("Error connecting to DB: %v", err)
+		return nil
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := client.Ping(pingCtx, nil); err != nil {
+		log.Fatalf("Error pinging DB: %v", err)
+		return nil
+	}
+
+	db := client.Database(dbName)
+	col := db.Collection(collectionName)
+
+	indexModel := mongo.IndexModel{Keys: bson.D{{Key: "order_id", Value: 1}}}
+	if _, err := col.Indexes().CreateOne(ctx, indexModel); err != nil {
+		log.Printf("Warning: Could not create index on payments: %v", err)
+	}
+
+	events := db.Collection(collectionName + "_webhook_events")
+	ttlIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "received_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(webhookEventsRetention.Seconds())),
+	}
+	if _, err := events.Indexes().CreateOne(ctx, ttlIndex); err != nil {
+		log.Printf("Warning: Could not create TTL index on payment webhook events: %v", err)
+	}
+
+	return &MongoPaymentsRepository{col: col, events: events}
+}
+
+// Save crea o reemplaza un pago
+func (r *MongoPaymentsRepository) Save(ctx context.Context, payment domain.Payment) (domain.Payment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if payment.ID == "" {
+		return domain.Payment{}, errors.New("payment ID is required")
+	}
+
+	paymentDAO := dao.FromDomainPayment(payment)
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.col.ReplaceOne(ctx, bson.M{"_id": paymentDAO.ID}, paymentDAO, opts); err != nil {
+		return domain.Payment{}, err
+	}
+	return paymentDAO.ToDomain(), nil
+}
+
+// GetByID obtiene un pago por el ID del proveedor
+func (r *MongoPaymentsRepository) GetByID(ctx context.Context, id string) (domain.Payment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var paymentDAO dao.Payment
+	if err := r.col.FindOne(ctx, bson.M{"_id": id}).Decode(&paymentDAO); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Payment{}, errors.New("payment not found")
+		}
+		return domain.Payment{}, err
+	}
+	return paymentDAO.ToDomain(), nil
+}
+
+// RecordWebhookEvent registra un webhook antes de procesarlo
+// Si el ID ya estaba registrado devuelve "webhook event already processed" (lo garantiza el índice único de _id)
+func (r *MongoPaymentsRepository) RecordWebhookEvent(ctx context.Context, event domain.PaymentEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := r.events.InsertOne(ctx, paymentWebhookEvent{
+		ID:         event.ID,
+		Type:       event.Type,
+		PaymentID:  event.PaymentID,
+		ReceivedAt: time.Now().UTC(),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return errors.New("webhook event already processed")
+	}
+	return err
+}
+
+// DeleteWebhookEvent borra el registro de un webhook que no se pudo procesar, para que el reintento del proveedor entre
+func (r *MongoPaymentsRepository) DeleteWebhookEvent(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := r.events.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
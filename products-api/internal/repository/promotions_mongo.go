@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"products-api/internal/dao"
+	"products-api/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoPromotionsRepository guarda las reglas de promociones automáticas en MongoDB
+type MongoPromotionsRepository struct {
+	col *mongo.Collection
+}
+
+// NewMongoPromotionsRepository crea una nueva instancia del repository y se conecta a MongoDB
+func NewMongoPromotionsRepository(ctx context.Context, uri, dbName, collectionName string) *MongoPromotionsRepository {
+	opt := options.Client().ApplyURI(uri)
+	opt.SetServerSelectionTimeout(10 * time.Second)
+
+	client, err := mongo.Connect(ctx, opt)
+	if err != nil {
+		log.Fatalf("Error connecting to DB: %v", err)
+		return nil
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := client.Ping(pingCtx, nil); err != nil {
+		log.Fatalf("Error pinging DB: %v", err)
+		return nil
+	}
+
+	col := client.Database(dbName).Collection(collectionName)
+
+	// Cada carrito consulta las promociones activas
+	indexModel := mongo.IndexModel{Keys: bson.D{{Key: "active", Value: 1}, {Key: "priority", Value: -1}}}
+	if _, err := col.Indexes().CreateOne(ctx, indexModel); err != nil {
+		log.Printf("Warning: Could not create index on promotions: %v", err)
+	}
+
+	return &MongoPromotionsRepository{col: col}
+}
+
+// List obtiene las promociones ordenadas por prioridad. Con onlyActive filtra las desactivadas
+func (r *MongoPromotionsRepository) List(ctx context.Context, onlyActive bool) ([]domain.Promotion, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if onlyActive {
+		filter["active"] = true
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "priority", Value: -1}, {Key: "created_at", Value: 1}})
+	cur, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var promotionsDAO []dao.Promotion
+	if err := cur.All(ctx, &promotionsDAO); err != nil {
+		return nil, err
+	}
+
+	promotions := make([]domain.Promotion, len(promotionsDAO))
+	for i, promotionDAO := range promotionsDAO {
+		promotions[i] = promotionDAO.ToDomain()
+	}
+	return promotions, nil
+}
+
+// Create inserta una nueva promoción
+func (r *MongoPromotionsRepository) Create(ctx context.Context, promotion domain.Promotion) (domain.Promotion, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	promotionDAO := dao.FromDomainPromotion(promotion)
+	promotionDAO.ID = primitive.NewObjectID()
+	now := time.Now().UTC()
+	promotionDAO.CreatedAt = now
+	promotionDAO.UpdatedAt = now
+
+	if _, err := r.col.InsertOne(ctx, promotionDAO); err != nil {
+		return domain.Promotion{}, err
+	}
+	return promotionDAO.ToDomain(), nil
+}
+
+// GetByID obtiene una promoción por su ID
+func (r *MongoPromotionsRepository) GetByID(ctx context.Context, id string) (domain.Promotion, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Promotion{}, errors.New("invalid ObjectID format")
+	}
+
+	var promotionDAO dao.Promotion
+	if err := r.col.FindOne(ctx, bson.M{"_id": objectID}).Decode(&promotionDAO); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Promotion{}, errors.New("promotion not found")
+		}
+		return domain.Promotion{}, err
+	}
+	return promotionDAO.ToDomain(), nil
+}
+
+// Update reemplaza la regla de una promoción
+func (r *MongoPromotionsRepository) Update(ctx context.Context, id string, promotion domain.Promotion) (domain.Promotion, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Promotion{}, errors.New("invalid ObjectID format")
+	}
+
+	promotionDAO := dao.FromDomainPromotion(promotion)
+	update := bson.M{"$set": bson.M{
+		"name":         promotionDAO.Name,
+		"description":  promotionDAO.Description,
+		"type":         promotionDAO.Type,
+		"percentage":   promotionDAO.Percentage,
+		"buy_quantity": promotionDAO.BuyQuantity,
+		"get_quantity": promotionDAO.GetQuantity,
+		"categories":   promotionDAO.Categories,
+		"item_ids":     promotionDAO.ItemIDs,
+		"weekdays":     promotionDAO.Weekdays,
+		"starts_at":    promotionDAO.StartsAt,
+		"ends_at":      promotionDAO.EndsAt,
+		"priority":     promotionDAO.Priority,
+		"stackable":    promotionDAO.Stackable,
+		"active":       promotionDAO.Active,
+		"updated_at":   time.Now().UTC(),
+	}}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated dao.Promotion
+	if err := r.col.FindOneAndUpdate(ctx, bson.M{"_id": objectID}, update, opts).Decode(&updated); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Promotion{}, errors.New("promotion not found")
+		}
+		return domain.Promotion{}, err
+	}
+	return updated.ToDomain(), nil
+}
+
+// Delete elimina una promoción
+func (r *MongoPromotionsRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid ObjectID format")
+	}
+	result, err := r.col.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("promotion not found")
+	}
+	return nil
+}
@@ -55,3 +55,8 @@ func (r SalesLocalCacheRepository) Delete(ctx context.Context, id string) error
 	}
 	return nil
 }
+
+func (r SalesLocalCacheRepository) DeleteByOrderID(ctx context.Context, orderID string) error {
+	// Cache local solo puede borrar por clave exacta (ID)
+	return fmt.Errorf("deleteByOrderID is not supported in local cache")
+}
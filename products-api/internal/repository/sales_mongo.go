@@ -180,3 +180,16 @@ func (r *MongoSalesRepository) Delete(ctx context.Context, id string) error {
 
 	return nil
 }
+
+// DeleteByOrderID elimina todas las ventas generadas por una orden (compensación del checkout)
+func (r *MongoSalesRepository) DeleteByOrderID(ctx context.Context, orderID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if orderID == "" {
+		return errors.New("order_id is required")
+	}
+
+	_, err := r.col.DeleteMany(ctx, bson.M{"order_id": orderID})
+	return err
+}
@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"products-api/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoSalesReportsRepository calcula reportes de ventas con aggregation pipelines de MongoDB
+type MongoSalesReportsRepository struct {
+	col              *mongo.Collection
+	itemsCollection  string
+	ordersCollection string
+}
+
+// reportedOrderStatuses son los estados de orden cuyas ventas cuentan en los reportes
+// Las órdenes esperando el pago, canceladas o reintegradas no suman facturación
+var reportedOrderStatuses = bson.A{domain.OrderStatusPaid, domain.OrderStatusShipped}
+
+// NewMongoSalesReportsRepository crea una nueva instancia del repository y se conecta a MongoDB
+func NewMongoSalesReportsRepository(ctx context.Context, uri, dbName, salesCollection, itemsCollection, ordersCollection string) *MongoSalesReportsRepository {
+	opt := options.Client().ApplyURI(uri)
+	opt.SetServerSelectionTimeout(10 * time.Second)
+
+	client, err := mongo.Connect(ctx, opt)
+	if err != nil {
+		log.Fatalf("Error connecting to DB: %v", err)
+		return nil
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := client.Ping(pingCtx, nil); err != nil {
+		log.Fatalf("Error pinging DB: %v", err)
+		return nil
+	}
+
+	return &MongoSalesReportsRepository{
+		col:              client.Database(dbName).Collection(salesCollection),
+		itemsCollection:  itemsCollection,
+		ordersCollection: ordersCollection,
+	}
+}
+
+// matchSales arma los stages que eligen las ventas del reporte: las del rango de fechas [from, to)
+// que son ventas directas o de una orden pagada o despachada
+// order_id se guarda como string, por eso se convierte a ObjectID antes del $lookup
+func (r *MongoSalesReportsRepository) matchSales(filters domain.ReportFilters) []bson.D {
+	return []bson.D{
+		{{Key: "$match", Value: bson.M{
+			"sale_date": bson.M{"$gte": filters.From, "$lt": filters.To},
+		}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": r.ordersCollection,
+			"let":  bson.M{"order_oid": bson.M{"$convert": bson.M{"input": "$order_id", "to": "objectId", "onError": nil, "onNull": nil}}},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$order_oid"}}}},
+				bson.M{"$project": bson.M{"status": 1}},
+			},
+			"as": "order",
+		}}},
+		{{Key: "$match", Value: bson.M{"$or": bson.A{
+			bson.M{"order_id": bson.M{"$in": bson.A{nil, ""}}},
+			bson.M{"order.status": bson.M{"$in": reportedOrderStatuses}},
+		}}}},
+		{{Key: "$project", Value: bson.M{"order": 0}}},
+	}
+}
+
+// lookupItem agrega los datos del item (nombre y categoría) a cada venta
+// Solo hace falta para ventas anteriores al snapshot de item en la venta
+// item_id se guarda como string, por eso se convierte a ObjectID antes del $lookup
+func (r *MongoSalesReportsRepository) lookupItem() []bson.D {
+	return []bson.D{
+		{{Key: "$addFields", Value: bson.M{
+			"item_oid": bson.M{"$convert": bson.M{"input": "$item_id", "to": "objectId", "onError": nil, "onNull": nil}},
+		}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         r.itemsCollection,
+			"localField":   "item_oid",
+			"foreignField": "_id",
+			"as":           "item",
+		}}},
+		{{Key: "$unwind", Value: bson.M{"path": "$item", "preserveNullAndEmptyArrays": true}}},
+	}
+}
+
+// RevenueByPeriod agrupa facturación y unidades por día, semana o mes
+func (r *MongoSalesReportsRepository) RevenueByPeriod(ctx context.Context, filters domain.ReportFilters) ([]domain.RevenuePoint, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline(r.matchSales(filters))
+	pipeline = append(pipeline,
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id": bson.M{"$dateTrunc": bson.M{
+				"date":        "$sale_date",
+				"unit":        filters.Granularity,
+				"startOfWeek": "monday",
+			}},
+			"revenue": bson.M{"$sum": "$total_price"},
+			"units":   bson.M{"$sum": "$quantity"},
+			"orders":  bson.M{"$addToSet": orderKey},
+		}}},
+		bson.D{{Key: "$addFields", Value: bson.M{"orders": bson.M{"$size": "$orders"}}}},
+		bson.D{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	)
+
+	cur, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var rows []struct {
+		Period  time.Time `bson:"_id"`
+		Revenue float64   `bson:"revenue"`
+		Units   int       `bson:"units"`
+		Orders  int       `bson:"orders"`
+	}
+	if err := cur.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	points := make([]domain.RevenuePoint, len(rows))
+	for i, row := range rows {
+		points[i] = domain.RevenuePoint{
+			Period:  row.Period.UTC(),
+			Revenue: row.Revenue,
+			Units:   row.Units,
+			Orders:  row.Orders,
+		}
+	}
+	return points, nil
+}
+
+// TopProducts devuelve los N productos con más unidades vendidas
+func (r *MongoSalesReportsRepository) TopProducts(ctx context.Context, filters domain.ReportFilters) ([]domain.TopProduct, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline(r.matchSales(filters))
+	pipeline = append(pipeline,
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id":     "$item_id",
+			"name":    bson.M{"$last": "$item_name"},
+			"units":   bson.M{"$sum": "$quantity"},
+			"revenue": bson.M{"$sum": "$total_price"},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "units", Value: -1}, {Key: "revenue", Value: -1}}}},
+		bson.D{{Key: "$limit", Value: filters.Limit}},
+		bson.D{{Key: "$addFields", Value: bson.M{"item_id": "$_id"}}},
+	)
+	pipeline = append(pipeline, r.lookupItem()...)
+
+	cur, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var rows []struct {
+		ItemID  string  `bson:"_id"`
+		Name    string  `bson:"name"`
+		Units   int     `bson:"units"`
+		Revenue float64 `bson:"revenue"`
+		Item    struct {
+			Name string `bson:"name"`
+		} `bson:"item"`
+	}
+	if err := cur.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	products := make([]domain.TopProduct, len(rows))
+	for i, row := range rows {
+		// Preferimos el nombre guardado en la venta; las ventas viejas no lo tienen
+		name := row.Name
+		if name == "" {
+			name = row.Item.Name
+		}
+		products[i] = domain.TopProduct{
+			ItemID:  row.ItemID,
+			Name:    name,
+			Units:   row.Units,
+			Revenue: row.Revenue,
+		}
+	}
+	return products, nil
+}
+
+// RevenueByCategory agrupa la facturación por categoría del producto
+func (r *MongoSalesReportsRepository) RevenueByCategory(ctx context.Context, filters domain.ReportFilters) ([]domain.CategoryRevenue, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline(r.matchSales(filters))
+	pipeline = append(pipeline, r.lookupItem()...)
+	pipeline = append(pipeline,
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id":     bson.M{"$ifNull": bson.A{"$category", "$item.category", "sin categoria"}},
+			"revenue": bson.M{"$sum": "$total_price"},
+			"units":   bson.M{"$sum": "$quantity"},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.M{"revenue": -1}}},
+	)
+
+	cur, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var rows []struct {
+		Category string  `bson:"_id"`
+		Revenue  float64 `bson:"revenue"`
+		Units    int     `bson:"units"`
+	}
+	if err := cur.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	categories := make([]domain.CategoryRevenue, len(rows))
+	for i, row := range rows {
+		categories[i] = domain.CategoryRevenue{
+			Category: row.Category,
+			Revenue:  row.Revenue,
+			Units:    row.Units,
+		}
+	}
+	return categories, nil
+}
+
+// orderKey identifica la orden de una venta: las ventas de un checkout comparten order_id,
+// las ventas directas (POST /sales) son una orden cada una
+var orderKey = bson.M{"$ifNull": bson.A{"$order_id", bson.M{"$toString": "$_id"}}}
+
+// Summary calcula totales, ticket promedio y tasa de clientes recurrentes
+func (r *MongoSalesReportsRepository) Summary(ctx context.Context, filters domain.ReportFilters) (domain.SalesSummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline(r.matchSales(filters))
+	pipeline = append(pipeline,
+		// Primero agrupamos las líneas de cada orden
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id":         orderKey,
+			"customer_id": bson.M{"$first": "$customer_id"},
+			"revenue":     bson.M{"$sum": "$total_price"},
+			"units":       bson.M{"$sum": "$quantity"},
+		}}},
+		// Después agrupamos por cliente para saber cuántas órdenes hizo cada uno
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id":     "$customer_id",
+			"orders":  bson.M{"$sum": 1},
+			"revenue": bson.M{"$sum": "$revenue"},
+			"units":   bson.M{"$sum": "$units"},
+		}}},
+		// Después agrupamos todo para obtener los totales
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id":       nil,
+			"customers": bson.M{"$sum": 1},
+			"repeat": bson.M{"$sum": bson.M{
+				"$cond": bson.A{bson.M{"$gte": bson.A{"$orders", 2}}, 1, 0},
+			}},
+			"orders":  bson.M{"$sum": "$orders"},
+			"revenue": bson.M{"$sum": "$revenue"},
+			"units":   bson.M{"$sum": "$units"},
+		}}},
+	)
+
+	cur, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return domain.SalesSummary{}, err
+	}
+	defer cur.Close(ctx)
+
+	var rows []struct {
+		Customers int     `bson:"customers"`
+		Repeat    int     `bson:"repeat"`
+		Orders    int     `bson:"orders"`
+		Revenue   float64 `bson:"revenue"`
+		Units     int     `bson:"units"`
+	}
+	if err := cur.All(ctx, &rows); err != nil {
+		return domain.SalesSummary{}, err
+	}
+
+	summary := domain.SalesSummary{From: filters.From, To: filters.To}
+	if len(rows) == 0 {
+		return summary, nil
+	}
+
+	summary.Revenue = rows[0].Revenue
+	summary.Units = rows[0].Units
+	summary.Orders = rows[0].Orders
+	summary.Customers = rows[0].Customers
+	summary.RepeatCustomers = rows[0].Repeat
+	return summary, nil
+}
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"products-api/internal/dao"
+	"products-api/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoWishlistsRepository guarda las listas de deseos de los clientes en MongoDB
+type MongoWishlistsRepository struct {
+	col *mongo.Collection
+}
+
+// NewMongoWishlistsRepository crea una nueva instancia del repository y se conecta a MongoDB
+func NewMongoWishlistsRepository(ctx context.Context, uri, dbName, collectionName string) *MongoWishlistsRepository {
+	opt := options.Client().ApplyURI(uri)
+	opt.SetServerSelectionTimeout(10 * time.Second)
+
+	client, err := mongo.Connect(ctx, opt)
+	if err != nil {
+		log.Fatalf("Error connecting to DB: %v", err)
+		return nil
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := client.Ping(pingCtx, nil); err != nil {
+		log.Fatalf("Error pinging DB: %v", err)
+		return nil
+	}
+
+	col := client.Database(dbName).Collection(collectionName)
+
+	// Un cliente no puede tener dos listas con el mismo nombre, y cada token de compartir apunta a una sola lista
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "customer_id", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "share_token", Value: 1}},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+	}
+	if _, err := col.Indexes().CreateMany(ctx, indexes); err != nil {
+		log.Printf("Warning: Could not create wishlist indexes: %v", err)
+	}
+
+	log.Printf("✓ Connected to MongoDB - Wishlists Collection: %s", collectionName)
+	return &MongoWishlistsRepository{col: col}
+}
+
+// List obtiene las listas de un cliente, las más recientes primero
+func (r *MongoWishlistsRepository) List(ctx context.Context, customerID int) ([]domain.Wishlist, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cur, err := r.col.Find(ctx, bson.M{"customer_id": customerID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var wishlistsDAO []dao.Wishlist
+	if err := cur.All(ctx, &wishlistsDAO); err != nil {
+		return nil, err
+	}
+
+	wishlists := make([]domain.Wishlist, len(wishlistsDAO))
+	for i, wishlistDAO := range wishlistsDAO {
+		wishlists[i] = wishlistDAO.ToDomain()
+	}
+	return wishlists, nil
+}
+
+// Count cuenta las listas de un cliente
+func (r *MongoWishlistsRepository) Count(ctx context.Context, customerID int) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	count, err := r.col.CountDocuments(ctx, bson.M{"customer_id": customerID})
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// GetByID obtiene una lista por su ID
+func (r *MongoWishlistsRepository) GetByID(ctx context.Context, id string) (domain.Wishlist, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Wishlist{}, errors.New("invalid ObjectID format")
+	}
+	return r.findOne(ctx, bson.M{"_id": objectID})
+}
+
+// GetByShareToken obtiene una lista compartida por su token
+func (r *MongoWishlistsRepository) GetByShareToken(ctx context.Context, token string) (domain.Wishlist, error) {
+	return r.findOne(ctx, bson.M{"share_token": token})
+}
+
+func (r *MongoWishlistsRepository) findOne(ctx context.Context, filter bson.M) (domain.Wishlist, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var wishlistDAO dao.Wishlist
+	if err := r.col.FindOne(ctx, filter).Decode(&wishlistDAO); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Wishlist{}, errors.New("wishlist not found")
+		}
+		return domain.Wishlist{}, err
+	}
+	return wishlistDAO.ToDomain(), nil
+}
+
+// Create inserta una nueva lista vacía
+func (r *MongoWishlistsRepository) Create(ctx context.Context, wishlist domain.Wishlist) (domain.Wishlist, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	now := time.Now().UTC()
+	wishlistDAO := dao.Wishlist{
+		ID:         primitive.NewObjectID(),
+		CustomerID: wishlist.CustomerID,
+		Name:       wishlist.Name,
+		Items:      dao.FromDomainWishlistItems(wishlist.Items),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if _, err := r.col.InsertOne(ctx, wishlistDAO); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.Wishlist{}, errors.New("wishlist name already exists")
+		}
+		return domain.Wishlist{}, err
+	}
+	return wishlistDAO.ToDomain(), nil
+}
+
+// Update guarda el nombre, los ítems y el token de compartir de una lista
+// Un token vacío deja de compartir la lista
+func (r *MongoWishlistsRepository) Update(ctx context.Context, wishlist domain.Wishlist) (domain.Wishlist, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(wishlist.ID)
+	if err != nil {
+		return domain.Wishlist{}, errors.New("invalid ObjectID format")
+	}
+
+	set := bson.M{
+		"name":       wishlist.Name,
+		"items":      dao.FromDomainWishlistItems(wishlist.Items),
+		"updated_at": time.Now().UTC(),
+	}
+	update := bson.M{"$set": set}
+	if wishlist.ShareToken != "" {
+		set["share_token"] = wishlist.ShareToken
+	} else {
+		update["$unset"] = bson.M{"share_token": ""}
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	filter := bson.M{"_id": objectID, "customer_id": wishlist.CustomerID}
+	var updated dao.Wishlist
+	if err := r.col.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Wishlist{}, errors.New("wishlist not found")
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.Wishlist{}, errors.New("wishlist name already exists")
+		}
+		return domain.Wishlist{}, err
+	}
+	return updated.ToDomain(), nil
+}
+
+// Delete elimina una lista de un cliente
+func (r *MongoWishlistsRepository) Delete(ctx context.Context, customerID int, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid ObjectID format")
+	}
+	result, err := r.col.DeleteOne(ctx, bson.M{"_id": objectID, "customer_id": customerID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("wishlist not found")
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"products-api/internal/domain"
+	"time"
+)
+
+// AbandonedCartsRepository define las consultas del job de carritos abandonados
+type AbandonedCartsRepository interface {
+	FindAbandoned(ctx context.Context, idleSince, remindBefore time.Time, maxReminders, limit int) ([]domain.Cart, error)
+	MarkReminded(ctx context.Context, cart domain.Cart, at time.Time) (bool, error)
+	PurgeEmpty(ctx context.Context, before time.Time) (int64, error)
+}
+
+// CartEventsPublisher publica los eventos de carritos (RabbitMQ)
+type CartEventsPublisher interface {
+	PublishCartEvent(ctx context.Context, event domain.CartEvent) error
+}
+
+// AbandonedCartsPolicy define cuándo un carrito se considera abandonado y cada cuánto se recuerda
+type AbandonedCartsPolicy struct {
+	IdleAfter        time.Duration // Tiempo sin modificaciones para considerar el carrito abandonado
+	ReminderInterval time.Duration // Tiempo mínimo entre dos recordatorios al mismo cliente
+	MaxReminders     int           // Recordatorios por abandono; se reinicia si el cliente vuelve a modificar el carrito
+	PurgeEmptyAfter  time.Duration // Los carritos vacíos sin modificaciones durante este tiempo se eliminan
+	BatchSize        int           // Carritos que se procesan por corrida
+}
+
+// AbandonedCartsServiceImpl detecta carritos abandonados, publica cart.abandoned y limpia los carritos vacíos viejos
+type AbandonedCartsServiceImpl struct {
+	repository AbandonedCartsRepository
+	carts      *CartServiceImpl
+	events     CartEventsPublisher
+	policy     AbandonedCartsPolicy
+}
+
+// NewAbandonedCartsService crea una nueva instancia del service
+func NewAbandonedCartsService(repository AbandonedCartsRepository, carts *CartServiceImpl, events CartEventsPublisher, policy AbandonedCartsPolicy) *AbandonedCartsServiceImpl {
+	if policy.BatchSize <= 0 {
+		policy.BatchSize = 100
+	}
+	return &AbandonedCartsServiceImpl{
+		repository: repository,
+		carts:      carts,
+		events:     events,
+		policy:     policy,
+	}
+}
+
+// StartWorker corre el job cada interval hasta que se cancele el context
+func (s *AbandonedCartsServiceImpl) StartWorker(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("🛑 Abandoned carts worker stopped")
+				return
+			case <-ticker.C:
+				s.Run(ctx)
+			}
+		}
+	}()
+}
+
+// Run publica un recordatorio por cada carrito abandonado y elimina los carritos vacíos viejos
+// Devuelve cuántos recordatorios se publicaron y cuántos carritos se eliminaron
+func (s *AbandonedCartsServiceImpl) Run(ctx context.Context) (int, int64) {
+	now := time.Now().UTC()
+
+	carts, err := s.repository.FindAbandoned(ctx, now.Add(-s.policy.IdleAfter), now.Add(-s.policy.ReminderInterval), s.policy.MaxReminders, s.policy.BatchSize)
+	if err != nil {
+		log.Printf("❌ Error finding abandoned carts: %v", err)
+	}
+
+	reminded := 0
+	for _, cart := range carts {
+		if !s.policy.dueForReminder(cart, now) {
+			continue
+		}
+		if err := s.remind(ctx, cart, now); err != nil {
+			log.Printf("⚠️ Error reminding abandoned cart of customer %d: %v", cart.CustomerID, err)
+			continue
+		}
+		reminded++
+	}
+
+	purged, err := s.repository.PurgeEmpty(ctx, now.Add(-s.policy.PurgeEmptyAfter))
+	if err != nil {
+		log.Printf("❌ Error purging empty carts: %v", err)
+	}
+
+	if reminded > 0 || purged > 0 {
+		log.Printf("🛒 Abandoned carts: %d reminders published, %d empty carts purged", reminded, purged)
+	}
+	return reminded, purged
+}
+
+// remind registra el recordatorio y publica cart.abandoned con el carrito enriquecido
+// El recordatorio se registra antes de publicar: si dos instancias toman el mismo carrito solo una lo publica
+func (s *AbandonedCartsServiceImpl) remind(ctx context.Context, cart domain.Cart, now time.Time) error {
+	enriched, err := s.carts.enrichCart(ctx, cart)
+	if err != nil {
+		return fmt.Errorf("error enriching cart: %w", err)
+	}
+	if enriched.ItemCount == 0 {
+		// Ninguno de sus productos existe más: no hay nada que recordar
+		return nil
+	}
+
+	claimed, err := s.repository.MarkReminded(ctx, cart, now)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		// El cliente modificó el carrito o ya se le recordó en otra corrida
+		return nil
+	}
+
+	reminderNumber := cart.ReminderCount + 1
+	event := domain.CartEvent{
+		ID:             fmt.Sprintf("%s:%s:%d", cart.ID, domain.CartEventAbandoned, reminderNumber),
+		Type:           domain.CartEventAbandoned,
+		OccurredAt:     now,
+		CustomerID:     cart.CustomerID,
+		ReminderNumber: reminderNumber,
+		IdleSince:      cart.UpdatedAt,
+		Cart:           enriched,
+	}
+	if err := s.events.PublishCartEvent(ctx, event); err != nil {
+		return fmt.Errorf("error publishing %s: %w", domain.CartEventAbandoned, err)
+	}
+
+	log.Printf("📣 Published %s for customer %d (reminder %d/%d)", domain.CartEventAbandoned, cart.CustomerID, reminderNumber, s.policy.MaxReminders)
+	return nil
+}
+
+// dueForReminder decide si a un carrito le corresponde un recordatorio ahora
+func (p AbandonedCartsPolicy) dueForReminder(cart domain.Cart, now time.Time) bool {
+	if cart.CustomerID <= 0 || len(cart.Items) == 0 {
+		return false
+	}
+	if now.Sub(cart.UpdatedAt) < p.IdleAfter {
+		return false
+	}
+	if cart.ReminderCount >= p.MaxReminders {
+		return false
+	}
+	if cart.LastReminderAt != nil && now.Sub(*cart.LastReminderAt) < p.ReminderInterval {
+		return false
+	}
+	return true
+}
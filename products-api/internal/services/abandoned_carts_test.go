@@ -0,0 +1,31 @@
+package services
+
+import (
+	"products-api/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestAbandonedCartsPolicy_DueForReminder(t *testing.T) {
+	policy := AbandonedCartsPolicy{IdleAfter: 24 * time.Hour, ReminderInterval: 48 * time.Hour, MaxReminders: 2}
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	items := []domain.CartItem{{ItemID: "mate-1", Quantity: 1}}
+	recent := now.Add(-12 * time.Hour)
+
+	tests := []struct {
+		name string
+		cart domain.Cart
+		want bool
+	}{
+		{"idle cart without reminders", domain.Cart{CustomerID: 1, Items: items, UpdatedAt: now.Add(-25 * time.Hour)}, true},
+		{"recently updated", domain.Cart{CustomerID: 1, Items: items, UpdatedAt: now.Add(-2 * time.Hour)}, false},
+		{"empty cart", domain.Cart{CustomerID: 1, Items: []domain.CartItem{}, UpdatedAt: now.Add(-72 * time.Hour)}, false},
+		{"reminded too recently", domain.Cart{CustomerID: 1, Items: items, UpdatedAt: now.Add(-72 * time.Hour), ReminderCount: 1, LastReminderAt: &recent}, false},
+		{"max reminders reached", domain.Cart{CustomerID: 1, Items: items, UpdatedAt: now.Add(-240 * time.Hour), ReminderCount: 2}, false},
+	}
+	for _, tt := range tests {
+		if got := policy.dueForReminder(tt.cart, now); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
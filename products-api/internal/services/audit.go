@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"products-api/internal/domain"
+	"time"
+)
+
+// AuditRepository guarda los eventos de auditoría
+type AuditRepository interface {
+	Create(ctx context.Context, event domain.AuditEvent) (domain.AuditEvent, error)
+}
+
+// AuditServiceImpl registra los intentos de acceso denegados en un almacenamiento durable
+type AuditServiceImpl struct {
+	repository AuditRepository
+}
+
+// NewAuditService crea una nueva instancia del service
+func NewAuditService(repository AuditRepository) *AuditServiceImpl {
+	return &AuditServiceImpl{
+		repository: repository,
+	}
+}
+
+// RecordAccessDenied guarda un acceso denegado
+// También queda en el log del proceso, así el intento no se pierde si la base no responde
+func (s *AuditServiceImpl) RecordAccessDenied(ctx context.Context, event domain.AuditEvent) error {
+	event.Action = domain.AuditActionAccessDenied
+	event.CreatedAt = time.Now().UTC()
+
+	log.Printf("🚨 AUDIT access denied: user %d (admin=%t, role=%s) tried %s %s (customer=%d, permission=%s) from %s",
+		event.UserID, event.IsAdmin, event.Role, event.Method, event.Path, event.CustomerID, event.Permission, event.ClientIP)
+
+	if _, err := s.repository.Create(ctx, event); err != nil {
+		return fmt.Errorf("error saving audit event: %w", err)
+	}
+	return nil
+}
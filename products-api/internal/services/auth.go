@@ -2,79 +2,156 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"products-api/internal/domain"
 	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Errores de la verificación de tokens
+var (
+	ErrTokenRequired = errors.New("token is required")
+	ErrInvalidToken  = errors.New("invalid or expired token")
+	ErrNotAdmin      = errors.New("invalid admin token or insufficient permissions")
 )
 
+// tokenIssuer y tokenSubject identifican los access tokens de users-api
+// (los tokens de verificación de email tienen otro subject y no sirven acá)
+const (
+	tokenIssuer  = "backend"
+	tokenSubject = "auth"
+)
+
+// tokenClaims son los claims de los access tokens de users-api
+type tokenClaims struct {
+	IsAdmin       bool     `json:"is_admin"`
+	UserID        int      `json:"user_id"`
+	Role          string   `json:"role"`
+	Permissions   []string `json:"permissions"`
+	EmailVerified bool     `json:"email_verified"`
+	jwt.RegisteredClaims
+}
+
 type AuthServiceImpl struct {
 	usersAPIURL string
-	httpClient  *http.Client
+	keys        *JWKSCache
+	// introspection: si las claves no están disponibles se le pregunta a users-api por el token
+	introspection bool
+	httpClient    *http.Client
 }
 
 // NewAuthService crea una nueva instancia del servicio de autenticación
-func NewAuthService(usersAPIURL string) *AuthServiceImpl {
+// Los tokens se verifican localmente con las claves públicas de users-api (keys); si introspection está
+// activo y las claves no se pudieron traer, se verifican llamando a /auth/verify-token como antes
+func NewAuthService(usersAPIURL string, keys *JWKSCache, introspection bool) *AuthServiceImpl {
 	return &AuthServiceImpl{
-		usersAPIURL: usersAPIURL,
-		httpClient:  &http.Client{},
+		usersAPIURL:   usersAPIURL,
+		keys:          keys,
+		introspection: introspection,
+		httpClient:    &http.Client{Timeout: 5 * time.Second},
 	}
 }
 
-// VerifyToken verifica un token llamando al users-api
-func (s *AuthServiceImpl) VerifyToken(ctx context.Context, token string) error {
-	if strings.TrimSpace(token) == "" {
-		return errors.New("token is required")
+// VerifyToken verifica la firma y vigencia del token y devuelve sus claims
+func (s *AuthServiceImpl) VerifyToken(ctx context.Context, token string) (domain.AuthClaims, error) {
+	claims, err := s.verifyLocal(ctx, token)
+	if errors.Is(err, ErrKeysUnavailable) && s.introspection {
+		log.Printf("⚠️ JWKS unavailable, verifying token with users-api")
+		return s.introspect(ctx, token, "verify-token", ErrInvalidToken)
 	}
+	return claims, err
+}
 
-	url := fmt.Sprintf("%s/auth/verify-token", s.usersAPIURL)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+// VerifyAdminToken verifica el token y que sea de un admin
+func (s *AuthServiceImpl) VerifyAdminToken(ctx context.Context, token string) (domain.AuthClaims, error) {
+	claims, err := s.verifyLocal(ctx, token)
+	if errors.Is(err, ErrKeysUnavailable) && s.introspection {
+		log.Printf("⚠️ JWKS unavailable, verifying admin token with users-api")
+		return s.introspect(ctx, token, "verify-admin-token", ErrNotAdmin)
+	}
 	if err != nil {
-		return fmt.Errorf("error creating request: %w", err)
+		return domain.AuthClaims{}, err
+	}
+	if !claims.IsAdmin {
+		return domain.AuthClaims{}, ErrNotAdmin
 	}
+	return claims, nil
+}
 
-	req.Header.Set("Authorization", token)
-	req.Header.Set("Content-Type", "application/json")
+// verifyLocal valida el token con la clave del kid de su header, controlando que el algoritmo sea el de la clave
+func (s *AuthServiceImpl) verifyLocal(ctx context.Context, token string) (domain.AuthClaims, error) {
+	if strings.TrimSpace(token) == "" {
+		return domain.AuthClaims{}, ErrTokenRequired
+	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	var keyErr error
+	parsed, err := jwt.ParseWithClaims(token, &tokenClaims{}, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, alg, err := s.keys.Key(ctx, kid)
+		if err != nil {
+			keyErr = err
+			return nil, err
+		}
+		if t.Method.Alg() != alg {
+			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		return key, nil
+	},
+		jwt.WithValidMethods([]string{"RS256", "EdDSA"}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithSubject(tokenSubject),
+		jwt.WithExpirationRequired(),
+	)
+	if errors.Is(keyErr, ErrKeysUnavailable) {
+		return domain.AuthClaims{}, keyErr
+	}
 	if err != nil {
-		return fmt.Errorf("error calling users-api: %w", err)
+		return domain.AuthClaims{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return errors.New("invalid or expired token")
+	claims, ok := parsed.Claims.(*tokenClaims)
+	if !ok || !parsed.Valid || claims.UserID == 0 {
+		return domain.AuthClaims{}, ErrInvalidToken
 	}
-
-	return nil
+	return domain.AuthClaims{
+		UserID:        claims.UserID,
+		IsAdmin:       claims.IsAdmin,
+		Role:          claims.Role,
+		Permissions:   claims.Permissions,
+		EmailVerified: claims.EmailVerified,
+	}, nil
 }
 
-// VerifyAdminToken verifica un token de admin llamando al users-api
-func (s *AuthServiceImpl) VerifyAdminToken(ctx context.Context, token string) error {
-	if strings.TrimSpace(token) == "" {
-		return errors.New("token is required")
-	}
-
-	url := fmt.Sprintf("%s/auth/verify-admin-token", s.usersAPIURL)
+// introspect llama al endpoint de verificación de users-api, que responde con los claims del token
+func (s *AuthServiceImpl) introspect(ctx context.Context, token, endpoint string, errInvalid error) (domain.AuthClaims, error) {
+	url := fmt.Sprintf("%s/auth/%s", s.usersAPIURL, endpoint)
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
 	if err != nil {
-		return fmt.Errorf("error creating request: %w", err)
+		return domain.AuthClaims{}, fmt.Errorf("error creating request: %w", err)
 	}
 
 	req.Header.Set("Authorization", token)
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("error calling users-api: %w", err)
+		return domain.AuthClaims{}, fmt.Errorf("error calling users-api: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.New("invalid admin token or insufficient permissions")
+		return domain.AuthClaims{}, errInvalid
 	}
 
-	return nil
+	var claims domain.AuthClaims
+	if err := json.NewDecoder(resp.Body).Decode(&claims); err != nil {
+		return domain.AuthClaims{}, fmt.Errorf("error decoding token claims: %w", err)
+	}
+	return claims, nil
 }
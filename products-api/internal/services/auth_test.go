@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"products-api/internal/domain"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// testSigner simula una clave de users-api: firma tokens y se publica en el JWKS del server de prueba
+type testSigner struct {
+	kid     string
+	private ed25519.PrivateKey
+}
+
+func newTestSigner(t *testing.T, kid string) testSigner {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return testSigner{kid: kid, private: private}
+}
+
+func (s testSigner) sign(t *testing.T, userID int, isAdmin bool, expiresIn time.Duration) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, tokenClaims{
+		UserID:  userID,
+		IsAdmin: isAdmin,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   tokenSubject,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+		},
+	})
+	token.Header["kid"] = s.kid
+	signed, err := token.SignedString(s.private)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return signed
+}
+
+// newJWKSServer publica las claves de *signers y cuenta cuántas veces se pidió el JWKS
+func newJWKSServer(signers *[]testSigner, fetches *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(fetches, 1)
+		keys := []jwk{}
+		for _, s := range *signers {
+			keys = append(keys, jwk{
+				Kty: "OKP",
+				Kid: s.kid,
+				Alg: "EdDSA",
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(s.private.Public().(ed25519.PublicKey)),
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	}))
+}
+
+func TestAuthService_VerifiesLocallyAndRefreshesOnRotation(t *testing.T) {
+	previousDelay := jwksMinRefreshDelay
+	jwksMinRefreshDelay = 0
+	defer func() { jwksMinRefreshDelay = previousDelay }()
+
+	old := newTestSigner(t, "k1")
+	signers := []testSigner{old}
+	var fetches int32
+	server := newJWKSServer(&signers, &fetches)
+	defer server.Close()
+
+	service := NewAuthService(server.URL, NewJWKSCache(server.URL, time.Hour), false)
+	ctx := context.Background()
+
+	claims, err := service.VerifyToken(ctx, old.sign(t, 7, false, time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if claims.UserID != 7 || claims.IsAdmin {
+		t.Errorf("Expected user 7 without admin, got %+v", claims)
+	}
+
+	// Con el cache vigente no se vuelve a pedir el JWKS
+	if _, err := service.VerifyToken(ctx, old.sign(t, 7, false, time.Minute)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if fetches != 1 {
+		t.Errorf("Expected 1 JWKS fetch, got %d", fetches)
+	}
+
+	// users-api rota la clave: el kid nuevo dispara un refresh
+	current := newTestSigner(t, "k2")
+	signers = []testSigner{old, current}
+	if _, err := service.VerifyAdminToken(ctx, current.sign(t, 1, true, time.Minute)); err != nil {
+		t.Fatalf("Expected the rotated key to verify, got %v", err)
+	}
+	if fetches != 2 {
+		t.Errorf("Expected a refresh for the new kid, got %d fetches", fetches)
+	}
+
+	if _, err := service.VerifyAdminToken(ctx, current.sign(t, 7, false, time.Minute)); !errors.Is(err, ErrNotAdmin) {
+		t.Errorf("Expected ErrNotAdmin, got %v", err)
+	}
+	if _, err := service.VerifyToken(ctx, current.sign(t, 7, false, -time.Minute)); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected an expired token to be invalid, got %v", err)
+	}
+
+	// Un kid que users-api no publica no se acepta
+	stranger := newTestSigner(t, "k3")
+	if _, err := service.VerifyToken(ctx, stranger.sign(t, 7, false, time.Minute)); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected an unknown kid to be invalid, got %v", err)
+	}
+
+	// Tampoco un HS256 con el kid de una clave publicada
+	hmac := jwt.NewWithClaims(jwt.SigningMethodHS256, tokenClaims{UserID: 1, IsAdmin: true, RegisteredClaims: jwt.RegisteredClaims{
+		Issuer: tokenIssuer, Subject: tokenSubject, ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}})
+	hmac.Header["kid"] = current.kid
+	forged, _ := hmac.SignedString([]byte("jwtSecret"))
+	if _, err := service.VerifyAdminToken(ctx, forged); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected an HS256 token to be invalid, got %v", err)
+	}
+}
+
+func TestAuthService_IntrospectionFallback(t *testing.T) {
+	token := newTestSigner(t, "k1").sign(t, 9, false, time.Minute)
+
+	// users-api sin JWKS pero con el endpoint de verificación
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/auth/verify-token" || r.Header.Get("Authorization") != token {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"user_id": 9, "is_admin": false})
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	withoutFallback := NewAuthService(server.URL, NewJWKSCache(server.URL+"/.well-known/jwks.json", time.Hour), false)
+	if _, err := withoutFallback.VerifyToken(ctx, token); !errors.Is(err, ErrKeysUnavailable) {
+		t.Errorf("Expected ErrKeysUnavailable without fallback, got %v", err)
+	}
+
+	withFallback := NewAuthService(server.URL, NewJWKSCache(server.URL+"/.well-known/jwks.json", time.Hour), true)
+	claims, err := withFallback.VerifyToken(ctx, token)
+	if err != nil {
+		t.Fatalf("Expected the fallback to verify the token, got %v", err)
+	}
+	if claims.UserID != 9 {
+		t.Errorf("Expected user 9, got %+v", claims)
+	}
+}
+
+func TestAuthService_ExposesRoleAndPermissions(t *testing.T) {
+	signer := newTestSigner(t, "k1")
+	signers := []testSigner{signer}
+	var fetches int32
+	server := newJWKSServer(&signers, &fetches)
+	defer server.Close()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, tokenClaims{
+		UserID:      3,
+		Role:        "order_operator",
+		Permissions: []string{domain.PermSalesRead, domain.PermSalesWrite, domain.PermOrdersWrite},
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   tokenSubject,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	})
+	token.Header["kid"] = signer.kid
+	signed, _ := token.SignedString(signer.private)
+
+	service := NewAuthService(server.URL, NewJWKSCache(server.URL, time.Hour), false)
+	claims, err := service.VerifyToken(context.Background(), signed)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if claims.Role != "order_operator" || !claims.HasPermission(domain.PermOrdersWrite) || claims.HasPermission(domain.PermItemsWrite) {
+		t.Errorf("Expected order operator permissions, got %+v", claims)
+	}
+	// Un operador ve las ventas de otros clientes por permiso, no por ser su dueño
+	if claims.CanAccessCustomer(10) {
+		t.Error("Expected an order operator not to own other customers' data")
+	}
+	if _, err := service.VerifyAdminToken(context.Background(), signed); !errors.Is(err, ErrNotAdmin) {
+		t.Errorf("Expected ErrNotAdmin for an order operator, got %v", err)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log"
 	"products-api/internal/domain"
+	"strings"
+	"time"
 )
 
 // CartRepository define las operaciones de datos para Cart
@@ -17,21 +19,45 @@ type CartRepository interface {
 	Upsert(ctx context.Context, cart domain.Cart) (domain.Cart, error)
 }
 
+// GuestCartRepository define las operaciones de datos de los carritos de invitados
+// Los carritos vencidos tienen que desaparecer solos (GetByToken no los devuelve)
+type GuestCartRepository interface {
+	GetByToken(ctx context.Context, token string) (domain.Cart, error)
+	Upsert(ctx context.Context, cart domain.Cart, expiresAt time.Time) (domain.Cart, error)
+	Delete(ctx context.Context, token string) error
+}
+
+// ErrCartChanged se devuelve en el checkout si alguna línea cambió (precio, stock, producto eliminado)
+// desde que el cliente la agregó y todavía no aceptó los cambios
+var ErrCartChanged = errors.New("cart has changes since items were added: review and acknowledge them before checkout")
+
+// ErrCartItemNotFound se devuelve al modificar o quitar un producto que no está en el carrito
+var ErrCartItemNotFound = errors.New("item not found in cart")
+
 // CartServiceImpl implementa CartService
 type CartServiceImpl struct {
 	repository   CartRepository
 	localCache   CartRepository
 	itemsService ItemsService
-	salesService *SalesServiceImpl
+	checkoutSaga *CheckoutSagaServiceImpl
+	shipping     *ShippingServiceImpl
+	pricing      *PricingServiceImpl
+	guestCarts   GuestCartRepository
+	guestTTL     time.Duration
 }
 
 // NewCartService crea una nueva instancia del service
-func NewCartService(repository CartRepository, cache CartRepository, itemsService ItemsService, salesService *SalesServiceImpl) *CartServiceImpl {
+// guestTTL es cuánto vive un carrito de invitado desde su última modificación
+func NewCartService(repository CartRepository, cache CartRepository, itemsService ItemsService, checkoutSaga *CheckoutSagaServiceImpl, shipping *ShippingServiceImpl, pricing *PricingServiceImpl, guestCarts GuestCartRepository, guestTTL time.Duration) *CartServiceImpl {
 	return &CartServiceImpl{
 		repository:   repository,
 		localCache:   cache,
 		itemsService: itemsService,
-		salesService: salesService,
+		checkoutSaga: checkoutSaga,
+		shipping:     shipping,
+		pricing:      pricing,
+		guestCarts:   guestCarts,
+		guestTTL:     guestTTL,
 	}
 }
 
@@ -48,13 +74,24 @@ func (s *CartServiceImpl) CreateCart(ctx context.Context, customerID int) (domai
 
 // GetCart obtiene el carrito de un cliente con información enriquecida
 func (s *CartServiceImpl) GetCart(ctx context.Context, customerID int) (domain.CartResponse, error) {
+	cart, err := s.loadCart(ctx, customerID)
+	if err != nil {
+		return domain.CartResponse{}, err
+	}
+
+	// Enriquecer el carrito con información de los productos
+	return s.enrichCart(ctx, cart)
+}
+
+// loadCart obtiene el carrito de un cliente, primero del cache
+func (s *CartServiceImpl) loadCart(ctx context.Context, customerID int) (domain.Cart, error) {
 	// Intentar obtener del cache primero
 	cart, err := s.localCache.GetByCustomerID(ctx, customerID)
 	if err != nil {
 		// Si no está en cache, buscar en repository
 		cart, err = s.repository.GetByCustomerID(ctx, customerID)
 		if err != nil {
-			return domain.CartResponse{}, fmt.Errorf("error getting cart from repository: %w", err)
+			return domain.Cart{}, fmt.Errorf("error getting cart from repository: %w", err)
 		}
 		log.Printf("🔍 Cache MISS - Cart fetched from repository for customer: %d", customerID)
 
@@ -63,27 +100,11 @@ func (s *CartServiceImpl) GetCart(ctx context.Context, customerID int) (domain.C
 	} else {
 		log.Printf("✅ Cache HIT - Cart fetched from cache for customer: %d", customerID)
 	}
-
-	// Enriquecer el carrito con información de los productos
-	return s.enrichCart(ctx, cart)
+	return cart, nil
 }
 
 // AddItem agrega un producto al carrito o incrementa su cantidad
 func (s *CartServiceImpl) AddItem(ctx context.Context, customerID int, req domain.AddItemRequest) (domain.CartResponse, error) {
-	// Validar que el producto exista y tenga stock
-	item, err := s.itemsService.GetByID(ctx, req.ItemID)
-	if err != nil {
-		return domain.CartResponse{}, fmt.Errorf("error getting item: %w", err)
-	}
-
-	if item.ID == "" {
-		return domain.CartResponse{}, errors.New("item does not exist")
-	}
-
-	if item.Stock < req.Quantity {
-		return domain.CartResponse{}, fmt.Errorf("insufficient stock: requested %d, available %d", req.Quantity, item.Stock)
-	}
-
 	// Obtener carrito actual
 	cart, err := s.repository.GetByCustomerID(ctx, customerID)
 	if err != nil {
@@ -95,27 +116,9 @@ func (s *CartServiceImpl) AddItem(ctx context.Context, customerID int, req domai
 		}
 	}
 
-	// Buscar si el item ya está en el carrito
-	found := false
-	for i, cartItem := range cart.Items {
-		if cartItem.ItemID == req.ItemID {
-			// Validar que la cantidad total no exceda el stock
-			newQuantity := cartItem.Quantity + req.Quantity
-			if newQuantity > item.Stock {
-				return domain.CartResponse{}, fmt.Errorf("insufficient stock: total quantity %d exceeds available %d", newQuantity, item.Stock)
-			}
-			cart.Items[i].Quantity = newQuantity
-			found = true
-			break
-		}
-	}
-
-	// Si no está en el carrito, agregarlo
-	if !found {
-		cart.Items = append(cart.Items, domain.CartItem{
-			ItemID:   req.ItemID,
-			Quantity: req.Quantity,
-		})
+	cart, err = s.addToCart(ctx, cart, req)
+	if err != nil {
+		return domain.CartResponse{}, err
 	}
 
 	// Actualizar en la base de datos (upsert)
@@ -146,28 +149,9 @@ func (s *CartServiceImpl) UpdateItemCart(ctx context.Context, customerID int, it
 		return s.RemoveItem(ctx, customerID, itemID)
 	}
 
-	// Validar stock disponible
-	item, err := s.itemsService.GetByID(ctx, itemID)
+	cart, err = s.setItemQuantity(ctx, cart, itemID, req.Quantity)
 	if err != nil {
-		return domain.CartResponse{}, fmt.Errorf("error getting item: %w", err)
-	}
-
-	if item.Stock < req.Quantity {
-		return domain.CartResponse{}, fmt.Errorf("insufficient stock: requested %d, available %d", req.Quantity, item.Stock)
-	}
-
-	// Buscar el item en el carrito y actualizar
-	found := false
-	for i, cartItem := range cart.Items {
-		if cartItem.ItemID == itemID {
-			cart.Items[i].Quantity = req.Quantity
-			found = true
-			break
-		}
-	}
-
-	if !found {
-		return domain.CartResponse{}, errors.New("item not found in cart")
+		return domain.CartResponse{}, err
 	}
 
 	// Actualizar en la base de datos
@@ -192,23 +176,11 @@ func (s *CartServiceImpl) RemoveItem(ctx context.Context, customerID int, itemID
 		return domain.CartResponse{}, fmt.Errorf("cart not found: %w", err)
 	}
 
-	// Filtrar el item a eliminar
-	newItems := []domain.CartItem{}
-	found := false
-	for _, cartItem := range cart.Items {
-		if cartItem.ItemID != itemID {
-			newItems = append(newItems, cartItem)
-		} else {
-			found = true
-		}
-	}
-
-	if !found {
-		return domain.CartResponse{}, errors.New("item not found in cart")
+	cart, err = removeFromCart(cart, itemID)
+	if err != nil {
+		return domain.CartResponse{}, err
 	}
 
-	cart.Items = newItems
-
 	// Si el carrito quedó vacío, podríamos eliminarlo completamente
 	// Pero es mejor dejarlo vacío para mantener la referencia
 	cart, err = s.repository.Update(ctx, customerID, cart)
@@ -244,96 +216,275 @@ func (s *CartServiceImpl) ClearCart(ctx context.Context, customerID int) error {
 	return nil
 }
 
+// ApplyCoupon aplica un cupón al carrito. Si el cupón no es válido para el carrito actual no se guarda
+// Un carrito tiene a lo sumo un cupón: aplicar otro reemplaza al anterior
+func (s *CartServiceImpl) ApplyCoupon(ctx context.Context, customerID int, req domain.ApplyCouponRequest) (domain.CartResponse, error) {
+	cart, err := s.repository.GetByCustomerID(ctx, customerID)
+	if err != nil {
+		return domain.CartResponse{}, fmt.Errorf("cart not found: %w", err)
+	}
+	if len(cart.Items) == 0 {
+		return domain.CartResponse{}, errors.New("cart is empty")
+	}
+
+	// Se valida con las promociones ya aplicadas, igual que en el checkout
+	_, pricing, err := s.priceCart(ctx, domain.Cart{CustomerID: customerID, Items: cart.Items, CouponCode: req.Code})
+	if err != nil {
+		return domain.CartResponse{}, err
+	}
+	if pricing.CouponErr != nil {
+		return domain.CartResponse{}, pricing.CouponErr
+	}
+
+	cart.CouponCode = pricing.CouponCode
+	cart, err = s.repository.Update(ctx, customerID, cart)
+	if err != nil {
+		return domain.CartResponse{}, fmt.Errorf("error updating cart: %w", err)
+	}
+	_, _ = s.localCache.Update(ctx, customerID, cart)
+
+	log.Printf("🎟️ Coupon %s applied to cart - Customer: %d", cart.CouponCode, customerID)
+	return s.enrichCart(ctx, cart)
+}
+
+// RemoveCoupon quita el cupón del carrito
+func (s *CartServiceImpl) RemoveCoupon(ctx context.Context, customerID int) (domain.CartResponse, error) {
+	cart, err := s.repository.GetByCustomerID(ctx, customerID)
+	if err != nil {
+		return domain.CartResponse{}, fmt.Errorf("cart not found: %w", err)
+	}
+
+	cart.CouponCode = ""
+	cart, err = s.repository.Update(ctx, customerID, cart)
+	if err != nil {
+		return domain.CartResponse{}, fmt.Errorf("error updating cart: %w", err)
+	}
+	_, _ = s.localCache.Update(ctx, customerID, cart)
+
+	log.Printf("🎟️ Coupon removed from cart - Customer: %d", customerID)
+	return s.enrichCart(ctx, cart)
+}
+
+// AcknowledgeChanges acepta los cambios del carrito: las líneas pasan a tener el precio actual,
+// las cantidades se limitan al stock y se quitan los productos eliminados o sin stock
+func (s *CartServiceImpl) AcknowledgeChanges(ctx context.Context, customerID int) (domain.CartResponse, error) {
+	cart, err := s.repository.GetByCustomerID(ctx, customerID)
+	if err != nil {
+		return domain.CartResponse{}, fmt.Errorf("cart not found: %w", err)
+	}
+
+	items := []domain.CartItem{}
+	for _, cartItem := range cart.Items {
+		item, found, err := s.lookupItem(ctx, cartItem.ItemID)
+		if err != nil {
+			return domain.CartResponse{}, err
+		}
+		if !found || item.Stock <= 0 {
+			continue
+		}
+		cartItem.Quantity = min(cartItem.Quantity, item.Stock)
+		cartItem.PriceAtAdd = item.Price
+		items = append(items, cartItem)
+	}
+	cart.Items = items
+
+	cart, err = s.repository.Update(ctx, customerID, cart)
+	if err != nil {
+		return domain.CartResponse{}, fmt.Errorf("error updating cart: %w", err)
+	}
+	_, _ = s.localCache.Update(ctx, customerID, cart)
+
+	log.Printf("✅ Cart changes acknowledged - Customer: %d", customerID)
+	return s.enrichCart(ctx, cart)
+}
+
 // Checkout procesa la compra del carrito
-func (s *CartServiceImpl) Checkout(ctx context.Context, customerID int) ([]domain.Sales, error) {
+// La compra se ejecuta como una saga persistida (ver CheckoutSagaServiceImpl):
+// si algún paso falla se compensan los anteriores y el stock vuelve a su valor original
+func (s *CartServiceImpl) Checkout(ctx context.Context, customerID int, req domain.CheckoutRequest) (domain.CheckoutResult, error) {
 	// Obtener carrito
 	cart, err := s.repository.GetByCustomerID(ctx, customerID)
 	if err != nil {
-		return nil, fmt.Errorf("cart not found: %w", err)
+		return domain.CheckoutResult{}, fmt.Errorf("cart not found: %w", err)
 	}
 
 	if len(cart.Items) == 0 {
-		return nil, errors.New("cart is empty")
+		return domain.CheckoutResult{}, errors.New("cart is empty")
 	}
 
-	// Validar stock de todos los items antes de procesar
-	for _, cartItem := range cart.Items {
+	// No se cobra un precio distinto del que el cliente vio sin que lo acepte
+	response, err := s.enrichCart(ctx, cart)
+	if err != nil {
+		return domain.CheckoutResult{}, err
+	}
+	if response.HasChanges {
+		return domain.CheckoutResult{}, ErrCartChanged
+	}
 
-		item, err := s.itemsService.GetByID(ctx, cartItem.ItemID)
-		if err != nil {
-			return nil, fmt.Errorf("error validating item %s: %w", cartItem.ItemID, err)
-		}
+	return s.checkoutSaga.Start(ctx, cart, req)
+}
 
-		s.itemsService.DecrementStockAtomic(ctx, cartItem.ItemID, cartItem.Quantity)
+// Quote cotiza el carrito para un destino: subtotal de productos - descuentos + costo de envío, con IVA y cuotas
+// Usa las mismas reglas de precios y de envío que el checkout, así el total cotizado es el que se cobra
+func (s *CartServiceImpl) Quote(ctx context.Context, customerID int, req domain.CartQuoteRequest) (domain.CartQuote, error) {
+	cart, err := s.loadCart(ctx, customerID)
+	if err != nil {
+		return domain.CartQuote{}, err
+	}
+	if len(cart.Items) == 0 {
+		return domain.CartQuote{}, errors.New("cart is empty")
+	}
 
-		if item.Stock < cartItem.Quantity {
-			return nil, fmt.Errorf("insufficient stock for item %s: requested %d, available %d", item.Name, cartItem.Quantity, item.Stock)
-		}
+	// Cotizar con otro cupón (o sin cupón) no lo aplica al carrito
+	if req.CouponCode != nil {
+		cart.CouponCode = NormalizeCouponCode(*req.CouponCode)
+	}
+	priced, _, err := s.priceCart(ctx, cart)
+	if err != nil {
+		return domain.CartQuote{}, err
+	}
+
+	method, address, err := s.shipping.ResolveAddress(ctx, customerID, req.ShippingRequest)
+	if err != nil {
+		return domain.CartQuote{}, err
 	}
 
-	// Crear una venta por cada item del carrito
-	sales := []domain.Sales{}
+	weightKg := 0.0
+	for _, item := range priced.Items {
+		weightKg += item.WeightKg * float64(item.Quantity)
+	}
+	shipping, err := s.shipping.Quote(method, address, weightKg)
+	if err != nil {
+		return domain.CartQuote{}, err
+	}
 
-	for _, cartItem := range cart.Items {
-		saleData := domain.BodySales{
-			ItemID:     cartItem.ItemID,
-			Quantity:   cartItem.Quantity,
-			CustomerID: fmt.Sprintf("%d", customerID),
-		}
+	taxLines := make([]domain.TaxBreakdown, 0, len(priced.Items))
+	for _, item := range priced.Items {
+		taxLines = append(taxLines, item.Tax)
+	}
+	taxes := s.pricing.OrderTaxes(taxLines, shipping.Cost)
+
+	return domain.CartQuote{
+		CustomerID:      customerID,
+		Items:           priced.Items,
+		ItemCount:       priced.ItemCount,
+		Subtotal:        priced.Subtotal,
+		Discounts:       priced.Discounts,
+		DiscountTotal:   priced.DiscountTotal,
+		CouponCode:      priced.CouponCode,
+		CouponError:     priced.CouponError,
+		Shipping:        shipping,
+		ShippingTax:     s.pricing.ShippingTax(shipping.Cost),
+		ShippingAddress: address,
+		Taxes:           taxes,
+		Total:           taxes.Gross,
+		Installments:    s.pricing.Installments(taxes.Gross),
+		HasChanges:      priced.HasChanges,
+	}, nil
+}
 
-		// Crear la venta usando el salesService
-		sale, err := s.salesService.Create(ctx, saleData)
-		if err != nil {
-			// Si falla alguna venta, intentar revertir las ventas ya creadas
-			log.Printf("❌ Error creating sale during checkout: %v", err)
-
-			// Revertir ventas ya creadas
-			for _, createdSale := range sales {
-				if deleteErr := s.salesService.Delete(ctx, createdSale.ID); deleteErr != nil {
-					log.Printf("⚠️ Error reverting sale %s: %v", createdSale.ID, deleteErr)
-				}
+// addToCart valida que el producto exista y tenga stock, y lo suma al carrito
+func (s *CartServiceImpl) addToCart(ctx context.Context, cart domain.Cart, req domain.AddItemRequest) (domain.Cart, error) {
+	// Validar que el producto exista y tenga stock
+	item, err := s.itemsService.GetByID(ctx, req.ItemID)
+	if err != nil {
+		return domain.Cart{}, fmt.Errorf("error getting item: %w", err)
+	}
+
+	if item.ID == "" {
+		return domain.Cart{}, errors.New("item does not exist")
+	}
+
+	if item.Stock < req.Quantity {
+		return domain.Cart{}, fmt.Errorf("insufficient stock: requested %d, available %d", req.Quantity, item.Stock)
+	}
+
+	// Buscar si el item ya está en el carrito
+	for i, cartItem := range cart.Items {
+		if cartItem.ItemID == req.ItemID {
+			// Validar que la cantidad total no exceda el stock
+			newQuantity := cartItem.Quantity + req.Quantity
+			if newQuantity > item.Stock {
+				return domain.Cart{}, fmt.Errorf("insufficient stock: total quantity %d exceeds available %d", newQuantity, item.Stock)
 			}
+			cart.Items[i].Quantity = newQuantity
+			return cart, nil
+		}
+	}
+
+	// Si no está en el carrito, agregarlo
+	cart.Items = append(cart.Items, domain.CartItem{
+		ItemID:     req.ItemID,
+		Quantity:   req.Quantity,
+		PriceAtAdd: item.Price,
+	})
+	return cart, nil
+}
 
-			return nil, fmt.Errorf("error creating sale for item %s: %w", cartItem.ItemID, err)
+// setItemQuantity cambia la cantidad de un producto que ya está en el carrito, validando el stock
+func (s *CartServiceImpl) setItemQuantity(ctx context.Context, cart domain.Cart, itemID string, quantity int) (domain.Cart, error) {
+	// Validar stock disponible
+	item, err := s.itemsService.GetByID(ctx, itemID)
+	if err != nil {
+		return domain.Cart{}, fmt.Errorf("error getting item: %w", err)
+	}
+
+	if item.Stock < quantity {
+		return domain.Cart{}, fmt.Errorf("insufficient stock: requested %d, available %d", quantity, item.Stock)
+	}
+
+	// Buscar el item en el carrito y actualizar
+	for i, cartItem := range cart.Items {
+		if cartItem.ItemID == itemID {
+			cart.Items[i].Quantity = quantity
+			return cart, nil
 		}
+	}
 
-		sales = append(sales, sale)
-		log.Printf("✅ Sale created - ID: %s, Item: %s, Quantity: %d", sale.ID, cartItem.ItemID, cartItem.Quantity)
+	return domain.Cart{}, ErrCartItemNotFound
+}
+
+// removeFromCart saca un producto del carrito
+func removeFromCart(cart domain.Cart, itemID string) (domain.Cart, error) {
+	newItems := []domain.CartItem{}
+	found := false
+	for _, cartItem := range cart.Items {
+		if cartItem.ItemID != itemID {
+			newItems = append(newItems, cartItem)
+		} else {
+			found = true
+		}
 	}
 
-	// Vaciar el carrito después del checkout exitoso
-	err = s.ClearCart(ctx, customerID)
-	if err != nil {
-		log.Printf("⚠️ Warning: Could not clear cart after checkout: %v", err)
+	if !found {
+		return domain.Cart{}, ErrCartItemNotFound
 	}
 
-	log.Printf("🎉 Checkout completed for customer: %d, total items: %d, total sales: %d", customerID, len(cart.Items), len(sales))
-	return sales, nil
+	cart.Items = newItems
+	return cart, nil
 }
 
-// enrichCart enriquece el carrito con información completa de los productos
+// enrichCart enriquece el carrito con información completa de los productos y sus descuentos
 func (s *CartServiceImpl) enrichCart(ctx context.Context, cart domain.Cart) (domain.CartResponse, error) {
+	response, _, err := s.priceCart(ctx, cart)
+	return response, err
+}
+
+// priceCart arma la respuesta del carrito con los precios actuales y aplica promociones y cupón
+func (s *CartServiceImpl) priceCart(ctx context.Context, cart domain.Cart) (domain.CartResponse, PricingResult, error) {
 	itemsWithDetails := []domain.CartItemWithDetails{}
+	deletedItems := []domain.CartItemWithDetails{}
 	totalItems := 0
 
 	for _, cartItem := range cart.Items {
-		// Obtener información completa del producto
-		item, err := s.itemsService.GetByID(ctx, cartItem.ItemID)
+		itemWithDetails, found, err := s.enrichLine(ctx, cartItem)
 		if err != nil {
-			log.Printf("⚠️ Warning: Could not get item details for %s: %v", cartItem.ItemID, err)
-			// Continuar con los demás items
-			continue
+			return domain.CartResponse{}, PricingResult{}, err
 		}
-
-		itemWithDetails := domain.CartItemWithDetails{
-			ItemID:      cartItem.ItemID,                         // Traigo ID del cart
-			Name:        item.Name,                               // Traigo nombre del producto
-			Description: item.Description,                        // Traigo descripción del producto
-			ImageURL:    item.ImageURL,                           // Traigo imagen del producto
-			Price:       item.Price,                              // Usar el precio actual del producto
-			Quantity:    cartItem.Quantity,                       // Traigo cantidad del cart
-			Subtotal:    item.Price * float64(cartItem.Quantity), // Calculo subtotal con precio actual
-			Stock:       item.Stock,                              // Traigo stock actual del producto
+		if !found {
+			// El producto se eliminó: la línea se muestra marcada y no suma al total
+			deletedItems = append(deletedItems, itemWithDetails)
+			continue
 		}
 
 		itemsWithDetails = append(itemsWithDetails, itemWithDetails)
@@ -341,18 +492,158 @@ func (s *CartServiceImpl) enrichCart(ctx context.Context, cart domain.Cart) (dom
 	}
 
 	// Recalcular el total basado en los precios actuales
-	total := 0.0
+	subtotal := 0.0
 	for _, item := range itemsWithDetails {
-		total += item.Subtotal
+		subtotal += item.Subtotal
+	}
+	subtotal = roundMoney(subtotal)
+
+	response := domain.CartResponse{
+		ID:           cart.ID,
+		CustomerID:   cart.CustomerID,
+		SessionToken: cart.SessionToken,
+		ExpiresAt:    cart.ExpiresAt,
+		Items:        itemsWithDetails,
+		Subtotal:     subtotal,
+		Discounts:    []domain.Discount{},
+		Total:        subtotal,
+		ItemCount:    totalItems,
+		CouponCode:   cart.CouponCode,
+		CreatedAt:    cart.CreatedAt,
+		UpdatedAt:    cart.UpdatedAt,
 	}
 
-	return domain.CartResponse{
-		ID:         cart.ID,
-		CustomerID: cart.CustomerID,
-		Items:      itemsWithDetails,
-		Total:      total,
-		ItemCount:  totalItems,
-		CreatedAt:  cart.CreatedAt,
-		UpdatedAt:  cart.UpdatedAt,
-	}, nil
+	// Promociones y cupón se recalculan con los precios actuales: si el cupón dejó de aplicar se informa el motivo
+	pricing, err := s.pricing.Discounts(ctx, cart.CustomerID, cart.CouponCode, discountLines(itemsWithDetails))
+	if err != nil {
+		return domain.CartResponse{}, PricingResult{}, err
+	}
+	if pricing.CouponErr != nil {
+		response.CouponError = pricing.CouponErr.Error()
+	}
+	response.Discounts = pricing.Discounts
+	response.DiscountTotal = pricing.DiscountTotal
+	for _, discount := range pricing.Discounts {
+		applyLineDiscounts(response.Items, discount)
+	}
+
+	// El IVA se calcula sobre cada línea ya descontada
+	taxLines := make([]domain.TaxBreakdown, len(response.Items))
+	for i, item := range response.Items {
+		response.Items[i].Tax = s.pricing.LineTax(item.TaxClass, item.Subtotal, item.Discount)
+		taxLines[i] = response.Items[i].Tax
+	}
+	response.Taxes = s.pricing.SummarizeTaxes(taxLines)
+	response.Total = response.Taxes.Gross
+
+	response.Items = append(response.Items, deletedItems...)
+	for _, item := range response.Items {
+		if len(item.Changes) > 0 {
+			response.HasChanges = true
+		}
+	}
+
+	return response, pricing, nil
+}
+
+// enrichLine completa una línea con los datos actuales del producto y lo que cambió desde que se agregó
+// Si el producto se eliminó devuelve found false y la línea marcada como eliminada
+// La usan el carrito y las listas de deseos
+func (s *CartServiceImpl) enrichLine(ctx context.Context, cartItem domain.CartItem) (domain.CartItemWithDetails, bool, error) {
+	// Obtener información completa del producto
+	item, found, err := s.lookupItem(ctx, cartItem.ItemID)
+	if err != nil {
+		return domain.CartItemWithDetails{}, false, err
+	}
+	if !found {
+		return domain.CartItemWithDetails{
+			ItemID:     cartItem.ItemID,
+			Quantity:   cartItem.Quantity,
+			PriceAtAdd: cartItem.PriceAtAdd,
+			Changes:    []string{domain.CartLineItemDeleted},
+		}, false, nil
+	}
+
+	return domain.CartItemWithDetails{
+		ItemID:      cartItem.ItemID,                         // Traigo ID del cart
+		Name:        item.Name,                               // Traigo nombre del producto
+		Description: item.Description,                        // Traigo descripción del producto
+		Category:    item.Category,                           // Traigo categoría (para los descuentos)
+		ImageURL:    item.ImageURL,                           // Traigo imagen del producto
+		Price:       item.Price,                              // Usar el precio actual del producto
+		Quantity:    cartItem.Quantity,                       // Traigo cantidad del cart
+		Subtotal:    item.Price * float64(cartItem.Quantity), // Calculo subtotal con precio actual
+		Stock:       item.Stock,                              // Traigo stock actual del producto
+		WeightKg:    s.shipping.UnitWeightKg(item),           // Peso facturable para el envío
+		TaxClass:    NormalizeTaxClass(item.TaxClass),        // Alícuota de IVA del producto
+		PriceAtAdd:  cartItem.PriceAtAdd,                     // Precio que vio el cliente al agregarlo
+		Changes:     cartLineChanges(cartItem, item),         // Qué cambió desde entonces
+	}, true, nil
+}
+
+// lookupItem busca un producto; found es false si se eliminó del catálogo
+// Cualquier otro error (base de datos, cache) se devuelve para no mostrar un carrito incompleto
+func (s *CartServiceImpl) lookupItem(ctx context.Context, itemID string) (domain.Item, bool, error) {
+	item, err := s.itemsService.GetByID(ctx, itemID)
+	if err != nil {
+		if strings.Contains(err.Error(), "item not found") {
+			return domain.Item{}, false, nil
+		}
+		return domain.Item{}, false, fmt.Errorf("error getting item %s: %w", itemID, err)
+	}
+	if item.ID == "" {
+		return domain.Item{}, false, nil
+	}
+	return item, true, nil
+}
+
+// cartLineChanges compara la línea con el producto actual
+// Las líneas anteriores a que se guardara el precio (PriceAtAdd 0) solo se comparan por stock
+func cartLineChanges(cartItem domain.CartItem, item domain.Item) []string {
+	changes := []string{}
+	if cartItem.PriceAtAdd > 0 {
+		switch diff := roundMoney(item.Price - cartItem.PriceAtAdd); {
+		case diff > 0:
+			changes = append(changes, domain.CartLinePriceIncreased)
+		case diff < 0:
+			changes = append(changes, domain.CartLinePriceDecreased)
+		}
+	}
+	switch {
+	case item.Stock <= 0:
+		changes = append(changes, domain.CartLineOutOfStock)
+	case item.Stock < cartItem.Quantity:
+		changes = append(changes, domain.CartLineInsufficientStock)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
+
+// discountLines arma las líneas que necesita el motor de descuentos
+func discountLines(items []domain.CartItemWithDetails) []domain.DiscountLine {
+	lines := make([]domain.DiscountLine, len(items))
+	for i, item := range items {
+		lines[i] = domain.DiscountLine{
+			ItemID:    item.ItemID,
+			Category:  item.Category,
+			UnitPrice: item.Price,
+			Quantity:  item.Quantity,
+			Subtotal:  item.Subtotal,
+		}
+	}
+	return lines
+}
+
+// applyLineDiscounts suma a cada línea la parte del descuento que le corresponde y el motivo
+func applyLineDiscounts(items []domain.CartItemWithDetails, discount domain.Discount) {
+	for _, line := range discount.Lines {
+		for i := range items {
+			if items[i].ItemID == line.ItemID {
+				items[i].Discount = roundMoney(items[i].Discount + line.Amount)
+				items[i].DiscountReasons = append(items[i].DiscountReasons, line.Reason)
+			}
+		}
+	}
 }
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"products-api/internal/domain"
+	"reflect"
+	"testing"
+)
+
+func TestCartLineChanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		cartItem domain.CartItem
+		item     domain.Item
+		want     []string
+	}{
+		{"unchanged", domain.CartItem{Quantity: 2, PriceAtAdd: 1000}, domain.Item{Price: 1000, Stock: 5}, nil},
+		{"price up", domain.CartItem{Quantity: 1, PriceAtAdd: 1000}, domain.Item{Price: 1200, Stock: 5}, []string{domain.CartLinePriceIncreased}},
+		{"price down and short on stock", domain.CartItem{Quantity: 3, PriceAtAdd: 1000}, domain.Item{Price: 900, Stock: 2}, []string{domain.CartLinePriceDecreased, domain.CartLineInsufficientStock}},
+		{"out of stock", domain.CartItem{Quantity: 1, PriceAtAdd: 1000}, domain.Item{Price: 1000, Stock: 0}, []string{domain.CartLineOutOfStock}},
+		{"legacy line without price", domain.CartItem{Quantity: 1}, domain.Item{Price: 1500, Stock: 5}, nil},
+		{"rounding noise", domain.CartItem{Quantity: 1, PriceAtAdd: 10.1}, domain.Item{Price: 10.100000001, Stock: 5}, nil},
+	}
+	for _, tt := range tests {
+		if got := cartLineChanges(tt.cartItem, tt.item); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
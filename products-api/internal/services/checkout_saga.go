@@ -0,0 +1,592 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"products-api/internal/domain"
+	"strings"
+	"time"
+)
+
+// CheckoutSagaRepository persiste el estado de cada saga de checkout
+// Update solo guarda si la version no cambió desde la lectura (ver domain.CheckoutSaga)
+type CheckoutSagaRepository interface {
+	Create(ctx context.Context, saga domain.CheckoutSaga) (domain.CheckoutSaga, error)
+	Update(ctx context.Context, saga domain.CheckoutSaga) (domain.CheckoutSaga, error)
+	ClaimStale(ctx context.Context, leaseDuration time.Duration) (domain.CheckoutSaga, bool, error)
+}
+
+// OrdersRepository define las operaciones de datos para Orders
+type OrdersRepository interface {
+	Save(ctx context.Context, order domain.Order) (domain.Order, error)
+	GetByID(ctx context.Context, id string) (domain.Order, error)
+	UpdateStatus(ctx context.Context, id string, status string) error
+}
+
+// CheckoutPayments autoriza, cobra y reintegra el pago de una orden durante el checkout
+type CheckoutPayments interface {
+	Authorize(ctx context.Context, order domain.Order, method domain.PaymentMethod) (domain.Payment, error)
+	Capture(ctx context.Context, paymentID string) (domain.Payment, error)
+	Refund(ctx context.Context, paymentID string) (domain.Payment, error)
+}
+
+// ErrCheckoutSagaConflict indica que otra instancia tomó la saga (venció el lease): esta deja de ejecutarla
+var ErrCheckoutSagaConflict = errors.New("checkout saga taken over by another instance")
+
+// sagaRun guarda el estado en memoria de una ejecución de la saga
+// Los datos de la tarjeta solo viven acá: nunca se persisten con la saga
+type sagaRun struct {
+	saga    domain.CheckoutSaga
+	order   domain.Order
+	sales   []domain.Sales
+	payment domain.PaymentMethod
+}
+
+// sagaStep es un paso de la saga con su acción y su compensación
+// Las dos funciones tienen que ser idempotentes: se reintentan si el proceso se cae a mitad de camino
+type sagaStep struct {
+	name       string
+	action     func(ctx context.Context, run *sagaRun) error
+	compensate func(ctx context.Context, run *sagaRun) error
+}
+
+// CheckoutSagaServiceImpl orquesta el checkout como una saga persistida:
+// reservar stock -> crear la orden -> redimir el cupón -> cobrar -> vaciar el carrito
+type CheckoutSagaServiceImpl struct {
+	repository   CheckoutSagaRepository
+	orders       OrdersRepository
+	itemsService ItemsService
+	salesService *SalesServiceImpl
+	carts        CartRepository
+	cartCache    CartRepository
+	payments     CheckoutPayments
+	shipping     *ShippingServiceImpl
+	pricing      *PricingServiceImpl
+	coupons      *CouponsServiceImpl
+	events       OrderEventsPublisher
+	lease        time.Duration
+	steps        []sagaStep
+}
+
+// NewCheckoutSagaService crea una nueva instancia del orquestador
+func NewCheckoutSagaService(repository CheckoutSagaRepository, orders OrdersRepository, itemsService ItemsService, salesService *SalesServiceImpl, carts CartRepository, cartCache CartRepository, payments CheckoutPayments, shipping *ShippingServiceImpl, pricing *PricingServiceImpl, coupons *CouponsServiceImpl, events OrderEventsPublisher) *CheckoutSagaServiceImpl {
+	s := &CheckoutSagaServiceImpl{
+		repository:   repository,
+		orders:       orders,
+		itemsService: itemsService,
+		salesService: salesService,
+		carts:        carts,
+		cartCache:    cartCache,
+		payments:     payments,
+		shipping:     shipping,
+		pricing:      pricing,
+		coupons:      coupons,
+		events:       events,
+		lease:        time.Minute,
+	}
+	s.steps = []sagaStep{
+		{name: domain.SagaStepReserveStock, action: s.reserveStock, compensate: s.releaseStock},
+		{name: domain.SagaStepCreateOrder, action: s.createOrder, compensate: s.cancelOrder},
+		{name: domain.SagaStepRedeemCoupon, action: s.redeemCoupon, compensate: s.releaseCoupon},
+		{name: domain.SagaStepCapturePayment, action: s.capturePayment, compensate: s.refundPayment},
+		// Vaciar el carrito y confirmar el stock no se compensan: si fallan, la recuperación los reintenta
+		{name: domain.SagaStepClearCart, action: s.clearCart},
+		{name: domain.SagaStepConfirmStock, action: s.confirmStock},
+	}
+	return s
+}
+
+// Start ejecuta el checkout de un carrito. Si algún paso falla, compensa los anteriores
+func (s *CheckoutSagaServiceImpl) Start(ctx context.Context, cart domain.Cart, req domain.CheckoutRequest) (domain.CheckoutResult, error) {
+	if strings.TrimSpace(req.Payment.CardNumber) == "" {
+		return domain.CheckoutResult{}, ErrPaymentMethodRequired
+	}
+
+	shippingMethod, shippingAddress, err := s.shipping.ResolveAddress(ctx, cart.CustomerID, req.ShippingRequest)
+	if err != nil {
+		return domain.CheckoutResult{}, err
+	}
+
+	lines := make([]domain.SagaLine, len(cart.Items))
+	for i, cartItem := range cart.Items {
+		lines[i] = domain.SagaLine{OrderLine: domain.OrderLine{ItemID: cartItem.ItemID, Quantity: cartItem.Quantity}}
+	}
+
+	saga, err := s.repository.Create(ctx, domain.CheckoutSaga{
+		CustomerID:      cart.CustomerID,
+		Status:          domain.SagaStatusRunning,
+		Lines:           lines,
+		CompletedSteps:  []string{},
+		ShippingMethod:  shippingMethod,
+		ShippingAddress: shippingAddress,
+		CouponCode:      cart.CouponCode,
+		LockedUntil:     time.Now().UTC().Add(s.lease),
+	})
+	if err != nil {
+		return domain.CheckoutResult{}, fmt.Errorf("error creating checkout saga: %w", err)
+	}
+
+	// La orden usa el mismo ID que la saga, así cada reintento escribe sobre la misma orden
+	saga.OrderID = saga.ID
+	run := &sagaRun{saga: saga, payment: req.Payment}
+	if err := s.persist(ctx, run); err != nil {
+		return domain.CheckoutResult{}, err
+	}
+
+	log.Printf("🧾 Checkout saga %s started for customer: %d", saga.ID, cart.CustomerID)
+
+	if err := s.runForward(ctx, run); err != nil {
+		// Si el pago ya se cobró la orden es válida: la recuperación termina los pasos que faltan
+		if run.saga.HasCompleted(domain.SagaStepCapturePayment) {
+			log.Printf("⚠️ Checkout saga %s paid but not finished, recovery will complete it: %v", saga.ID, err)
+			return domain.CheckoutResult{Order: run.order, Sales: run.sales}, nil
+		}
+		// Otra instancia tomó la saga: ella decide si la termina o la compensa
+		if errors.Is(err, ErrCheckoutSagaConflict) {
+			log.Printf("⚠️ Checkout saga %s taken over by recovery: %v", saga.ID, err)
+			return domain.CheckoutResult{}, err
+		}
+
+		log.Printf("❌ Checkout saga %s failed: %v", saga.ID, err)
+		if compErr := s.compensate(ctx, run, err); compErr != nil {
+			log.Printf("⚠️ Checkout saga %s compensation incomplete, recovery will retry: %v", saga.ID, compErr)
+		}
+		return domain.CheckoutResult{}, err
+	}
+
+	log.Printf("🎉 Checkout saga %s completed for customer: %d, order: %s", saga.ID, cart.CustomerID, run.order.ID)
+	return domain.CheckoutResult{Order: run.order, Sales: run.sales}, nil
+}
+
+// StartRecoveryWorker revisa periódicamente las sagas que quedaron a medias (por ejemplo, por un crash)
+func (s *CheckoutSagaServiceImpl) StartRecoveryWorker(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("🛑 Checkout saga recovery worker stopped")
+				return
+			case <-ticker.C:
+				s.RecoverPending(ctx)
+			}
+		}
+	}()
+}
+
+// RecoverPending termina o revierte todas las sagas cuyo lease venció
+// Si el pago ya se cobró, la saga se completa; si no, se compensa
+func (s *CheckoutSagaServiceImpl) RecoverPending(ctx context.Context) {
+	for {
+		saga, found, err := s.repository.ClaimStale(ctx, s.lease)
+		if err != nil {
+			log.Printf("❌ Error claiming stale checkout saga: %v", err)
+			return
+		}
+		if !found {
+			return
+		}
+
+		run := &sagaRun{saga: saga}
+		if saga.Status == domain.SagaStatusRunning && saga.HasCompleted(domain.SagaStepCapturePayment) {
+			log.Printf("🔁 Recovering checkout saga %s: rolling forward", saga.ID)
+			if err := s.runForward(ctx, run); err != nil {
+				log.Printf("⚠️ Checkout saga %s could not be completed, will retry: %v", saga.ID, err)
+			}
+			continue
+		}
+
+		log.Printf("🔁 Recovering checkout saga %s: rolling back", saga.ID)
+		cause := errors.New("checkout interrupted before payment")
+		if saga.Error != "" {
+			cause = errors.New(saga.Error)
+		}
+		if err := s.compensate(ctx, run, cause); err != nil {
+			log.Printf("⚠️ Checkout saga %s compensation incomplete, will retry: %v", saga.ID, err)
+		}
+	}
+}
+
+// runForward ejecuta los pasos pendientes en orden
+// Un paso solo cuenta como completado si quedó guardado: si no, se compensa como cualquier paso que falló
+func (s *CheckoutSagaServiceImpl) runForward(ctx context.Context, run *sagaRun) error {
+	for _, step := range s.steps {
+		if run.saga.HasCompleted(step.name) {
+			continue
+		}
+		if err := step.action(ctx, run); err != nil {
+			return fmt.Errorf("checkout step %s failed: %w", step.name, err)
+		}
+		run.saga.CompletedSteps = append(run.saga.CompletedSteps, step.name)
+		if err := s.persist(ctx, run); err != nil {
+			run.saga.CompletedSteps = removeStep(run.saga.CompletedSteps, step.name)
+			return fmt.Errorf("checkout step %s failed: %w", step.name, err)
+		}
+	}
+
+	// Si esto falla todos los pasos ya están guardados: la recuperación solo marca la saga como completada
+	run.saga.Status = domain.SagaStatusCompleted
+	run.saga.Error = ""
+	return s.persist(ctx, run)
+}
+
+// compensate deshace en orden inverso los pasos completados y el paso que falló
+func (s *CheckoutSagaServiceImpl) compensate(ctx context.Context, run *sagaRun, cause error) error {
+	run.saga.Status = domain.SagaStatusCompensating
+	run.saga.Error = cause.Error()
+	if err := s.persist(ctx, run); err != nil {
+		return err
+	}
+
+	// El paso que falló pudo haber quedado a medias, así que también se compensa
+	last := len(s.steps) - 1
+	for i, step := range s.steps {
+		if !run.saga.HasCompleted(step.name) {
+			last = i
+			break
+		}
+	}
+
+	for i := last; i >= 0; i-- {
+		step := s.steps[i]
+		if step.compensate == nil {
+			continue
+		}
+		if err := step.compensate(ctx, run); err != nil {
+			return fmt.Errorf("compensation of step %s failed: %w", step.name, err)
+		}
+		run.saga.CompletedSteps = removeStep(run.saga.CompletedSteps, step.name)
+		if err := s.persist(ctx, run); err != nil {
+			return fmt.Errorf("compensation of step %s failed: %w", step.name, err)
+		}
+	}
+
+	run.saga.Status = domain.SagaStatusCompensated
+	if err := s.persist(ctx, run); err != nil {
+		return err
+	}
+	log.Printf("↩️ Checkout saga %s compensated", run.saga.ID)
+	return nil
+}
+
+// persist guarda el estado de la saga y extiende el lease
+// Si no se puede guardar, la ejecución se corta: el worker de recuperación retoma desde el último estado guardado
+func (s *CheckoutSagaServiceImpl) persist(ctx context.Context, run *sagaRun) error {
+	run.saga.LockedUntil = time.Now().UTC().Add(s.lease)
+	saved, err := s.repository.Update(ctx, run.saga)
+	if err != nil {
+		if strings.Contains(err.Error(), "modified concurrently") {
+			return fmt.Errorf("%w: %s", ErrCheckoutSagaConflict, run.saga.ID)
+		}
+		return fmt.Errorf("error persisting checkout saga %s: %w", run.saga.ID, err)
+	}
+	run.saga = saved
+	return nil
+}
+
+// ========================================
+// Pasos de la saga y sus compensaciones
+// ========================================
+
+// reserveStock descuenta el stock de cada línea y guarda el snapshot del item
+// La clave de la reserva se guarda antes de descontar: si el proceso se cae en el medio, la compensación
+// la libera con esa clave (y si el descuento nunca llegó a aplicarse, liberar no hace nada)
+func (s *CheckoutSagaServiceImpl) reserveStock(ctx context.Context, run *sagaRun) error {
+	for i, line := range run.saga.Lines {
+		if line.Reserved {
+			continue
+		}
+
+		item, err := s.itemsService.GetByID(ctx, line.ItemID)
+		if err != nil {
+			return fmt.Errorf("error validating item %s: %w", line.ItemID, err)
+		}
+
+		if line.ReservationID == "" {
+			run.saga.Lines[i].ReservationID = fmt.Sprintf("%s:%d", run.saga.ID, i)
+			if err := s.persist(ctx, run); err != nil {
+				return err
+			}
+		}
+		reservationID := run.saga.Lines[i].ReservationID
+
+		ok, err := s.itemsService.ReserveStock(ctx, line.ItemID, line.Quantity, reservationID)
+		if err != nil {
+			return fmt.Errorf("error reserving stock for item %s: %w", line.ItemID, err)
+		}
+		if !ok {
+			return fmt.Errorf("%w for item %s: requested %d, available %d", ErrInsufficientStock, item.Name, line.Quantity, item.Stock)
+		}
+
+		run.saga.Lines[i].ItemName = item.Name
+		run.saga.Lines[i].Category = item.Category
+		run.saga.Lines[i].ImageURL = item.ImageURL
+		run.saga.Lines[i].TaxClass = NormalizeTaxClass(item.TaxClass)
+		run.saga.Lines[i].UnitPrice = item.Price
+		run.saga.Lines[i].Subtotal = item.Price * float64(line.Quantity)
+		run.saga.Lines[i].WeightKg = s.shipping.UnitWeightKg(item)
+		run.saga.Lines[i].Reserved = true
+		if err := s.persist(ctx, run); err != nil {
+			// Si otra instancia ya compensó la saga, nadie más va a liberar esta reserva
+			if releaseErr := s.itemsService.ReleaseStock(ctx, line.ItemID, line.Quantity, reservationID); releaseErr != nil {
+				log.Printf("⚠️ Error releasing stock reservation %s: %v", reservationID, releaseErr)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseStock devuelve el stock de cada línea con reserva (confirmada o solo iniciada)
+// Es idempotente por línea: liberar una reserva ya liberada o que nunca se aplicó no cambia el stock
+func (s *CheckoutSagaServiceImpl) releaseStock(ctx context.Context, run *sagaRun) error {
+	for i, line := range run.saga.Lines {
+		if line.ReservationID == "" {
+			continue
+		}
+		if err := s.itemsService.ReleaseStock(ctx, line.ItemID, line.Quantity, line.ReservationID); err != nil {
+			return fmt.Errorf("error releasing stock for item %s: %w", line.ItemID, err)
+		}
+		run.saga.Lines[i].ReservationID = ""
+		run.saga.Lines[i].Reserved = false
+		if err := s.persist(ctx, run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// confirmStock da por definitivas las reservas de stock una vez completada la compra
+func (s *CheckoutSagaServiceImpl) confirmStock(ctx context.Context, run *sagaRun) error {
+	for _, line := range run.saga.Lines {
+		if line.ReservationID == "" {
+			continue
+		}
+		if err := s.itemsService.ConfirmStockReservation(ctx, line.ItemID, line.ReservationID); err != nil {
+			return fmt.Errorf("error confirming stock for item %s: %w", line.ItemID, err)
+		}
+	}
+	return nil
+}
+
+// createOrder crea la orden pendiente de pago (con descuentos, costo de envío e IVA) y registra una venta por línea
+func (s *CheckoutSagaServiceImpl) createOrder(ctx context.Context, run *sagaRun) error {
+	order := domain.Order{
+		ID:              run.saga.OrderID,
+		CustomerID:      run.saga.CustomerID,
+		Status:          domain.OrderStatusPendingPayment,
+		Items:           make([]domain.OrderLine, len(run.saga.Lines)),
+		Discounts:       []domain.Discount{},
+		ShippingAddress: run.saga.ShippingAddress,
+	}
+	weightKg := 0.0
+	discountLines := make([]domain.DiscountLine, len(run.saga.Lines))
+	for i, line := range run.saga.Lines {
+		order.Items[i] = line.OrderLine
+		order.Subtotal += line.Subtotal
+		weightKg += line.WeightKg * float64(line.Quantity)
+		discountLines[i] = line.DiscountLine()
+	}
+	order.Subtotal = roundMoney(order.Subtotal)
+
+	// Promociones y cupón se calculan de nuevo con los precios reservados: el cupón pudo vencer desde que se aplicó al carrito
+	pricing, err := s.pricing.Discounts(ctx, run.saga.CustomerID, run.saga.CouponCode, discountLines)
+	if err != nil {
+		return err
+	}
+	if pricing.CouponErr != nil {
+		return pricing.CouponErr
+	}
+	order.CouponCode = pricing.CouponCode
+	order.Discounts = pricing.Discounts
+	order.DiscountTotal = pricing.DiscountTotal
+
+	shipping, err := s.shipping.Quote(run.saga.ShippingMethod, run.saga.ShippingAddress, weightKg)
+	if err != nil {
+		return err
+	}
+	order.Shipping = shipping
+
+	// IVA por línea sobre el subtotal ya descontado; el envío tributa a la alícuota general
+	discounts := lineDiscounts(order.Discounts)
+	taxLines := make([]domain.TaxBreakdown, 0, len(order.Items))
+	for i, line := range order.Items {
+		tax := s.pricing.LineTax(line.TaxClass, line.Subtotal, discounts[line.ItemID])
+		order.Items[i].Discount = roundMoney(discounts[line.ItemID])
+		order.Items[i].TaxRate = tax.Rate
+		order.Items[i].NetAmount = tax.Net
+		order.Items[i].TaxAmount = tax.Tax
+		order.Items[i].GrossAmount = tax.Gross
+		taxLines = append(taxLines, tax)
+	}
+	order.Taxes = s.pricing.OrderTaxes(taxLines, shipping.Cost)
+	order.Total = order.Taxes.Gross
+
+	order, err = s.orders.Save(ctx, order)
+	if err != nil {
+		return fmt.Errorf("error saving order: %w", err)
+	}
+
+	sales, err := s.salesService.CreateOrderLines(ctx, order.ID, order.CustomerID, order.Items)
+	if err != nil {
+		return err
+	}
+
+	saleIDs := make([]string, len(sales))
+	for i, sale := range sales {
+		saleIDs[i] = sale.ID
+	}
+	order.SaleIDs = saleIDs
+	run.saga.SaleIDs = saleIDs
+
+	order, err = s.orders.Save(ctx, order)
+	if err != nil {
+		return fmt.Errorf("error saving order: %w", err)
+	}
+
+	run.order = order
+	run.sales = sales
+	publishOrderEvent(ctx, s.events, domain.OrderEventCreated, order)
+	return nil
+}
+
+// cancelOrder elimina las ventas de la orden y la marca como cancelada
+func (s *CheckoutSagaServiceImpl) cancelOrder(ctx context.Context, run *sagaRun) error {
+	if run.saga.OrderID == "" {
+		return nil
+	}
+	if err := s.salesService.DeleteOrderLines(ctx, run.saga.OrderID, run.saga.SaleIDs); err != nil {
+		return err
+	}
+	err := s.orders.UpdateStatus(ctx, run.saga.OrderID, domain.OrderStatusCancelled)
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		return fmt.Errorf("error cancelling order: %w", err)
+	}
+	run.saga.SaleIDs = nil
+
+	// Solo se avisa si la orden llegó a existir
+	if err == nil {
+		if order, err := s.orders.GetByID(ctx, run.saga.OrderID); err == nil {
+			publishOrderEvent(ctx, s.events, domain.OrderEventCancelled, order)
+		}
+	}
+	return nil
+}
+
+// redeemCoupon registra el uso del cupón antes de cobrar, así un cupón agotado no llega a cobrarse
+func (s *CheckoutSagaServiceImpl) redeemCoupon(ctx context.Context, run *sagaRun) error {
+	if run.saga.CouponCode == "" {
+		return nil
+	}
+	order := run.order
+	if order.ID == "" {
+		found, err := s.orders.GetByID(ctx, run.saga.OrderID)
+		if err != nil {
+			return fmt.Errorf("error getting order: %w", err)
+		}
+		order = found
+	}
+	amount := 0.0
+	for _, discount := range order.Discounts {
+		if discount.Type == domain.DiscountTypeCoupon {
+			amount += discount.Amount
+		}
+	}
+	return s.coupons.Redeem(ctx, run.saga.CouponCode, order.ID, order.CustomerID, amount)
+}
+
+// releaseCoupon devuelve el uso del cupón si la compra no se completa
+func (s *CheckoutSagaServiceImpl) releaseCoupon(ctx context.Context, run *sagaRun) error {
+	if run.saga.CouponCode == "" || run.saga.OrderID == "" {
+		return nil
+	}
+	if err := s.coupons.Release(ctx, run.saga.OrderID); err != nil {
+		return fmt.Errorf("error releasing coupon: %w", err)
+	}
+	return nil
+}
+
+// capturePayment autoriza y cobra la orden; recién después del cobro la orden queda pagada
+func (s *CheckoutSagaServiceImpl) capturePayment(ctx context.Context, run *sagaRun) error {
+	order, err := s.orders.GetByID(ctx, run.saga.OrderID)
+	if err != nil {
+		return fmt.Errorf("error getting order: %w", err)
+	}
+
+	// El ID de la autorización se guarda antes de cobrar para poder anularla si el proceso se cae
+	if run.saga.PaymentID == "" {
+		payment, err := s.payments.Authorize(ctx, order, run.payment)
+		if err != nil {
+			return fmt.Errorf("error authorizing payment: %w", err)
+		}
+		run.saga.PaymentID = payment.ID
+		if err := s.persist(ctx, run); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.payments.Capture(ctx, run.saga.PaymentID); err != nil {
+		return fmt.Errorf("error capturing payment: %w", err)
+	}
+
+	order.PaymentID = run.saga.PaymentID
+	alreadyPaid := order.Status == domain.OrderStatusPaid
+	order.Status = domain.OrderStatusPaid
+	order, err = s.orders.Save(ctx, order)
+	if err != nil {
+		return fmt.Errorf("error saving order: %w", err)
+	}
+
+	run.order = order
+	if !alreadyPaid {
+		publishOrderEvent(ctx, s.events, domain.OrderEventPaid, order)
+	}
+	return nil
+}
+
+// refundPayment reintegra el pago si llegó a cobrarse o anula la autorización si no
+func (s *CheckoutSagaServiceImpl) refundPayment(ctx context.Context, run *sagaRun) error {
+	if run.saga.PaymentID == "" {
+		return nil
+	}
+	if _, err := s.payments.Refund(ctx, run.saga.PaymentID); err != nil {
+		return fmt.Errorf("error refunding payment %s: %w", run.saga.PaymentID, err)
+	}
+	run.saga.PaymentID = ""
+	return nil
+}
+
+// clearCart vacía el carrito del cliente una vez cobrada la orden
+func (s *CheckoutSagaServiceImpl) clearCart(ctx context.Context, run *sagaRun) error {
+	cart := domain.Cart{
+		CustomerID: run.saga.CustomerID,
+		Items:      []domain.CartItem{},
+		Total:      0,
+	}
+	if _, err := s.carts.Upsert(ctx, cart); err != nil {
+		return fmt.Errorf("error clearing cart: %w", err)
+	}
+	_ = s.cartCache.Delete(ctx, run.saga.CustomerID)
+
+	// En una recuperación la orden no está en memoria
+	if run.order.ID == "" {
+		if order, err := s.orders.GetByID(ctx, run.saga.OrderID); err == nil {
+			run.order = order
+		}
+	}
+	return nil
+}
+
+func removeStep(steps []string, name string) []string {
+	result := make([]string, 0, len(steps))
+	for _, step := range steps {
+		if step != name {
+			result = append(result, step)
+		}
+	}
+	return result
+}
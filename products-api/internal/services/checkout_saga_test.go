@@ -0,0 +1,572 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"products-api/internal/domain"
+	"testing"
+	"time"
+)
+
+// MockCheckoutSagaRepository guarda las sagas en memoria con el mismo control de version que Mongo
+// failUpdate hace fallar una sola escritura: la primera para la que devuelve true
+type MockCheckoutSagaRepository struct {
+	sagas      map[string]domain.CheckoutSaga
+	nextID     int
+	failUpdate func(saga domain.CheckoutSaga) bool
+}
+
+func NewMockCheckoutSagaRepository() *MockCheckoutSagaRepository {
+	return &MockCheckoutSagaRepository{sagas: map[string]domain.CheckoutSaga{}}
+}
+
+func (m *MockCheckoutSagaRepository) Create(ctx context.Context, saga domain.CheckoutSaga) (domain.CheckoutSaga, error) {
+	m.nextID++
+	saga.ID = fmt.Sprintf("saga-%d", m.nextID)
+	saga.Version = 1
+	m.sagas[saga.ID] = cloneSaga(saga)
+	return saga, nil
+}
+
+func (m *MockCheckoutSagaRepository) Update(ctx context.Context, saga domain.CheckoutSaga) (domain.CheckoutSaga, error) {
+	if m.failUpdate != nil && m.failUpdate(saga) {
+		m.failUpdate = nil
+		return domain.CheckoutSaga{}, errors.New("connection reset")
+	}
+	stored, ok := m.sagas[saga.ID]
+	if !ok {
+		return domain.CheckoutSaga{}, errors.New("checkout saga not found")
+	}
+	if stored.Version != saga.Version {
+		return domain.CheckoutSaga{}, errors.New("checkout saga modified concurrently")
+	}
+	saga.Version++
+	m.sagas[saga.ID] = cloneSaga(saga)
+	return cloneSaga(saga), nil
+}
+
+func (m *MockCheckoutSagaRepository) ClaimStale(ctx context.Context, leaseDuration time.Duration) (domain.CheckoutSaga, bool, error) {
+	now := time.Now().UTC()
+	for id, saga := range m.sagas {
+		pending := saga.Status == domain.SagaStatusRunning || saga.Status == domain.SagaStatusCompensating
+		if pending && saga.LockedUntil.Before(now) {
+			saga.LockedUntil = now.Add(leaseDuration)
+			saga.Version++
+			m.sagas[id] = saga
+			return cloneSaga(saga), true, nil
+		}
+	}
+	return domain.CheckoutSaga{}, false, nil
+}
+
+// expireLease simula que la instancia que ejecuta la saga se quedó sin lease
+func (m *MockCheckoutSagaRepository) expireLease(id string) {
+	saga := m.sagas[id]
+	saga.LockedUntil = time.Now().UTC().Add(-time.Second)
+	m.sagas[id] = saga
+}
+
+// cloneSaga copia los slices para que el test no comparta memoria con el servicio
+func cloneSaga(saga domain.CheckoutSaga) domain.CheckoutSaga {
+	saga.Lines = append([]domain.SagaLine(nil), saga.Lines...)
+	saga.CompletedSteps = append([]string(nil), saga.CompletedSteps...)
+	saga.SaleIDs = append([]string(nil), saga.SaleIDs...)
+	return saga
+}
+
+// MockStockItems simula el stock de los items con sus claves de reserva
+type MockStockItems struct {
+	items        map[string]domain.Item
+	reservations map[string]bool
+}
+
+func NewMockStockItems(items ...domain.Item) *MockStockItems {
+	m := &MockStockItems{items: map[string]domain.Item{}, reservations: map[string]bool{}}
+	for _, item := range items {
+		m.items[item.ID] = item
+	}
+	return m
+}
+
+func (m *MockStockItems) Create(ctx context.Context, item domain.Item) (domain.Item, error) {
+	return item, nil
+}
+
+func (m *MockStockItems) GetByID(ctx context.Context, id string) (domain.Item, error) {
+	item, ok := m.items[id]
+	if !ok {
+		return domain.Item{}, ErrItemNotFound
+	}
+	return item, nil
+}
+
+func (m *MockStockItems) Update(ctx context.Context, id string, item domain.Item) (domain.Item, error) {
+	return item, nil
+}
+
+func (m *MockStockItems) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+func (m *MockStockItems) DecrementStockAtomic(ctx context.Context, itemID string, quantity int) (bool, error) {
+	item := m.items[itemID]
+	if item.Stock < quantity {
+		return false, nil
+	}
+	item.Stock -= quantity
+	m.items[itemID] = item
+	return true, nil
+}
+
+func (m *MockStockItems) IncrementStock(ctx context.Context, itemID string, quantity int) error {
+	item := m.items[itemID]
+	item.Stock += quantity
+	m.items[itemID] = item
+	return nil
+}
+
+func (m *MockStockItems) ReserveStock(ctx context.Context, itemID string, quantity int, reservationID string) (bool, error) {
+	key := itemID + "|" + reservationID
+	if m.reservations[key] {
+		return true, nil
+	}
+	item := m.items[itemID]
+	if item.Stock < quantity {
+		return false, nil
+	}
+	item.Stock -= quantity
+	m.items[itemID] = item
+	m.reservations[key] = true
+	return true, nil
+}
+
+func (m *MockStockItems) ReleaseStock(ctx context.Context, itemID string, quantity int, reservationID string) error {
+	key := itemID + "|" + reservationID
+	if !m.reservations[key] {
+		return nil
+	}
+	delete(m.reservations, key)
+	return m.IncrementStock(ctx, itemID, quantity)
+}
+
+func (m *MockStockItems) ConfirmStockReservation(ctx context.Context, itemID string, reservationID string) error {
+	delete(m.reservations, itemID+"|"+reservationID)
+	return nil
+}
+
+// MockOrdersRepository guarda las órdenes en memoria
+type MockOrdersRepository struct {
+	orders map[string]domain.Order
+}
+
+func (m *MockOrdersRepository) Save(ctx context.Context, order domain.Order) (domain.Order, error) {
+	m.orders[order.ID] = order
+	return order, nil
+}
+
+func (m *MockOrdersRepository) GetByID(ctx context.Context, id string) (domain.Order, error) {
+	order, ok := m.orders[id]
+	if !ok {
+		return domain.Order{}, errors.New("order not found")
+	}
+	return order, nil
+}
+
+func (m *MockOrdersRepository) UpdateStatus(ctx context.Context, id string, status string) error {
+	order, ok := m.orders[id]
+	if !ok {
+		return errors.New("order not found")
+	}
+	order.Status = status
+	m.orders[id] = order
+	return nil
+}
+
+// MockSalesRepository guarda las ventas en memoria; failCreate simula una caída de la base
+type MockSalesRepository struct {
+	sales      map[string]domain.Sales
+	nextID     int
+	failCreate bool
+}
+
+func (m *MockSalesRepository) Create(ctx context.Context, sale domain.Sales) (domain.Sales, error) {
+	if m.failCreate {
+		return domain.Sales{}, errors.New("connection reset")
+	}
+	m.nextID++
+	sale.ID = fmt.Sprintf("sale-%d", m.nextID)
+	m.sales[sale.ID] = sale
+	return sale, nil
+}
+
+func (m *MockSalesRepository) GetByID(ctx context.Context, id string) (domain.Sales, error) {
+	return m.sales[id], nil
+}
+
+func (m *MockSalesRepository) GetByCustomerID(ctx context.Context, customerID int) ([]domain.Sales, error) {
+	return nil, nil
+}
+
+func (m *MockSalesRepository) Update(ctx context.Context, id string, sale domain.Sales) (domain.Sales, error) {
+	return sale, nil
+}
+
+func (m *MockSalesRepository) Delete(ctx context.Context, id string) error {
+	delete(m.sales, id)
+	return nil
+}
+
+func (m *MockSalesRepository) DeleteByOrderID(ctx context.Context, orderID string) error {
+	for id, sale := range m.sales {
+		if sale.OrderID == orderID {
+			delete(m.sales, id)
+		}
+	}
+	return nil
+}
+
+// MockCheckoutCarts acepta cualquier escritura del carrito
+type MockCheckoutCarts struct {
+	cleared bool
+}
+
+func (m *MockCheckoutCarts) GetByCustomerID(ctx context.Context, customerID int) (domain.Cart, error) {
+	return domain.Cart{CustomerID: customerID}, nil
+}
+
+func (m *MockCheckoutCarts) Create(ctx context.Context, cart domain.Cart) (domain.Cart, error) {
+	return cart, nil
+}
+
+func (m *MockCheckoutCarts) Update(ctx context.Context, customerID int, cart domain.Cart) (domain.Cart, error) {
+	return cart, nil
+}
+
+func (m *MockCheckoutCarts) Delete(ctx context.Context, customerID int) error {
+	return nil
+}
+
+func (m *MockCheckoutCarts) Upsert(ctx context.Context, cart domain.Cart) (domain.Cart, error) {
+	m.cleared = len(cart.Items) == 0
+	return cart, nil
+}
+
+// MockCheckoutPayments registra cobros y reintegros
+type MockCheckoutPayments struct {
+	captured    bool
+	refunded    bool
+	failCapture bool
+	onAuthorize func()
+}
+
+func (m *MockCheckoutPayments) Authorize(ctx context.Context, order domain.Order, method domain.PaymentMethod) (domain.Payment, error) {
+	if m.onAuthorize != nil {
+		m.onAuthorize()
+	}
+	return domain.Payment{ID: "pay-" + order.ID, OrderID: order.ID, Amount: order.Total}, nil
+}
+
+func (m *MockCheckoutPayments) Capture(ctx context.Context, paymentID string) (domain.Payment, error) {
+	if m.failCapture {
+		return domain.Payment{}, ErrPaymentDeclined
+	}
+	m.captured = true
+	return domain.Payment{ID: paymentID}, nil
+}
+
+func (m *MockCheckoutPayments) Refund(ctx context.Context, paymentID string) (domain.Payment, error) {
+	m.refunded = true
+	return domain.Payment{ID: paymentID}, nil
+}
+
+// MockNoPromotions no tiene promociones activas
+type MockNoPromotions struct{}
+
+func (MockNoPromotions) List(ctx context.Context, onlyActive bool) ([]domain.Promotion, error) {
+	return []domain.Promotion{}, nil
+}
+
+func (MockNoPromotions) Create(ctx context.Context, promotion domain.Promotion) (domain.Promotion, error) {
+	return promotion, nil
+}
+
+func (MockNoPromotions) GetByID(ctx context.Context, id string) (domain.Promotion, error) {
+	return domain.Promotion{}, errors.New("promotion not found")
+}
+
+func (MockNoPromotions) Update(ctx context.Context, id string, promotion domain.Promotion) (domain.Promotion, error) {
+	return promotion, nil
+}
+
+func (MockNoPromotions) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+type checkoutSagaFixture struct {
+	service  *CheckoutSagaServiceImpl
+	sagas    *MockCheckoutSagaRepository
+	items    *MockStockItems
+	orders   *MockOrdersRepository
+	sales    *MockSalesRepository
+	carts    *MockCheckoutCarts
+	payments *MockCheckoutPayments
+}
+
+// newCheckoutSagaFixture arma la saga con dos items: "mate" (stock 5) y "yerba" (stock 2)
+func newCheckoutSagaFixture() checkoutSagaFixture {
+	f := checkoutSagaFixture{
+		sagas: NewMockCheckoutSagaRepository(),
+		items: NewMockStockItems(
+			domain.Item{ID: "mate", Name: "Mate", Category: "bazar", Price: 1000, Stock: 5},
+			domain.Item{ID: "yerba", Name: "Yerba", Category: "almacen", Price: 500, Stock: 2},
+		),
+		orders:   &MockOrdersRepository{orders: map[string]domain.Order{}},
+		sales:    &MockSalesRepository{sales: map[string]domain.Sales{}},
+		carts:    &MockCheckoutCarts{},
+		payments: &MockCheckoutPayments{},
+	}
+	taxes := NewTaxService(true)
+	salesService := NewSalesService(f.sales, f.sales, f.items, taxes)
+	coupons := NewCouponsService(nil)
+	pricing := NewPricingService(NewPromotionsService(MockNoPromotions{}), coupons, taxes, nil)
+	shipping := NewShippingService(DefaultShippingRateTable(), &MockAddressBook{})
+	f.service = NewCheckoutSagaService(f.sagas, f.orders, f.items, &salesService, f.carts, f.carts, f.payments, shipping, pricing, coupons, nil)
+	return f
+}
+
+func newTestCheckout(yerba int) (domain.Cart, domain.CheckoutRequest) {
+	cart := domain.Cart{CustomerID: 1, Items: []domain.CartItem{{ItemID: "mate", Quantity: 2}, {ItemID: "yerba", Quantity: yerba}}}
+	req := domain.CheckoutRequest{
+		Payment:         domain.PaymentMethod{CardNumber: "4111111111111111"},
+		ShippingRequest: domain.ShippingRequest{Method: domain.ShippingMethodPickup},
+	}
+	return cart, req
+}
+
+func (f checkoutSagaFixture) assertStock(t *testing.T, mate, yerba int) {
+	t.Helper()
+	if f.items.items["mate"].Stock != mate || f.items.items["yerba"].Stock != yerba {
+		t.Errorf("Expected stock mate=%d yerba=%d, got mate=%d yerba=%d", mate, yerba, f.items.items["mate"].Stock, f.items.items["yerba"].Stock)
+	}
+}
+
+func (f checkoutSagaFixture) onlySaga(t *testing.T) domain.CheckoutSaga {
+	t.Helper()
+	if len(f.sagas.sagas) != 1 {
+		t.Fatalf("Expected one saga, got %d", len(f.sagas.sagas))
+	}
+	for _, saga := range f.sagas.sagas {
+		return saga
+	}
+	return domain.CheckoutSaga{}
+}
+
+func TestCheckoutSaga_Completes(t *testing.T) {
+	f := newCheckoutSagaFixture()
+	cart, req := newTestCheckout(1)
+
+	result, err := f.service.Start(context.Background(), cart, req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Order.Status != domain.OrderStatusPaid || len(result.Sales) != 2 {
+		t.Errorf("Expected a paid order with 2 sales, got %+v", result)
+	}
+	f.assertStock(t, 3, 1)
+	if len(f.items.reservations) != 0 {
+		t.Errorf("Expected the reservations to be confirmed, got %v", f.items.reservations)
+	}
+	if saga := f.onlySaga(t); saga.Status != domain.SagaStatusCompleted {
+		t.Errorf("Expected completed saga, got %s", saga.Status)
+	}
+	if !f.payments.captured || !f.carts.cleared {
+		t.Error("Expected the payment captured and the cart cleared")
+	}
+}
+
+// Las ventas que generó el checkout no se pueden editar ni borrar a mano: el stock y el pago los maneja la orden
+func TestCheckoutSaga_OrderSalesAreReadOnly(t *testing.T) {
+	f := newCheckoutSagaFixture()
+	cart, req := newTestCheckout(1)
+	result, err := f.service.Start(context.Background(), cart, req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	sales := NewSalesService(f.sales, f.sales, f.items, NewTaxService(true))
+	saleID := result.Sales[0].ID
+	if _, err := sales.Update(context.Background(), saleID, domain.UpdateBodySales{ItemID: "yerba", Quantity: 1}); !errors.Is(err, ErrSaleBelongsToOrder) {
+		t.Errorf("Expected ErrSaleBelongsToOrder on update, got %v", err)
+	}
+	if err := sales.Delete(context.Background(), saleID); !errors.Is(err, ErrSaleBelongsToOrder) {
+		t.Errorf("Expected ErrSaleBelongsToOrder on delete, got %v", err)
+	}
+	if _, ok := f.sales.sales[saleID]; !ok {
+		t.Error("Expected the sale to still exist")
+	}
+	f.assertStock(t, 3, 1)
+}
+
+// TestCheckoutSaga_CompensatesEachStep hace fallar cada paso (y cada escritura clave de la saga)
+// y verifica que el stock vuelve, la orden se cancela y el pago se reintegra
+func TestCheckoutSaga_CompensatesEachStep(t *testing.T) {
+	cases := []struct {
+		name         string
+		yerba        int
+		setup        func(f checkoutSagaFixture)
+		orderCreated bool
+		refunded     bool
+	}{
+		{name: "insufficient stock on the second line", yerba: 3},
+		{name: "intent not persisted", yerba: 1, setup: func(f checkoutSagaFixture) {
+			f.sagas.failUpdate = func(saga domain.CheckoutSaga) bool { return saga.Lines[1].ReservationID != "" }
+		}},
+		{name: "reservation not persisted", yerba: 1, setup: func(f checkoutSagaFixture) {
+			f.sagas.failUpdate = func(saga domain.CheckoutSaga) bool { return saga.Lines[1].Reserved }
+		}},
+		{name: "order step fails", yerba: 1, orderCreated: true, setup: func(f checkoutSagaFixture) {
+			f.sales.failCreate = true
+		}},
+		{name: "capture declined", yerba: 1, orderCreated: true, refunded: true, setup: func(f checkoutSagaFixture) {
+			f.payments.failCapture = true
+		}},
+		{name: "capture not persisted", yerba: 1, orderCreated: true, refunded: true, setup: func(f checkoutSagaFixture) {
+			f.sagas.failUpdate = func(saga domain.CheckoutSaga) bool { return saga.HasCompleted(domain.SagaStepCapturePayment) }
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newCheckoutSagaFixture()
+			if tc.setup != nil {
+				tc.setup(f)
+			}
+			cart, req := newTestCheckout(tc.yerba)
+
+			if _, err := f.service.Start(context.Background(), cart, req); err == nil {
+				t.Fatal("Expected the checkout to fail")
+			}
+
+			f.assertStock(t, 5, 2)
+			if len(f.items.reservations) != 0 {
+				t.Errorf("Expected no reservations left, got %v", f.items.reservations)
+			}
+			saga := f.onlySaga(t)
+			if saga.Status != domain.SagaStatusCompensated || len(saga.CompletedSteps) != 0 {
+				t.Errorf("Expected compensated saga without steps, got %s %v", saga.Status, saga.CompletedSteps)
+			}
+			order, found := f.orders.orders[saga.OrderID]
+			if found != tc.orderCreated || (found && order.Status != domain.OrderStatusCancelled) {
+				t.Errorf("Expected order created=%v and cancelled, got %+v", tc.orderCreated, order)
+			}
+			if len(f.sales.sales) != 0 {
+				t.Errorf("Expected no sales left, got %d", len(f.sales.sales))
+			}
+			if f.payments.refunded != tc.refunded {
+				t.Errorf("Expected refunded=%v, got %v", tc.refunded, f.payments.refunded)
+			}
+		})
+	}
+}
+
+func TestCheckoutSaga_ReleaseIsIdempotent(t *testing.T) {
+	f := newCheckoutSagaFixture()
+	ctx := context.Background()
+	saga, _ := f.sagas.Create(ctx, domain.CheckoutSaga{
+		Status: domain.SagaStatusRunning,
+		Lines:  []domain.SagaLine{{OrderLine: domain.OrderLine{ItemID: "mate", Quantity: 2}}},
+	})
+	run := &sagaRun{saga: saga}
+
+	if err := f.service.reserveStock(ctx, run); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// Una compensación anterior liberó la reserva pero se cayó antes de guardarlo
+	line := run.saga.Lines[0]
+	if err := f.items.ReleaseStock(ctx, line.ItemID, line.Quantity, line.ReservationID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := f.service.releaseStock(ctx, run); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := f.service.releaseStock(ctx, run); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	f.assertStock(t, 5, 2)
+}
+
+// TestCheckoutSaga_RecoveryResumesHalfDoneSaga simula un crash en dos momentos distintos
+func TestCheckoutSaga_RecoveryResumesHalfDoneSaga(t *testing.T) {
+	t.Run("after decrementing but before saving the reservation", func(t *testing.T) {
+		f := newCheckoutSagaFixture()
+		ctx := context.Background()
+		saga, _ := f.sagas.Create(ctx, domain.CheckoutSaga{
+			Status:      domain.SagaStatusRunning,
+			Lines:       []domain.SagaLine{{OrderLine: domain.OrderLine{ItemID: "mate", Quantity: 2}, ReservationID: "saga-1:0"}},
+			LockedUntil: time.Now().UTC().Add(-time.Minute),
+		})
+		if ok, _ := f.items.ReserveStock(ctx, "mate", 2, saga.Lines[0].ReservationID); !ok {
+			t.Fatal("Expected the reservation to apply")
+		}
+
+		f.service.RecoverPending(ctx)
+
+		f.assertStock(t, 5, 2)
+		if saga := f.onlySaga(t); saga.Status != domain.SagaStatusCompensated {
+			t.Errorf("Expected compensated saga, got %s", saga.Status)
+		}
+	})
+
+	t.Run("after capturing the payment", func(t *testing.T) {
+		f := newCheckoutSagaFixture()
+		cart, req := newTestCheckout(1)
+		f.sagas.failUpdate = func(saga domain.CheckoutSaga) bool { return saga.HasCompleted(domain.SagaStepClearCart) }
+
+		if _, err := f.service.Start(context.Background(), cart, req); err != nil {
+			t.Fatalf("Expected the paid checkout to succeed, got %v", err)
+		}
+		saga := f.onlySaga(t)
+		if saga.Status != domain.SagaStatusRunning || len(f.items.reservations) != 2 {
+			t.Fatalf("Expected a running saga with pending reservations, got %s %v", saga.Status, f.items.reservations)
+		}
+
+		f.sagas.expireLease(saga.ID)
+		f.service.RecoverPending(context.Background())
+
+		saga = f.onlySaga(t)
+		if saga.Status != domain.SagaStatusCompleted || !saga.HasCompleted(domain.SagaStepConfirmStock) {
+			t.Errorf("Expected completed saga, got %s %v", saga.Status, saga.CompletedSteps)
+		}
+		f.assertStock(t, 3, 1)
+		if len(f.items.reservations) != 0 || f.payments.refunded {
+			t.Errorf("Expected confirmed reservations and no refund, got %v refunded=%v", f.items.reservations, f.payments.refunded)
+		}
+	})
+}
+
+// TestCheckoutSaga_StopsWhenTakenOver verifica que una instancia sin lease no pisa a la recuperación
+func TestCheckoutSaga_StopsWhenTakenOver(t *testing.T) {
+	f := newCheckoutSagaFixture()
+	cart, req := newTestCheckout(1)
+	f.payments.onAuthorize = func() {
+		for id := range f.sagas.sagas {
+			f.sagas.expireLease(id)
+		}
+		if _, found, _ := f.sagas.ClaimStale(context.Background(), time.Minute); !found {
+			t.Fatal("Expected the saga to be claimed")
+		}
+	}
+
+	_, err := f.service.Start(context.Background(), cart, req)
+	if !errors.Is(err, ErrCheckoutSagaConflict) {
+		t.Fatalf("Expected ErrCheckoutSagaConflict, got %v", err)
+	}
+	// La compensación queda a cargo de quien tomó la saga
+	if f.payments.captured || f.payments.refunded {
+		t.Error("Expected no capture and no refund from the instance that lost the lease")
+	}
+	if saga := f.onlySaga(t); saga.Status != domain.SagaStatusRunning {
+		t.Errorf("Expected the saga untouched, got %s", saga.Status)
+	}
+}
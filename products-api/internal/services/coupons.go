@@ -0,0 +1,279 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"products-api/internal/domain"
+	"strings"
+	"time"
+)
+
+// CouponsRepository define las operaciones de datos para Coupons
+// Redeem tiene que ser atómico: dos checkouts concurrentes no pueden superar los límites del cupón
+type CouponsRepository interface {
+	List(ctx context.Context) ([]domain.Coupon, error)
+	Create(ctx context.Context, coupon domain.Coupon) (domain.Coupon, error)
+	GetByID(ctx context.Context, id string) (domain.Coupon, error)
+	GetByCode(ctx context.Context, code string) (domain.Coupon, error)
+	Update(ctx context.Context, id string, coupon domain.Coupon) (domain.Coupon, error)
+	Delete(ctx context.Context, id string) error
+	CustomerUsage(ctx context.Context, couponID string, customerID int) (int, error)
+	Redeem(ctx context.Context, coupon domain.Coupon, redemption domain.CouponRedemption) error
+	Release(ctx context.Context, orderID string) error
+}
+
+var (
+	ErrCouponNotFound      = errors.New("coupon not found")
+	ErrInvalidCoupon       = errors.New("invalid coupon")
+	ErrCouponCodeTaken     = errors.New("coupon code already exists")
+	ErrCouponInactive      = errors.New("coupon is not active")
+	ErrCouponNotStarted    = errors.New("coupon is not valid yet")
+	ErrCouponExpired       = errors.New("coupon has expired")
+	ErrCouponMinTotal      = errors.New("cart total is below the coupon minimum")
+	ErrCouponNotApplicable = errors.New("coupon does not apply to any item in the cart")
+	ErrCouponUsageLimit    = errors.New("coupon usage limit reached")
+	ErrCouponCustomerLimit = errors.New("coupon already used the maximum number of times by this customer")
+)
+
+// CouponsServiceImpl valida, calcula y redime cupones de descuento
+type CouponsServiceImpl struct {
+	repository CouponsRepository
+}
+
+// NewCouponsService crea una nueva instancia del service
+func NewCouponsService(repository CouponsRepository) *CouponsServiceImpl {
+	return &CouponsServiceImpl{
+		repository: repository,
+	}
+}
+
+// List obtiene todos los cupones
+func (s *CouponsServiceImpl) List(ctx context.Context) ([]domain.Coupon, error) {
+	return s.repository.List(ctx)
+}
+
+// GetByID obtiene un cupón por su ID
+func (s *CouponsServiceImpl) GetByID(ctx context.Context, id string) (domain.Coupon, error) {
+	coupon, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return domain.Coupon{}, mapCouponError(err)
+	}
+	return coupon, nil
+}
+
+// Create valida y crea un nuevo cupón
+func (s *CouponsServiceImpl) Create(ctx context.Context, coupon domain.Coupon) (domain.Coupon, error) {
+	coupon = normalizeCoupon(coupon)
+	if err := validateCoupon(coupon); err != nil {
+		return domain.Coupon{}, err
+	}
+	created, err := s.repository.Create(ctx, coupon)
+	if err != nil {
+		return domain.Coupon{}, mapCouponError(err)
+	}
+	log.Printf("🎟️ Coupon created: %s", created.Code)
+	return created, nil
+}
+
+// Update valida y actualiza un cupón existente
+func (s *CouponsServiceImpl) Update(ctx context.Context, id string, coupon domain.Coupon) (domain.Coupon, error) {
+	coupon = normalizeCoupon(coupon)
+	if err := validateCoupon(coupon); err != nil {
+		return domain.Coupon{}, err
+	}
+	updated, err := s.repository.Update(ctx, id, coupon)
+	if err != nil {
+		return domain.Coupon{}, mapCouponError(err)
+	}
+	return updated, nil
+}
+
+// Delete elimina un cupón
+func (s *CouponsServiceImpl) Delete(ctx context.Context, id string) error {
+	return mapCouponError(s.repository.Delete(ctx, id))
+}
+
+// Validate busca el cupón por código y calcula el descuento que le corresponde a las líneas del carrito
+// Verifica vigencia, límites de uso y restricciones antes de calcular
+func (s *CouponsServiceImpl) Validate(ctx context.Context, code string, customerID int, lines []domain.DiscountLine) (domain.Coupon, domain.Discount, error) {
+	coupon, err := s.repository.GetByCode(ctx, NormalizeCouponCode(code))
+	if err != nil {
+		return domain.Coupon{}, domain.Discount{}, mapCouponError(err)
+	}
+
+	if coupon.MaxRedemptions > 0 && coupon.Redemptions >= coupon.MaxRedemptions {
+		return domain.Coupon{}, domain.Discount{}, ErrCouponUsageLimit
+	}
+	if coupon.MaxPerCustomer > 0 {
+		used, err := s.repository.CustomerUsage(ctx, coupon.ID, customerID)
+		if err != nil {
+			return domain.Coupon{}, domain.Discount{}, fmt.Errorf("error checking coupon usage: %w", err)
+		}
+		if used >= coupon.MaxPerCustomer {
+			return domain.Coupon{}, domain.Discount{}, ErrCouponCustomerLimit
+		}
+	}
+
+	discount, err := EvaluateCoupon(coupon, lines, time.Now().UTC())
+	if err != nil {
+		return domain.Coupon{}, domain.Discount{}, err
+	}
+	return coupon, discount, nil
+}
+
+// Redeem registra el uso del cupón en una orden. Es idempotente por orden
+func (s *CouponsServiceImpl) Redeem(ctx context.Context, code, orderID string, customerID int, amount float64) error {
+	coupon, err := s.repository.GetByCode(ctx, NormalizeCouponCode(code))
+	if err != nil {
+		return mapCouponError(err)
+	}
+
+	err = s.repository.Redeem(ctx, coupon, domain.CouponRedemption{
+		OrderID:    orderID,
+		CouponID:   coupon.ID,
+		Code:       coupon.Code,
+		CustomerID: customerID,
+		Discount:   amount,
+		CreatedAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		return mapCouponError(err)
+	}
+
+	log.Printf("🎟️ Coupon %s redeemed on order %s", coupon.Code, orderID)
+	return nil
+}
+
+// Release deshace la redención del cupón de una orden (si la hubo)
+func (s *CouponsServiceImpl) Release(ctx context.Context, orderID string) error {
+	return s.repository.Release(ctx, orderID)
+}
+
+// EvaluateCoupon calcula el descuento de un cupón sobre las líneas de un carrito
+// El porcentaje se aplica sobre las líneas elegibles; el monto fijo no puede superar su subtotal
+// El descuento se reparte entre las líneas elegibles en proporción a su subtotal
+func EvaluateCoupon(coupon domain.Coupon, lines []domain.DiscountLine, now time.Time) (domain.Discount, error) {
+	if !coupon.Active {
+		return domain.Discount{}, ErrCouponInactive
+	}
+	if coupon.StartsAt != nil && now.Before(*coupon.StartsAt) {
+		return domain.Discount{}, ErrCouponNotStarted
+	}
+	if coupon.EndsAt != nil && !now.Before(*coupon.EndsAt) {
+		return domain.Discount{}, ErrCouponExpired
+	}
+
+	cartTotal := 0.0
+	for _, line := range lines {
+		cartTotal += line.Subtotal
+	}
+	if cartTotal < coupon.MinCartTotal {
+		return domain.Discount{}, fmt.Errorf("%w: minimum %.2f", ErrCouponMinTotal, coupon.MinCartTotal)
+	}
+
+	eligible := []domain.DiscountLine{}
+	eligibleTotal := 0.0
+	for _, line := range lines {
+		if appliesTo(coupon.Categories, coupon.ItemIDs, line) {
+			eligible = append(eligible, line)
+			eligibleTotal += line.Subtotal
+		}
+	}
+	if len(eligible) == 0 || eligibleTotal <= 0 {
+		return domain.Discount{}, ErrCouponNotApplicable
+	}
+
+	var amount float64
+	switch coupon.Type {
+	case domain.CouponTypePercentage:
+		amount = eligibleTotal * coupon.Value / 100
+	case domain.CouponTypeFixed:
+		amount = coupon.Value
+	}
+	amount = roundMoney(min(amount, eligibleTotal))
+
+	return domain.Discount{
+		Type:        domain.DiscountTypeCoupon,
+		Code:        coupon.Code,
+		Description: coupon.Description,
+		Amount:      amount,
+		Lines:       allocateDiscount(amount, eligible, eligibleTotal, "Cupón "+coupon.Code),
+	}, nil
+}
+
+// allocateDiscount reparte el monto entre las líneas; la última se lleva la diferencia de redondeo
+func allocateDiscount(amount float64, lines []domain.DiscountLine, total float64, reason string) []domain.LineDiscount {
+	result := make([]domain.LineDiscount, len(lines))
+	allocated := 0.0
+	for i, line := range lines {
+		share := roundMoney(amount * line.Subtotal / total)
+		if i == len(lines)-1 {
+			share = roundMoney(amount - allocated)
+		}
+		allocated += share
+		result[i] = domain.LineDiscount{ItemID: line.ItemID, Amount: share, Reason: reason}
+	}
+	return result
+}
+
+// NormalizeCouponCode compara los códigos sin mayúsculas ni espacios ("mate10" == "MATE10")
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func normalizeCoupon(coupon domain.Coupon) domain.Coupon {
+	coupon.Code = NormalizeCouponCode(coupon.Code)
+	coupon.Type = strings.ToLower(strings.TrimSpace(coupon.Type))
+	if coupon.Categories == nil {
+		coupon.Categories = []string{}
+	}
+	if coupon.ItemIDs == nil {
+		coupon.ItemIDs = []string{}
+	}
+	return coupon
+}
+
+func validateCoupon(coupon domain.Coupon) error {
+	if coupon.Code == "" {
+		return fmt.Errorf("%w: code is required", ErrInvalidCoupon)
+	}
+	switch coupon.Type {
+	case domain.CouponTypePercentage:
+		if coupon.Value <= 0 || coupon.Value > 100 {
+			return fmt.Errorf("%w: percentage must be between 0 and 100", ErrInvalidCoupon)
+		}
+	case domain.CouponTypeFixed:
+		if coupon.Value <= 0 {
+			return fmt.Errorf("%w: amount must be greater than zero", ErrInvalidCoupon)
+		}
+	default:
+		return fmt.Errorf("%w: type must be %s or %s", ErrInvalidCoupon, domain.CouponTypePercentage, domain.CouponTypeFixed)
+	}
+	if coupon.MinCartTotal < 0 || coupon.MaxRedemptions < 0 || coupon.MaxPerCustomer < 0 {
+		return fmt.Errorf("%w: limits cannot be negative", ErrInvalidCoupon)
+	}
+	if coupon.StartsAt != nil && coupon.EndsAt != nil && !coupon.EndsAt.After(*coupon.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidCoupon)
+	}
+	return nil
+}
+
+// mapCouponError traduce los errores del repository a los errores del service
+func mapCouponError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch {
+	case strings.Contains(err.Error(), "coupon not found"), strings.Contains(err.Error(), "invalid ObjectID"):
+		return ErrCouponNotFound
+	case strings.Contains(err.Error(), "coupon code already exists"):
+		return ErrCouponCodeTaken
+	case strings.Contains(err.Error(), "customer usage limit reached"):
+		return ErrCouponCustomerLimit
+	case strings.Contains(err.Error(), "coupon usage limit reached"):
+		return ErrCouponUsageLimit
+	}
+	return err
+}
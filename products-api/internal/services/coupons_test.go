@@ -0,0 +1,107 @@
+package services
+
+import (
+	"errors"
+	"products-api/internal/domain"
+	"testing"
+	"time"
+)
+
+func newTestCartLines() []domain.DiscountLine {
+	return []domain.DiscountLine{
+		{ItemID: "yerba-1", Category: "yerbas", UnitPrice: 3000, Quantity: 2, Subtotal: 6000},
+		{ItemID: "mate-1", Category: "mates", UnitPrice: 4000, Quantity: 1, Subtotal: 4000},
+	}
+}
+
+func TestEvaluateCoupon_PercentageOnWholeCart(t *testing.T) {
+	coupon := domain.Coupon{Code: "MATE10", Type: domain.CouponTypePercentage, Value: 10, Active: true}
+
+	discount, err := EvaluateCoupon(coupon, newTestCartLines(), time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if discount.Amount != 1000 {
+		t.Errorf("Expected discount 1000, got %.2f", discount.Amount)
+	}
+	if len(discount.Lines) != 2 || discount.Lines[0].Amount != 600 || discount.Lines[1].Amount != 400 {
+		t.Errorf("Expected per-line discounts 600/400, got %+v", discount.Lines)
+	}
+}
+
+func TestEvaluateCoupon_CategoryRestriction(t *testing.T) {
+	coupon := domain.Coupon{Code: "YERBA", Type: domain.CouponTypePercentage, Value: 20, Categories: []string{"Yerbas"}, Active: true}
+
+	discount, err := EvaluateCoupon(coupon, newTestCartLines(), time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if discount.Amount != 1200 {
+		t.Errorf("Expected discount 1200, got %.2f", discount.Amount)
+	}
+	if len(discount.Lines) != 1 || discount.Lines[0].ItemID != "yerba-1" {
+		t.Errorf("Expected only the yerba line to be discounted, got %+v", discount.Lines)
+	}
+
+	coupon.Categories = []string{"bombillas"}
+	if _, err := EvaluateCoupon(coupon, newTestCartLines(), time.Now()); !errors.Is(err, ErrCouponNotApplicable) {
+		t.Errorf("Expected ErrCouponNotApplicable, got %v", err)
+	}
+}
+
+func TestEvaluateCoupon_FixedAmountCappedAtEligibleTotal(t *testing.T) {
+	coupon := domain.Coupon{Code: "MATE5000", Type: domain.CouponTypeFixed, Value: 5000, ItemIDs: []string{"mate-1"}, Active: true}
+
+	discount, err := EvaluateCoupon(coupon, newTestCartLines(), time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if discount.Amount != 4000 {
+		t.Errorf("Expected discount capped at 4000, got %.2f", discount.Amount)
+	}
+}
+
+func TestEvaluateCoupon_Rules(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	yesterday := now.Add(-24 * time.Hour)
+	tomorrow := now.Add(24 * time.Hour)
+	base := domain.Coupon{Code: "MATE10", Type: domain.CouponTypePercentage, Value: 10, Active: true}
+
+	inactive := base
+	inactive.Active = false
+	notStarted := base
+	notStarted.StartsAt = &tomorrow
+	expired := base
+	expired.EndsAt = &yesterday
+	minTotal := base
+	minTotal.MinCartTotal = 20000
+
+	cases := []struct {
+		name   string
+		coupon domain.Coupon
+		want   error
+	}{
+		{"inactive", inactive, ErrCouponInactive},
+		{"not started", notStarted, ErrCouponNotStarted},
+		{"expired", expired, ErrCouponExpired},
+		{"min total", minTotal, ErrCouponMinTotal},
+	}
+
+	for _, tc := range cases {
+		if _, err := EvaluateCoupon(tc.coupon, newTestCartLines(), now); !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestValidateCoupon(t *testing.T) {
+	if err := validateCoupon(normalizeCoupon(domain.Coupon{Code: " mate10 ", Type: "PERCENTAGE", Value: 10})); err != nil {
+		t.Errorf("Expected valid coupon, got %v", err)
+	}
+	if err := validateCoupon(normalizeCoupon(domain.Coupon{Code: "MATE", Type: domain.CouponTypePercentage, Value: 150})); !errors.Is(err, ErrInvalidCoupon) {
+		t.Errorf("Expected ErrInvalidCoupon for a percentage over 100, got %v", err)
+	}
+	if err := validateCoupon(normalizeCoupon(domain.Coupon{Code: "MATE", Type: "bogus", Value: 10})); !errors.Is(err, ErrInvalidCoupon) {
+		t.Errorf("Expected ErrInvalidCoupon for an unknown type, got %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"products-api/internal/domain"
+	"strings"
+	"time"
+)
+
+// ErrGuestCartNotFound se devuelve cuando el token no existe o el carrito ya venció
+var ErrGuestCartNotFound = errors.New("guest cart not found")
+
+// CreateGuestCart abre un carrito de invitado y devuelve su token de sesión
+// El token es opaco: el frontend lo guarda y lo manda en cada request
+func (s *CartServiceImpl) CreateGuestCart(ctx context.Context) (domain.CartResponse, error) {
+	token, err := newSessionToken()
+	if err != nil {
+		return domain.CartResponse{}, err
+	}
+
+	cart, err := s.saveGuestCart(ctx, domain.Cart{SessionToken: token, Items: []domain.CartItem{}})
+	if err != nil {
+		return domain.CartResponse{}, err
+	}
+
+	log.Printf("🛒 Guest cart created, expires at %s", cart.ExpiresAt.Format(time.RFC3339))
+	return s.enrichCart(ctx, cart)
+}
+
+// GetGuestCart obtiene el carrito de un invitado con información enriquecida
+func (s *CartServiceImpl) GetGuestCart(ctx context.Context, token string) (domain.CartResponse, error) {
+	cart, err := s.getGuestCart(ctx, token)
+	if err != nil {
+		return domain.CartResponse{}, err
+	}
+	return s.enrichCart(ctx, cart)
+}
+
+// AddGuestItem agrega un producto al carrito de un invitado o incrementa su cantidad
+func (s *CartServiceImpl) AddGuestItem(ctx context.Context, token string, req domain.AddItemRequest) (domain.CartResponse, error) {
+	cart, err := s.getGuestCart(ctx, token)
+	if err != nil {
+		return domain.CartResponse{}, err
+	}
+
+	cart, err = s.addToCart(ctx, cart, req)
+	if err != nil {
+		return domain.CartResponse{}, err
+	}
+
+	cart, err = s.saveGuestCart(ctx, cart)
+	if err != nil {
+		return domain.CartResponse{}, err
+	}
+	return s.enrichCart(ctx, cart)
+}
+
+// UpdateGuestItem actualiza la cantidad de un producto del carrito de un invitado (0 lo elimina)
+func (s *CartServiceImpl) UpdateGuestItem(ctx context.Context, token, itemID string, req domain.UpdateItemRequest) (domain.CartResponse, error) {
+	if req.Quantity == 0 {
+		return s.RemoveGuestItem(ctx, token, itemID)
+	}
+
+	cart, err := s.getGuestCart(ctx, token)
+	if err != nil {
+		return domain.CartResponse{}, err
+	}
+
+	cart, err = s.setItemQuantity(ctx, cart, itemID, req.Quantity)
+	if err != nil {
+		return domain.CartResponse{}, err
+	}
+
+	cart, err = s.saveGuestCart(ctx, cart)
+	if err != nil {
+		return domain.CartResponse{}, err
+	}
+	return s.enrichCart(ctx, cart)
+}
+
+// RemoveGuestItem elimina un producto del carrito de un invitado
+func (s *CartServiceImpl) RemoveGuestItem(ctx context.Context, token, itemID string) (domain.CartResponse, error) {
+	cart, err := s.getGuestCart(ctx, token)
+	if err != nil {
+		return domain.CartResponse{}, err
+	}
+
+	cart, err = removeFromCart(cart, itemID)
+	if err != nil {
+		return domain.CartResponse{}, err
+	}
+
+	cart, err = s.saveGuestCart(ctx, cart)
+	if err != nil {
+		return domain.CartResponse{}, err
+	}
+	return s.enrichCart(ctx, cart)
+}
+
+// DeleteGuestCart descarta el carrito de un invitado
+func (s *CartServiceImpl) DeleteGuestCart(ctx context.Context, token string) error {
+	if _, err := s.getGuestCart(ctx, token); err != nil {
+		return err
+	}
+	return s.guestCarts.Delete(ctx, token)
+}
+
+// MergeGuestCart pasa el carrito de invitado al carrito del cliente cuando inicia sesión
+// Las cantidades de un mismo producto se suman y se limitan al stock disponible;
+// los productos que ya no existen o no tienen stock se descartan. El carrito de invitado se elimina
+func (s *CartServiceImpl) MergeGuestCart(ctx context.Context, customerID int, token string) (domain.CartResponse, error) {
+	guest, err := s.getGuestCart(ctx, token)
+	if err != nil {
+		return domain.CartResponse{}, err
+	}
+
+	cart, err := s.repository.GetByCustomerID(ctx, customerID)
+	if err != nil {
+		cart = domain.Cart{
+			CustomerID: customerID,
+			Items:      []domain.CartItem{},
+		}
+	}
+
+	for _, guestItem := range guest.Items {
+		item, err := s.itemsService.GetByID(ctx, guestItem.ItemID)
+		if err != nil || item.ID == "" {
+			log.Printf("⚠️ Guest cart item %s skipped on merge: %v", guestItem.ItemID, err)
+			continue
+		}
+		cart.Items = mergeCartItem(cart.Items, guestItem, item.Stock)
+	}
+
+	// El cupón del invitado se conserva solo si el cliente no tenía uno
+	if cart.CouponCode == "" {
+		cart.CouponCode = guest.CouponCode
+	}
+
+	cart, err = s.repository.Upsert(ctx, cart)
+	if err != nil {
+		return domain.CartResponse{}, fmt.Errorf("error updating cart: %w", err)
+	}
+	_, _ = s.localCache.Upsert(ctx, cart)
+
+	if err := s.guestCarts.Delete(ctx, token); err != nil {
+		log.Printf("⚠️ Error deleting merged guest cart: %v", err)
+	}
+
+	log.Printf("🔀 Guest cart merged into cart of customer %d (%d items)", customerID, len(guest.Items))
+	return s.enrichCart(ctx, cart)
+}
+
+// mergeCartItem suma la cantidad del invitado a la del cliente, sin superar el stock
+// Si no queda stock el producto sale del carrito
+func mergeCartItem(items []domain.CartItem, guestItem domain.CartItem, stock int) []domain.CartItem {
+	for i, cartItem := range items {
+		if cartItem.ItemID == guestItem.ItemID {
+			quantity := min(cartItem.Quantity+guestItem.Quantity, stock)
+			if quantity <= 0 {
+				return append(items[:i], items[i+1:]...)
+			}
+			items[i].Quantity = quantity
+			return items
+		}
+	}
+
+	quantity := min(guestItem.Quantity, stock)
+	if quantity <= 0 {
+		return items
+	}
+	return append(items, domain.CartItem{ItemID: guestItem.ItemID, Quantity: quantity, PriceAtAdd: guestItem.PriceAtAdd})
+}
+
+func (s *CartServiceImpl) getGuestCart(ctx context.Context, token string) (domain.Cart, error) {
+	if strings.TrimSpace(token) == "" {
+		return domain.Cart{}, ErrGuestCartNotFound
+	}
+	cart, err := s.guestCarts.GetByToken(ctx, token)
+	if err != nil {
+		if strings.Contains(err.Error(), "guest cart not found") {
+			return domain.Cart{}, ErrGuestCartNotFound
+		}
+		return domain.Cart{}, fmt.Errorf("error getting guest cart: %w", err)
+	}
+	return cart, nil
+}
+
+// saveGuestCart guarda el carrito y extiende su vencimiento: vence guestTTL después del último uso
+func (s *CartServiceImpl) saveGuestCart(ctx context.Context, cart domain.Cart) (domain.Cart, error) {
+	saved, err := s.guestCarts.Upsert(ctx, cart, time.Now().Add(s.guestTTL))
+	if err != nil {
+		return domain.Cart{}, fmt.Errorf("error updating guest cart: %w", err)
+	}
+	return saved, nil
+}
+
+// newSessionToken genera un token aleatorio de 256 bits
+func newSessionToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating session token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"products-api/internal/domain"
+	"testing"
+)
+
+func TestMergeCartItem(t *testing.T) {
+	items := []domain.CartItem{{ItemID: "mate-1", Quantity: 2}, {ItemID: "yerba-1", Quantity: 1}}
+
+	// Se suman las cantidades del mismo producto sin pasar el stock
+	items = mergeCartItem(items, domain.CartItem{ItemID: "mate-1", Quantity: 3}, 4)
+	if items[0].Quantity != 4 {
+		t.Errorf("Expected mate-1 capped at stock 4, got %d", items[0].Quantity)
+	}
+
+	// Un producto nuevo se agrega, también limitado al stock
+	items = mergeCartItem(items, domain.CartItem{ItemID: "bombilla-1", Quantity: 5}, 2)
+	if len(items) != 3 || items[2].ItemID != "bombilla-1" || items[2].Quantity != 2 {
+		t.Errorf("Expected bombilla-1 added with quantity 2, got %+v", items)
+	}
+
+	// Sin stock: el producto nuevo no se agrega y el existente sale del carrito
+	items = mergeCartItem(items, domain.CartItem{ItemID: "termo-1", Quantity: 1}, 0)
+	items = mergeCartItem(items, domain.CartItem{ItemID: "yerba-1", Quantity: 1}, 0)
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 items after merging out-of-stock products, got %+v", items)
+	}
+	for _, item := range items {
+		if item.ItemID == "termo-1" || item.ItemID == "yerba-1" {
+			t.Errorf("Expected %s to be left out of the cart", item.ItemID)
+		}
+	}
+}
+
+func TestNewSessionToken(t *testing.T) {
+	a, err := newSessionToken()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	b, _ := newSessionToken()
+	if len(a) != 43 || a == b {
+		t.Errorf("Expected two different 43-char tokens, got %q and %q", a, b)
+	}
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"products-api/internal/domain"
+	"time"
+)
+
+// IdempotencyRepository define las operaciones de datos para las claves de idempotencia
+// TakeOver tiene que ser atómico: solo un reintento puede tomar una clave con el lease vencido
+type IdempotencyRepository interface {
+	Acquire(ctx context.Context, record domain.IdempotencyRecord) (domain.IdempotencyRecord, bool, error)
+	TakeOver(ctx context.Context, key string, lockedUntil time.Time) (domain.IdempotencyRecord, bool, error)
+	ExtendLease(ctx context.Context, key string, lockedUntil time.Time) error
+	GetByKey(ctx context.Context, key string) (domain.IdempotencyRecord, error)
+	Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error
+	Delete(ctx context.Context, key string) error
+}
+
+var (
+	ErrIdempotencyKeyReused  = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyInProgress = errors.New("a request with this idempotency key is still in progress")
+)
+
+const (
+	idempotencyPollInterval   = 100 * time.Millisecond
+	defaultIdempotencyWaitFor = 5 * time.Second
+	defaultIdempotencyLease   = 30 * time.Second
+)
+
+// IdempotencyServiceImpl evita que un mismo request (por ejemplo un checkout reintentado) se procese dos veces
+type IdempotencyServiceImpl struct {
+	repository IdempotencyRepository
+	ttl        time.Duration
+	waitFor    time.Duration
+	lease      time.Duration
+}
+
+// NewIdempotencyService crea una nueva instancia del service
+// ttl es cuánto se guarda cada respuesta para poder repetirla
+func NewIdempotencyService(repository IdempotencyRepository, ttl time.Duration) *IdempotencyServiceImpl {
+	return &IdempotencyServiceImpl{
+		repository: repository,
+		ttl:        ttl,
+		waitFor:    defaultIdempotencyWaitFor,
+		lease:      defaultIdempotencyLease,
+	}
+}
+
+// Begin registra la clave antes de procesar el request
+// Devuelve replay=true con la respuesta guardada si el request ya se procesó
+// Si hay otro request con la misma clave en curso, espera a que termine y si no termina devuelve ErrIdempotencyInProgress
+// Si el request en curso dejó vencer su lease (se cayó sin terminar), este toma la clave y se procesa de nuevo
+func (s *IdempotencyServiceImpl) Begin(ctx context.Context, key, fingerprint string) (domain.IdempotencyRecord, bool, error) {
+	now := time.Now().UTC()
+	record := domain.IdempotencyRecord{
+		Key:         key,
+		Fingerprint: fingerprint,
+		Status:      domain.IdempotencyStatusInProgress,
+		LockedUntil: now.Add(s.lease),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	}
+
+	existing, acquired, err := s.repository.Acquire(ctx, record)
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, fmt.Errorf("error acquiring idempotency key: %w", err)
+	}
+	if acquired {
+		return existing, false, nil
+	}
+
+	if existing.Fingerprint != fingerprint {
+		return domain.IdempotencyRecord{}, false, ErrIdempotencyKeyReused
+	}
+
+	// Request duplicado concurrente: esperamos a que el original termine
+	deadline := time.Now().Add(s.waitFor)
+	for existing.Status != domain.IdempotencyStatusCompleted {
+		if existing.LockedUntil.Before(time.Now().UTC()) {
+			taken, ok, err := s.repository.TakeOver(ctx, key, time.Now().UTC().Add(s.lease))
+			if err != nil {
+				return domain.IdempotencyRecord{}, false, fmt.Errorf("error taking over idempotency key: %w", err)
+			}
+			if ok {
+				log.Printf("🔁 Idempotency key %s abandoned, processing the retry", key)
+				return taken, false, nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return domain.IdempotencyRecord{}, false, ErrIdempotencyInProgress
+		}
+
+		select {
+		case <-ctx.Done():
+			return domain.IdempotencyRecord{}, false, ctx.Err()
+		case <-time.After(idempotencyPollInterval):
+		}
+
+		existing, err = s.repository.GetByKey(ctx, key)
+		if err != nil {
+			// El request original falló y liberó la clave
+			return domain.IdempotencyRecord{}, false, ErrIdempotencyInProgress
+		}
+	}
+
+	return existing, true, nil
+}
+
+// KeepAlive extiende el lease de la clave mientras el request sigue en curso, así un reintento no la toma
+// Devuelve la función que hay que llamar cuando el request termina
+func (s *IdempotencyServiceImpl) KeepAlive(key string) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(s.lease / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := s.repository.ExtendLease(context.Background(), key, time.Now().UTC().Add(s.lease)); err != nil {
+					log.Printf("⚠️ Error extending idempotency lease %s: %v", key, err)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// Complete guarda la respuesta del request para repetirla en los reintentos
+func (s *IdempotencyServiceImpl) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	if err := s.repository.Complete(ctx, key, statusCode, contentType, body); err != nil {
+		return fmt.Errorf("error saving idempotent response: %w", err)
+	}
+	return nil
+}
+
+// Release libera la clave para que el cliente pueda reintentar (se usa ante errores del servidor)
+func (s *IdempotencyServiceImpl) Release(ctx context.Context, key string) error {
+	if err := s.repository.Delete(ctx, key); err != nil {
+		return fmt.Errorf("error releasing idempotency key: %w", err)
+	}
+	return nil
+}
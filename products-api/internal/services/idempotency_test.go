@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"products-api/internal/domain"
+	"sync"
+	"testing"
+	"time"
+)
+
+// MockIdempotencyRepository guarda las claves en memoria con las mismas reglas que Mongo
+type MockIdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[string]domain.IdempotencyRecord
+}
+
+func NewMockIdempotencyRepository() *MockIdempotencyRepository {
+	return &MockIdempotencyRepository{records: map[string]domain.IdempotencyRecord{}}
+}
+
+func (m *MockIdempotencyRepository) Acquire(ctx context.Context, record domain.IdempotencyRecord) (domain.IdempotencyRecord, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.records[record.Key]; ok {
+		return existing, false, nil
+	}
+	m.records[record.Key] = record
+	return record, true, nil
+}
+
+func (m *MockIdempotencyRepository) TakeOver(ctx context.Context, key string, lockedUntil time.Time) (domain.IdempotencyRecord, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, ok := m.records[key]
+	if !ok || record.Status != domain.IdempotencyStatusInProgress || !record.LockedUntil.Before(time.Now().UTC()) {
+		return domain.IdempotencyRecord{}, false, nil
+	}
+	record.LockedUntil = lockedUntil
+	m.records[key] = record
+	return record, true, nil
+}
+
+func (m *MockIdempotencyRepository) ExtendLease(ctx context.Context, key string, lockedUntil time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if record, ok := m.records[key]; ok && record.Status == domain.IdempotencyStatusInProgress {
+		record.LockedUntil = lockedUntil
+		m.records[key] = record
+	}
+	return nil
+}
+
+func (m *MockIdempotencyRepository) GetByKey(ctx context.Context, key string) (domain.IdempotencyRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, ok := m.records[key]
+	if !ok {
+		return domain.IdempotencyRecord{}, errors.New("idempotency key not found")
+	}
+	return record, nil
+}
+
+func (m *MockIdempotencyRepository) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record := m.records[key]
+	record.Status = domain.IdempotencyStatusCompleted
+	record.StatusCode = statusCode
+	record.ContentType = contentType
+	record.ResponseBody = body
+	m.records[key] = record
+	return nil
+}
+
+func (m *MockIdempotencyRepository) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.records, key)
+	return nil
+}
+
+func newTestIdempotencyService(lease time.Duration) (*IdempotencyServiceImpl, *MockIdempotencyRepository) {
+	repository := NewMockIdempotencyRepository()
+	service := NewIdempotencyService(repository, time.Hour)
+	service.waitFor = 50 * time.Millisecond
+	service.lease = lease
+	return service, repository
+}
+
+func TestIdempotency_RetryTakesOverExpiredLease(t *testing.T) {
+	service, _ := newTestIdempotencyService(20 * time.Millisecond)
+	ctx := context.Background()
+
+	if _, replay, err := service.Begin(ctx, "key", "body"); err != nil || replay {
+		t.Fatalf("Expected the first request to acquire the key, got replay=%v err=%v", replay, err)
+	}
+
+	// El primer request se cayó sin completar ni liberar la clave
+	time.Sleep(30 * time.Millisecond)
+	service.lease = time.Minute
+	record, replay, err := service.Begin(ctx, "key", "body")
+	if err != nil || replay {
+		t.Fatalf("Expected the retry to take over the key, got replay=%v err=%v", replay, err)
+	}
+	if !record.LockedUntil.After(time.Now().UTC()) {
+		t.Errorf("Expected a new lease, got %v", record.LockedUntil)
+	}
+
+	// Mientras el nuevo dueño tiene el lease, otro reintento espera y recibe ErrIdempotencyInProgress
+	if _, _, err := service.Begin(ctx, "key", "body"); !errors.Is(err, ErrIdempotencyInProgress) {
+		t.Errorf("Expected ErrIdempotencyInProgress, got %v", err)
+	}
+}
+
+func TestIdempotency_KeepAliveHoldsTheLease(t *testing.T) {
+	service, _ := newTestIdempotencyService(30 * time.Millisecond)
+	service.waitFor = 100 * time.Millisecond
+	ctx := context.Background()
+
+	if _, _, err := service.Begin(ctx, "key", "body"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	stop := service.KeepAlive("key")
+	defer stop()
+
+	// El request original sigue vivo más allá de su primer lease: el reintento no lo reemplaza
+	if _, _, err := service.Begin(ctx, "key", "body"); !errors.Is(err, ErrIdempotencyInProgress) {
+		t.Errorf("Expected ErrIdempotencyInProgress while the original is alive, got %v", err)
+	}
+}
+
+func TestIdempotency_ReplaysCompletedResponse(t *testing.T) {
+	service, _ := newTestIdempotencyService(time.Minute)
+	ctx := context.Background()
+
+	if _, _, err := service.Begin(ctx, "key", "body"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := service.Complete(ctx, "key", 201, "application/json", []byte(`{"id":"1"}`)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	record, replay, err := service.Begin(ctx, "key", "body")
+	if err != nil || !replay || record.StatusCode != 201 {
+		t.Errorf("Expected the saved response to be replayed, got %+v replay=%v err=%v", record, replay, err)
+	}
+	if _, _, err := service.Begin(ctx, "key", "other body"); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("Expected ErrIdempotencyKeyReused, got %v", err)
+	}
+}
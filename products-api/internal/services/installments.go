@@ -0,0 +1,66 @@
+package services
+
+import (
+	"fmt"
+	"products-api/internal/domain"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParseInstallmentPlans lee los planes con el formato "cuotas:recargo%" separados por coma (ej: "1:0,6:10")
+// Se devuelven ordenados por cantidad de cuotas
+func ParseInstallmentPlans(spec string) ([]domain.InstallmentPlan, error) {
+	plans := []domain.InstallmentPlan{}
+	seen := map[int]bool{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		count, rate, ok := strings.Cut(part, ":")
+		installments, err := strconv.Atoi(strings.TrimSpace(count))
+		if err != nil || installments < 1 {
+			return nil, fmt.Errorf("invalid installment plan %q: installments must be a positive integer", part)
+		}
+		interest := 0.0
+		if ok {
+			interest, err = strconv.ParseFloat(strings.TrimSpace(rate), 64)
+			if err != nil || interest < 0 {
+				return nil, fmt.Errorf("invalid installment plan %q: interest must be a non-negative percentage", part)
+			}
+		}
+		if seen[installments] {
+			return nil, fmt.Errorf("invalid installment plan %q: duplicated installments", part)
+		}
+		seen[installments] = true
+		plans = append(plans, domain.InstallmentPlan{Installments: installments, InterestRate: interest / 100})
+	}
+	if len(plans) == 0 {
+		return nil, fmt.Errorf("at least one installment plan is required")
+	}
+
+	sort.Slice(plans, func(i, j int) bool { return plans[i].Installments < plans[j].Installments })
+	return plans, nil
+}
+
+// InstallmentOptions calcula cuánto se paga por cuota en cada plan
+// Total es el total con el recargo; la cuota se redondea a centavos y la última absorbe la diferencia,
+// así en los planes sin interés Total es exactamente el total de la compra
+func InstallmentOptions(total float64, plans []domain.InstallmentPlan) []domain.InstallmentOption {
+	options := make([]domain.InstallmentOption, 0, len(plans))
+	if total <= 0 {
+		return options
+	}
+	for _, plan := range plans {
+		financed := roundMoney(total * (1 + plan.InterestRate))
+		options = append(options, domain.InstallmentOption{
+			Installments:      plan.Installments,
+			InterestRate:      plan.InterestRate,
+			InterestFree:      plan.InterestRate == 0,
+			InstallmentAmount: roundMoney(financed / float64(plan.Installments)),
+			Total:             financed,
+		})
+	}
+	return options
+}
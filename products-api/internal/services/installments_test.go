@@ -0,0 +1,51 @@
+package services
+
+import (
+	"products-api/internal/domain"
+	"testing"
+)
+
+func TestParseInstallmentPlans(t *testing.T) {
+	plans, err := ParseInstallmentPlans("12:25, 1:0,3")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []domain.InstallmentPlan{{Installments: 1}, {Installments: 3}, {Installments: 12, InterestRate: 0.25}}
+	if len(plans) != len(expected) {
+		t.Fatalf("Expected %d plans, got %+v", len(expected), plans)
+	}
+	for i := range expected {
+		if plans[i] != expected[i] {
+			t.Errorf("Expected plan %+v, got %+v", expected[i], plans[i])
+		}
+	}
+
+	for _, spec := range []string{"", "0:0", "3:-5", "3:0,3:10", "x:1"} {
+		if _, err := ParseInstallmentPlans(spec); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+func TestInstallmentOptions(t *testing.T) {
+	plans := []domain.InstallmentPlan{{Installments: 1}, {Installments: 3}, {Installments: 6, InterestRate: 0.1}}
+
+	options := InstallmentOptions(1000, plans)
+	if len(options) != 3 {
+		t.Fatalf("Expected 3 options, got %+v", options)
+	}
+	if options[0].InstallmentAmount != 1000 || options[0].Total != 1000 || !options[0].InterestFree {
+		t.Errorf("Expected a single interest-free payment of 1000, got %+v", options[0])
+	}
+	// La cuota se redondea a centavos pero el total sin interés no cambia
+	if options[1].InstallmentAmount != 333.33 || options[1].Total != 1000 {
+		t.Errorf("Expected 3 x 333.33, got %+v", options[1])
+	}
+	if options[2].InstallmentAmount != 183.33 || options[2].Total != 1100 || options[2].InterestFree {
+		t.Errorf("Expected 6 x 183.33 with interest, got %+v", options[2])
+	}
+
+	if len(InstallmentOptions(0, plans)) != 0 {
+		t.Error("Expected no options for a zero total")
+	}
+}
@@ -23,6 +23,12 @@ type ItemsService interface {
 
 	DecrementStockAtomic(ctx context.Context, itemID string, quantity int) (bool, error)
 	IncrementStock(ctx context.Context, itemID string, quantity int) error
+
+	// ReserveStock, ReleaseStock y ConfirmStockReservation descuentan y devuelven stock con una clave de reserva,
+	// así un reintento de la saga de checkout no descuenta ni devuelve dos veces
+	ReserveStock(ctx context.Context, itemID string, quantity int, reservationID string) (bool, error)
+	ReleaseStock(ctx context.Context, itemID string, quantity int, reservationID string) error
+	ConfirmStockReservation(ctx context.Context, itemID string, reservationID string) error
 }
 
 // ItemsRepository define las operaciones de datos para Items
@@ -43,6 +49,12 @@ type ItemsRepository interface {
 
 	DecrementStockAtomic(ctx context.Context, itemID string, quantity int) (bool, error)
 	IncrementStock(ctx context.Context, itemID string, quantity int) error
+
+	// ReserveStock, ReleaseStock y ConfirmStockReservation descuentan y devuelven stock con una clave de reserva,
+	// así un reintento de la saga de checkout no descuenta ni devuelve dos veces
+	ReserveStock(ctx context.Context, itemID string, quantity int, reservationID string) (bool, error)
+	ReleaseStock(ctx context.Context, itemID string, quantity int, reservationID string) error
+	ConfirmStockReservation(ctx context.Context, itemID string, reservationID string) error
 } // ItemsServiceImpl implementa ItemsService
 
 type ItemsRepositoryCache interface {
@@ -78,6 +90,7 @@ func NewItemsService(repository ItemsRepository, localCache ItemsRepositoryCache
 // Consigna 1: Validar name no vacío y price >= 0
 func (s *ItemsServiceImpl) Create(ctx context.Context, item domain.Item) (domain.Item, error) {
 
+	item.TaxClass = NormalizeTaxClass(item.TaxClass)
 	if err := s.validateItem(item); err != nil {
 		return domain.Item{}, fmt.Errorf("validation error: %w", err)
 	}
@@ -138,6 +151,7 @@ func (s *ItemsServiceImpl) GetByID(ctx context.Context, id string) (domain.Item,
 // Consigna 3: Validar campos antes de actualizar
 func (s *ItemsServiceImpl) Update(ctx context.Context, id string, item domain.Item) (domain.Item, error) {
 
+	item.TaxClass = NormalizeTaxClass(item.TaxClass)
 	if err := s.validateItem(item); err != nil {
 		return domain.Item{}, fmt.Errorf("validation error: %w", err)
 	}
@@ -239,6 +253,48 @@ func (s *ItemsServiceImpl) IncrementStock(ctx context.Context, itemID string, qu
 	return nil
 }
 
+// ReserveStock descuenta el stock de una reserva de checkout (idempotente por reservationID)
+func (s *ItemsServiceImpl) ReserveStock(ctx context.Context, itemID string, quantity int, reservationID string) (bool, error) {
+	ok, err := s.repository.ReserveStock(ctx, itemID, quantity, reservationID)
+	if err != nil {
+		return false, fmt.Errorf("error reserving stock: %w", err)
+	}
+	if ok {
+		s.invalidateStockCaches(itemID)
+	}
+	return ok, nil
+}
+
+// ReleaseStock devuelve el stock de una reserva de checkout (idempotente por reservationID)
+func (s *ItemsServiceImpl) ReleaseStock(ctx context.Context, itemID string, quantity int, reservationID string) error {
+	if err := s.repository.ReleaseStock(ctx, itemID, quantity, reservationID); err != nil {
+		return fmt.Errorf("error releasing stock: %w", err)
+	}
+	s.invalidateStockCaches(itemID)
+	return nil
+}
+
+// ConfirmStockReservation da por definitiva una reserva: el stock ya no se devuelve
+func (s *ItemsServiceImpl) ConfirmStockReservation(ctx context.Context, itemID string, reservationID string) error {
+	if err := s.repository.ConfirmStockReservation(ctx, itemID, reservationID); err != nil {
+		return fmt.Errorf("error confirming stock reservation: %w", err)
+	}
+	return nil
+}
+
+// invalidateStockCaches borra el item de los caches en background (ya que el stock cambió)
+func (s *ItemsServiceImpl) invalidateStockCaches(itemID string) {
+	go func() {
+		bgCtx := context.Background()
+		if err := s.localCache.Delete(bgCtx, itemID); err != nil {
+			slog.Warn("⚠️ Error deleting item from local cache", slog.String("item_id", itemID))
+		}
+		if err := s.distributedCache.Delete(bgCtx, itemID); err != nil {
+			slog.Warn("⚠️ Error deleting item from distributed cache", slog.String("item_id", itemID))
+		}
+	}()
+}
+
 // validateItem aplica reglas de negocio para validar un item
 // 🎯 Función helper para reutilizar validaciones
 func (s *ItemsServiceImpl) validateItem(item domain.Item) error {
@@ -254,6 +310,14 @@ func (s *ItemsServiceImpl) validateItem(item domain.Item) error {
 		return fmt.Errorf("error, the stock cannot be negative")
 	}
 
+	if item.WeightKg < 0 || item.Dimensions.LengthCm < 0 || item.Dimensions.WidthCm < 0 || item.Dimensions.HeightCm < 0 {
+		return fmt.Errorf("error, the weight and dimensions cannot be negative")
+	}
+
+	if err := ValidateTaxClass(item.TaxClass); err != nil {
+		return err
+	}
+
 	// ✅ Todas las validaciones pasaron
 	return nil
 }
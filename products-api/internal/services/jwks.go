@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Errores de las claves de verificación
+var (
+	ErrUnknownKeyID    = errors.New("unknown token key id")
+	ErrKeysUnavailable = errors.New("token verification keys unavailable")
+)
+
+// jwksMinRefreshDelay es el tiempo mínimo entre dos fetch del JWKS
+var jwksMinRefreshDelay = 30 * time.Second
+
+// jwk es una clave pública del JWKS que publica users-api
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+}
+
+// verificationKey es una clave pública ya decodificada con el algoritmo con el que firma
+type verificationKey struct {
+	alg string
+	key crypto.PublicKey
+}
+
+// JWKSCache guarda las claves públicas de users-api para verificar los tokens sin llamarlo en cada request
+// Se refresca cuando vence el TTL o cuando llega un token con un kid desconocido (users-api rotó la clave),
+// como mucho una vez cada jwksMinRefreshDelay para que tokens con kids inventados no disparen un fetch cada uno
+type JWKSCache struct {
+	url        string
+	ttl        time.Duration
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	keys        map[string]verificationKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+}
+
+// NewJWKSCache crea el cache de claves; las claves se traen en el primer token a verificar
+func NewJWKSCache(url string, ttl time.Duration) *JWKSCache {
+	return &JWKSCache{
+		url:        url,
+		ttl:        ttl,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		keys:       map[string]verificationKey{},
+	}
+}
+
+// Key devuelve la clave pública y el algoritmo de un kid
+func (c *JWKSCache) Key(ctx context.Context, kid string) (crypto.PublicKey, string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	key, known := c.keys[kid]
+	stale := now.Sub(c.fetchedAt) > c.ttl
+	if (!known || stale) && now.Sub(c.lastAttempt) >= jwksMinRefreshDelay {
+		c.lastAttempt = now
+		if err := c.refresh(ctx); err != nil {
+			// Con las claves que ya teníamos se sigue verificando aunque users-api no responda
+			log.Printf("⚠️ Error refreshing JWKS from %s: %v", c.url, err)
+			if len(c.keys) == 0 {
+				return nil, "", fmt.Errorf("%w: %v", ErrKeysUnavailable, err)
+			}
+		}
+		key, known = c.keys[kid]
+	}
+
+	if !known {
+		if len(c.keys) == 0 {
+			return nil, "", ErrKeysUnavailable
+		}
+		return nil, "", ErrUnknownKeyID
+	}
+	return key.key, key.alg, nil
+}
+
+// refresh trae el JWKS y reemplaza las claves (las que users-api dejó de publicar dejan de valer)
+func (c *JWKSCache) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling users-api: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("error decoding JWKS: %w", err)
+	}
+
+	keys := make(map[string]verificationKey, len(body.Keys))
+	for _, k := range body.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			log.Printf("⚠️ Skipping JWKS key %q: %v", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return errors.New("JWKS has no usable keys")
+	}
+
+	c.keys = keys
+	c.fetchedAt = time.Now()
+	return nil
+}
+
+// publicKey decodifica la clave según su tipo: RSA (RS256) u OKP Ed25519 (EdDSA)
+func (k jwk) publicKey() (verificationKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return verificationKey{}, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return verificationKey{}, errors.New("invalid exponent")
+		}
+		return verificationKey{
+			alg: "RS256",
+			key: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())},
+		}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return verificationKey{}, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return verificationKey{}, errors.New("invalid Ed25519 key")
+		}
+		return verificationKey{alg: "EdDSA", key: ed25519.PublicKey(x)}, nil
+	}
+	return verificationKey{}, fmt.Errorf("unsupported key type %q", k.Kty)
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+	"log"
+	"products-api/internal/domain"
+	"time"
+)
+
+// OrderEventsPublisher publica los eventos del ciclo de vida de las órdenes (RabbitMQ)
+type OrderEventsPublisher interface {
+	PublishOrderEvent(ctx context.Context, event domain.OrderEvent) error
+}
+
+// publishOrderEvent publica el evento con la orden completa
+// Si falla solo se registra: la orden ya cambió de estado y no se revierte por un error del broker
+func publishOrderEvent(ctx context.Context, publisher OrderEventsPublisher, eventType string, order domain.Order) {
+	if publisher == nil {
+		return
+	}
+
+	event := domain.OrderEvent{
+		ID:         order.ID + ":" + eventType,
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Order:      order,
+	}
+	if err := publisher.PublishOrderEvent(ctx, event); err != nil {
+		log.Printf("⚠️ Error publishing %s for order %s: %v", eventType, order.ID, err)
+		return
+	}
+	log.Printf("📣 Published %s for order %s", eventType, order.ID)
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"products-api/internal/domain"
+	"strings"
+	"time"
+)
+
+var (
+	ErrOrderNotFound      = errors.New("order not found")
+	ErrInvalidOrderStatus = errors.New("invalid order status for this operation")
+)
+
+// OrdersServiceImpl maneja las operaciones sobre órdenes ya creadas por el checkout
+type OrdersServiceImpl struct {
+	repository OrdersRepository
+	events     OrderEventsPublisher
+}
+
+// NewOrdersService crea una nueva instancia del service
+func NewOrdersService(repository OrdersRepository, events OrderEventsPublisher) *OrdersServiceImpl {
+	return &OrdersServiceImpl{
+		repository: repository,
+		events:     events,
+	}
+}
+
+// GetByID obtiene una orden por su ID
+func (s *OrdersServiceImpl) GetByID(ctx context.Context, id string) (domain.Order, error) {
+	order, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return domain.Order{}, mapOrderError(err)
+	}
+	return order, nil
+}
+
+// Ship marca como despachada una orden pagada y avisa con order.shipped
+func (s *OrdersServiceImpl) Ship(ctx context.Context, id string, req domain.ShipOrderRequest) (domain.Order, error) {
+	order, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return domain.Order{}, mapOrderError(err)
+	}
+	if order.Status != domain.OrderStatusPaid {
+		return domain.Order{}, fmt.Errorf("%w: order is %s", ErrInvalidOrderStatus, order.Status)
+	}
+
+	now := time.Now().UTC()
+	order.Status = domain.OrderStatusShipped
+	order.TrackingNumber = strings.TrimSpace(req.TrackingNumber)
+	order.ShippedAt = &now
+
+	order, err = s.repository.Save(ctx, order)
+	if err != nil {
+		return domain.Order{}, fmt.Errorf("error saving order: %w", err)
+	}
+
+	log.Printf("📦 Order %s shipped", order.ID)
+	publishOrderEvent(ctx, s.events, domain.OrderEventShipped, order)
+	return order, nil
+}
+
+// mapOrderError traduce los errores del repository a los errores del service
+func mapOrderError(err error) error {
+	if strings.Contains(err.Error(), "order not found") || strings.Contains(err.Error(), "invalid ObjectID") {
+		return ErrOrderNotFound
+	}
+	return err
+}
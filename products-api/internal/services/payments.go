@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"products-api/internal/domain"
+	"strings"
+	"time"
+)
+
+// PaymentGateway abstrae al proveedor de pagos (tarjetas)
+// Refund sobre un pago solo autorizado lo anula sin cobrar
+type PaymentGateway interface {
+	Name() string
+	Authorize(ctx context.Context, req domain.PaymentRequest) (domain.Payment, error)
+	Capture(ctx context.Context, paymentID string, amount float64) (domain.Payment, error)
+	Refund(ctx context.Context, paymentID string, amount float64) (domain.Payment, error)
+	ParseWebhook(payload []byte, signature string) (domain.PaymentEvent, error)
+}
+
+// PaymentsRepository define las operaciones de datos para Payments
+// RecordWebhookEvent falla con "already processed" si el ID del evento ya estaba registrado
+type PaymentsRepository interface {
+	Save(ctx context.Context, payment domain.Payment) (domain.Payment, error)
+	GetByID(ctx context.Context, id string) (domain.Payment, error)
+	RecordWebhookEvent(ctx context.Context, event domain.PaymentEvent) error
+	DeleteWebhookEvent(ctx context.Context, id string) error
+}
+
+var (
+	ErrPaymentMethodRequired = errors.New("payment method is required")
+	ErrInvalidPaymentMethod  = errors.New("invalid payment method")
+	ErrPaymentDeclined       = errors.New("payment declined")
+	ErrPaymentTimeout        = errors.New("payment provider timed out")
+	ErrPaymentNotFound       = errors.New("payment not found")
+	ErrInvalidWebhook        = errors.New("invalid payment webhook")
+)
+
+const paymentsCurrency = "ARS"
+
+// PaymentsServiceImpl registra los pagos de las órdenes y procesa las notificaciones del proveedor
+type PaymentsServiceImpl struct {
+	gateway    PaymentGateway
+	repository PaymentsRepository
+	orders     OrdersRepository
+	events     OrderEventsPublisher
+}
+
+// NewPaymentsService crea una nueva instancia del service
+func NewPaymentsService(gateway PaymentGateway, repository PaymentsRepository, orders OrdersRepository, events OrderEventsPublisher) *PaymentsServiceImpl {
+	return &PaymentsServiceImpl{
+		gateway:    gateway,
+		repository: repository,
+		orders:     orders,
+		events:     events,
+	}
+}
+
+// Authorize reserva el monto de la orden en la tarjeta del cliente sin cobrarlo
+func (s *PaymentsServiceImpl) Authorize(ctx context.Context, order domain.Order, method domain.PaymentMethod) (domain.Payment, error) {
+	if strings.TrimSpace(method.CardNumber) == "" {
+		return domain.Payment{}, ErrPaymentMethodRequired
+	}
+
+	payment, err := s.gateway.Authorize(ctx, domain.PaymentRequest{
+		OrderID:    order.ID,
+		CustomerID: order.CustomerID,
+		Amount:     order.Total,
+		Currency:   paymentsCurrency,
+		Method:     method,
+	})
+	if err != nil {
+		return domain.Payment{}, err
+	}
+
+	payment, err = s.repository.Save(ctx, payment)
+	if err != nil {
+		return domain.Payment{}, fmt.Errorf("error saving payment: %w", err)
+	}
+
+	log.Printf("💳 Payment %s authorized for order %s: $%.2f", payment.ID, order.ID, payment.Amount)
+	return payment, nil
+}
+
+// Capture cobra un pago autorizado. Si ya estaba cobrado no hace nada
+func (s *PaymentsServiceImpl) Capture(ctx context.Context, paymentID string) (domain.Payment, error) {
+	payment, err := s.repository.GetByID(ctx, paymentID)
+	if err != nil {
+		return domain.Payment{}, fmt.Errorf("%w: %s", ErrPaymentNotFound, paymentID)
+	}
+	if payment.Status == domain.PaymentStatusCaptured {
+		return payment, nil
+	}
+
+	captured, err := s.gateway.Capture(ctx, paymentID, payment.Amount)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+
+	payment, err = s.updateStatus(ctx, payment, captured.Status)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	if payment.Status != domain.PaymentStatusCaptured {
+		return domain.Payment{}, fmt.Errorf("%w: payment %s is %s", ErrPaymentDeclined, payment.ID, payment.Status)
+	}
+
+	log.Printf("💰 Payment %s captured for order %s", payment.ID, payment.OrderID)
+	return payment, nil
+}
+
+// Refund reintegra un pago cobrado o anula uno autorizado. Si ya estaba reintegrado no hace nada
+// Si se devolvió plata (no una simple anulación), la orden pasa a reintegrada y se avisa con order.refunded
+func (s *PaymentsServiceImpl) Refund(ctx context.Context, paymentID string) (domain.Payment, error) {
+	payment, err := s.repository.GetByID(ctx, paymentID)
+	if err != nil {
+		return domain.Payment{}, fmt.Errorf("%w: %s", ErrPaymentNotFound, paymentID)
+	}
+
+	if payment.Status != domain.PaymentStatusRefunded && payment.Status != domain.PaymentStatusVoided {
+		refunded, err := s.gateway.Refund(ctx, paymentID, payment.Amount)
+		if err != nil {
+			return domain.Payment{}, err
+		}
+
+		payment, err = s.updateStatus(ctx, payment, refunded.Status)
+		if err != nil {
+			return domain.Payment{}, err
+		}
+		log.Printf("↩️ Payment %s %s for order %s", payment.ID, payment.Status, payment.OrderID)
+	}
+
+	// También en un reintento: si la vez anterior no se llegó a actualizar la orden, se actualiza ahora
+	if payment.Status == domain.PaymentStatusRefunded {
+		if err := s.markOrderRefunded(ctx, payment.OrderID); err != nil {
+			return domain.Payment{}, err
+		}
+	}
+	return payment, nil
+}
+
+// HandleWebhook procesa una notificación del proveedor y actualiza el pago y su orden
+// Cada evento se procesa una sola vez: uno repetido responde OK sin efectos
+// Si el procesamiento falla se borra el registro para que el reintento del proveedor vuelva a entrar
+func (s *PaymentsServiceImpl) HandleWebhook(ctx context.Context, payload []byte, signature string) (domain.PaymentEvent, error) {
+	event, err := s.gateway.ParseWebhook(payload, signature)
+	if err != nil {
+		return domain.PaymentEvent{}, fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+	}
+
+	if err := s.repository.RecordWebhookEvent(ctx, event); err != nil {
+		if strings.Contains(err.Error(), "already processed") {
+			log.Printf("⏭️ Payment webhook %s already processed", event.ID)
+			return event, nil
+		}
+		return domain.PaymentEvent{}, fmt.Errorf("error recording webhook event: %w", err)
+	}
+
+	if err := s.applyWebhook(ctx, event); err != nil {
+		if deleteErr := s.repository.DeleteWebhookEvent(ctx, event.ID); deleteErr != nil {
+			log.Printf("⚠️ Error releasing payment webhook %s: %v", event.ID, deleteErr)
+		}
+		return domain.PaymentEvent{}, err
+	}
+	return event, nil
+}
+
+// applyWebhook actualiza el pago y la orden según el evento
+func (s *PaymentsServiceImpl) applyWebhook(ctx context.Context, event domain.PaymentEvent) error {
+	payment, err := s.repository.GetByID(ctx, event.PaymentID)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrPaymentNotFound, event.PaymentID)
+	}
+
+	var paymentStatus, orderStatus string
+	switch event.Type {
+	case domain.PaymentEventCaptured:
+		paymentStatus, orderStatus = domain.PaymentStatusCaptured, domain.OrderStatusPaid
+	case domain.PaymentEventRefunded:
+		paymentStatus, orderStatus = domain.PaymentStatusRefunded, domain.OrderStatusRefunded
+	case domain.PaymentEventFailed:
+		paymentStatus = domain.PaymentStatusFailed
+	default:
+		// Los eventos que no conocemos se aceptan para que el proveedor no los reintente
+		log.Printf("⚠️ Ignoring payment webhook %s of type %s", event.ID, event.Type)
+		return nil
+	}
+
+	payment, err = s.updateStatus(ctx, payment, paymentStatus)
+	if err != nil {
+		return err
+	}
+	// Si el pago no pudo pasar al estado del evento (p. ej. un captured repetido sobre un pago reintegrado), la orden tampoco cambia
+	if payment.Status != paymentStatus {
+		return nil
+	}
+
+	switch orderStatus {
+	case domain.OrderStatusPaid:
+		if err := s.moveOrder(ctx, payment.OrderID, domain.OrderStatusPaid, domain.OrderEventPaid); err != nil {
+			return err
+		}
+	case domain.OrderStatusRefunded:
+		if err := s.markOrderRefunded(ctx, payment.OrderID); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("📬 Payment webhook %s processed: %s for payment %s", event.ID, event.Type, event.PaymentID)
+	return nil
+}
+
+// markOrderRefunded es el único lugar que pasa una orden a reintegrada: así cualquier reintegro
+// (webhook del proveedor, compensación de la saga) publica order.refunded una sola vez
+func (s *PaymentsServiceImpl) markOrderRefunded(ctx context.Context, orderID string) error {
+	return s.moveOrder(ctx, orderID, domain.OrderStatusRefunded, domain.OrderEventRefunded)
+}
+
+// moveOrder cambia el estado de la orden de un pago y publica el evento, si la orden no estaba ya en ese estado
+// Los cambios que no permite domain.CanMoveOrder (una reintegrada que vuelve a pagada, por ejemplo) se ignoran
+func (s *PaymentsServiceImpl) moveOrder(ctx context.Context, orderID, status, eventType string) error {
+	if orderID == "" {
+		return nil
+	}
+	order, err := s.orders.GetByID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("error getting order: %w", err)
+	}
+	if order.Status == status {
+		return nil
+	}
+	if !domain.CanMoveOrder(order.Status, status) {
+		log.Printf("⚠️ Ignoring order %s transition from %s to %s", order.ID, order.Status, status)
+		return nil
+	}
+
+	if err := s.orders.UpdateStatus(ctx, order.ID, status); err != nil {
+		return fmt.Errorf("error updating order: %w", err)
+	}
+	order.Status = status
+	publishOrderEvent(ctx, s.events, eventType, order)
+	return nil
+}
+
+// updateStatus guarda el nuevo estado del pago si domain.CanMovePayment lo permite
+// Si no lo permite devuelve el pago sin cambios: quien llama compara el estado para saber si se aplicó
+func (s *PaymentsServiceImpl) updateStatus(ctx context.Context, payment domain.Payment, status string) (domain.Payment, error) {
+	if payment.Status == status {
+		return payment, nil
+	}
+	if !domain.CanMovePayment(payment.Status, status) {
+		log.Printf("⚠️ Ignoring payment %s transition from %s to %s", payment.ID, payment.Status, status)
+		return payment, nil
+	}
+	payment.Status = status
+	payment.UpdatedAt = time.Now().UTC()
+
+	saved, err := s.repository.Save(ctx, payment)
+	if err != nil {
+		return domain.Payment{}, fmt.Errorf("error saving payment: %w", err)
+	}
+	return saved, nil
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"products-api/internal/domain"
+	"testing"
+)
+
+// MockPaymentGateway reintegra los pagos cobrados y anula los autorizados, como un proveedor real
+// El webhook que devuelve ParseWebhook es el que el test deja en webhook
+type MockPaymentGateway struct {
+	payments map[string]domain.Payment
+	webhook  domain.PaymentEvent
+}
+
+func (m *MockPaymentGateway) Name() string {
+	return "mock"
+}
+
+func (m *MockPaymentGateway) Authorize(ctx context.Context, req domain.PaymentRequest) (domain.Payment, error) {
+	return domain.Payment{}, errors.New("not implemented")
+}
+
+func (m *MockPaymentGateway) Capture(ctx context.Context, paymentID string, amount float64) (domain.Payment, error) {
+	return domain.Payment{ID: paymentID, Status: domain.PaymentStatusCaptured}, nil
+}
+
+func (m *MockPaymentGateway) Refund(ctx context.Context, paymentID string, amount float64) (domain.Payment, error) {
+	if m.payments[paymentID].Status == domain.PaymentStatusAuthorized {
+		return domain.Payment{ID: paymentID, Status: domain.PaymentStatusVoided}, nil
+	}
+	return domain.Payment{ID: paymentID, Status: domain.PaymentStatusRefunded}, nil
+}
+
+func (m *MockPaymentGateway) ParseWebhook(payload []byte, signature string) (domain.PaymentEvent, error) {
+	return m.webhook, nil
+}
+
+// MockPaymentsRepository guarda los pagos en memoria (comparte el mapa con el gateway) y los webhooks procesados
+type MockPaymentsRepository struct {
+	payments map[string]domain.Payment
+	events   map[string]bool
+}
+
+func (m *MockPaymentsRepository) Save(ctx context.Context, payment domain.Payment) (domain.Payment, error) {
+	m.payments[payment.ID] = payment
+	return payment, nil
+}
+
+func (m *MockPaymentsRepository) GetByID(ctx context.Context, id string) (domain.Payment, error) {
+	payment, ok := m.payments[id]
+	if !ok {
+		return domain.Payment{}, errors.New("payment not found")
+	}
+	return payment, nil
+}
+
+func (m *MockPaymentsRepository) RecordWebhookEvent(ctx context.Context, event domain.PaymentEvent) error {
+	if m.events[event.ID] {
+		return errors.New("webhook event already processed")
+	}
+	m.events[event.ID] = true
+	return nil
+}
+
+func (m *MockPaymentsRepository) DeleteWebhookEvent(ctx context.Context, id string) error {
+	delete(m.events, id)
+	return nil
+}
+
+// MockOrderEvents guarda los eventos publicados
+type MockOrderEvents struct {
+	published []domain.OrderEvent
+}
+
+func (m *MockOrderEvents) PublishOrderEvent(ctx context.Context, event domain.OrderEvent) error {
+	m.published = append(m.published, event)
+	return nil
+}
+
+func (m *MockOrderEvents) count(eventType string) int {
+	count := 0
+	for _, event := range m.published {
+		if event.Type == eventType {
+			count++
+		}
+	}
+	return count
+}
+
+func newTestPaymentsService(payment domain.Payment, orderStatus string) (*PaymentsServiceImpl, *MockPaymentGateway, *MockOrdersRepository, *MockOrderEvents) {
+	payments := map[string]domain.Payment{payment.ID: payment}
+	gateway := &MockPaymentGateway{payments: payments}
+	orders := &MockOrdersRepository{orders: map[string]domain.Order{
+		payment.OrderID: {ID: payment.OrderID, CustomerID: 1, Status: orderStatus},
+	}}
+	events := &MockOrderEvents{}
+	return NewPaymentsService(gateway, &MockPaymentsRepository{payments: payments, events: map[string]bool{}}, orders, events), gateway, orders, events
+}
+
+// TestPaymentsRefund_PublishesOrderRefunded cubre el reintegro que hace la compensación de la saga
+func TestPaymentsRefund_PublishesOrderRefunded(t *testing.T) {
+	payment := domain.Payment{ID: "pay-1", OrderID: "order-1", Amount: 1000, Status: domain.PaymentStatusCaptured}
+	service, gateway, orders, events := newTestPaymentsService(payment, domain.OrderStatusPaid)
+	ctx := context.Background()
+
+	refunded, err := service.Refund(ctx, payment.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if refunded.Status != domain.PaymentStatusRefunded || orders.orders["order-1"].Status != domain.OrderStatusRefunded {
+		t.Errorf("Expected payment and order refunded, got %s and %s", refunded.Status, orders.orders["order-1"].Status)
+	}
+
+	// Un reintento y el webhook atrasado del proveedor no vuelven a publicar
+	if _, err := service.Refund(ctx, payment.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	gateway.webhook = domain.PaymentEvent{ID: "evt-1", Type: domain.PaymentEventRefunded, PaymentID: payment.ID}
+	if _, err := service.HandleWebhook(ctx, nil, ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := events.count(domain.OrderEventRefunded); got != 1 {
+		t.Errorf("Expected one order.refunded event, got %d", got)
+	}
+}
+
+func TestPaymentsRefund_VoidDoesNotRefundOrder(t *testing.T) {
+	payment := domain.Payment{ID: "pay-1", OrderID: "order-1", Amount: 1000, Status: domain.PaymentStatusAuthorized}
+	service, _, orders, events := newTestPaymentsService(payment, domain.OrderStatusPendingPayment)
+
+	voided, err := service.Refund(context.Background(), payment.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if voided.Status != domain.PaymentStatusVoided || orders.orders["order-1"].Status != domain.OrderStatusPendingPayment {
+		t.Errorf("Expected a voided payment and the order untouched, got %s and %s", voided.Status, orders.orders["order-1"].Status)
+	}
+	if len(events.published) != 0 {
+		t.Errorf("Expected no events, got %v", events.published)
+	}
+}
+
+func TestPaymentsWebhook_RefundPublishesOrderRefunded(t *testing.T) {
+	payment := domain.Payment{ID: "pay-1", OrderID: "order-1", Amount: 1000, Status: domain.PaymentStatusCaptured}
+	service, gateway, orders, events := newTestPaymentsService(payment, domain.OrderStatusShipped)
+	gateway.webhook = domain.PaymentEvent{ID: "evt-1", Type: domain.PaymentEventRefunded, PaymentID: payment.ID}
+
+	if _, err := service.HandleWebhook(context.Background(), nil, ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if orders.orders["order-1"].Status != domain.OrderStatusRefunded || events.count(domain.OrderEventRefunded) != 1 {
+		t.Errorf("Expected the shipped order refunded with one event, got %s %v", orders.orders["order-1"].Status, events.published)
+	}
+}
+
+// Un captured repetido no revive una orden reintegrada ni vuelve a publicar order.paid
+func TestPaymentsWebhook_ReplayedCaptureAfterRefundIsIgnored(t *testing.T) {
+	payment := domain.Payment{ID: "pay-1", OrderID: "order-1", Amount: 1000, Status: domain.PaymentStatusRefunded}
+	service, gateway, orders, events := newTestPaymentsService(payment, domain.OrderStatusRefunded)
+	gateway.webhook = domain.PaymentEvent{ID: "evt-1", Type: domain.PaymentEventCaptured, PaymentID: payment.ID}
+
+	if _, err := service.HandleWebhook(context.Background(), nil, ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if gateway.payments["pay-1"].Status != domain.PaymentStatusRefunded || orders.orders["order-1"].Status != domain.OrderStatusRefunded {
+		t.Errorf("Expected payment and order still refunded, got %s and %s", gateway.payments["pay-1"].Status, orders.orders["order-1"].Status)
+	}
+	if len(events.published) != 0 {
+		t.Errorf("Expected no events, got %v", events.published)
+	}
+}
+
+func TestPaymentsWebhook_FailedDoesNotOverwriteCapture(t *testing.T) {
+	payment := domain.Payment{ID: "pay-1", OrderID: "order-1", Amount: 1000, Status: domain.PaymentStatusCaptured}
+	service, gateway, orders, _ := newTestPaymentsService(payment, domain.OrderStatusPaid)
+	gateway.webhook = domain.PaymentEvent{ID: "evt-1", Type: domain.PaymentEventFailed, PaymentID: payment.ID}
+
+	if _, err := service.HandleWebhook(context.Background(), nil, ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if gateway.payments["pay-1"].Status != domain.PaymentStatusCaptured || orders.orders["order-1"].Status != domain.OrderStatusPaid {
+		t.Errorf("Expected the captured payment and paid order untouched, got %s and %s", gateway.payments["pay-1"].Status, orders.orders["order-1"].Status)
+	}
+}
+
+func TestPaymentsWebhook_CaptureMovesPendingOrderOnce(t *testing.T) {
+	payment := domain.Payment{ID: "pay-1", OrderID: "order-1", Amount: 1000, Status: domain.PaymentStatusAuthorized}
+	service, gateway, orders, events := newTestPaymentsService(payment, domain.OrderStatusPendingPayment)
+	gateway.webhook = domain.PaymentEvent{ID: "evt-1", Type: domain.PaymentEventCaptured, PaymentID: payment.ID}
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.HandleWebhook(context.Background(), nil, ""); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if orders.orders["order-1"].Status != domain.OrderStatusPaid || events.count(domain.OrderEventPaid) != 1 {
+		t.Errorf("Expected the order paid with one event, got %s %v", orders.orders["order-1"].Status, events.published)
+	}
+}
+
+// Un webhook repetido responde OK sin tocar nada, aunque el pago haya cambiado en el medio
+func TestPaymentsWebhook_DuplicateEventHasNoEffect(t *testing.T) {
+	payment := domain.Payment{ID: "pay-1", OrderID: "order-1", Amount: 1000, Status: domain.PaymentStatusAuthorized}
+	service, gateway, orders, events := newTestPaymentsService(payment, domain.OrderStatusPendingPayment)
+	gateway.webhook = domain.PaymentEvent{ID: "evt-1", Type: domain.PaymentEventCaptured, PaymentID: payment.ID}
+	ctx := context.Background()
+
+	if _, err := service.HandleWebhook(ctx, nil, ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := orders.UpdateStatus(ctx, "order-1", domain.OrderStatusPendingPayment); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	event, err := service.HandleWebhook(ctx, nil, "")
+	if err != nil || event.ID != "evt-1" {
+		t.Fatalf("Expected the duplicate to be accepted, got %+v %v", event, err)
+	}
+	if orders.orders["order-1"].Status != domain.OrderStatusPendingPayment || events.count(domain.OrderEventPaid) != 1 {
+		t.Errorf("Expected the duplicate to have no effect, got %s %v", orders.orders["order-1"].Status, events.published)
+	}
+}
+
+// Si el webhook no se pudo procesar, el reintento del proveedor vuelve a entrar
+func TestPaymentsWebhook_FailedProcessingCanBeRetried(t *testing.T) {
+	payment := domain.Payment{ID: "pay-1", OrderID: "order-1", Amount: 1000, Status: domain.PaymentStatusAuthorized}
+	service, gateway, _, _ := newTestPaymentsService(payment, domain.OrderStatusPendingPayment)
+	gateway.webhook = domain.PaymentEvent{ID: "evt-1", Type: domain.PaymentEventCaptured, PaymentID: "pay-2"}
+	ctx := context.Background()
+
+	if _, err := service.HandleWebhook(ctx, nil, ""); !errors.Is(err, ErrPaymentNotFound) {
+		t.Fatalf("Expected ErrPaymentNotFound, got %v", err)
+	}
+
+	gateway.payments["pay-2"] = domain.Payment{ID: "pay-2", Status: domain.PaymentStatusAuthorized}
+	if _, err := service.HandleWebhook(ctx, nil, ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if gateway.payments["pay-2"].Status != domain.PaymentStatusCaptured {
+		t.Errorf("Expected the retry to capture the payment, got %s", gateway.payments["pay-2"].Status)
+	}
+}
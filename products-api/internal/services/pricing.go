@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"products-api/internal/domain"
+	"time"
+)
+
+// PricingResult es el resultado de aplicar promociones y cupón a las líneas de una compra
+// CouponErr explica por qué el cupón no descontó (vencido, mínimo no alcanzado...): el carrito
+// lo muestra, el checkout lo trata como error
+type PricingResult struct {
+	Discounts     []domain.Discount
+	DiscountTotal float64
+	CouponCode    string
+	CouponAmount  float64
+	CouponErr     error
+}
+
+// PricingServiceImpl calcula los descuentos de una compra: primero las promociones automáticas y
+// después el cupón sobre lo que queda de cada línea. Lo usan el carrito, la cotización y el checkout,
+// así los tres llegan al mismo total. El IVA se calcula al final, sobre cada línea ya descontada
+type PricingServiceImpl struct {
+	promotions   *PromotionsServiceImpl
+	coupons      *CouponsServiceImpl
+	taxes        *TaxServiceImpl
+	installments []domain.InstallmentPlan
+}
+
+// NewPricingService crea una nueva instancia del service
+// installments son los planes de cuotas que se ofrecen sobre el total (ver ParseInstallmentPlans)
+func NewPricingService(promotions *PromotionsServiceImpl, coupons *CouponsServiceImpl, taxes *TaxServiceImpl, installments []domain.InstallmentPlan) *PricingServiceImpl {
+	return &PricingServiceImpl{
+		promotions:   promotions,
+		coupons:      coupons,
+		taxes:        taxes,
+		installments: installments,
+	}
+}
+
+// Discounts calcula los descuentos de las líneas para un cliente y un código de cupón (opcional)
+func (s *PricingServiceImpl) Discounts(ctx context.Context, customerID int, couponCode string, lines []domain.DiscountLine) (PricingResult, error) {
+	result := PricingResult{Discounts: []domain.Discount{}}
+	if len(lines) == 0 {
+		return result, nil
+	}
+
+	promotions, err := s.promotions.Active(ctx)
+	if err != nil {
+		return PricingResult{}, fmt.Errorf("error getting promotions: %w", err)
+	}
+	result.Discounts = append(result.Discounts, EvaluatePromotions(promotions, lines, time.Now())...)
+
+	// El cupón se calcula sobre lo que queda de cada línea después de las promociones
+	net := netLines(lines, result.Discounts)
+	if couponCode != "" {
+		coupon, discount, err := s.coupons.Validate(ctx, couponCode, customerID, net)
+		if err != nil {
+			result.CouponErr = err
+		} else {
+			result.Discounts = append(result.Discounts, discount)
+			result.CouponCode = coupon.Code
+			result.CouponAmount = discount.Amount
+		}
+	}
+
+	for _, discount := range result.Discounts {
+		result.DiscountTotal += discount.Amount
+	}
+	result.DiscountTotal = roundMoney(result.DiscountTotal)
+	return result, nil
+}
+
+// netLines descuenta de cada línea lo que ya le aplicaron los descuentos
+func netLines(lines []domain.DiscountLine, discounts []domain.Discount) []domain.DiscountLine {
+	net := make([]domain.DiscountLine, len(lines))
+	copy(net, lines)
+	for _, discount := range discounts {
+		for _, lineDiscount := range discount.Lines {
+			for i := range net {
+				if net[i].ItemID == lineDiscount.ItemID {
+					net[i].Subtotal = roundMoney(net[i].Subtotal - lineDiscount.Amount)
+					break
+				}
+			}
+		}
+	}
+	return net
+}
+
+// LineTax calcula el IVA de una línea sobre su subtotal menos el descuento que recibió
+func (s *PricingServiceImpl) LineTax(taxClass string, subtotal, discount float64) domain.TaxBreakdown {
+	return s.taxes.Breakdown(taxClass, subtotal-discount)
+}
+
+// ShippingTax calcula el IVA del envío, que tributa a la alícuota general
+func (s *PricingServiceImpl) ShippingTax(cost float64) domain.TaxBreakdown {
+	return s.taxes.Breakdown(domain.TaxClassGeneral, cost)
+}
+
+// OrderTaxes arma el resumen de IVA de una compra con envío: las líneas ya descontadas más el costo de envío
+// Su Gross es el total a cobrar; lo usan la cotización y el checkout para llegar al mismo número
+func (s *PricingServiceImpl) OrderTaxes(lines []domain.TaxBreakdown, shippingCost float64) domain.TaxSummary {
+	taxLines := make([]domain.TaxBreakdown, 0, len(lines)+1)
+	taxLines = append(taxLines, lines...)
+	taxLines = append(taxLines, s.ShippingTax(shippingCost))
+	return s.SummarizeTaxes(taxLines)
+}
+
+// Installments calcula las opciones de pago en cuotas para el total de una compra
+func (s *PricingServiceImpl) Installments(total float64) []domain.InstallmentOption {
+	return InstallmentOptions(total, s.installments)
+}
+
+// SummarizeTaxes arma el resumen de IVA de una compra
+func (s *PricingServiceImpl) SummarizeTaxes(lines []domain.TaxBreakdown) domain.TaxSummary {
+	return s.taxes.Summarize(lines)
+}
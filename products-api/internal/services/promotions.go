@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"products-api/internal/domain"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PromotionsRepository define las operaciones de datos para Promotions
+type PromotionsRepository interface {
+	List(ctx context.Context, onlyActive bool) ([]domain.Promotion, error)
+	Create(ctx context.Context, promotion domain.Promotion) (domain.Promotion, error)
+	GetByID(ctx context.Context, id string) (domain.Promotion, error)
+	Update(ctx context.Context, id string, promotion domain.Promotion) (domain.Promotion, error)
+	Delete(ctx context.Context, id string) error
+}
+
+var (
+	ErrPromotionNotFound = errors.New("promotion not found")
+	ErrInvalidPromotion  = errors.New("invalid promotion")
+)
+
+// PromotionsServiceImpl administra las reglas de promociones automáticas
+type PromotionsServiceImpl struct {
+	repository PromotionsRepository
+}
+
+// NewPromotionsService crea una nueva instancia del service
+func NewPromotionsService(repository PromotionsRepository) *PromotionsServiceImpl {
+	return &PromotionsServiceImpl{
+		repository: repository,
+	}
+}
+
+// List obtiene todas las promociones (activas o no)
+func (s *PromotionsServiceImpl) List(ctx context.Context) ([]domain.Promotion, error) {
+	return s.repository.List(ctx, false)
+}
+
+// Active obtiene las promociones activas, ordenadas por prioridad
+func (s *PromotionsServiceImpl) Active(ctx context.Context) ([]domain.Promotion, error) {
+	return s.repository.List(ctx, true)
+}
+
+// GetByID obtiene una promoción por su ID
+func (s *PromotionsServiceImpl) GetByID(ctx context.Context, id string) (domain.Promotion, error) {
+	promotion, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return domain.Promotion{}, mapPromotionError(err)
+	}
+	return promotion, nil
+}
+
+// Create valida y crea una nueva promoción
+func (s *PromotionsServiceImpl) Create(ctx context.Context, promotion domain.Promotion) (domain.Promotion, error) {
+	promotion = normalizePromotion(promotion)
+	if err := validatePromotion(promotion); err != nil {
+		return domain.Promotion{}, err
+	}
+	created, err := s.repository.Create(ctx, promotion)
+	if err != nil {
+		return domain.Promotion{}, err
+	}
+	log.Printf("🏷️ Promotion created: %s (%s)", created.Name, created.Type)
+	return created, nil
+}
+
+// Update valida y actualiza una promoción existente
+func (s *PromotionsServiceImpl) Update(ctx context.Context, id string, promotion domain.Promotion) (domain.Promotion, error) {
+	promotion = normalizePromotion(promotion)
+	if err := validatePromotion(promotion); err != nil {
+		return domain.Promotion{}, err
+	}
+	updated, err := s.repository.Update(ctx, id, promotion)
+	if err != nil {
+		return domain.Promotion{}, mapPromotionError(err)
+	}
+	return updated, nil
+}
+
+// Delete elimina una promoción
+func (s *PromotionsServiceImpl) Delete(ctx context.Context, id string) error {
+	return mapPromotionError(s.repository.Delete(ctx, id))
+}
+
+// EvaluatePromotions aplica las promociones vigentes sobre las líneas de una compra
+// Se evalúan de mayor a menor prioridad; cada promoción descuenta sobre lo que quedó de la línea
+// Una promoción no acumulable no toca líneas ya descontadas y bloquea las que descuenta
+func EvaluatePromotions(promotions []domain.Promotion, lines []domain.DiscountLine, now time.Time) []domain.Discount {
+	sorted := make([]domain.Promotion, len(promotions))
+	copy(sorted, promotions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority > sorted[j].Priority
+	})
+
+	remaining := make([]float64, len(lines))
+	for i, line := range lines {
+		remaining[i] = line.Subtotal
+	}
+	discounted := make([]bool, len(lines))
+	locked := make([]bool, len(lines))
+
+	discounts := []domain.Discount{}
+	for _, promotion := range sorted {
+		if !promotionIsLive(promotion, now) {
+			continue
+		}
+
+		eligible := []int{}
+		for i, line := range lines {
+			if locked[i] || remaining[i] <= 0 || (!promotion.Stackable && discounted[i]) {
+				continue
+			}
+			if appliesTo(promotion.Categories, promotion.ItemIDs, line) {
+				eligible = append(eligible, i)
+			}
+		}
+		if len(eligible) == 0 {
+			continue
+		}
+
+		var amounts map[int]float64
+		var reasons map[int]string
+		switch promotion.Type {
+		case domain.PromotionTypePercentage:
+			amounts, reasons = percentagePromotion(promotion, lines, remaining, eligible)
+		case domain.PromotionTypeBuyXGetY:
+			amounts, reasons = buyXGetYPromotion(promotion, lines, remaining, eligible)
+		}
+
+		discount := domain.Discount{
+			Type:        domain.DiscountTypePromotion,
+			PromotionID: promotion.ID,
+			Description: promotion.Name,
+			Lines:       []domain.LineDiscount{},
+		}
+		for _, i := range eligible {
+			amount := roundMoney(min(amounts[i], remaining[i]))
+			if amount <= 0 {
+				continue
+			}
+			remaining[i] = roundMoney(remaining[i] - amount)
+			discounted[i] = true
+			if !promotion.Stackable {
+				locked[i] = true
+			}
+			discount.Amount += amount
+			discount.Lines = append(discount.Lines, domain.LineDiscount{ItemID: lines[i].ItemID, Amount: amount, Reason: reasons[i]})
+		}
+		if len(discount.Lines) == 0 {
+			continue
+		}
+		discount.Amount = roundMoney(discount.Amount)
+		discounts = append(discounts, discount)
+	}
+	return discounts
+}
+
+// percentagePromotion descuenta un porcentaje de lo que queda de cada línea
+func percentagePromotion(promotion domain.Promotion, lines []domain.DiscountLine, remaining []float64, eligible []int) (map[int]float64, map[int]string) {
+	amounts := map[int]float64{}
+	reasons := map[int]string{}
+	for _, i := range eligible {
+		amounts[i] = remaining[i] * promotion.Percentage / 100
+		reasons[i] = fmt.Sprintf("%s: %g%% off", promotion.Name, promotion.Percentage)
+	}
+	return amounts, reasons
+}
+
+// buyXGetYPromotion agrupa las unidades elegibles de mayor a menor precio en grupos de BuyQuantity+GetQuantity
+// y bonifica las GetQuantity más baratas de cada grupo (2x1: BuyQuantity=1, GetQuantity=1)
+// Las unidades de distintos productos elegibles se combinan, como en "2x1 en bombillas"
+func buyXGetYPromotion(promotion domain.Promotion, lines []domain.DiscountLine, remaining []float64, eligible []int) (map[int]float64, map[int]string) {
+	type unit struct {
+		line  int
+		price float64
+	}
+	units := []unit{}
+	for _, i := range eligible {
+		for q := 0; q < lines[i].Quantity; q++ {
+			units = append(units, unit{line: i, price: lines[i].UnitPrice})
+		}
+	}
+	sort.SliceStable(units, func(a, b int) bool {
+		return units[a].price > units[b].price
+	})
+
+	percentage := promotion.Percentage
+	if percentage <= 0 {
+		percentage = 100
+	}
+
+	groupSize := promotion.BuyQuantity + promotion.GetQuantity
+	freeUnits := map[int]int{}
+	amounts := map[int]float64{}
+	for start := 0; start+groupSize <= len(units); start += groupSize {
+		for _, u := range units[start+promotion.BuyQuantity : start+groupSize] {
+			freeUnits[u.line]++
+			amounts[u.line] += u.price * percentage / 100
+		}
+	}
+
+	reasons := map[int]string{}
+	for i, count := range freeUnits {
+		if percentage >= 100 {
+			reasons[i] = fmt.Sprintf("%s: %d unidad(es) sin cargo", promotion.Name, count)
+		} else {
+			reasons[i] = fmt.Sprintf("%s: %d unidad(es) con %g%% off", promotion.Name, count, percentage)
+		}
+	}
+	return amounts, reasons
+}
+
+// promotionIsLive indica si la promoción está activa, dentro de sus fechas y en uno de sus días
+func promotionIsLive(promotion domain.Promotion, now time.Time) bool {
+	if !promotion.Active {
+		return false
+	}
+	if promotion.StartsAt != nil && now.Before(*promotion.StartsAt) {
+		return false
+	}
+	if promotion.EndsAt != nil && !now.Before(*promotion.EndsAt) {
+		return false
+	}
+	if len(promotion.Weekdays) == 0 {
+		return true
+	}
+	for _, day := range promotion.Weekdays {
+		if time.Weekday(day) == now.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// appliesTo indica si una línea entra en las restricciones de categoría/ítem (vacías: aplica a todo)
+func appliesTo(categories, itemIDs []string, line domain.DiscountLine) bool {
+	if len(categories) == 0 && len(itemIDs) == 0 {
+		return true
+	}
+	for _, itemID := range itemIDs {
+		if itemID == line.ItemID {
+			return true
+		}
+	}
+	for _, category := range categories {
+		if strings.EqualFold(category, line.Category) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizePromotion(promotion domain.Promotion) domain.Promotion {
+	promotion.Name = strings.TrimSpace(promotion.Name)
+	promotion.Type = strings.ToLower(strings.TrimSpace(promotion.Type))
+	if promotion.Categories == nil {
+		promotion.Categories = []string{}
+	}
+	if promotion.ItemIDs == nil {
+		promotion.ItemIDs = []string{}
+	}
+	if promotion.Weekdays == nil {
+		promotion.Weekdays = []int{}
+	}
+	return promotion
+}
+
+func validatePromotion(promotion domain.Promotion) error {
+	if promotion.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidPromotion)
+	}
+	switch promotion.Type {
+	case domain.PromotionTypePercentage:
+		if promotion.Percentage <= 0 || promotion.Percentage > 100 {
+			return fmt.Errorf("%w: percentage must be between 0 and 100", ErrInvalidPromotion)
+		}
+	case domain.PromotionTypeBuyXGetY:
+		if promotion.BuyQuantity < 1 || promotion.GetQuantity < 1 {
+			return fmt.Errorf("%w: buy_quantity and get_quantity must be at least 1", ErrInvalidPromotion)
+		}
+		if promotion.Percentage < 0 || promotion.Percentage > 100 {
+			return fmt.Errorf("%w: percentage must be between 0 and 100 (0 means free)", ErrInvalidPromotion)
+		}
+	default:
+		return fmt.Errorf("%w: type must be %s or %s", ErrInvalidPromotion, domain.PromotionTypePercentage, domain.PromotionTypeBuyXGetY)
+	}
+	for _, day := range promotion.Weekdays {
+		if day < 0 || day > 6 {
+			return fmt.Errorf("%w: weekdays must be between 0 (sunday) and 6 (saturday)", ErrInvalidPromotion)
+		}
+	}
+	if promotion.StartsAt != nil && promotion.EndsAt != nil && !promotion.EndsAt.After(*promotion.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidPromotion)
+	}
+	return nil
+}
+
+// mapPromotionError traduce los errores del repository a los errores del service
+func mapPromotionError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if strings.Contains(err.Error(), "promotion not found") || strings.Contains(err.Error(), "invalid ObjectID") {
+		return ErrPromotionNotFound
+	}
+	return err
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"errors"
+	"products-api/internal/domain"
+	"testing"
+	"time"
+)
+
+// Un martes
+var testPromotionNow = time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+
+func TestEvaluatePromotions_TwoForOne(t *testing.T) {
+	promotion := domain.Promotion{ID: "p1", Name: "2x1 en bombillas", Type: domain.PromotionTypeBuyXGetY, BuyQuantity: 1, GetQuantity: 1, Categories: []string{"bombillas"}, Active: true}
+	lines := []domain.DiscountLine{
+		{ItemID: "bombilla-alpaca", Category: "bombillas", UnitPrice: 5000, Quantity: 1, Subtotal: 5000},
+		{ItemID: "bombilla-acero", Category: "bombillas", UnitPrice: 2000, Quantity: 2, Subtotal: 4000},
+		{ItemID: "yerba-1", Category: "yerbas", UnitPrice: 3000, Quantity: 1, Subtotal: 3000},
+	}
+
+	discounts := EvaluatePromotions([]domain.Promotion{promotion}, lines, testPromotionNow)
+	if len(discounts) != 1 {
+		t.Fatalf("Expected 1 discount, got %d", len(discounts))
+	}
+	// Unidades ordenadas: 5000, 2000, 2000 -> un solo grupo de 2, la más barata del grupo sale gratis
+	if discounts[0].Amount != 2000 {
+		t.Errorf("Expected discount 2000, got %.2f", discounts[0].Amount)
+	}
+	if len(discounts[0].Lines) != 1 || discounts[0].Lines[0].ItemID != "bombilla-acero" {
+		t.Errorf("Expected the discount on bombilla-acero, got %+v", discounts[0].Lines)
+	}
+	if discounts[0].Lines[0].Reason == "" {
+		t.Errorf("Expected a reason on the discounted line")
+	}
+}
+
+func TestEvaluatePromotions_BuyThreeGetOneHalfOff(t *testing.T) {
+	promotion := domain.Promotion{ID: "p1", Name: "4ta unidad al 50%", Type: domain.PromotionTypeBuyXGetY, BuyQuantity: 3, GetQuantity: 1, Percentage: 50, ItemIDs: []string{"yerba-1"}, Active: true}
+	lines := []domain.DiscountLine{
+		{ItemID: "yerba-1", Category: "yerbas", UnitPrice: 3000, Quantity: 9, Subtotal: 27000},
+	}
+
+	discounts := EvaluatePromotions([]domain.Promotion{promotion}, lines, testPromotionNow)
+	// 9 unidades: dos grupos completos de 4 -> 2 unidades al 50%
+	if len(discounts) != 1 || discounts[0].Amount != 3000 {
+		t.Fatalf("Expected discount 3000, got %+v", discounts)
+	}
+}
+
+func TestEvaluatePromotions_Weekdays(t *testing.T) {
+	promotion := domain.Promotion{ID: "p1", Name: "Martes de yerbas", Type: domain.PromotionTypePercentage, Percentage: 15, Categories: []string{"yerbas"}, Weekdays: []int{int(time.Tuesday)}, Active: true}
+	lines := []domain.DiscountLine{
+		{ItemID: "yerba-1", Category: "yerbas", UnitPrice: 3000, Quantity: 2, Subtotal: 6000},
+	}
+
+	discounts := EvaluatePromotions([]domain.Promotion{promotion}, lines, testPromotionNow)
+	if len(discounts) != 1 || discounts[0].Amount != 900 {
+		t.Fatalf("Expected 15%% off on tuesday (900), got %+v", discounts)
+	}
+
+	wednesday := testPromotionNow.Add(24 * time.Hour)
+	if discounts := EvaluatePromotions([]domain.Promotion{promotion}, lines, wednesday); len(discounts) != 0 {
+		t.Errorf("Expected no discount on wednesday, got %+v", discounts)
+	}
+}
+
+func TestEvaluatePromotions_PriorityAndStacking(t *testing.T) {
+	lines := []domain.DiscountLine{
+		{ItemID: "yerba-1", Category: "yerbas", UnitPrice: 1000, Quantity: 2, Subtotal: 2000},
+		{ItemID: "mate-1", Category: "mates", UnitPrice: 4000, Quantity: 1, Subtotal: 4000},
+	}
+	twoForOne := domain.Promotion{ID: "p1", Name: "2x1 yerbas", Type: domain.PromotionTypeBuyXGetY, BuyQuantity: 1, GetQuantity: 1, Categories: []string{"yerbas"}, Priority: 10, Active: true}
+	storeWide := domain.Promotion{ID: "p2", Name: "10% en toda la tienda", Type: domain.PromotionTypePercentage, Percentage: 10, Priority: 1, Active: true}
+
+	// No acumulables: el 2x1 (más prioritario) bloquea la yerba, el 10% solo aplica al mate
+	discounts := EvaluatePromotions([]domain.Promotion{storeWide, twoForOne}, lines, testPromotionNow)
+	if len(discounts) != 2 {
+		t.Fatalf("Expected 2 discounts, got %+v", discounts)
+	}
+	if discounts[0].PromotionID != "p1" || discounts[0].Amount != 1000 {
+		t.Errorf("Expected the 2x1 first with 1000 off, got %+v", discounts[0])
+	}
+	if discounts[1].PromotionID != "p2" || discounts[1].Amount != 400 || len(discounts[1].Lines) != 1 {
+		t.Errorf("Expected 10%% only on the mate (400), got %+v", discounts[1])
+	}
+
+	// Acumulables: el 10% se aplica también sobre lo que queda de la yerba
+	twoForOne.Stackable = true
+	storeWide.Stackable = true
+	discounts = EvaluatePromotions([]domain.Promotion{storeWide, twoForOne}, lines, testPromotionNow)
+	if len(discounts) != 2 || discounts[1].Amount != 500 {
+		t.Errorf("Expected stacked 10%% of 5000 (500), got %+v", discounts)
+	}
+}
+
+func TestValidatePromotion(t *testing.T) {
+	valid := normalizePromotion(domain.Promotion{Name: "2x1", Type: "BUY_X_GET_Y", BuyQuantity: 1, GetQuantity: 1})
+	if err := validatePromotion(valid); err != nil {
+		t.Errorf("Expected valid promotion, got %v", err)
+	}
+	invalid := normalizePromotion(domain.Promotion{Name: "Martes", Type: domain.PromotionTypePercentage, Percentage: 10, Weekdays: []int{7}})
+	if err := validatePromotion(invalid); !errors.Is(err, ErrInvalidPromotion) {
+		t.Errorf("Expected ErrInvalidPromotion for weekday 7, got %v", err)
+	}
+}
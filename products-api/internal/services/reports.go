@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"products-api/internal/domain"
+	"time"
+)
+
+// ReportsRepository define las consultas de reportes sobre la colección de ventas
+type ReportsRepository interface {
+	RevenueByPeriod(ctx context.Context, filters domain.ReportFilters) ([]domain.RevenuePoint, error)
+	TopProducts(ctx context.Context, filters domain.ReportFilters) ([]domain.TopProduct, error)
+	RevenueByCategory(ctx context.Context, filters domain.ReportFilters) ([]domain.CategoryRevenue, error)
+	Summary(ctx context.Context, filters domain.ReportFilters) (domain.SalesSummary, error)
+}
+
+// ReportsServiceImpl implementa la lógica de los reportes de ventas para admins
+type ReportsServiceImpl struct {
+	repository ReportsRepository
+}
+
+const (
+	defaultReportRangeDays = 30
+	defaultTopProducts     = 10
+	maxTopProducts         = 100
+)
+
+var ErrInvalidReportFilters = errors.New("invalid report filters")
+
+// NewReportsService crea una nueva instancia del service
+func NewReportsService(repository ReportsRepository) *ReportsServiceImpl {
+	return &ReportsServiceImpl{
+		repository: repository,
+	}
+}
+
+// RevenueByPeriod devuelve facturación y unidades agrupadas por día, semana o mes
+func (s *ReportsServiceImpl) RevenueByPeriod(ctx context.Context, filters domain.ReportFilters) ([]domain.RevenuePoint, error) {
+	filters, err := s.normalizeFilters(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	switch filters.Granularity {
+	case "":
+		filters.Granularity = "day"
+	case "day", "week", "month":
+	default:
+		return nil, fmt.Errorf("%w: granularity must be day, week or month", ErrInvalidReportFilters)
+	}
+
+	points, err := s.repository.RevenueByPeriod(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("error getting revenue by period: %w", err)
+	}
+	for i := range points {
+		points[i].Revenue = roundMoney(points[i].Revenue)
+	}
+	return points, nil
+}
+
+// TopProducts devuelve el ranking de productos más vendidos
+func (s *ReportsServiceImpl) TopProducts(ctx context.Context, filters domain.ReportFilters) ([]domain.TopProduct, error) {
+	filters, err := s.normalizeFilters(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	if filters.Limit == 0 {
+		filters.Limit = defaultTopProducts
+	}
+	if filters.Limit < 0 || filters.Limit > maxTopProducts {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidReportFilters, maxTopProducts)
+	}
+
+	products, err := s.repository.TopProducts(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("error getting top products: %w", err)
+	}
+	for i := range products {
+		products[i].Revenue = roundMoney(products[i].Revenue)
+	}
+	return products, nil
+}
+
+// RevenueByCategory devuelve la facturación agrupada por categoría
+func (s *ReportsServiceImpl) RevenueByCategory(ctx context.Context, filters domain.ReportFilters) ([]domain.CategoryRevenue, error) {
+	filters, err := s.normalizeFilters(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.repository.RevenueByCategory(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("error getting revenue by category: %w", err)
+	}
+	for i := range categories {
+		categories[i].Revenue = roundMoney(categories[i].Revenue)
+	}
+	return categories, nil
+}
+
+// Summary devuelve totales, ticket promedio y tasa de clientes recurrentes
+func (s *ReportsServiceImpl) Summary(ctx context.Context, filters domain.ReportFilters) (domain.SalesSummary, error) {
+	filters, err := s.normalizeFilters(filters)
+	if err != nil {
+		return domain.SalesSummary{}, err
+	}
+
+	summary, err := s.repository.Summary(ctx, filters)
+	if err != nil {
+		return domain.SalesSummary{}, fmt.Errorf("error getting sales summary: %w", err)
+	}
+
+	if summary.Orders > 0 {
+		summary.AverageOrderValue = roundMoney(summary.Revenue / float64(summary.Orders))
+	}
+	if summary.Customers > 0 {
+		summary.RepeatCustomerRate = math.Round(float64(summary.RepeatCustomers)/float64(summary.Customers)*10000) / 10000
+	}
+	summary.Revenue = roundMoney(summary.Revenue)
+	return summary, nil
+}
+
+// normalizeFilters completa el rango de fechas por defecto y valida que sea coherente
+func (s *ReportsServiceImpl) normalizeFilters(filters domain.ReportFilters) (domain.ReportFilters, error) {
+	if filters.To.IsZero() {
+		filters.To = time.Now().UTC()
+	}
+	if filters.From.IsZero() {
+		filters.From = filters.To.AddDate(0, 0, -defaultReportRangeDays)
+	}
+	if !filters.From.Before(filters.To) {
+		return filters, fmt.Errorf("%w: from must be before to", ErrInvalidReportFilters)
+	}
+	return filters, nil
+}
+
+// roundMoney redondea un importe a 2 decimales
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"products-api/internal/domain"
+	"testing"
+	"time"
+)
+
+// MockReportsRepository devuelve resultados fijos y guarda los filtros con los que se lo llamó
+type MockReportsRepository struct {
+	filters    domain.ReportFilters
+	points     []domain.RevenuePoint
+	products   []domain.TopProduct
+	categories []domain.CategoryRevenue
+	summary    domain.SalesSummary
+}
+
+func (m *MockReportsRepository) RevenueByPeriod(ctx context.Context, filters domain.ReportFilters) ([]domain.RevenuePoint, error) {
+	m.filters = filters
+	return m.points, nil
+}
+
+func (m *MockReportsRepository) TopProducts(ctx context.Context, filters domain.ReportFilters) ([]domain.TopProduct, error) {
+	m.filters = filters
+	return m.products, nil
+}
+
+func (m *MockReportsRepository) RevenueByCategory(ctx context.Context, filters domain.ReportFilters) ([]domain.CategoryRevenue, error) {
+	m.filters = filters
+	return m.categories, nil
+}
+
+func (m *MockReportsRepository) Summary(ctx context.Context, filters domain.ReportFilters) (domain.SalesSummary, error) {
+	m.filters = filters
+	return m.summary, nil
+}
+
+func TestReports_DateRange(t *testing.T) {
+	repository := &MockReportsRepository{}
+	service := NewReportsService(repository)
+	ctx := context.Background()
+
+	// Sin fechas: los últimos 30 días hasta ahora
+	if _, err := service.RevenueByCategory(ctx, domain.ReportFilters{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if time.Since(repository.filters.To) > time.Minute || repository.filters.To.Sub(repository.filters.From) != 30*24*time.Hour {
+		t.Errorf("Expected the last 30 days, got %v - %v", repository.filters.From, repository.filters.To)
+	}
+
+	// Solo to: el rango termina ahí
+	to := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	if _, err := service.RevenueByCategory(ctx, domain.ReportFilters{To: to}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !repository.filters.From.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected from 2024-03-01, got %v", repository.filters.From)
+	}
+
+	invalid := []domain.ReportFilters{
+		{From: to, To: to},
+		{From: to.AddDate(0, 0, 1), To: to},
+	}
+	for _, filters := range invalid {
+		if _, err := service.Summary(ctx, filters); !errors.Is(err, ErrInvalidReportFilters) {
+			t.Errorf("Expected ErrInvalidReportFilters for %v - %v, got %v", filters.From, filters.To, err)
+		}
+	}
+}
+
+func TestReports_RevenueGranularity(t *testing.T) {
+	repository := &MockReportsRepository{points: []domain.RevenuePoint{{Revenue: 1234.5678, Units: 3, Orders: 2}}}
+	service := NewReportsService(repository)
+	ctx := context.Background()
+
+	points, err := service.RevenueByPeriod(ctx, domain.ReportFilters{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if repository.filters.Granularity != "day" {
+		t.Errorf("Expected day by default, got %q", repository.filters.Granularity)
+	}
+	if points[0].Revenue != 1234.57 {
+		t.Errorf("Expected revenue rounded to 1234.57, got %v", points[0].Revenue)
+	}
+
+	for _, granularity := range []string{"day", "week", "month"} {
+		if _, err := service.RevenueByPeriod(ctx, domain.ReportFilters{Granularity: granularity}); err != nil {
+			t.Errorf("Expected %s to be valid, got %v", granularity, err)
+		}
+		if repository.filters.Granularity != granularity {
+			t.Errorf("Expected %s to reach the repository, got %q", granularity, repository.filters.Granularity)
+		}
+	}
+
+	if _, err := service.RevenueByPeriod(ctx, domain.ReportFilters{Granularity: "year"}); !errors.Is(err, ErrInvalidReportFilters) {
+		t.Errorf("Expected ErrInvalidReportFilters for year, got %v", err)
+	}
+}
+
+func TestReports_TopProductsLimit(t *testing.T) {
+	repository := &MockReportsRepository{products: []domain.TopProduct{{ItemID: "1", Units: 5, Revenue: 99.999}}}
+	service := NewReportsService(repository)
+	ctx := context.Background()
+
+	products, err := service.TopProducts(ctx, domain.ReportFilters{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if repository.filters.Limit != 10 || products[0].Revenue != 100 {
+		t.Errorf("Expected limit 10 and revenue 100, got %d and %v", repository.filters.Limit, products[0].Revenue)
+	}
+
+	for _, limit := range []int{-1, 101} {
+		if _, err := service.TopProducts(ctx, domain.ReportFilters{Limit: limit}); !errors.Is(err, ErrInvalidReportFilters) {
+			t.Errorf("Expected ErrInvalidReportFilters for limit %d, got %v", limit, err)
+		}
+	}
+}
+
+func TestReports_SummaryRates(t *testing.T) {
+	repository := &MockReportsRepository{summary: domain.SalesSummary{Revenue: 1000, Orders: 3, Customers: 3, RepeatCustomers: 1}}
+	service := NewReportsService(repository)
+
+	summary, err := service.Summary(context.Background(), domain.ReportFilters{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if summary.AverageOrderValue != 333.33 || summary.RepeatCustomerRate != 0.3333 {
+		t.Errorf("Expected 333.33 per order and 0.3333 repeat rate, got %v and %v", summary.AverageOrderValue, summary.RepeatCustomerRate)
+	}
+
+	// Sin órdenes no hay división por cero
+	repository.summary = domain.SalesSummary{}
+	summary, err = service.Summary(context.Background(), domain.ReportFilters{})
+	if err != nil || summary.AverageOrderValue != 0 || summary.RepeatCustomerRate != 0 {
+		t.Errorf("Expected zero rates without orders, got %+v %v", summary, err)
+	}
+}
@@ -19,6 +19,7 @@ type SalesRepository interface {
 	GetByCustomerID(ctx context.Context, customerID int) ([]domain.Sales, error)
 	Update(ctx context.Context, id string, sale domain.Sales) (domain.Sales, error)
 	Delete(ctx context.Context, id string) error
+	DeleteByOrderID(ctx context.Context, orderID string) error
 }
 
 // SalesServiceImpl implementa SalesService
@@ -26,6 +27,7 @@ type SalesServiceImpl struct {
 	repository   SalesRepository
 	localCache   SalesRepository
 	itemsService ItemsService
+	taxes        *TaxServiceImpl
 }
 
 var (
@@ -33,14 +35,17 @@ var (
 	ErrCustomerNotFound  = errors.New("customer not found")
 	ErrInsufficientStock = errors.New("insufficient stock")
 	ErrInvalidInput      = errors.New("invalid input")
+	// ErrSaleBelongsToOrder: las ventas de una orden las manejan la orden y la saga, no se tocan a mano
+	ErrSaleBelongsToOrder = errors.New("sale belongs to an order")
 )
 
 // NewSalesService crea una nueva instancia del service
-func NewSalesService(repository SalesRepository, cache SalesRepository, itemsService ItemsService) SalesServiceImpl {
+func NewSalesService(repository SalesRepository, cache SalesRepository, itemsService ItemsService, taxes *TaxServiceImpl) SalesServiceImpl {
 	return SalesServiceImpl{
 		repository:   repository,
 		localCache:   cache,
 		itemsService: itemsService,
+		taxes:        taxes,
 	}
 }
 
@@ -58,19 +63,15 @@ func (s *SalesServiceImpl) Create(ctx context.Context, sale domain.BodySales) (d
 	}
 
 	// VALIDACIONES CONCURRENTES usando Go Routines, Channels y WaitGroup
-	itemPrice, stockAvailable, err := s.validateConcurrently(ctx, sale, customerIDint)
+	item, err := s.validateConcurrently(ctx, sale, customerIDint)
 	if err != nil {
 		return domain.Sales{}, err
 	}
 
-	log.Printf("✅ Concurrent validations passed: stock=%d", stockAvailable)
+	log.Printf("✅ Concurrent validations passed: stock=%d", item.Stock)
 
-	newSale := domain.Sales{
-		ItemID:     sale.ItemID,
-		Quantity:   sale.Quantity,
-		TotalPrice: itemPrice * float64(sale.Quantity),
-		CustomerID: customerIDint,
-	}
+	// Guardamos un snapshot del item para que el historial no dependa del catalogo actual
+	newSale := s.newSaleFromItem(item, sale.Quantity, customerIDint)
 
 	// Decrementar el stock del item de forma atomica para evitar condiciones de carrera y generar sobreventas
 	ok, err := s.itemsService.DecrementStockAtomic(ctx, sale.ItemID, sale.Quantity)
@@ -103,8 +104,83 @@ func (s *SalesServiceImpl) Create(ctx context.Context, sale domain.BodySales) (d
 	return created, nil
 }
 
-// validateConcurrently ejecuta validaciones en paralelo
-func (s *SalesServiceImpl) validateConcurrently(ctx context.Context, sale domain.BodySales, customerID int) (float64, int, error) {
+// newSaleFromItem arma una venta con el snapshot del item (nombre, categoria, imagen y precio unitario)
+// y el IVA del total (las ventas directas no tienen descuentos)
+func (s *SalesServiceImpl) newSaleFromItem(item domain.Item, quantity int, customerID int) domain.Sales {
+	totalPrice := item.Price * float64(quantity)
+	tax := s.taxes.Breakdown(item.TaxClass, totalPrice)
+	return domain.Sales{
+		ItemID:     item.ID,
+		Quantity:   quantity,
+		TotalPrice: totalPrice,
+		CustomerID: customerID,
+		ItemName:   item.Name,
+		Category:   item.Category,
+		ImageURL:   item.ImageURL,
+		UnitPrice:  item.Price,
+
+		TaxClass:    tax.TaxClass,
+		TaxRate:     tax.Rate,
+		NetAmount:   tax.Net,
+		TaxAmount:   tax.Tax,
+		GrossAmount: tax.Gross,
+	}
+}
+
+// CreateOrderLines registra una venta por cada línea de una orden de checkout
+// No descuenta stock: la saga de checkout ya lo reservó en un paso anterior
+// Es idempotente: si la orden ya tenía ventas registradas (reintento), se reemplazan
+func (s *SalesServiceImpl) CreateOrderLines(ctx context.Context, orderID string, customerID int, lines []domain.OrderLine) ([]domain.Sales, error) {
+	if err := s.repository.DeleteByOrderID(ctx, orderID); err != nil {
+		return nil, fmt.Errorf("error cleaning previous sales of order %s: %w", orderID, err)
+	}
+
+	created := make([]domain.Sales, 0, len(lines))
+	for _, line := range lines {
+		sale := domain.Sales{
+			ItemID:     line.ItemID,
+			Quantity:   line.Quantity,
+			TotalPrice: line.Subtotal,
+			CustomerID: customerID,
+			OrderID:    orderID,
+			ItemName:   line.ItemName,
+			Category:   line.Category,
+			ImageURL:   line.ImageURL,
+			UnitPrice:  line.UnitPrice,
+
+			TaxClass:    line.TaxClass,
+			TaxRate:     line.TaxRate,
+			Discount:    line.Discount,
+			NetAmount:   line.NetAmount,
+			TaxAmount:   line.TaxAmount,
+			GrossAmount: line.GrossAmount,
+		}
+
+		sale, err := s.repository.Create(ctx, sale)
+		if err != nil {
+			return created, fmt.Errorf("error creating sale for item %s: %w", line.ItemID, err)
+		}
+		created = append(created, sale)
+	}
+
+	return created, nil
+}
+
+// DeleteOrderLines elimina las ventas de una orden sin tocar el stock
+// El stock lo devuelve la compensación del paso de reserva de la saga
+func (s *SalesServiceImpl) DeleteOrderLines(ctx context.Context, orderID string, saleIDs []string) error {
+	for _, id := range saleIDs {
+		_ = s.localCache.Delete(ctx, id)
+	}
+
+	if err := s.repository.DeleteByOrderID(ctx, orderID); err != nil {
+		return fmt.Errorf("error deleting sales of order %s: %w", orderID, err)
+	}
+	return nil
+}
+
+// validateConcurrently ejecuta validaciones en paralelo y devuelve el item validado
+func (s *SalesServiceImpl) validateConcurrently(ctx context.Context, sale domain.BodySales, customerID int) (domain.Item, error) {
 	// Canal para recibir resultados de las goroutines
 	results := make(chan domain.ValidationResult, 2)
 
@@ -148,14 +224,11 @@ func (s *SalesServiceImpl) validateConcurrently(ctx context.Context, sale domain
 			return
 		}
 
-		// Éxito: enviar precio y stock disponible
+		// Éxito: enviar el item completo (precio, stock y datos para el snapshot)
 		results <- domain.ValidationResult{
 			Name:    "item",
 			Success: true,
-			Data: map[string]interface{}{
-				"price": item.Price,
-				"stock": item.Stock,
-			},
+			Data:    item,
 		}
 		log.Printf("✅ Goroutine 1: Item validated - price=%.2f, stock=%d", item.Price, item.Stock)
 	}()
@@ -193,32 +266,29 @@ func (s *SalesServiceImpl) validateConcurrently(ctx context.Context, sale domain
 	}()
 
 	// Recolectar resultados del canal
-	var itemPrice float64
-	var stockAvailable int
+	var item domain.Item
 	validations := make(map[string]bool)
 
 	for result := range results { // Lee del canal hasta que esté cerrado
 		if !result.Success {
 			log.Printf("❌ Validation failed: %s - %v", result.Name, result.Error)
-			return 0, 0, result.Error
+			return domain.Item{}, result.Error
 		}
 
 		validations[result.Name] = true // Marcar validación como exitosa
 
 		// Extraer datos del item si esta validado
 		if result.Name == "item" {
-			itemData := result.Data.(map[string]interface{})
-			itemPrice = itemData["price"].(float64)
-			stockAvailable = itemData["stock"].(int)
+			item = result.Data.(domain.Item)
 		}
 	}
 
 	// Verificar que ambas validaciones pasaron
 	if !validations["item"] || !validations["customer"] {
-		return 0, 0, errors.New("incomplete validations")
+		return domain.Item{}, errors.New("incomplete validations")
 	}
 
-	return itemPrice, stockAvailable, nil
+	return item, nil
 }
 
 // GetByID obtiene una venta por su ID
@@ -246,6 +316,7 @@ func (s *SalesServiceImpl) GetByID(ctx context.Context, id string) (domain.Sales
 }
 
 // GetByCustomerID obtiene todas las ventas de un cliente específico
+// Cada venta ya trae el snapshot del item, no hace falta consultar ItemsService
 func (s *SalesServiceImpl) GetByCustomerID(ctx context.Context, customerID string) ([]domain.Sales, error) {
 
 	customerIDint, err := strconv.Atoi(customerID)
@@ -270,6 +341,9 @@ func (s *SalesServiceImpl) Update(ctx context.Context, id string, sale domain.Up
 	if err != nil {
 		return domain.Sales{}, fmt.Errorf("error getting original sale: %w", err)
 	}
+	if originalSale.OrderID != "" {
+		return domain.Sales{}, fmt.Errorf("%w: sale %s is part of order %s", ErrSaleBelongsToOrder, id, originalSale.OrderID)
+	}
 
 	// Obtener el item para validar stock
 	item, err := s.itemsService.GetByID(ctx, sale.ItemID)
@@ -296,15 +370,9 @@ func (s *SalesServiceImpl) Update(ctx context.Context, id string, sale domain.Up
 		return domain.Sales{}, fmt.Errorf("error updating item stock: %w", err)
 	}
 
-	newSale := domain.Sales{
-		ItemID:     sale.ItemID,
-		Quantity:   sale.Quantity,
-		TotalPrice: 0, // Se recalculará abajo
-		CustomerID: originalSale.CustomerID,
-	}
-
-	// Recalcular el precio total
-	newSale.TotalPrice = item.Price * float64(newSale.Quantity)
+	// Recalcular el precio total y refrescar el snapshot con los datos actuales del item
+	newSale := s.newSaleFromItem(item, sale.Quantity, originalSale.CustomerID)
+	newSale.SaleDate = originalSale.SaleDate
 
 	// Actualizar el stock si hubo cambios
 	if quantityDiff != 0 {
@@ -336,6 +404,9 @@ func (s *SalesServiceImpl) Delete(ctx context.Context, id string) error {
 	if err != nil {
 		return fmt.Errorf("error getting sale: %w", err)
 	}
+	if sale.OrderID != "" {
+		return fmt.Errorf("%w: sale %s is part of order %s", ErrSaleBelongsToOrder, id, sale.OrderID)
+	}
 
 	// Obtener el item para restaurar el stock
 	item, err := s.itemsService.GetByID(ctx, sale.ItemID)
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"products-api/internal/domain"
+	"strings"
+)
+
+// ShippingRateTable resuelve la zona de una dirección y la tarifa para un peso
+// La implementación por defecto es ZoneRateTable, pero se puede reemplazar (por ejemplo, por la API de un correo)
+type ShippingRateTable interface {
+	ZoneFor(address domain.ShippingAddress) (string, bool)
+	RateFor(zone, method string, weightKg float64) (domain.ShippingRate, bool)
+}
+
+// AddressBook obtiene las direcciones guardadas de un usuario (viven en users-api)
+type AddressBook interface {
+	GetAddress(ctx context.Context, customerID, addressID int) (domain.ShippingAddress, error)
+}
+
+var (
+	ErrInvalidShippingMethod   = errors.New("invalid shipping method")
+	ErrShippingAddressRequired = errors.New("shipping address is required")
+	ErrInvalidShippingAddress  = errors.New("invalid shipping address")
+	ErrAddressNotFound         = errors.New("address not found")
+	ErrShippingNotAvailable    = errors.New("shipping not available for this destination")
+)
+
+// defaultItemWeightKg se usa para los items cargados antes de tener peso
+const defaultItemWeightKg = 0.5
+
+// ShippingServiceImpl calcula el costo de envío de una compra
+type ShippingServiceImpl struct {
+	rates       ShippingRateTable
+	addressBook AddressBook
+}
+
+// NewShippingService crea una nueva instancia del service
+func NewShippingService(rates ShippingRateTable, addressBook AddressBook) *ShippingServiceImpl {
+	return &ShippingServiceImpl{
+		rates:       rates,
+		addressBook: addressBook,
+	}
+}
+
+// ResolveAddress valida el request de envío y devuelve la dirección de entrega
+// Si se mandó un address_id se busca en la libreta del usuario. Para retiro en sucursal devuelve nil
+func (s *ShippingServiceImpl) ResolveAddress(ctx context.Context, customerID int, req domain.ShippingRequest) (string, *domain.ShippingAddress, error) {
+	method := strings.ToLower(strings.TrimSpace(req.Method))
+	if method == "" {
+		method = domain.ShippingMethodStandard
+	}
+
+	switch method {
+	case domain.ShippingMethodPickup:
+		return method, nil, nil
+	case domain.ShippingMethodStandard, domain.ShippingMethodExpress:
+	default:
+		return "", nil, fmt.Errorf("%w: %s", ErrInvalidShippingMethod, req.Method)
+	}
+
+	var address domain.ShippingAddress
+	switch {
+	case req.AddressID != 0:
+		found, err := s.addressBook.GetAddress(ctx, customerID, req.AddressID)
+		if err != nil {
+			return "", nil, err
+		}
+		address = found
+	case req.Address != nil:
+		address = *req.Address
+	default:
+		return "", nil, ErrShippingAddressRequired
+	}
+
+	if err := validateShippingAddress(address); err != nil {
+		return "", nil, err
+	}
+
+	// Se valida que haya tarifa para el destino antes de reservar stock
+	zone, ok := s.rates.ZoneFor(address)
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %s %s", ErrShippingNotAvailable, address.Province, address.PostalCode)
+	}
+	if _, ok := s.rates.RateFor(zone, method, 0); !ok {
+		return "", nil, fmt.Errorf("%w: %s shipping to %s", ErrShippingNotAvailable, method, zone)
+	}
+
+	return method, &address, nil
+}
+
+// Quote calcula el costo de envío para un peso total
+func (s *ShippingServiceImpl) Quote(method string, address *domain.ShippingAddress, weightKg float64) (domain.ShippingQuote, error) {
+	weightKg = roundWeight(weightKg)
+	if method == domain.ShippingMethodPickup {
+		return domain.ShippingQuote{Method: method, WeightKg: weightKg}, nil
+	}
+	if address == nil {
+		return domain.ShippingQuote{}, ErrShippingAddressRequired
+	}
+
+	zone, ok := s.rates.ZoneFor(*address)
+	if !ok {
+		return domain.ShippingQuote{}, fmt.Errorf("%w: %s %s", ErrShippingNotAvailable, address.Province, address.PostalCode)
+	}
+	rate, ok := s.rates.RateFor(zone, method, weightKg)
+	if !ok {
+		return domain.ShippingQuote{}, fmt.Errorf("%w: %.2f kg exceeds the %s limit for %s", ErrShippingNotAvailable, weightKg, method, zone)
+	}
+
+	return domain.ShippingQuote{
+		Method:        method,
+		Zone:          zone,
+		WeightKg:      weightKg,
+		Cost:          roundMoney(rate.Cost),
+		EstimatedDays: rate.EstimatedDays,
+	}, nil
+}
+
+// UnitWeightKg es el peso facturable de una unidad del item
+func (s *ShippingServiceImpl) UnitWeightKg(item domain.Item) float64 {
+	if weight := item.BillableWeightKg(); weight > 0 {
+		return weight
+	}
+	return defaultItemWeightKg
+}
+
+func validateShippingAddress(address domain.ShippingAddress) error {
+	missing := []string{}
+	if strings.TrimSpace(address.RecipientName) == "" {
+		missing = append(missing, "recipient_name")
+	}
+	if strings.TrimSpace(address.Street) == "" {
+		missing = append(missing, "street")
+	}
+	if strings.TrimSpace(address.Number) == "" {
+		missing = append(missing, "number")
+	}
+	if strings.TrimSpace(address.City) == "" {
+		missing = append(missing, "city")
+	}
+	if strings.TrimSpace(address.Province) == "" {
+		missing = append(missing, "province")
+	}
+	if strings.TrimSpace(address.PostalCode) == "" {
+		missing = append(missing, "postal_code")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing %s", ErrInvalidShippingAddress, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// roundWeight redondea a gramos
+func roundWeight(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"products-api/internal/domain"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ZoneRateTable es una tabla de tarifas en memoria: zonas por provincia/código postal
+// y tarifas escalonadas por peso máximo
+type ZoneRateTable struct {
+	zones []domain.ShippingZone
+	rates []domain.ShippingRate
+}
+
+// rateTableFile es el formato del archivo JSON de tarifas
+type rateTableFile struct {
+	Zones []domain.ShippingZone `json:"zones"`
+	Rates []domain.ShippingRate `json:"rates"`
+}
+
+// NewZoneRateTable crea una tabla con las zonas y tarifas indicadas
+func NewZoneRateTable(zones []domain.ShippingZone, rates []domain.ShippingRate) *ZoneRateTable {
+	sorted := make([]domain.ShippingRate, len(rates))
+	copy(sorted, rates)
+	// Ordenadas por peso para quedarnos con el primer escalón que alcance
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].MaxWeightKg < sorted[j].MaxWeightKg
+	})
+	return &ZoneRateTable{zones: zones, rates: sorted}
+}
+
+// LoadZoneRateTable lee la tabla de tarifas desde un archivo JSON
+func LoadZoneRateTable(path string) (*ZoneRateTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading shipping rates: %w", err)
+	}
+	var file rateTableFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("error parsing shipping rates: %w", err)
+	}
+	if len(file.Zones) == 0 || len(file.Rates) == 0 {
+		return nil, fmt.Errorf("shipping rates file must define zones and rates")
+	}
+	return NewZoneRateTable(file.Zones, file.Rates), nil
+}
+
+// ZoneFor busca la zona de la dirección: primero por código postal y si no por provincia
+func (t *ZoneRateTable) ZoneFor(address domain.ShippingAddress) (string, bool) {
+	if postalCode, ok := numericPostalCode(address.PostalCode); ok {
+		for _, zone := range t.zones {
+			for _, r := range zone.PostalCodes {
+				if postalCode >= r.From && postalCode <= r.To {
+					return zone.Name, true
+				}
+			}
+		}
+	}
+
+	province := normalizeProvince(address.Province)
+	for _, zone := range t.zones {
+		for _, p := range zone.Provinces {
+			if normalizeProvince(p) == province {
+				return zone.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// RateFor devuelve la tarifa del primer escalón de peso que alcanza
+func (t *ZoneRateTable) RateFor(zone, method string, weightKg float64) (domain.ShippingRate, bool) {
+	for _, rate := range t.rates {
+		if rate.Zone == zone && rate.Method == method && weightKg <= rate.MaxWeightKg {
+			return rate, true
+		}
+	}
+	return domain.ShippingRate{}, false
+}
+
+// numericPostalCode extrae los 4 dígitos del código postal (acepta "5000" y el CPA "X5000ABC")
+func numericPostalCode(postalCode string) (int, bool) {
+	postalCode = strings.ToUpper(strings.TrimSpace(postalCode))
+	if len(postalCode) >= 5 && unicode.IsLetter(rune(postalCode[0])) {
+		postalCode = postalCode[1:5]
+	}
+	if len(postalCode) != 4 {
+		return 0, false
+	}
+	value, err := strconv.Atoi(postalCode)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+var accentReplacer = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u")
+
+// normalizeProvince compara provincias sin mayúsculas ni tildes ("Córdoba" == "cordoba")
+func normalizeProvince(province string) string {
+	province = accentReplacer.Replace(strings.ToLower(province))
+	return strings.Join(strings.Fields(province), " ")
+}
+
+// DefaultShippingRateTable es la tabla que se usa si no se configura un archivo de tarifas
+func DefaultShippingRateTable() *ZoneRateTable {
+	zones := []domain.ShippingZone{
+		{
+			Name:        "amba",
+			Provinces:   []string{"CABA", "Ciudad Autónoma de Buenos Aires", "Capital Federal"},
+			PostalCodes: []domain.PostalCodeRange{{From: 1000, To: 1499}, {From: 1600, To: 1899}},
+		},
+		{
+			Name:      "centro",
+			Provinces: []string{"Buenos Aires", "Córdoba", "Santa Fe", "Entre Ríos", "La Pampa"},
+		},
+		{
+			Name:      "cuyo",
+			Provinces: []string{"Mendoza", "San Juan", "San Luis", "La Rioja"},
+		},
+		{
+			Name:      "norte",
+			Provinces: []string{"Tucumán", "Salta", "Jujuy", "Catamarca", "Santiago del Estero", "Chaco", "Formosa", "Corrientes", "Misiones"},
+		},
+		{
+			Name:      "patagonia",
+			Provinces: []string{"Neuquén", "Río Negro", "Chubut", "Santa Cruz", "Tierra del Fuego"},
+		},
+	}
+
+	// Costo base (hasta 1 kg) y días de entrega por zona
+	base := map[string]struct {
+		cost        float64
+		days        int
+		expressDays int
+	}{
+		"amba":      {cost: 2500, days: 3, expressDays: 1},
+		"centro":    {cost: 3800, days: 5, expressDays: 2},
+		"cuyo":      {cost: 4600, days: 6, expressDays: 3},
+		"norte":     {cost: 5200, days: 7, expressDays: 3},
+		"patagonia": {cost: 6100, days: 8, expressDays: 4},
+	}
+	// Escalones de peso: multiplicador sobre el costo base
+	brackets := []struct {
+		maxWeightKg float64
+		factor      float64
+	}{
+		{1, 1}, {5, 1.5}, {10, 2.2}, {25, 3.5},
+	}
+	const expressFactor = 1.7
+
+	rates := []domain.ShippingRate{}
+	for _, zone := range zones {
+		b := base[zone.Name]
+		for _, bracket := range brackets {
+			cost := b.cost * bracket.factor
+			rates = append(rates,
+				domain.ShippingRate{Zone: zone.Name, Method: domain.ShippingMethodStandard, MaxWeightKg: bracket.maxWeightKg, Cost: roundMoney(cost), EstimatedDays: b.days},
+				domain.ShippingRate{Zone: zone.Name, Method: domain.ShippingMethodExpress, MaxWeightKg: bracket.maxWeightKg, Cost: roundMoney(cost * expressFactor), EstimatedDays: b.expressDays},
+			)
+		}
+	}
+
+	return NewZoneRateTable(zones, rates)
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"products-api/internal/domain"
+	"testing"
+)
+
+// MockAddressBook simula las direcciones guardadas en users-api
+type MockAddressBook struct {
+	addresses map[int]domain.ShippingAddress
+}
+
+func (m *MockAddressBook) GetAddress(ctx context.Context, customerID, addressID int) (domain.ShippingAddress, error) {
+	address, ok := m.addresses[addressID]
+	if !ok {
+		return domain.ShippingAddress{}, ErrAddressNotFound
+	}
+	return address, nil
+}
+
+func newTestShippingAddress(province, postalCode string) domain.ShippingAddress {
+	return domain.ShippingAddress{
+		RecipientName: "Juan Perez",
+		Street:        "Av. Colón",
+		Number:        "1234",
+		City:          "Córdoba",
+		Province:      province,
+		PostalCode:    postalCode,
+	}
+}
+
+func TestZoneRateTable_ZoneByPostalCodeAndProvince(t *testing.T) {
+	rates := DefaultShippingRateTable()
+
+	cases := []struct {
+		address domain.ShippingAddress
+		want    string
+	}{
+		{newTestShippingAddress("Buenos Aires", "C1425ABC"), "amba"},
+		{newTestShippingAddress("Buenos Aires", "1714"), "amba"},
+		{newTestShippingAddress("Buenos Aires", "7600"), "centro"},
+		{newTestShippingAddress("cordoba", "5000"), "centro"},
+		{newTestShippingAddress("Neuquén", "Q8300"), "patagonia"},
+	}
+
+	for _, tc := range cases {
+		zone, ok := rates.ZoneFor(tc.address)
+		if !ok || zone != tc.want {
+			t.Errorf("Expected zone %s for %s %s, got %s (found=%v)", tc.want, tc.address.Province, tc.address.PostalCode, zone, ok)
+		}
+	}
+}
+
+func TestShippingQuote_WeightBrackets(t *testing.T) {
+	service := NewShippingService(DefaultShippingRateTable(), &MockAddressBook{})
+	address := newTestShippingAddress("Córdoba", "5000")
+
+	light, err := service.Quote(domain.ShippingMethodStandard, &address, 0.8)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	heavy, err := service.Quote(domain.ShippingMethodStandard, &address, 7)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if light.Cost != 3800 || heavy.Cost != 8360 {
+		t.Errorf("Expected costs 3800 and 8360, got %.2f and %.2f", light.Cost, heavy.Cost)
+	}
+
+	express, err := service.Quote(domain.ShippingMethodExpress, &address, 0.8)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if express.Cost <= light.Cost || express.EstimatedDays >= light.EstimatedDays {
+		t.Errorf("Expected express to be faster and more expensive, got %+v vs %+v", express, light)
+	}
+
+	if _, err := service.Quote(domain.ShippingMethodStandard, &address, 40); !errors.Is(err, ErrShippingNotAvailable) {
+		t.Errorf("Expected ErrShippingNotAvailable for 40 kg, got %v", err)
+	}
+}
+
+func TestShippingResolveAddress(t *testing.T) {
+	saved := newTestShippingAddress("Mendoza", "5500")
+	service := NewShippingService(DefaultShippingRateTable(), &MockAddressBook{addresses: map[int]domain.ShippingAddress{7: saved}})
+	ctx := context.Background()
+
+	method, address, err := service.ResolveAddress(ctx, 1, domain.ShippingRequest{AddressID: 7})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if method != domain.ShippingMethodStandard || address == nil || address.City != saved.City {
+		t.Errorf("Expected saved address with standard shipping, got %s %+v", method, address)
+	}
+
+	if _, _, err := service.ResolveAddress(ctx, 1, domain.ShippingRequest{AddressID: 99}); !errors.Is(err, ErrAddressNotFound) {
+		t.Errorf("Expected ErrAddressNotFound, got %v", err)
+	}
+	if _, _, err := service.ResolveAddress(ctx, 1, domain.ShippingRequest{}); !errors.Is(err, ErrShippingAddressRequired) {
+		t.Errorf("Expected ErrShippingAddressRequired, got %v", err)
+	}
+	if _, _, err := service.ResolveAddress(ctx, 1, domain.ShippingRequest{Method: "drone"}); !errors.Is(err, ErrInvalidShippingMethod) {
+		t.Errorf("Expected ErrInvalidShippingMethod, got %v", err)
+	}
+
+	incomplete := domain.ShippingAddress{Province: "Mendoza"}
+	if _, _, err := service.ResolveAddress(ctx, 1, domain.ShippingRequest{Address: &incomplete}); !errors.Is(err, ErrInvalidShippingAddress) {
+		t.Errorf("Expected ErrInvalidShippingAddress, got %v", err)
+	}
+
+	method, address, err = service.ResolveAddress(ctx, 1, domain.ShippingRequest{Method: "pickup"})
+	if err != nil || method != domain.ShippingMethodPickup || address != nil {
+		t.Errorf("Expected pickup without address, got %s %+v %v", method, address, err)
+	}
+}
+
+func TestItemBillableWeight(t *testing.T) {
+	item := domain.Item{WeightKg: 1, Dimensions: domain.Dimensions{LengthCm: 50, WidthCm: 40, HeightCm: 30}}
+	if weight := item.BillableWeightKg(); weight != 12 {
+		t.Errorf("Expected volumetric weight 12, got %.2f", weight)
+	}
+
+	service := NewShippingService(DefaultShippingRateTable(), &MockAddressBook{})
+	if weight := service.UnitWeightKg(domain.Item{}); weight != defaultItemWeightKg {
+		t.Errorf("Expected default weight for items without weight, got %.2f", weight)
+	}
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"fmt"
+	"products-api/internal/domain"
+	"strings"
+)
+
+// taxRates son las alícuotas de IVA por clase
+var taxRates = map[string]float64{
+	domain.TaxClassGeneral: 0.21,
+	domain.TaxClassReduced: 0.105,
+	domain.TaxClassExempt:  0,
+}
+
+// taxClassOrder es el orden en que se informan las alícuotas en los resúmenes
+var taxClassOrder = []string{domain.TaxClassGeneral, domain.TaxClassReduced, domain.TaxClassExempt}
+
+// TaxServiceImpl calcula el IVA de líneas y órdenes
+// Con pricesIncludeTax los precios del catálogo son finales (el IVA se despeja);
+// si no, son netos y el IVA se suma
+type TaxServiceImpl struct {
+	pricesIncludeTax bool
+}
+
+// NewTaxService crea una nueva instancia del service
+func NewTaxService(pricesIncludeTax bool) *TaxServiceImpl {
+	return &TaxServiceImpl{
+		pricesIncludeTax: pricesIncludeTax,
+	}
+}
+
+// PricesIncludeTax indica si los precios del catálogo incluyen IVA
+func (s *TaxServiceImpl) PricesIncludeTax() bool {
+	return s.pricesIncludeTax
+}
+
+// Breakdown separa el importe de una línea (ya con descuentos) en neto, IVA y bruto
+func (s *TaxServiceImpl) Breakdown(taxClass string, amount float64) domain.TaxBreakdown {
+	taxClass = NormalizeTaxClass(taxClass)
+	rate := taxRates[taxClass]
+
+	var net, tax float64
+	if s.pricesIncludeTax {
+		gross := roundMoney(amount)
+		net = roundMoney(gross / (1 + rate))
+		tax = roundMoney(gross - net)
+	} else {
+		net = roundMoney(amount)
+		tax = roundMoney(net * rate)
+	}
+
+	return domain.TaxBreakdown{
+		TaxClass: taxClass,
+		Rate:     rate,
+		Net:      net,
+		Tax:      tax,
+		Gross:    roundMoney(net + tax),
+	}
+}
+
+// Summarize suma las líneas y arma los totales por alícuota
+func (s *TaxServiceImpl) Summarize(lines []domain.TaxBreakdown) domain.TaxSummary {
+	summary := domain.TaxSummary{
+		PricesIncludeTax: s.pricesIncludeTax,
+		ByClass:          []domain.TaxBreakdown{},
+	}
+
+	byClass := map[string]*domain.TaxBreakdown{}
+	for _, line := range lines {
+		summary.Net += line.Net
+		summary.Tax += line.Tax
+		summary.Gross += line.Gross
+
+		total, ok := byClass[line.TaxClass]
+		if !ok {
+			total = &domain.TaxBreakdown{TaxClass: line.TaxClass, Rate: line.Rate}
+			byClass[line.TaxClass] = total
+		}
+		total.Net += line.Net
+		total.Tax += line.Tax
+		total.Gross += line.Gross
+	}
+
+	for _, class := range taxClassOrder {
+		if total, ok := byClass[class]; ok {
+			total.Net = roundMoney(total.Net)
+			total.Tax = roundMoney(total.Tax)
+			total.Gross = roundMoney(total.Gross)
+			summary.ByClass = append(summary.ByClass, *total)
+		}
+	}
+	summary.Net = roundMoney(summary.Net)
+	summary.Tax = roundMoney(summary.Tax)
+	summary.Gross = roundMoney(summary.Gross)
+	return summary
+}
+
+// NormalizeTaxClass devuelve la clase de IVA en minúsculas; vacía es la general (21%)
+func NormalizeTaxClass(taxClass string) string {
+	taxClass = strings.ToLower(strings.TrimSpace(taxClass))
+	if taxClass == "" {
+		return domain.TaxClassGeneral
+	}
+	return taxClass
+}
+
+// ValidateTaxClass verifica que la clase de IVA exista
+func ValidateTaxClass(taxClass string) error {
+	if _, ok := taxRates[NormalizeTaxClass(taxClass)]; !ok {
+		return fmt.Errorf("error, tax_class must be %s, %s or %s", domain.TaxClassGeneral, domain.TaxClassReduced, domain.TaxClassExempt)
+	}
+	return nil
+}
+
+// lineDiscounts suma por ítem lo que descuentan promociones y cupón
+func lineDiscounts(discounts []domain.Discount) map[string]float64 {
+	result := map[string]float64{}
+	for _, discount := range discounts {
+		for _, line := range discount.Lines {
+			result[line.ItemID] += line.Amount
+		}
+	}
+	return result
+}
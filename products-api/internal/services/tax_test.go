@@ -0,0 +1,67 @@
+package services
+
+import (
+	"products-api/internal/domain"
+	"testing"
+)
+
+func TestTaxBreakdown_PricesIncludeTax(t *testing.T) {
+	taxes := NewTaxService(true)
+
+	general := taxes.Breakdown(domain.TaxClassGeneral, 1210)
+	if general.Net != 1000 || general.Tax != 210 || general.Gross != 1210 {
+		t.Errorf("Expected 1000 + 210 = 1210, got %+v", general)
+	}
+
+	reduced := taxes.Breakdown(domain.TaxClassReduced, 1105)
+	if reduced.Net != 1000 || reduced.Tax != 105 || reduced.Gross != 1105 {
+		t.Errorf("Expected 1000 + 105 = 1105, got %+v", reduced)
+	}
+
+	// Vacío es la alícuota general
+	if empty := taxes.Breakdown("", 1210); empty.TaxClass != domain.TaxClassGeneral || empty.Tax != 210 {
+		t.Errorf("Expected the general rate for an empty class, got %+v", empty)
+	}
+}
+
+func TestTaxBreakdown_PricesExcludeTax(t *testing.T) {
+	taxes := NewTaxService(false)
+
+	general := taxes.Breakdown(domain.TaxClassGeneral, 1000)
+	if general.Net != 1000 || general.Tax != 210 || general.Gross != 1210 {
+		t.Errorf("Expected 1000 + 210 = 1210, got %+v", general)
+	}
+
+	exempt := taxes.Breakdown(domain.TaxClassExempt, 1000)
+	if exempt.Tax != 0 || exempt.Gross != 1000 {
+		t.Errorf("Expected no tax on exempt items, got %+v", exempt)
+	}
+}
+
+func TestTaxSummarize(t *testing.T) {
+	taxes := NewTaxService(false)
+	summary := taxes.Summarize([]domain.TaxBreakdown{
+		taxes.Breakdown(domain.TaxClassReduced, 2000),
+		taxes.Breakdown(domain.TaxClassGeneral, 1000),
+		taxes.Breakdown(domain.TaxClassGeneral, 500),
+		taxes.Breakdown(domain.TaxClassExempt, 300),
+	})
+
+	if summary.Net != 3800 || summary.Tax != 525 || summary.Gross != 4325 {
+		t.Errorf("Expected 3800 + 525 = 4325, got %+v", summary)
+	}
+	if len(summary.ByClass) != 3 || summary.ByClass[0].TaxClass != domain.TaxClassGeneral || summary.ByClass[0].Tax != 315 {
+		t.Errorf("Expected general first with 315 of tax, got %+v", summary.ByClass)
+	}
+}
+
+func TestValidateTaxClass(t *testing.T) {
+	for _, class := range []string{"", "general", "REDUCED", "exempt"} {
+		if err := ValidateTaxClass(class); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", class, err)
+		}
+	}
+	if err := ValidateTaxClass("super-reduced"); err == nil {
+		t.Errorf("Expected an error for an unknown tax class")
+	}
+}
@@ -0,0 +1,430 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"products-api/internal/domain"
+	"strings"
+	"time"
+)
+
+// WishlistsRepository define las operaciones de datos de las listas de deseos
+type WishlistsRepository interface {
+	List(ctx context.Context, customerID int) ([]domain.Wishlist, error)
+	Count(ctx context.Context, customerID int) (int, error)
+	GetByID(ctx context.Context, id string) (domain.Wishlist, error)
+	GetByShareToken(ctx context.Context, token string) (domain.Wishlist, error)
+	Create(ctx context.Context, wishlist domain.Wishlist) (domain.Wishlist, error)
+	Update(ctx context.Context, wishlist domain.Wishlist) (domain.Wishlist, error)
+	Delete(ctx context.Context, customerID int, id string) error
+}
+
+var (
+	ErrWishlistNotFound     = errors.New("wishlist not found")
+	ErrInvalidWishlist      = errors.New("invalid wishlist")
+	ErrWishlistNameTaken    = errors.New("wishlist name already exists")
+	ErrTooManyWishlists     = errors.New("maximum number of wishlists reached")
+	ErrWishlistItemNotFound = errors.New("item not found in wishlist")
+)
+
+const (
+	maxWishlistsPerCustomer = 20
+	maxWishlistNameLength   = 60
+)
+
+// WishlistsServiceImpl maneja las listas de deseos: varias listas con nombre por cliente,
+// mover productos entre el carrito y una lista, y compartir una lista en solo lectura
+type WishlistsServiceImpl struct {
+	repository WishlistsRepository
+	carts      *CartServiceImpl
+}
+
+// NewWishlistsService crea una nueva instancia del service
+// Usa el service del carrito para enriquecer los ítems y para mover productos al carrito y desde él
+func NewWishlistsService(repository WishlistsRepository, carts *CartServiceImpl) *WishlistsServiceImpl {
+	return &WishlistsServiceImpl{
+		repository: repository,
+		carts:      carts,
+	}
+}
+
+// List obtiene las listas de un cliente con el precio y el stock actuales
+func (s *WishlistsServiceImpl) List(ctx context.Context, customerID int) ([]domain.WishlistResponse, error) {
+	wishlists, err := s.repository.List(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]domain.WishlistResponse, len(wishlists))
+	for i, wishlist := range wishlists {
+		if responses[i], err = s.enrichWishlist(ctx, wishlist); err != nil {
+			return nil, err
+		}
+	}
+	return responses, nil
+}
+
+// Create crea una lista vacía
+func (s *WishlistsServiceImpl) Create(ctx context.Context, customerID int, req domain.WishlistRequest) (domain.WishlistResponse, error) {
+	name, err := normalizeWishlistName(req.Name)
+	if err != nil {
+		return domain.WishlistResponse{}, err
+	}
+
+	count, err := s.repository.Count(ctx, customerID)
+	if err != nil {
+		return domain.WishlistResponse{}, err
+	}
+	if count >= maxWishlistsPerCustomer {
+		return domain.WishlistResponse{}, fmt.Errorf("%w: a customer can have up to %d", ErrTooManyWishlists, maxWishlistsPerCustomer)
+	}
+
+	wishlist, err := s.repository.Create(ctx, domain.Wishlist{
+		CustomerID: customerID,
+		Name:       name,
+		Items:      []domain.WishlistItem{},
+	})
+	if err != nil {
+		return domain.WishlistResponse{}, mapWishlistError(err)
+	}
+
+	log.Printf("💝 Wishlist created - Customer: %d, Name: %s", customerID, name)
+	return s.enrichWishlist(ctx, wishlist)
+}
+
+// Get obtiene una lista del cliente
+func (s *WishlistsServiceImpl) Get(ctx context.Context, customerID int, wishlistID string) (domain.WishlistResponse, error) {
+	wishlist, err := s.getOwned(ctx, customerID, wishlistID)
+	if err != nil {
+		return domain.WishlistResponse{}, err
+	}
+	return s.enrichWishlist(ctx, wishlist)
+}
+
+// Rename cambia el nombre de una lista
+func (s *WishlistsServiceImpl) Rename(ctx context.Context, customerID int, wishlistID string, req domain.WishlistRequest) (domain.WishlistResponse, error) {
+	name, err := normalizeWishlistName(req.Name)
+	if err != nil {
+		return domain.WishlistResponse{}, err
+	}
+
+	wishlist, err := s.getOwned(ctx, customerID, wishlistID)
+	if err != nil {
+		return domain.WishlistResponse{}, err
+	}
+
+	wishlist.Name = name
+	return s.save(ctx, wishlist)
+}
+
+// Delete elimina una lista (y su enlace compartido)
+func (s *WishlistsServiceImpl) Delete(ctx context.Context, customerID int, wishlistID string) error {
+	if err := s.repository.Delete(ctx, customerID, wishlistID); err != nil {
+		return mapWishlistError(err)
+	}
+	log.Printf("🗑️ Wishlist deleted - Customer: %d, Wishlist: %s", customerID, wishlistID)
+	return nil
+}
+
+// AddItem guarda un producto en la lista; si ya estaba no hace nada
+// A diferencia del carrito no exige stock: la lista sirve justamente para recordar lo que no se compra todavía
+func (s *WishlistsServiceImpl) AddItem(ctx context.Context, customerID int, wishlistID string, req domain.AddWishlistItemRequest) (domain.WishlistResponse, error) {
+	wishlist, err := s.getOwned(ctx, customerID, wishlistID)
+	if err != nil {
+		return domain.WishlistResponse{}, err
+	}
+
+	item, found, err := s.carts.lookupItem(ctx, req.ItemID)
+	if err != nil {
+		return domain.WishlistResponse{}, err
+	}
+	if !found {
+		return domain.WishlistResponse{}, ErrItemNotFound
+	}
+
+	var added bool
+	wishlist, added = addWishlistItem(wishlist, req.ItemID, item.Price, time.Now().UTC())
+	if !added {
+		return s.enrichWishlist(ctx, wishlist)
+	}
+	return s.save(ctx, wishlist)
+}
+
+// RemoveItem saca un producto de la lista
+func (s *WishlistsServiceImpl) RemoveItem(ctx context.Context, customerID int, wishlistID, itemID string) (domain.WishlistResponse, error) {
+	wishlist, err := s.getOwned(ctx, customerID, wishlistID)
+	if err != nil {
+		return domain.WishlistResponse{}, err
+	}
+
+	wishlist, err = removeWishlistItem(wishlist, itemID)
+	if err != nil {
+		return domain.WishlistResponse{}, err
+	}
+	return s.save(ctx, wishlist)
+}
+
+// MoveToCart agrega el producto al carrito y recién entonces lo saca de la lista
+// Si falla el carrito (sin stock, producto eliminado) la lista queda como estaba
+func (s *WishlistsServiceImpl) MoveToCart(ctx context.Context, customerID int, wishlistID, itemID string, req domain.MoveToCartRequest) (domain.WishlistMoveResult, error) {
+	wishlist, err := s.getOwned(ctx, customerID, wishlistID)
+	if err != nil {
+		return domain.WishlistMoveResult{}, err
+	}
+	if !hasWishlistItem(wishlist, itemID) {
+		return domain.WishlistMoveResult{}, ErrWishlistItemNotFound
+	}
+
+	quantity := req.Quantity
+	if quantity <= 0 {
+		quantity = 1
+	}
+
+	cart, err := s.carts.AddItem(ctx, customerID, domain.AddItemRequest{ItemID: itemID, Quantity: quantity})
+	if err != nil {
+		return domain.WishlistMoveResult{}, mapWishlistCartError(err)
+	}
+
+	wishlist, err = removeWishlistItem(wishlist, itemID)
+	if err != nil {
+		return domain.WishlistMoveResult{}, err
+	}
+	response, err := s.save(ctx, wishlist)
+	if err != nil {
+		return domain.WishlistMoveResult{}, err
+	}
+
+	log.Printf("🛒 Item moved from wishlist to cart - Customer: %d, Wishlist: %s, Item: %s", customerID, wishlistID, itemID)
+	return domain.WishlistMoveResult{Cart: cart, Wishlist: response}, nil
+}
+
+// MoveFromCart guarda una línea del carrito en la lista y la saca del carrito, liberando la reserva de stock del checkout
+// Se conserva el precio que el cliente vio al agregarla para seguir avisando si cambia
+func (s *WishlistsServiceImpl) MoveFromCart(ctx context.Context, customerID int, itemID string, req domain.MoveToWishlistRequest) (domain.WishlistMoveResult, error) {
+	wishlist, err := s.getOwned(ctx, customerID, req.WishlistID)
+	if err != nil {
+		return domain.WishlistMoveResult{}, err
+	}
+
+	cart, err := s.carts.repository.GetByCustomerID(ctx, customerID)
+	if err != nil {
+		return domain.WishlistMoveResult{}, ErrCartItemNotFound
+	}
+	line, ok := findCartLine(cart, itemID)
+	if !ok {
+		return domain.WishlistMoveResult{}, ErrCartItemNotFound
+	}
+
+	priceAtAdd := line.PriceAtAdd
+	if priceAtAdd == 0 {
+		item, found, err := s.carts.lookupItem(ctx, itemID)
+		if err != nil {
+			return domain.WishlistMoveResult{}, err
+		}
+		if found {
+			priceAtAdd = item.Price
+		}
+	}
+
+	// Primero la lista: si después falla el carrito, el producto queda en los dos lugares y no se pierde
+	wishlist, _ = addWishlistItem(wishlist, itemID, priceAtAdd, time.Now().UTC())
+	response, err := s.save(ctx, wishlist)
+	if err != nil {
+		return domain.WishlistMoveResult{}, err
+	}
+
+	cartResponse, err := s.carts.RemoveItem(ctx, customerID, itemID)
+	if err != nil {
+		return domain.WishlistMoveResult{}, err
+	}
+
+	log.Printf("💝 Item moved from cart to wishlist - Customer: %d, Wishlist: %s, Item: %s", customerID, req.WishlistID, itemID)
+	return domain.WishlistMoveResult{Cart: cartResponse, Wishlist: response}, nil
+}
+
+// Share genera el token para ver la lista en solo lectura; si ya estaba compartida devuelve el mismo
+func (s *WishlistsServiceImpl) Share(ctx context.Context, customerID int, wishlistID string) (domain.WishlistResponse, error) {
+	wishlist, err := s.getOwned(ctx, customerID, wishlistID)
+	if err != nil {
+		return domain.WishlistResponse{}, err
+	}
+	if wishlist.ShareToken != "" {
+		return s.enrichWishlist(ctx, wishlist)
+	}
+
+	token, err := newSessionToken()
+	if err != nil {
+		return domain.WishlistResponse{}, err
+	}
+	wishlist.ShareToken = token
+
+	log.Printf("🔗 Wishlist shared - Customer: %d, Wishlist: %s", customerID, wishlistID)
+	return s.save(ctx, wishlist)
+}
+
+// Unshare invalida el token: los enlaces ya enviados dejan de funcionar
+func (s *WishlistsServiceImpl) Unshare(ctx context.Context, customerID int, wishlistID string) (domain.WishlistResponse, error) {
+	wishlist, err := s.getOwned(ctx, customerID, wishlistID)
+	if err != nil {
+		return domain.WishlistResponse{}, err
+	}
+	if wishlist.ShareToken == "" {
+		return s.enrichWishlist(ctx, wishlist)
+	}
+
+	wishlist.ShareToken = ""
+	return s.save(ctx, wishlist)
+}
+
+// GetShared obtiene una lista compartida en solo lectura, sin datos del cliente ni el token
+func (s *WishlistsServiceImpl) GetShared(ctx context.Context, token string) (domain.WishlistResponse, error) {
+	if token == "" {
+		return domain.WishlistResponse{}, ErrWishlistNotFound
+	}
+	wishlist, err := s.repository.GetByShareToken(ctx, token)
+	if err != nil {
+		return domain.WishlistResponse{}, mapWishlistError(err)
+	}
+
+	response, err := s.enrichWishlist(ctx, wishlist)
+	if err != nil {
+		return domain.WishlistResponse{}, err
+	}
+	response.CustomerID = 0
+	response.ShareToken = ""
+	response.ReadOnly = true
+	return response, nil
+}
+
+// getOwned obtiene una lista y verifica que sea del cliente
+// Las listas de otro cliente se informan como inexistentes para no revelar sus IDs
+func (s *WishlistsServiceImpl) getOwned(ctx context.Context, customerID int, wishlistID string) (domain.Wishlist, error) {
+	wishlist, err := s.repository.GetByID(ctx, wishlistID)
+	if err != nil {
+		return domain.Wishlist{}, mapWishlistError(err)
+	}
+	if wishlist.CustomerID != customerID {
+		return domain.Wishlist{}, ErrWishlistNotFound
+	}
+	return wishlist, nil
+}
+
+func (s *WishlistsServiceImpl) save(ctx context.Context, wishlist domain.Wishlist) (domain.WishlistResponse, error) {
+	updated, err := s.repository.Update(ctx, wishlist)
+	if err != nil {
+		return domain.WishlistResponse{}, mapWishlistError(err)
+	}
+	return s.enrichWishlist(ctx, updated)
+}
+
+// enrichWishlist completa cada ítem con el precio y el stock actuales, igual que las líneas del carrito
+func (s *WishlistsServiceImpl) enrichWishlist(ctx context.Context, wishlist domain.Wishlist) (domain.WishlistResponse, error) {
+	items := make([]domain.CartItemWithDetails, 0, len(wishlist.Items))
+	for _, wishlistItem := range wishlist.Items {
+		line, _, err := s.carts.enrichLine(ctx, domain.CartItem{
+			ItemID:     wishlistItem.ItemID,
+			Quantity:   1,
+			PriceAtAdd: wishlistItem.PriceAtAdd,
+		})
+		if err != nil {
+			return domain.WishlistResponse{}, err
+		}
+		items = append(items, line)
+	}
+
+	return domain.WishlistResponse{
+		ID:         wishlist.ID,
+		CustomerID: wishlist.CustomerID,
+		Name:       wishlist.Name,
+		Items:      items,
+		ItemCount:  len(items),
+		ShareToken: wishlist.ShareToken,
+		Shared:     wishlist.ShareToken != "",
+		CreatedAt:  wishlist.CreatedAt,
+		UpdatedAt:  wishlist.UpdatedAt,
+	}, nil
+}
+
+// normalizeWishlistName limpia los espacios del nombre y valida su largo
+func normalizeWishlistName(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return "", fmt.Errorf("%w: name is required", ErrInvalidWishlist)
+	}
+	if len([]rune(name)) > maxWishlistNameLength {
+		return "", fmt.Errorf("%w: name must be at most %d characters", ErrInvalidWishlist, maxWishlistNameLength)
+	}
+	return name, nil
+}
+
+// addWishlistItem agrega el producto al final de la lista; added es false si ya estaba
+func addWishlistItem(wishlist domain.Wishlist, itemID string, price float64, at time.Time) (domain.Wishlist, bool) {
+	if hasWishlistItem(wishlist, itemID) {
+		return wishlist, false
+	}
+	wishlist.Items = append(wishlist.Items, domain.WishlistItem{
+		ItemID:     itemID,
+		PriceAtAdd: price,
+		AddedAt:    at,
+	})
+	return wishlist, true
+}
+
+// removeWishlistItem saca un producto de la lista
+func removeWishlistItem(wishlist domain.Wishlist, itemID string) (domain.Wishlist, error) {
+	items := make([]domain.WishlistItem, 0, len(wishlist.Items))
+	for _, item := range wishlist.Items {
+		if item.ItemID != itemID {
+			items = append(items, item)
+		}
+	}
+	if len(items) == len(wishlist.Items) {
+		return domain.Wishlist{}, ErrWishlistItemNotFound
+	}
+	wishlist.Items = items
+	return wishlist, nil
+}
+
+func hasWishlistItem(wishlist domain.Wishlist, itemID string) bool {
+	for _, item := range wishlist.Items {
+		if item.ItemID == itemID {
+			return true
+		}
+	}
+	return false
+}
+
+func findCartLine(cart domain.Cart, itemID string) (domain.CartItem, bool) {
+	for _, item := range cart.Items {
+		if item.ItemID == itemID {
+			return item, true
+		}
+	}
+	return domain.CartItem{}, false
+}
+
+func mapWishlistError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch {
+	case strings.Contains(err.Error(), "wishlist not found"), strings.Contains(err.Error(), "invalid ObjectID"):
+		return ErrWishlistNotFound
+	case strings.Contains(err.Error(), "wishlist name already exists"):
+		return ErrWishlistNameTaken
+	}
+	return err
+}
+
+// mapWishlistCartError traduce los errores del carrito al mover un producto desde la lista
+func mapWishlistCartError(err error) error {
+	switch {
+	case strings.Contains(err.Error(), "insufficient stock"):
+		return fmt.Errorf("%w: %s", ErrInsufficientStock, strings.TrimPrefix(err.Error(), "insufficient stock: "))
+	case strings.Contains(err.Error(), "item not found"), strings.Contains(err.Error(), "item does not exist"):
+		return ErrItemNotFound
+	}
+	return err
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"errors"
+	"products-api/internal/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeWishlistName(t *testing.T) {
+	name, err := normalizeWishlistName("  Regalos   de   cumple ")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if name != "Regalos de cumple" {
+		t.Errorf("Expected collapsed spaces, got %q", name)
+	}
+
+	if _, err := normalizeWishlistName("   "); !errors.Is(err, ErrInvalidWishlist) {
+		t.Errorf("Expected ErrInvalidWishlist for a blank name, got %v", err)
+	}
+	if _, err := normalizeWishlistName(strings.Repeat("ñ", maxWishlistNameLength+1)); !errors.Is(err, ErrInvalidWishlist) {
+		t.Errorf("Expected ErrInvalidWishlist for a long name, got %v", err)
+	}
+}
+
+func TestWishlistItems(t *testing.T) {
+	now := time.Now().UTC()
+	wishlist := domain.Wishlist{Items: []domain.WishlistItem{}}
+
+	wishlist, added := addWishlistItem(wishlist, "mate-1", 1500, now)
+	if !added || len(wishlist.Items) != 1 || wishlist.Items[0].PriceAtAdd != 1500 {
+		t.Fatalf("Expected mate-1 added at 1500, got %+v", wishlist.Items)
+	}
+
+	// Agregar dos veces el mismo producto no lo duplica ni pisa el precio guardado
+	wishlist, added = addWishlistItem(wishlist, "mate-1", 1800, now)
+	if added || len(wishlist.Items) != 1 || wishlist.Items[0].PriceAtAdd != 1500 {
+		t.Errorf("Expected mate-1 to stay once at 1500, got %+v", wishlist.Items)
+	}
+
+	wishlist, err := removeWishlistItem(wishlist, "mate-1")
+	if err != nil || len(wishlist.Items) != 0 {
+		t.Errorf("Expected mate-1 removed, got %+v (%v)", wishlist.Items, err)
+	}
+	if _, err := removeWishlistItem(wishlist, "mate-1"); !errors.Is(err, ErrWishlistItemNotFound) {
+		t.Errorf("Expected ErrWishlistItemNotFound, got %v", err)
+	}
+}
@@ -3,13 +3,16 @@ package main
 import (
 	"log"
 	"net/http"
+	"strings"
 	"time"
+	"users-api/internal/clients"
 	"users-api/internal/config"
 	"users-api/internal/controllers"
 	"users-api/internal/db"
 	"users-api/internal/middleware"
 	"users-api/internal/repository"
 	"users-api/internal/services"
+	"users-api/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -32,15 +35,85 @@ func main() {
 	// Capa de datos: maneja operaciones MySQL con GORM
 	userRepo := repository.NewMySQLUsersRepository(mysqlDB)
 
+	// Hash de contraseñas (los hashes SHA-256 viejos se migran en el login)
+	passwordHasher, err := utils.NewPasswordHasher(utils.PasswordHashConfig{
+		Algorithm:       cfg.PasswordHashAlgorithm,
+		BcryptCost:      cfg.BcryptCost,
+		Argon2Time:      uint32(cfg.Argon2Time),
+		Argon2MemoryKiB: uint32(cfg.Argon2MemoryKiB),
+		Argon2Threads:   uint8(cfg.Argon2Threads),
+	})
+	if err != nil {
+		log.Fatalf("password hasher config error: %v", err)
+	}
+
+	// Refresh tokens (hasheados) de las sesiones abiertas
+	refreshTokensRepo := repository.NewMySQLRefreshTokensRepository(mysqlDB)
+
+	// Links de recuperación de contraseña (hasheados, de un solo uso)
+	passwordResetsRepo := repository.NewMySQLPasswordResetsRepository(mysqlDB)
+
+	// 🔒 Contadores de logins fallidos (por cuenta y por IP)
+	var loginAttempts services.LoginAttemptsStore
+	switch cfg.LoginAttemptsStore {
+	case "mysql":
+		loginAttempts = repository.NewMySQLLoginAttemptsRepository(mysqlDB)
+	case "memory":
+		loginAttempts = repository.NewInMemoryLoginAttemptsRepository()
+	default:
+		log.Fatalf("unknown LOGIN_ATTEMPTS_STORE %q (use mysql or memory)", cfg.LoginAttemptsStore)
+	}
+	loginThrottle := services.LoginThrottle{
+		MaxAccountFailures: cfg.LoginMaxAccountFailures,
+		MaxIPFailures:      cfg.LoginMaxIPFailures,
+		Window:             time.Duration(cfg.LoginFailureWindowMinutes) * time.Minute,
+		Lockout:            time.Duration(cfg.LoginLockoutMinutes) * time.Minute,
+		BaseDelay:          time.Duration(cfg.LoginBaseDelayMillis) * time.Millisecond,
+		MaxDelay:           time.Duration(cfg.LoginMaxDelayMillis) * time.Millisecond,
+	}
+
+	// 🔑 Claves de firma de los JWT (la activa firma, todas verifican y se publican en el JWKS)
+	jwtManager, err := loadJWTManager(cfg)
+	if err != nil {
+		log.Fatalf("jwt keys config error: %v", err)
+	}
+
+	// 📣 Eventos de usuarios (user.registered y user.password_reset con sus links) para notifications-service
+	userEvents := clients.NewRabbitMQEventsPublisher(cfg.RabbitMQUser, cfg.RabbitMQPass, cfg.RabbitMQHost, cfg.RabbitMQPort, cfg.RabbitMQUsersExchange)
+
 	// Capa de lógica de negocio: validaciones, transformaciones
-	userService := services.NewUsersService(userRepo)
+	userService := services.NewUsersService(services.UsersServiceDeps{
+		Repository:     userRepo,
+		ProductsAPIURL: cfg.ProductsAPIURL,
+		Passwords:      passwordHasher,
+		Tokens:         jwtManager,
+		RefreshTokens:  refreshTokensRepo,
+		RefreshTTL:     time.Duration(cfg.RefreshTokenTTLHours) * time.Hour,
+		Events:         userEvents,
+		VerifyURL:      cfg.EmailVerificationURL,
+		VerifyTTL:      time.Duration(cfg.EmailVerificationTTLHours) * time.Hour,
+		PasswordResets: passwordResetsRepo,
+		ResetURL:       cfg.PasswordResetURL,
+		LoginAttempts:  loginAttempts,
+		Throttle:       loginThrottle,
+	})
 
 	// Capa de controladores: maneja HTTP requests/responses
 	userController := controllers.NewUsersController(userService)
 
+	// Libreta de direcciones de entrega (la usa products-api en el checkout)
+	addressRepo := repository.NewMySQLAddressesRepository(mysqlDB)
+	addressService := services.NewAddressesService(addressRepo, userRepo)
+	addressController := controllers.NewAddressesController(addressService, userService)
+
 	// 🌐 Configurar router HTTP con Gin
 	router := gin.Default()
 
+	// La IP del cliente (para contar los logins fallidos) sale de X-Forwarded-For solo si viene de un proxy conocido
+	if err := router.SetTrustedProxies(trustedProxies(cfg.TrustedProxies)); err != nil {
+		log.Fatalf("trusted proxies config error: %v", err)
+	}
+
 	// Middleware: funciones que se ejecutan en cada request
 	router.Use(middleware.CORSMiddleware)
 
@@ -62,19 +135,56 @@ func main() {
 	// GET /users/email/:email - obtener usuario por email
 	router.GET("/users/email/:email", userController.GetUserByEmail)
 
-	// PUT /users/:id - actualizar usuario existente
+	// PUT /users/:id - actualizar usuario existente (él mismo o quien tenga users:write; no cambia el rol)
 	router.PUT("/users/:id", userController.UpdateUser)
 
-	// DELETE /users/:id - eliminar usuario
+	// DELETE /users/:id - eliminar usuario (él mismo o quien tenga users:write)
 	router.DELETE("/users/:id", userController.DeleteUser)
 
+	// 📍 Libreta de direcciones del usuario (con token: el dueño o quien tenga users:read/users:write)
+	router.GET("/users/:id/addresses", addressController.GetAddresses)
+	router.POST("/users/:id/addresses", addressController.CreateAddress)
+	router.GET("/users/:id/addresses/:addressID", addressController.GetAddress)
+	router.DELETE("/users/:id/addresses/:addressID", addressController.DeleteAddress)
+
 	// POST /auth/login - login de usuario
 	router.POST("/auth/login", userController.Login)
 
+	// GET /auth/verify-email - confirmar el email con el token del link de verificación
+	router.GET("/auth/verify-email", userController.VerifyEmail)
+
+	// POST /auth/resend-verification - pedir otro link de verificación
+	router.POST("/auth/resend-verification", userController.ResendVerification)
+
+	// POST /auth/password-reset/request - pedir el email para elegir otra contraseña (responde siempre 200)
+	router.POST("/auth/password-reset/request", userController.RequestPasswordReset)
+
+	// POST /auth/password-reset/confirm - cambiar la contraseña con el token del link (cierra todas las sesiones)
+	router.POST("/auth/password-reset/confirm", userController.ConfirmPasswordReset)
+
+	// POST /auth/refresh - nuevo access token a cambio del refresh token (que se rota)
+	router.POST("/auth/refresh", userController.Refresh)
+
+	// POST /auth/logout - cerrar la sesión del refresh token
+	router.POST("/auth/logout", userController.Logout)
+
+	// POST /users/:id/logout-all - cerrar todas las sesiones del usuario (él mismo o un admin)
+	router.POST("/users/:id/logout-all", userController.LogoutAll)
+
+	// 🛡️ Roles y permisos
+	// GET /roles - roles disponibles y sus permisos
+	router.GET("/roles", userController.GetRoles)
+
+	// PUT /users/:id/role - asignar un rol (solo super admins)
+	router.PUT("/users/:id/role", userController.AssignRole)
+
 	router.POST("/auth/verify-token", userController.VerifyToken)
 
 	router.POST("/auth/verify-admin-token", userController.VerifyAdminToken)
 
+	// GET /.well-known/jwks.json - claves públicas para verificar los access tokens
+	router.GET("/.well-known/jwks.json", userController.JWKS)
+
 	// Configuración del server HTTP
 	srv := &http.Server{
 		Addr:              ":" + cfg.Port,
@@ -91,3 +201,50 @@ func main() {
 		log.Fatalf("server error: %v", err)
 	}
 }
+
+// loadJWTManager arma el JWTManager con las claves del directorio y/o la clave inline
+// Sin claves configuradas genera una efímera: sirve para desarrollo, pero los tokens no sobreviven un reinicio
+func loadJWTManager(cfg config.Config) (*utils.JWTManager, error) {
+	keys := []utils.SigningKey{}
+	if cfg.JWTKeysDir != "" {
+		dirKeys, err := utils.LoadSigningKeys(cfg.JWTKeysDir)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, dirKeys...)
+	}
+	if cfg.JWTPrivateKey != "" {
+		key, err := utils.ParseSigningKey(cfg.JWTKeyID, []byte(cfg.JWTPrivateKey))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		key, err := utils.NewEphemeralSigningKey()
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("⚠️ No JWT keys configured (JWT_KEYS_DIR / JWT_PRIVATE_KEY): using ephemeral key %s", key.ID)
+		return utils.NewJWTManager([]utils.SigningKey{key}, key.ID)
+	}
+
+	manager, err := utils.NewJWTManager(keys, cfg.JWTActiveKID)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("🔑 JWT signing with kid %s (%d verification keys)", manager.ActiveKID(), len(keys))
+	return manager, nil
+}
+
+// trustedProxies separa la lista de proxies de la config (vacía: no se confía en ninguno)
+func trustedProxies(value string) []string {
+	var proxies []string
+	for _, proxy := range strings.Split(value, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
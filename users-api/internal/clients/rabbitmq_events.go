@@ -0,0 +1,58 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"users-api/internal/domain"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// RabbitMQEventsPublisher publica los eventos de usuarios en un exchange topic
+// notifications-service declara su propia cola y la bindea con las routing keys que le interesan
+type RabbitMQEventsPublisher struct {
+	connection *amqp091.Connection
+	channel    *amqp091.Channel
+	exchange   string
+}
+
+// NewRabbitMQEventsPublisher se conecta a RabbitMQ y declara el exchange topic (durable)
+func NewRabbitMQEventsPublisher(user, password, host, port, exchange string) *RabbitMQEventsPublisher {
+	connStr := fmt.Sprintf("amqp://%s:%s@%s:%s/", user, password, host, port)
+	connection, err := amqp091.Dial(connStr)
+	if err != nil {
+		log.Fatalf("failed to connect to RabbitMQ: %v", err)
+	}
+	channel, err := connection.Channel()
+	if err != nil {
+		log.Fatalf("failed to open a channel: %v", err)
+	}
+	if err := channel.ExchangeDeclare(exchange, amqp091.ExchangeTopic, true, false, false, false, nil); err != nil {
+		log.Fatalf("failed to declare exchange %s: %v", exchange, err)
+	}
+	return &RabbitMQEventsPublisher{connection: connection, channel: channel, exchange: exchange}
+}
+
+// PublishUserEvent publica el evento con su tipo como routing key (user.registered)
+func (r *RabbitMQEventsPublisher) PublishUserEvent(ctx context.Context, event domain.UserEvent) error {
+	bytes, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error marshalling event to JSON: %w", err)
+	}
+
+	if err := r.channel.PublishWithContext(ctx, r.exchange, event.Type, false, false, amqp091.Publishing{
+		ContentType:     "application/json",
+		ContentEncoding: "UTF-8",
+		DeliveryMode:    amqp091.Persistent,
+		MessageId:       event.ID,
+		Type:            event.Type,
+		Timestamp:       event.OccurredAt,
+		AppId:           "users-api",
+		Body:            bytes,
+	}); err != nil {
+		return fmt.Errorf("error publishing event to RabbitMQ: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+)
 
 type Config struct {
 	Port   string
@@ -9,6 +12,44 @@ type Config struct {
 	DBUser string
 	DBPass string
 	DBName string
+	// ProductsAPIURL es la URL interna de products-api (carrito del cliente en el login)
+	ProductsAPIURL string
+	// Hash de contraseñas: algoritmo (argon2id o bcrypt) y costo
+	PasswordHashAlgorithm string
+	BcryptCost            int
+	Argon2Time            int
+	Argon2MemoryKiB       int
+	Argon2Threads         int
+	// RefreshTokenTTLHours es cuánto vale un refresh token sin usarse
+	RefreshTokenTTLHours int
+	// Claves de firma de los JWT (RS256/EdDSA): un directorio con <kid>.pem (privadas) y <kid>.pub.pem
+	// (solo verificación, para claves retiradas), o una clave privada inline con su kid
+	JWTKeysDir    string
+	JWTActiveKID  string
+	JWTPrivateKey string
+	JWTKeyID      string
+	// RabbitMQ: los eventos de usuarios (user.registered, user.password_reset) se publican en el exchange topic users
+	RabbitMQUser          string
+	RabbitMQPass          string
+	RabbitMQHost          string
+	RabbitMQPort          string
+	RabbitMQUsersExchange string
+	// Verificación de email: link del frontend (se le agrega ?token=) y horas de vigencia
+	EmailVerificationURL      string
+	EmailVerificationTTLHours int
+	// Recuperación de contraseña: link del frontend (se le agrega ?token=), vale 30 minutos
+	PasswordResetURL string
+	// Protección del login: "mysql" (compartido entre réplicas) o "memory" (una sola réplica)
+	LoginAttemptsStore        string
+	LoginMaxAccountFailures   int
+	LoginMaxIPFailures        int
+	LoginFailureWindowMinutes int
+	LoginLockoutMinutes       int
+	LoginBaseDelayMillis      int
+	LoginMaxDelayMillis       int
+	// TrustedProxies son los proxies (IPs o CIDRs separados por coma) de los que se acepta X-Forwarded-For
+	// Vacío: la IP del cliente es la de la conexión
+	TrustedProxies string
 }
 
 func Load() Config {
@@ -19,6 +60,42 @@ func Load() Config {
 		DBUser: getEnv("DB_USER", "root"),
 		DBPass: getEnv("DB_PASS", "password"),
 		DBName: getEnv("DB_NAME", "users_db"),
+
+		ProductsAPIURL: getEnv("PRODUCTS_API_URL", "http://products-api:8080"),
+
+		PasswordHashAlgorithm: getEnv("PASSWORD_HASH_ALGORITHM", "argon2id"),
+		BcryptCost:            getEnvInt("BCRYPT_COST", 12),
+		Argon2Time:            getEnvInt("ARGON2_TIME", 3),
+		Argon2MemoryKiB:       getEnvInt("ARGON2_MEMORY_KIB", 64*1024),
+		Argon2Threads:         getEnvInt("ARGON2_THREADS", 2),
+
+		RefreshTokenTTLHours: getEnvInt("REFRESH_TOKEN_TTL_HOURS", 30*24),
+
+		JWTKeysDir:    getEnv("JWT_KEYS_DIR", ""),
+		JWTActiveKID:  getEnv("JWT_ACTIVE_KID", ""),
+		JWTPrivateKey: getEnv("JWT_PRIVATE_KEY", ""),
+		JWTKeyID:      getEnv("JWT_KEY_ID", "default"),
+
+		RabbitMQUser:          getEnv("RABBITMQ_USER", "admin"),
+		RabbitMQPass:          getEnv("RABBITMQ_PASS", "admin"),
+		RabbitMQHost:          getEnv("RABBITMQ_HOST", "localhost"),
+		RabbitMQPort:          getEnv("RABBITMQ_PORT", "5672"),
+		RabbitMQUsersExchange: getEnv("RABBITMQ_USERS_EXCHANGE", "users"),
+
+		EmailVerificationURL:      getEnv("EMAIL_VERIFICATION_URL", "http://localhost:3000/verificar-email"),
+		EmailVerificationTTLHours: getEnvInt("EMAIL_VERIFICATION_TTL_HOURS", 48),
+
+		PasswordResetURL: getEnv("PASSWORD_RESET_URL", "http://localhost:3000/restablecer-password"),
+
+		LoginAttemptsStore:        getEnv("LOGIN_ATTEMPTS_STORE", "mysql"),
+		LoginMaxAccountFailures:   getEnvInt("LOGIN_MAX_ACCOUNT_FAILURES", 5),
+		LoginMaxIPFailures:        getEnvInt("LOGIN_MAX_IP_FAILURES", 20),
+		LoginFailureWindowMinutes: getEnvInt("LOGIN_FAILURE_WINDOW_MINUTES", 15),
+		LoginLockoutMinutes:       getEnvInt("LOGIN_LOCKOUT_MINUTES", 15),
+		LoginBaseDelayMillis:      getEnvInt("LOGIN_BASE_DELAY_MS", 250),
+		LoginMaxDelayMillis:       getEnvInt("LOGIN_MAX_DELAY_MS", 4000),
+
+		TrustedProxies: getEnv("TRUSTED_PROXIES", ""),
 	}
 }
 
@@ -29,6 +106,14 @@ func getEnv(k, def string) string {
 	return def
 }
 
+func getEnvInt(k string, def int) int {
+	v, err := strconv.Atoi(getEnv(k, ""))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
 // GetDSN construye la cadena de conexión para MySQL
 func (c Config) GetDSN() string {
 	return c.DBUser + ":" + c.DBPass + "@tcp(" + c.DBHost + ":" + c.DBPort + ")/" + c.DBName + "?charset=utf8mb4&parseTime=True&loc=Local"
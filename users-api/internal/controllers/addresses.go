@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"users-api/internal/domain"
+	"users-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AddressesService define las operaciones de la libreta de direcciones
+type AddressesService interface {
+	List(ctx context.Context, caller domain.TokenClaims, userID int) ([]domain.Address, error)
+	GetByID(ctx context.Context, caller domain.TokenClaims, userID, id int) (domain.Address, error)
+	Create(ctx context.Context, caller domain.TokenClaims, userID int, address domain.Address) (domain.Address, error)
+	Delete(ctx context.Context, caller domain.TokenClaims, userID, id int) error
+}
+
+// AddressesController maneja las direcciones de entrega de los usuarios
+// Todas las rutas piden token: solo el dueño (o quien tenga el permiso) ve y edita sus direcciones
+type AddressesController struct {
+	service AddressesService
+	tokens  TokenVerifier
+}
+
+// NewAddressesController crea una nueva instancia del controller
+// tokens verifica el token de quien hace la request (el service de usuarios)
+func NewAddressesController(service AddressesService, tokens TokenVerifier) *AddressesController {
+	return &AddressesController{
+		service: service,
+		tokens:  tokens,
+	}
+}
+
+// GetAddresses lista las direcciones de un usuario
+// GET /users/:id/addresses
+func (c *AddressesController) GetAddresses(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	caller, ok := verifyCaller(ctx, c.tokens)
+	if !ok {
+		return
+	}
+
+	addresses, err := c.service.List(ctx, caller, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, addresses)
+}
+
+// GetAddress obtiene una dirección de un usuario
+// GET /users/:id/addresses/:addressID
+func (c *AddressesController) GetAddress(ctx *gin.Context) {
+	userID, addressID, ok := parseAddressParams(ctx)
+	if !ok {
+		return
+	}
+
+	caller, ok := verifyCaller(ctx, c.tokens)
+	if !ok {
+		return
+	}
+
+	address, err := c.service.GetByID(ctx, caller, userID, addressID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrForbidden):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrAddressNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, address)
+}
+
+// CreateAddress agrega una dirección a la libreta del usuario
+// POST /users/:id/addresses
+func (c *AddressesController) CreateAddress(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var address domain.Address
+	if err := ctx.ShouldBindJSON(&address); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	caller, ok := verifyCaller(ctx, c.tokens)
+	if !ok {
+		return
+	}
+
+	created, err := c.service.Create(ctx, caller, userID, address)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrForbidden):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidAddress):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrUserNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, created)
+}
+
+// DeleteAddress elimina una dirección de la libreta del usuario
+// DELETE /users/:id/addresses/:addressID
+func (c *AddressesController) DeleteAddress(ctx *gin.Context) {
+	userID, addressID, ok := parseAddressParams(ctx)
+	if !ok {
+		return
+	}
+
+	caller, ok := verifyCaller(ctx, c.tokens)
+	if !ok {
+		return
+	}
+
+	if err := c.service.Delete(ctx, caller, userID, addressID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrForbidden):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrAddressNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func parseAddressParams(ctx *gin.Context) (int, int, bool) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return 0, 0, false
+	}
+	addressID, err := strconv.Atoi(ctx.Param("addressID"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid address ID"})
+		return 0, 0, false
+	}
+	return userID, addressID, true
+}
@@ -3,10 +3,13 @@ package controllers
 import (
 	"context"
 	"errors"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"users-api/internal/domain"
 	"users-api/internal/services"
+	"users-api/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -19,11 +22,21 @@ type UsersService interface {
 	Create(ctx context.Context, user domain.User) (domain.UserResponse, error)
 	GetByID(ctx context.Context, id string) (domain.UserResponse, error)
 	GetByEmail(ctx context.Context, email string) (domain.User, error)
-	Update(ctx context.Context, id string, user domain.User) (domain.UserResponse, error)
-	Delete(ctx context.Context, id string) error
+	Update(ctx context.Context, caller domain.TokenClaims, id string, user domain.User) (domain.UserResponse, error)
+	Delete(ctx context.Context, caller domain.TokenClaims, id string) error
 	Login(ctx context.Context, loginReq domain.LoginRequest) (domain.LoginResponse, error)
-	VerifyToken(token string) error
-	VerifyAdminToken(token string) error
+	VerifyToken(token string) (domain.TokenClaims, error)
+	VerifyAdminToken(token string) (domain.TokenClaims, error)
+	JWKS() utils.JWKS
+	Refresh(ctx context.Context, refreshToken string) (domain.TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+	LogoutAll(ctx context.Context, caller domain.TokenClaims, userID int) error
+	ListRoles() []domain.RoleInfo
+	VerifyEmail(ctx context.Context, token string) (domain.VerifyEmailResponse, error)
+	ResendVerification(ctx context.Context, email, clientIP string) error
+	RequestPasswordReset(ctx context.Context, email string)
+	ConfirmPasswordReset(ctx context.Context, token, password string) error
+	AssignRole(ctx context.Context, caller domain.TokenClaims, userID int, role string) (domain.UserResponse, error)
 }
 
 type UsersController struct {
@@ -71,7 +84,7 @@ func (c *UsersController) CreateUser(ctx *gin.Context) {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		case errors.Is(err, services.ErrLastNameRequired):
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		case errors.Is(err, services.ErrEmailRequired), errors.Is(err, services.ErrPasswordRequired):
+		case errors.Is(err, services.ErrEmailRequired), errors.Is(err, services.ErrPasswordRequired), errors.Is(err, services.ErrPasswordTooShort):
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		case errors.Is(err, services.ErrEmailAlreadyExists):
 			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
@@ -131,7 +144,8 @@ func (c *UsersController) GetUserByEmail(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, user)
 }
 
-// UpdateUser actualiza un usuario existente
+// UpdateUser actualiza un usuario existente (él mismo o quien tenga users:write)
+// El rol y is_admin no se cambian acá: para eso está PUT /users/:id/role
 // PUT /users/:id
 func (c *UsersController) UpdateUser(ctx *gin.Context) {
 	// 1. Extraemos y validamos el ID
@@ -142,6 +156,11 @@ func (c *UsersController) UpdateUser(ctx *gin.Context) {
 		return
 	}
 
+	caller, ok := c.callerClaims(ctx)
+	if !ok {
+		return
+	}
+
 	// 2. Parseamos el JSON del body
 	var user domain.User
 	if err := ctx.ShouldBindJSON(&user); err != nil {
@@ -150,11 +169,17 @@ func (c *UsersController) UpdateUser(ctx *gin.Context) {
 	}
 
 	// 3. Actualizamos el usuario
-	updatedUser, err := c.service.Update(ctx, strconv.Itoa(id), user)
+	updatedUser, err := c.service.Update(ctx, caller, strconv.Itoa(id), user)
 	if err != nil {
 		switch {
+		case errors.Is(err, services.ErrForbidden):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrEmailAlreadyExists):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		case errors.Is(err, services.ErrFirstNameRequired):
 			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrPasswordTooShort):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		case errors.Is(err, services.ErrUserNotFound):
 			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		case errors.Is(err, services.ErrInvalidUserID):
@@ -168,7 +193,7 @@ func (c *UsersController) UpdateUser(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, updatedUser)
 }
 
-// DeleteUser elimina un usuario por ID
+// DeleteUser elimina un usuario por ID (él mismo o quien tenga users:write)
 // DELETE /users/:id
 func (c *UsersController) DeleteUser(ctx *gin.Context) {
 	// 1. Extraemos y validamos el ID
@@ -179,10 +204,17 @@ func (c *UsersController) DeleteUser(ctx *gin.Context) {
 		return
 	}
 
+	caller, ok := c.callerClaims(ctx)
+	if !ok {
+		return
+	}
+
 	// 2. Eliminamos el usuario
-	err = c.service.Delete(ctx, strconv.Itoa(id))
+	err = c.service.Delete(ctx, caller, strconv.Itoa(id))
 	if err != nil {
 		switch {
+		case errors.Is(err, services.ErrForbidden):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		case errors.Is(err, services.ErrUserNotFound):
 			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		case errors.Is(err, services.ErrInvalidUserID):
@@ -205,11 +237,22 @@ func (c *UsersController) Login(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 		return
 	}
+	request.ClientIP = ctx.ClientIP()
 
 	// 2. Llamar al servicio de login (pasando el LoginRequest completo)
 	response, err := c.service.Login(ctx, request)
 	if err != nil {
+		var locked *services.LoginLockedError
 		switch {
+		case errors.As(err, &locked):
+			// Cuenta o IP bloqueada: 429 con cuándo se puede volver a intentar
+			retryAfter := int(math.Ceil(locked.RetryAfter.Seconds()))
+			code := "account_locked"
+			if errors.Is(err, services.ErrTooManyLoginAttempts) {
+				code = "too_many_attempts"
+			}
+			ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+			ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error(), "code": code, "retry_after_seconds": retryAfter})
 		case errors.Is(err, services.ErrInvalidCredentials):
 			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		case errors.Is(err, services.ErrEmailRequired), errors.Is(err, services.ErrPasswordRequired):
@@ -224,6 +267,224 @@ func (c *UsersController) Login(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, response)
 }
 
+// Refresh cambia el refresh token por un access token nuevo y otro refresh token
+// POST /auth/refresh
+func (c *UsersController) Refresh(ctx *gin.Context) {
+	var request domain.RefreshRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "refresh_token is required"})
+		return
+	}
+
+	tokens, err := c.service.Refresh(ctx, request.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrRefreshTokenReused):
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error(), "code": "refresh_token_reused"})
+		case errors.Is(err, services.ErrInvalidRefreshToken):
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+// VerifyEmail confirma el email con el token del link que se mandó al registrarse
+// GET /auth/verify-email?token=...
+func (c *UsersController) VerifyEmail(ctx *gin.Context) {
+	token := ctx.Query("token")
+	if token == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "token is required"})
+		return
+	}
+
+	result, err := c.service.VerifyEmail(ctx, token)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidVerificationToken) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// ResendVerification manda otro link de verificación
+// Responde siempre 202 para no revelar qué emails están registrados, o 429 si se pidieron demasiados
+// POST /auth/resend-verification
+func (c *UsersController) ResendVerification(ctx *gin.Context) {
+	var request domain.ResendVerificationRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "email is required"})
+		return
+	}
+
+	if err := c.service.ResendVerification(ctx, request.Email, ctx.ClientIP()); err != nil {
+		var locked *services.LoginLockedError
+		if errors.As(err, &locked) {
+			retryAfter := int(math.Ceil(locked.RetryAfter.Seconds()))
+			ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+			ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error(), "code": "too_many_requests", "retry_after_seconds": retryAfter})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "If the account exists and is not verified, a new verification email was sent"})
+}
+
+// RequestPasswordReset manda el email con el link para elegir otra contraseña
+// Responde siempre 200 para no revelar qué emails están registrados
+// POST /auth/password-reset/request
+func (c *UsersController) RequestPasswordReset(ctx *gin.Context) {
+	var request domain.PasswordResetRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "email is required"})
+		return
+	}
+
+	c.service.RequestPasswordReset(ctx, request.Email)
+	ctx.JSON(http.StatusOK, gin.H{"message": "If the account exists, an email with a link to reset the password was sent"})
+}
+
+// ConfirmPasswordReset cambia la contraseña con el token del link y cierra todas las sesiones
+// POST /auth/password-reset/confirm
+func (c *UsersController) ConfirmPasswordReset(ctx *gin.Context) {
+	var request domain.PasswordResetConfirm
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "token and password are required"})
+		return
+	}
+
+	if err := c.service.ConfirmPasswordReset(ctx, request.Token, request.Password); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidResetToken), errors.Is(err, services.ErrPasswordRequired), errors.Is(err, services.ErrPasswordTooShort):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Password updated, please log in again"})
+}
+
+// Logout revoca la sesión del refresh token
+// POST /auth/logout
+func (c *UsersController) Logout(ctx *gin.Context) {
+	var request domain.LogoutRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "refresh_token is required"})
+		return
+	}
+
+	if err := c.service.Logout(ctx, request.RefreshToken); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// LogoutAll revoca todas las sesiones de un usuario
+// POST /users/:id/logout-all
+func (c *UsersController) LogoutAll(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	caller, ok := c.callerClaims(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.LogoutAll(ctx, caller, userID); err != nil {
+		if errors.Is(err, services.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "All sessions logged out successfully"})
+}
+
+// GetRoles lista los roles con sus permisos
+func (c *UsersController) GetRoles(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.service.ListRoles())
+}
+
+// AssignRole cambia el rol de un usuario (solo super admins)
+func (c *UsersController) AssignRole(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req domain.AssignRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	caller, ok := c.callerClaims(ctx)
+	if !ok {
+		return
+	}
+
+	user, err := c.service.AssignRole(ctx, caller, userID, req.Role)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrRoleAssignForbidden):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidRole), errors.Is(err, services.ErrCannotChangeOwnRole):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrUserNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+// TokenVerifier verifica un access token y devuelve sus claims
+type TokenVerifier interface {
+	VerifyToken(token string) (domain.TokenClaims, error)
+}
+
+// callerClaims verifica el token de quien hace la request; si no es válido ya deja respondido el 401
+func (c *UsersController) callerClaims(ctx *gin.Context) (domain.TokenClaims, bool) {
+	return verifyCaller(ctx, c.service)
+}
+
+// verifyCaller verifica el token del header Authorization; si no es válido ya deja respondido el 401
+func verifyCaller(ctx *gin.Context, verifier TokenVerifier) (domain.TokenClaims, bool) {
+	// El frontend y products-api mandan "Bearer <token>"; se acepta también el token solo
+	token := strings.TrimPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+	if token == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Token is required"})
+		return domain.TokenClaims{}, false
+	}
+	caller, err := verifier.VerifyToken(token)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		return domain.TokenClaims{}, false
+	}
+	return caller, true
+}
+
 func (c *UsersController) VerifyToken(ctx *gin.Context) {
 	// recibo el token desde el header de la request
 	token := ctx.GetHeader("Authorization")
@@ -234,12 +495,13 @@ func (c *UsersController) VerifyToken(ctx *gin.Context) {
 	}
 
 	// llamar al servicio de verify token a través de la interfaz
-	err := c.service.VerifyToken(token)
+	claims, err := c.service.VerifyToken(token)
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 		ctx.Abort()
 		return
 	}
+	ctx.JSON(http.StatusOK, claims)
 }
 
 func (c *UsersController) VerifyAdminToken(ctx *gin.Context) {
@@ -251,10 +513,18 @@ func (c *UsersController) VerifyAdminToken(ctx *gin.Context) {
 		return
 	}
 	// llamar al servicio de verify admin token a través de la interfaz
-	err := c.service.VerifyAdminToken(token)
+	claims, err := c.service.VerifyAdminToken(token)
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 		ctx.Abort()
 		return
 	}
+	ctx.JSON(http.StatusOK, claims)
+}
+
+// JWKS publica las claves públicas para que otros servicios verifiquen los tokens sin llamarnos
+// Se puede cachear un rato: al rotar, la clave nueva se publica antes de empezar a firmar con ella
+func (c *UsersController) JWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, c.service.JWKS())
 }
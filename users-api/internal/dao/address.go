@@ -0,0 +1,61 @@
+package dao
+
+import (
+	"time"
+	"users-api/internal/domain"
+)
+
+type AddressModel struct {
+	ID            int       `gorm:"primaryKey;autoIncrement"`
+	UserID        int       `gorm:"index;not null"` // FK a users
+	Label         string    `gorm:"type:varchar(50)"`
+	RecipientName string    `gorm:"type:varchar(150);not null"`
+	Street        string    `gorm:"type:varchar(150);not null"`
+	Number        string    `gorm:"type:varchar(20);not null"`
+	Apartment     string    `gorm:"type:varchar(20)"`
+	City          string    `gorm:"type:varchar(100);not null"`
+	Province      string    `gorm:"type:varchar(100);not null"`
+	PostalCode    string    `gorm:"type:varchar(10);not null"`
+	Phone         string    `gorm:"type:varchar(30)"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName usa "addresses" como nombre de la tabla
+func (AddressModel) TableName() string {
+	return "addresses"
+}
+
+func (a AddressModel) ToDomain() domain.Address {
+	return domain.Address{
+		ID:            a.ID,
+		UserID:        a.UserID,
+		Label:         a.Label,
+		RecipientName: a.RecipientName,
+		Street:        a.Street,
+		Number:        a.Number,
+		Apartment:     a.Apartment,
+		City:          a.City,
+		Province:      a.Province,
+		PostalCode:    a.PostalCode,
+		Phone:         a.Phone,
+		CreatedAt:     a.CreatedAt,
+		UpdatedAt:     a.UpdatedAt,
+	}
+}
+
+func FromDomainAddress(address domain.Address) AddressModel {
+	return AddressModel{
+		ID:            address.ID,
+		UserID:        address.UserID,
+		Label:         address.Label,
+		RecipientName: address.RecipientName,
+		Street:        address.Street,
+		Number:        address.Number,
+		Apartment:     address.Apartment,
+		City:          address.City,
+		Province:      address.Province,
+		PostalCode:    address.PostalCode,
+		Phone:         address.Phone,
+	}
+}
@@ -0,0 +1,30 @@
+package dao
+
+import (
+	"time"
+	"users-api/internal/domain"
+)
+
+// LoginAttemptModel cuenta los logins fallidos de una cuenta o de una IP dentro de la ventana
+// La fila se borra con el login exitoso de la cuenta
+type LoginAttemptModel struct {
+	Key            string     `gorm:"column:attempt_key;type:varchar(191);primaryKey"`
+	Failures       int        `gorm:"not null;default:0"`
+	FirstFailureAt time.Time  `gorm:"not null"` // Inicio de la ventana en la que se cuentan los fallos
+	LastFailureAt  time.Time  `gorm:"not null"`
+	LockedUntil    *time.Time // Bloqueo temporal después de demasiados fallos
+}
+
+// TableName usa "login_attempts" como nombre de la tabla
+func (LoginAttemptModel) TableName() string {
+	return "login_attempts"
+}
+
+func (a LoginAttemptModel) ToDomain() domain.LoginAttempts {
+	return domain.LoginAttempts{
+		Key:            a.Key,
+		Failures:       a.Failures,
+		FirstFailureAt: a.FirstFailureAt,
+		LockedUntil:    a.LockedUntil,
+	}
+}
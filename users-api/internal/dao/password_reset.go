@@ -0,0 +1,42 @@
+package dao
+
+import (
+	"time"
+	"users-api/internal/domain"
+)
+
+// PasswordResetModel guarda el hash (SHA-256) del token de un link de recuperación, nunca el token
+type PasswordResetModel struct {
+	ID        int        `gorm:"primaryKey;autoIncrement"`
+	UserID    int        `gorm:"index;not null"`
+	TokenHash string     `gorm:"type:char(64);uniqueIndex;not null"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time // Cuándo se usó (o se invalidó porque se usó otro link del usuario)
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+}
+
+// TableName usa "password_resets" como nombre de la tabla
+func (PasswordResetModel) TableName() string {
+	return "password_resets"
+}
+
+func (p PasswordResetModel) ToDomain() domain.PasswordReset {
+	return domain.PasswordReset{
+		ID:        p.ID,
+		UserID:    p.UserID,
+		TokenHash: p.TokenHash,
+		ExpiresAt: p.ExpiresAt,
+		UsedAt:    p.UsedAt,
+		CreatedAt: p.CreatedAt,
+	}
+}
+
+func FromDomainPasswordReset(reset domain.PasswordReset) PasswordResetModel {
+	return PasswordResetModel{
+		ID:        reset.ID,
+		UserID:    reset.UserID,
+		TokenHash: reset.TokenHash,
+		ExpiresAt: reset.ExpiresAt,
+		UsedAt:    reset.UsedAt,
+	}
+}
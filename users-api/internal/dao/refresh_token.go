@@ -0,0 +1,50 @@
+package dao
+
+import (
+	"time"
+	"users-api/internal/domain"
+)
+
+// RefreshTokenModel guarda el hash (SHA-256) de un refresh token, nunca el token
+// Todos los tokens que salen de un mismo login comparten FamilyID: si uno ya usado se vuelve a presentar
+// se revoca la familia entera
+type RefreshTokenModel struct {
+	ID        int        `gorm:"primaryKey;autoIncrement"`
+	UserID    int        `gorm:"index;not null"`
+	FamilyID  string     `gorm:"type:varchar(64);index;not null"`
+	TokenHash string     `gorm:"type:char(64);uniqueIndex;not null"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time // Cuándo se rotó por uno nuevo
+	RevokedAt *time.Time // Logout o reuso detectado
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+}
+
+// TableName usa "refresh_tokens" como nombre de la tabla
+func (RefreshTokenModel) TableName() string {
+	return "refresh_tokens"
+}
+
+func (t RefreshTokenModel) ToDomain() domain.RefreshToken {
+	return domain.RefreshToken{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		FamilyID:  t.FamilyID,
+		TokenHash: t.TokenHash,
+		ExpiresAt: t.ExpiresAt,
+		UsedAt:    t.UsedAt,
+		RevokedAt: t.RevokedAt,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+func FromDomainRefreshToken(token domain.RefreshToken) RefreshTokenModel {
+	return RefreshTokenModel{
+		ID:        token.ID,
+		UserID:    token.UserID,
+		FamilyID:  token.FamilyID,
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt,
+		UsedAt:    token.UsedAt,
+		RevokedAt: token.RevokedAt,
+	}
+}
@@ -21,11 +21,21 @@ func Connect(cfg config.Config) (*gorm.DB, error) {
 		return nil, err
 	}
 
+	// Las cuentas creadas antes de la verificación de email se dan por verificadas
+	// (solo la primera vez: cuando la columna todavía no existe)
+	grandfatherEmails := db.Migrator().HasTable(&dao.UserModel{}) && !db.Migrator().HasColumn(&dao.UserModel{}, "EmailVerifiedAt")
+
 	// Auto-migrar los modelos (crear tablas si no existen)
-	err = db.AutoMigrate(&dao.UserModel{})
+	err = db.AutoMigrate(&dao.UserModel{}, &dao.AddressModel{}, &dao.RefreshTokenModel{}, &dao.PasswordResetModel{}, &dao.LoginAttemptModel{})
 	if err != nil {
 		return nil, err
 	}
 
+	if grandfatherEmails {
+		if err := db.Model(&dao.UserModel{}).Where("email_verified_at IS NULL").Update("email_verified_at", gorm.Expr("created_at")).Error; err != nil {
+			return nil, err
+		}
+	}
+
 	return db, nil
 }
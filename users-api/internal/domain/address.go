@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"time"
+)
+
+// Address es una dirección de entrega guardada en la libreta del usuario
+type Address struct {
+	ID            int       `json:"id"`
+	UserID        int       `json:"user_id"`
+	Label         string    `json:"label"` // Ej: "Casa", "Trabajo"
+	RecipientName string    `json:"recipient_name"`
+	Street        string    `json:"street"`
+	Number        string    `json:"number"`
+	Apartment     string    `json:"apartment"`
+	City          string    `json:"city"`
+	Province      string    `json:"province"`
+	PostalCode    string    `json:"postal_code"`
+	Phone         string    `json:"phone"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
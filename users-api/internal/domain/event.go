@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// Tipos de eventos de usuarios (son las routing keys del exchange users)
+const (
+	EventUserRegistered = "user.registered"
+	EventPasswordReset  = "user.password_reset"
+)
+
+// UserEvent es el evento de cuenta que consume notifications-service
+// ActionURL es el link que tiene que seguir el usuario (verificar el email o elegir otra contraseña)
+type UserEvent struct {
+	ID         string     `json:"id"`
+	Type       string     `json:"type"`
+	OccurredAt time.Time  `json:"occurred_at"`
+	User       EventUser  `json:"user"`
+	ActionURL  string     `json:"action_url,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
+
+// EventUser son los datos del usuario que viajan en el evento (sin password ni rol)
+type EventUser struct {
+	ID        int    `json:"id"`
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// VerifyEmailResponse es la respuesta de GET /auth/verify-email
+type VerifyEmailResponse struct {
+	Message    string    `json:"message"`
+	UserID     int       `json:"user_id"`
+	Email      string    `json:"email"`
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+// ResendVerificationRequest pide otro email de verificación
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required"`
+}
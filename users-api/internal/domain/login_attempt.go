@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// LoginAttempts son los logins fallidos recientes de una cuenta o de una IP
+// Key es "account:<email>" o "ip:<dirección>"
+type LoginAttempts struct {
+	Key            string
+	Failures       int
+	FirstFailureAt time.Time
+	LockedUntil    *time.Time
+}
+
+// LockedAt dice si el bloqueo sigue vigente en el momento now
+func (a LoginAttempts) LockedAt(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
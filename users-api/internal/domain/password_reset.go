@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// PasswordReset es un link de recuperación de contraseña emitido (solo se guarda el hash del token)
+type PasswordReset struct {
+	ID        int
+	UserID    int
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// PasswordResetRequest pide el email con el link para elegir otra contraseña
+type PasswordResetRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// PasswordResetConfirm cambia la contraseña con el token del link
+type PasswordResetConfirm struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
@@ -0,0 +1,81 @@
+package domain
+
+// Roles de los usuarios
+// Los usuarios con IsAdmin de antes de los roles cuentan como super admin
+const (
+	RoleCustomer       = "customer"
+	RoleCatalogManager = "catalog_manager"
+	RoleOrderOperator  = "order_operator"
+	RoleSupport        = "support"
+	RoleSuperAdmin     = "super_admin"
+)
+
+// Permisos que viajan en el token; products-api los controla en cada ruta
+const (
+	PermItemsWrite   = "items:write"
+	PermPricingWrite = "pricing:write" // cupones y promociones
+	PermSalesRead    = "sales:read"
+	PermSalesWrite   = "sales:write"
+	PermOrdersWrite  = "orders:write"
+	PermReportsRead  = "reports:read"
+	PermUsersRead    = "users:read"
+	PermUsersWrite   = "users:write"
+	PermRolesAssign  = "roles:assign"
+)
+
+// rolePermissions es lo que puede hacer cada rol (el cliente solo opera sobre lo suyo, sin permisos extra)
+var rolePermissions = map[string][]string{
+	RoleCustomer:       {},
+	RoleCatalogManager: {PermItemsWrite},
+	RoleOrderOperator:  {PermSalesRead, PermSalesWrite, PermOrdersWrite},
+	RoleSupport:        {PermUsersRead},
+	RoleSuperAdmin: {
+		PermItemsWrite, PermPricingWrite, PermSalesRead, PermSalesWrite, PermOrdersWrite,
+		PermReportsRead, PermUsersRead, PermUsersWrite, PermRolesAssign,
+	},
+}
+
+// roleOrder es el orden en que se listan los roles
+var roleOrder = []string{RoleCustomer, RoleCatalogManager, RoleOrderOperator, RoleSupport, RoleSuperAdmin}
+
+// RoleInfo describe un rol y sus permisos
+type RoleInfo struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+// AssignRoleRequest cambia el rol de un usuario
+type AssignRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// IsValidRole indica si el rol existe
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// EffectiveRole resuelve el rol de un usuario: los IsAdmin sin rol (anteriores a los roles) son super admin
+func EffectiveRole(role string, isAdmin bool) string {
+	if isAdmin {
+		return RoleSuperAdmin
+	}
+	if !IsValidRole(role) {
+		return RoleCustomer
+	}
+	return role
+}
+
+// PermissionsFor devuelve una copia de los permisos del rol
+func PermissionsFor(role string) []string {
+	return append([]string{}, rolePermissions[role]...)
+}
+
+// Roles lista los roles con sus permisos
+func Roles() []RoleInfo {
+	roles := make([]RoleInfo, 0, len(roleOrder))
+	for _, role := range roleOrder {
+		roles = append(roles, RoleInfo{Role: role, Permissions: PermissionsFor(role)})
+	}
+	return roles
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// RefreshToken es un refresh token emitido (solo se guarda su hash)
+type RefreshToken struct {
+	ID        int
+	UserID    int
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// RefreshRequest pide un nuevo access token a cambio del refresh token (que queda usado)
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest cierra la sesión del refresh token
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// TokenPair es un access token nuevo y el refresh token que reemplaza al usado
+type TokenPair struct {
+	Token            string    `json:"token"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
@@ -7,24 +7,35 @@ import (
 type User struct {
 	ID int `json:"id"`
 	//Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	Password  string    `json:"password"`
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	IsAdmin   bool      `json:"is_admin"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Email     string `json:"email"`
+	Password  string `json:"password"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	IsAdmin   bool   `json:"is_admin"`
+	Role      string `json:"role"`
+	// EmailVerifiedAt es nil hasta que el usuario confirma su email
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
 type UserResponse struct {
 	ID int `json:"id"`
 	//Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	IsAdmin   bool      `json:"is_admin"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	IsAdmin   bool   `json:"is_admin"`
+	Role      string `json:"role"`
+	// EmailVerifiedAt es nil hasta que el usuario confirma su email
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// IsEmailVerified indica si el usuario ya confirmó su email
+func (u UserResponse) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
 
 // funcion para pasar el User a UserResponse para evitar exponer el password
@@ -32,25 +43,59 @@ func (u User) ToResponse() UserResponse {
 	return UserResponse{
 		ID: u.ID,
 		//Username:  u.Username,
-		Email:     u.Email,
-		FirstName: u.FirstName,
-		LastName:  u.LastName,
-		IsAdmin:   u.IsAdmin,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		Email:           u.Email,
+		FirstName:       u.FirstName,
+		LastName:        u.LastName,
+		IsAdmin:         u.IsAdmin,
+		Role:            u.Role,
+		EmailVerifiedAt: u.EmailVerifiedAt,
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
 	}
 }
 
 type LoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	// GuestCartToken es el token del carrito de invitado; si viene, ese carrito se fusiona con el del cliente
+	GuestCartToken string `json:"guest_cart_token,omitempty"`
+	// ClientIP la completa el controller: los logins fallidos se cuentan también por IP
+	ClientIP string `json:"-"`
 }
 
 type LoginResponse struct {
-	Token      string      `json:"token"`
-	Name       string      `json:"name"`
-	Surname    string      `json:"surname"`
-	CustomerID int         `json:"customer_id"`
-	IsAdmin    bool        `json:"is_admin"`
-	Cart       interface{} `json:"cart,omitempty"`
+	Token string `json:"token"`
+	// RefreshToken permite pedir otro access token en /auth/refresh sin volver a loguearse
+	RefreshToken     string      `json:"refresh_token"`
+	RefreshExpiresAt time.Time   `json:"refresh_expires_at"`
+	Name             string      `json:"name"`
+	Surname          string      `json:"surname"`
+	CustomerID       int         `json:"customer_id"`
+	IsAdmin          bool        `json:"is_admin"`
+	Role             string      `json:"role"`
+	Permissions      []string    `json:"permissions"`
+	EmailVerified    bool        `json:"email_verified"`
+	Cart             interface{} `json:"cart,omitempty"`
+}
+
+// TokenClaims son los datos verificados de un token que se devuelven a los otros servicios
+type TokenClaims struct {
+	UserID        int      `json:"user_id"`
+	IsAdmin       bool     `json:"is_admin"`
+	Role          string   `json:"role"`
+	Permissions   []string `json:"permissions"`
+	EmailVerified bool     `json:"email_verified"`
+}
+
+// HasPermission indica si el token tiene el permiso (los super admin los tienen todos)
+func (c TokenClaims) HasPermission(permission string) bool {
+	if c.IsAdmin {
+		return true
+	}
+	for _, p := range c.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"users-api/internal/dao"
+	"users-api/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// MySQLAddressesRepository guarda la libreta de direcciones de los usuarios en MySQL
+type MySQLAddressesRepository struct {
+	db *gorm.DB
+}
+
+func NewMySQLAddressesRepository(db *gorm.DB) *MySQLAddressesRepository {
+	return &MySQLAddressesRepository{db: db}
+}
+
+// ListByUser obtiene las direcciones de un usuario
+func (r *MySQLAddressesRepository) ListByUser(ctx context.Context, userID int) ([]domain.Address, error) {
+	var daoAddresses []dao.AddressModel
+
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&daoAddresses).Error; err != nil {
+		return nil, err
+	}
+
+	addresses := make([]domain.Address, len(daoAddresses))
+	for i, daoAddress := range daoAddresses {
+		addresses[i] = daoAddress.ToDomain()
+	}
+	return addresses, nil
+}
+
+// GetByID busca una dirección de un usuario
+func (r *MySQLAddressesRepository) GetByID(ctx context.Context, userID, id int) (domain.Address, error) {
+	var daoAddress dao.AddressModel
+
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&daoAddress).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Address{}, errors.New("address not found")
+		}
+		return domain.Address{}, err
+	}
+	return daoAddress.ToDomain(), nil
+}
+
+// Create inserta una nueva dirección
+func (r *MySQLAddressesRepository) Create(ctx context.Context, address domain.Address) (domain.Address, error) {
+	daoAddress := dao.FromDomainAddress(address)
+
+	if err := r.db.WithContext(ctx).Create(&daoAddress).Error; err != nil {
+		return domain.Address{}, err
+	}
+	return daoAddress.ToDomain(), nil
+}
+
+// Delete elimina una dirección de un usuario
+func (r *MySQLAddressesRepository) Delete(ctx context.Context, userID, id int) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&dao.AddressModel{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("address not found")
+	}
+	return nil
+}
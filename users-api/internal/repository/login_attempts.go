@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+	"users-api/internal/dao"
+	"users-api/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MySQLLoginAttemptsRepository guarda los contadores de logins fallidos en MySQL
+// Lo comparten todas las réplicas de users-api
+type MySQLLoginAttemptsRepository struct {
+	db *gorm.DB
+}
+
+func NewMySQLLoginAttemptsRepository(db *gorm.DB) *MySQLLoginAttemptsRepository {
+	return &MySQLLoginAttemptsRepository{db: db}
+}
+
+// Get devuelve los fallos de la clave (sin fallos si no hay fila)
+func (r *MySQLLoginAttemptsRepository) Get(ctx context.Context, key string) (domain.LoginAttempts, error) {
+	var model dao.LoginAttemptModel
+	if err := r.db.WithContext(ctx).Where("attempt_key = ?", key).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.LoginAttempts{Key: key}, nil
+		}
+		return domain.LoginAttempts{}, err
+	}
+	return model.ToDomain(), nil
+}
+
+// RegisterFailure suma un fallo en un solo upsert, así dos réplicas no pisan el contador
+// Si la ventana o el bloqueo anterior ya vencieron se vuelve a contar desde 1
+// MySQL aplica las asignaciones en orden: failures y first_failure_at se calculan con los valores viejos
+func (r *MySQLLoginAttemptsRepository) RegisterFailure(ctx context.Context, key string, now time.Time, window time.Duration) (domain.LoginAttempts, error) {
+	expired := "(first_failure_at <= ? OR (locked_until IS NOT NULL AND locked_until <= ?))"
+	windowStart := now.Add(-window)
+
+	model := dao.LoginAttemptModel{Key: key, Failures: 1, FirstFailureAt: now, LastFailureAt: now}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "failures"}, Value: gorm.Expr("IF"+expired+", 1, failures + 1)", windowStart, now)},
+			{Column: clause.Column{Name: "first_failure_at"}, Value: gorm.Expr("IF"+expired+", ?, first_failure_at)", windowStart, now, now)},
+			{Column: clause.Column{Name: "locked_until"}, Value: gorm.Expr("IF(locked_until IS NOT NULL AND locked_until <= ?, NULL, locked_until)", now)},
+			{Column: clause.Column{Name: "last_failure_at"}, Value: now},
+		},
+	}).Create(&model).Error
+	if err != nil {
+		return domain.LoginAttempts{}, err
+	}
+	return r.Get(ctx, key)
+}
+
+// Lock bloquea la clave hasta until
+func (r *MySQLLoginAttemptsRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&dao.LoginAttemptModel{}).
+		Where("attempt_key = ?", key).
+		Update("locked_until", until).Error
+}
+
+// Reset borra los fallos de la clave (login exitoso)
+func (r *MySQLLoginAttemptsRepository) Reset(ctx context.Context, key string) error {
+	return r.db.WithContext(ctx).Where("attempt_key = ?", key).Delete(&dao.LoginAttemptModel{}).Error
+}
+
+// InMemoryLoginAttemptsRepository guarda los contadores en memoria
+// Solo sirve con una réplica (o en desarrollo): cada proceso cuenta por su lado y se pierden al reiniciar
+type InMemoryLoginAttemptsRepository struct {
+	mu       sync.Mutex
+	attempts map[string]domain.LoginAttempts
+}
+
+func NewInMemoryLoginAttemptsRepository() *InMemoryLoginAttemptsRepository {
+	return &InMemoryLoginAttemptsRepository{attempts: make(map[string]domain.LoginAttempts)}
+}
+
+func (r *InMemoryLoginAttemptsRepository) Get(ctx context.Context, key string) (domain.LoginAttempts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if attempts, ok := r.attempts[key]; ok {
+		return attempts, nil
+	}
+	return domain.LoginAttempts{Key: key}, nil
+}
+
+// RegisterFailure suma un fallo con las mismas reglas que la versión MySQL
+func (r *InMemoryLoginAttemptsRepository) RegisterFailure(ctx context.Context, key string, now time.Time, window time.Duration) (domain.LoginAttempts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempts, ok := r.attempts[key]
+	lockExpired := attempts.LockedUntil != nil && !now.Before(*attempts.LockedUntil)
+	if !ok || !attempts.FirstFailureAt.After(now.Add(-window)) || lockExpired {
+		attempts = domain.LoginAttempts{Key: key, FirstFailureAt: now, LockedUntil: attempts.LockedUntil}
+	}
+	if lockExpired {
+		attempts.LockedUntil = nil
+	}
+	attempts.Failures++
+	r.attempts[key] = attempts
+	return attempts, nil
+}
+
+func (r *InMemoryLoginAttemptsRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attempts := r.attempts[key]
+	attempts.Key = key
+	attempts.LockedUntil = &until
+	r.attempts[key] = attempts
+	return nil
+}
+
+func (r *InMemoryLoginAttemptsRepository) Reset(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.attempts, key)
+	return nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+	"users-api/internal/dao"
+	"users-api/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// MySQLPasswordResetsRepository guarda los links de recuperación de contraseña (hasheados) en MySQL con GORM
+type MySQLPasswordResetsRepository struct {
+	db *gorm.DB
+}
+
+func NewMySQLPasswordResetsRepository(db *gorm.DB) *MySQLPasswordResetsRepository {
+	return &MySQLPasswordResetsRepository{db: db}
+}
+
+// Create guarda un link de recuperación nuevo
+func (r *MySQLPasswordResetsRepository) Create(ctx context.Context, reset domain.PasswordReset) (domain.PasswordReset, error) {
+	model := dao.FromDomainPasswordReset(reset)
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return domain.PasswordReset{}, err
+	}
+	return model.ToDomain(), nil
+}
+
+// GetByHash busca un link de recuperación por el hash del token
+func (r *MySQLPasswordResetsRepository) GetByHash(ctx context.Context, hash string) (domain.PasswordReset, error) {
+	var model dao.PasswordResetModel
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.PasswordReset{}, errors.New("password reset not found")
+		}
+		return domain.PasswordReset{}, err
+	}
+	return model.ToDomain(), nil
+}
+
+// Consume usa el link, cambia la contraseña y revoca las sesiones del usuario en una sola transacción
+// El link se marca usado solo si seguía vigente: si otro request lo usó antes devuelve false y no se cambia nada
+// Si falla algo se deshace todo y el link sigue sirviendo. Los demás links pendientes del usuario quedan invalidados
+func (r *MySQLPasswordResetsRepository) Consume(ctx context.Context, reset domain.PasswordReset, passwordHash string, at time.Time) (bool, error) {
+	consumed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&dao.PasswordResetModel{}).
+			Where("id = ? AND used_at IS NULL AND expires_at > ?", reset.ID, at).
+			Update("used_at", at)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		result = tx.Model(&dao.UserModel{}).
+			Where("id = ?", reset.UserID).
+			Update("password_hash", passwordHash)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("user not found")
+		}
+
+		if err := tx.Model(&dao.PasswordResetModel{}).
+			Where("user_id = ? AND used_at IS NULL", reset.UserID).
+			Update("used_at", at).Error; err != nil {
+			return err
+		}
+
+		// Quien tenía la contraseña vieja no puede seguir renovando su sesión
+		if err := revokeAllRefreshTokens(tx, reset.UserID, at); err != nil {
+			return err
+		}
+		consumed = true
+		return nil
+	})
+	return consumed, err
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+	"users-api/internal/dao"
+	"users-api/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// MySQLRefreshTokensRepository guarda los refresh tokens (hasheados) en MySQL con GORM
+type MySQLRefreshTokensRepository struct {
+	db *gorm.DB
+}
+
+func NewMySQLRefreshTokensRepository(db *gorm.DB) *MySQLRefreshTokensRepository {
+	return &MySQLRefreshTokensRepository{db: db}
+}
+
+// Create guarda un refresh token nuevo
+func (r *MySQLRefreshTokensRepository) Create(ctx context.Context, token domain.RefreshToken) (domain.RefreshToken, error) {
+	model := dao.FromDomainRefreshToken(token)
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return domain.RefreshToken{}, err
+	}
+	return model.ToDomain(), nil
+}
+
+// GetByHash busca un refresh token por el hash del token
+func (r *MySQLRefreshTokensRepository) GetByHash(ctx context.Context, hash string) (domain.RefreshToken, error) {
+	var model dao.RefreshTokenModel
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.RefreshToken{}, errors.New("refresh token not found")
+		}
+		return domain.RefreshToken{}, err
+	}
+	return model.ToDomain(), nil
+}
+
+// MarkUsed marca el token como usado solo si todavía estaba vigente
+// Devuelve false si otro request lo usó (o revocó) antes: dos refresh concurrentes no pueden rotar el mismo token
+func (r *MySQLRefreshTokensRepository) MarkUsed(ctx context.Context, id int, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&dao.RefreshTokenModel{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// RevokeFamily revoca todos los tokens de una sesión
+func (r *MySQLRefreshTokensRepository) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&dao.RefreshTokenModel{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", at).Error
+}
+
+// RevokeAllForUser revoca todas las sesiones de un usuario
+func (r *MySQLRefreshTokensRepository) RevokeAllForUser(ctx context.Context, userID int, at time.Time) error {
+	return revokeAllRefreshTokens(r.db.WithContext(ctx), userID, at)
+}
+
+// revokeAllRefreshTokens revoca las sesiones del usuario con db, que puede ser una transacción en curso
+// (la usa el cambio de contraseña del link de recuperación)
+func revokeAllRefreshTokens(db *gorm.DB, userID int, at time.Time) error {
+	return db.Model(&dao.RefreshTokenModel{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 	"users-api/internal/dao"
 	"users-api/internal/domain"
 
@@ -81,8 +82,8 @@ func (r *MySQLUsersRepository) Update(ctx context.Context, id int, user domain.U
 		"email":      user.Email,
 		"first_name": user.FirstName,
 		"last_name":  user.LastName,
-		"is_admin":   user.IsAdmin,
 	}
+	// is_admin y role no se tocan acá: solo cambian con UpdateRole
 
 	// Solo agregar password si no está vacío
 	if strings.TrimSpace(user.Password) != "" {
@@ -106,6 +107,50 @@ func (r *MySQLUsersRepository) Update(ctx context.Context, id int, user domain.U
 	return userDAO.ToDomainResponse(), nil
 }
 
+// UpdatePasswordHash reemplaza el hash de la contraseña (migración de hashes en el login)
+func (r *MySQLUsersRepository) UpdatePasswordHash(ctx context.Context, id int, hash string) error {
+	result := r.db.WithContext(ctx).
+		Model(&dao.UserModel{}).
+		Where("id = ?", id).
+		Update("password_hash", hash)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// UpdateRole cambia el rol del usuario; is_admin acompaña al rol de super admin
+func (r *MySQLUsersRepository) UpdateRole(ctx context.Context, id int, role string, isAdmin bool) (domain.UserResponse, error) {
+	result := r.db.WithContext(ctx).
+		Model(&dao.UserModel{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"role": role, "is_admin": isAdmin})
+	if result.Error != nil {
+		return domain.UserResponse{}, result.Error
+	}
+
+	var userDAO dao.UserModel
+	if err := r.db.WithContext(ctx).First(&userDAO, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.UserResponse{}, errors.New("user not found")
+		}
+		return domain.UserResponse{}, err
+	}
+	return userDAO.ToDomainResponse(), nil
+}
+
+// SetEmailVerifiedAt marca el email como verificado (o lo desmarca con nil, al cambiar el email)
+// No controla RowsAffected: desmarcar un email que ya estaba sin verificar no cambia la fila
+func (r *MySQLUsersRepository) SetEmailVerifiedAt(ctx context.Context, id int, at *time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&dao.UserModel{}).
+		Where("id = ?", id).
+		Update("email_verified_at", at).Error
+}
+
 // Delete elimina un usuario por ID
 func (r *MySQLUsersRepository) Delete(ctx context.Context, id int) error {
 	result := r.db.WithContext(ctx).Delete(&dao.UserModel{}, id)
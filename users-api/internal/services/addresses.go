@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"users-api/internal/domain"
+)
+
+// AddressesRepository define las operaciones de datos para la libreta de direcciones
+type AddressesRepository interface {
+	ListByUser(ctx context.Context, userID int) ([]domain.Address, error)
+	GetByID(ctx context.Context, userID, id int) (domain.Address, error)
+	Create(ctx context.Context, address domain.Address) (domain.Address, error)
+	Delete(ctx context.Context, userID, id int) error
+}
+
+var (
+	ErrAddressNotFound = errors.New("address not found")
+	ErrInvalidAddress  = errors.New("recipient name, street, number, city, province and postal code are required")
+)
+
+// AddressesServiceImpl maneja la libreta de direcciones de los usuarios
+type AddressesServiceImpl struct {
+	repository AddressesRepository
+	users      UsersRepository
+}
+
+// NewAddressesService crea una nueva instancia del service
+func NewAddressesService(repository AddressesRepository, users UsersRepository) *AddressesServiceImpl {
+	return &AddressesServiceImpl{
+		repository: repository,
+		users:      users,
+	}
+}
+
+// List obtiene las direcciones de un usuario (él mismo o quien puede ver usuarios)
+func (s *AddressesServiceImpl) List(ctx context.Context, caller domain.TokenClaims, userID int) ([]domain.Address, error) {
+	if err := authorizeAddressBook(caller, userID, domain.PermUsersRead); err != nil {
+		return nil, err
+	}
+	return s.repository.ListByUser(ctx, userID)
+}
+
+// GetByID obtiene una dirección del usuario (él mismo o quien puede ver usuarios)
+// products-api la pide en el checkout con el token del cliente
+func (s *AddressesServiceImpl) GetByID(ctx context.Context, caller domain.TokenClaims, userID, id int) (domain.Address, error) {
+	if err := authorizeAddressBook(caller, userID, domain.PermUsersRead); err != nil {
+		return domain.Address{}, err
+	}
+	address, err := s.repository.GetByID(ctx, userID, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return domain.Address{}, ErrAddressNotFound // 404
+		}
+		return domain.Address{}, err
+	}
+	return address, nil
+}
+
+// Create valida y guarda una nueva dirección para el usuario (él mismo o quien puede editar usuarios)
+func (s *AddressesServiceImpl) Create(ctx context.Context, caller domain.TokenClaims, userID int, address domain.Address) (domain.Address, error) {
+	if err := authorizeAddressBook(caller, userID, domain.PermUsersWrite); err != nil {
+		return domain.Address{}, err
+	}
+	if _, err := s.users.GetByID(ctx, userID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return domain.Address{}, ErrUserNotFound // 404
+		}
+		return domain.Address{}, err
+	}
+
+	address.ID = 0
+	address.UserID = userID
+	address.Label = strings.TrimSpace(address.Label)
+	address.RecipientName = strings.TrimSpace(address.RecipientName)
+	address.Street = strings.TrimSpace(address.Street)
+	address.Number = strings.TrimSpace(address.Number)
+	address.City = strings.TrimSpace(address.City)
+	address.Province = strings.TrimSpace(address.Province)
+	address.PostalCode = strings.ToUpper(strings.TrimSpace(address.PostalCode))
+
+	if address.RecipientName == "" || address.Street == "" || address.Number == "" ||
+		address.City == "" || address.Province == "" || address.PostalCode == "" {
+		return domain.Address{}, ErrInvalidAddress // 400
+	}
+
+	return s.repository.Create(ctx, address)
+}
+
+// Delete elimina una dirección del usuario (él mismo o quien puede editar usuarios)
+func (s *AddressesServiceImpl) Delete(ctx context.Context, caller domain.TokenClaims, userID, id int) error {
+	if err := authorizeAddressBook(caller, userID, domain.PermUsersWrite); err != nil {
+		return err
+	}
+	if err := s.repository.Delete(ctx, userID, id); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return ErrAddressNotFound // 404
+		}
+		return err
+	}
+	return nil
+}
+
+// authorizeAddressBook deja operar sobre las direcciones al dueño o a quien tenga el permiso
+// Son datos personales: nadie más puede listarlas, crearlas ni borrarlas
+func authorizeAddressBook(caller domain.TokenClaims, userID int, permission string) error {
+	if caller.UserID != userID && !caller.HasPermission(permission) {
+		return ErrForbidden // 403
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+	"users-api/internal/domain"
+	"users-api/internal/utils"
+)
+
+// UserEventsPublisher publica los eventos de cuenta (los consume notifications-service)
+type UserEventsPublisher interface {
+	PublishUserEvent(ctx context.Context, event domain.UserEvent) error
+}
+
+var (
+	ErrInvalidVerificationToken    = errors.New("invalid or expired verification link")
+	ErrTooManyVerificationRequests = errors.New("too many verification emails requested, try again later")
+)
+
+// Límites del reenvío del link de verificación; se cuentan en el mismo store que los logins fallidos
+const (
+	verificationResendWindow       = time.Hour
+	maxVerificationResendsPerEmail = 3
+	maxVerificationResendsPerIP    = 10
+)
+
+// VerifyEmail confirma el email con el token del link de verificación
+// Es idempotente: volver a abrir el link de un email ya verificado no es un error
+func (s *UsersServiceImpl) VerifyEmail(ctx context.Context, token string) (domain.VerifyEmailResponse, error) {
+	claims, err := s.tokens.ParseAction(token, utils.PurposeEmailVerification)
+	if err != nil {
+		return domain.VerifyEmailResponse{}, ErrInvalidVerificationToken
+	}
+
+	user, err := s.repository.GetByID(ctx, claims.UserID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return domain.VerifyEmailResponse{}, ErrInvalidVerificationToken
+		}
+		return domain.VerifyEmailResponse{}, err
+	}
+	// El link es del email que tenía al registrarse: si lo cambió, no vale para el nuevo
+	if !strings.EqualFold(user.Email, claims.Email) {
+		return domain.VerifyEmailResponse{}, ErrInvalidVerificationToken
+	}
+
+	if user.IsEmailVerified() {
+		return domain.VerifyEmailResponse{Message: "Email already verified", UserID: user.ID, Email: user.Email, VerifiedAt: *user.EmailVerifiedAt}, nil
+	}
+
+	now := time.Now().UTC()
+	if err := s.repository.SetEmailVerifiedAt(ctx, user.ID, &now); err != nil {
+		return domain.VerifyEmailResponse{}, err
+	}
+	log.Printf("✅ Email verified for user %d", user.ID)
+	return domain.VerifyEmailResponse{Message: "Email verified successfully", UserID: user.ID, Email: user.Email, VerifiedAt: now}, nil
+}
+
+// ResendVerification manda otro link de verificación
+// No dice si el email existe o si ya estaba verificado: siempre responde igual
+// Los pedidos se cuentan por email (exista o no) y por IP; pasado el máximo devuelve un *LoginLockedError
+// con ErrTooManyVerificationRequests y no manda nada
+func (s *UsersServiceImpl) ResendVerification(ctx context.Context, email, clientIP string) error {
+	email = strings.TrimSpace(email)
+	now := time.Now().UTC()
+
+	if clientIP != "" {
+		if retryAfter, limited := s.countVerificationResend(ctx, "resend:ip:"+clientIP, maxVerificationResendsPerIP, now); limited {
+			return &LoginLockedError{Reason: ErrTooManyVerificationRequests, RetryAfter: retryAfter}
+		}
+	}
+	if retryAfter, limited := s.countVerificationResend(ctx, "resend:email:"+strings.ToLower(email), maxVerificationResendsPerEmail, now); limited {
+		return &LoginLockedError{Reason: ErrTooManyVerificationRequests, RetryAfter: retryAfter}
+	}
+
+	user, err := s.repository.GetByEmail(ctx, email)
+	if err != nil || user.EmailVerifiedAt != nil {
+		return nil
+	}
+	s.sendVerificationEmail(ctx, user.ToResponse())
+	return nil
+}
+
+// countVerificationResend suma el pedido a la clave y, si pasó maxRequests dentro de la ventana,
+// devuelve cuánto falta para que la ventana venza
+// Si el store no responde el pedido sigue, igual que el login
+func (s *UsersServiceImpl) countVerificationResend(ctx context.Context, key string, maxRequests int, now time.Time) (time.Duration, bool) {
+	attempts, err := s.loginAttempts.RegisterFailure(ctx, key, now, verificationResendWindow)
+	if err != nil {
+		log.Printf("Error counting verification resends: %v", err)
+		return 0, false
+	}
+	if attempts.Failures <= maxRequests {
+		return 0, false
+	}
+	log.Printf("🔒 Verification resend limited for %s", key)
+	return attempts.FirstFailureAt.Add(verificationResendWindow).Sub(now), true
+}
+
+// sendVerificationEmail publica user.registered con el link de verificación
+// Si falla solo se loguea: el usuario puede pedir otro link con ResendVerification
+func (s *UsersServiceImpl) sendVerificationEmail(ctx context.Context, user domain.UserResponse) {
+	token, expiresAt, err := s.tokens.GenerateAction(utils.PurposeEmailVerification, user.ID, user.Email, s.verifyTTL)
+	if err != nil {
+		log.Printf("Error generating verification token for user %d: %v", user.ID, err)
+		return
+	}
+
+	if err := s.publishUserEvent(ctx, domain.EventUserRegistered, user, actionLink(s.verifyURL, token), expiresAt); err != nil {
+		log.Printf("Error publishing %s for user %d: %v", domain.EventUserRegistered, user.ID, err)
+		return
+	}
+	log.Printf("📧 Verification email requested for user %d", user.ID)
+}
+
+// publishUserEvent publica un evento de cuenta con el link que tiene que seguir el usuario
+func (s *UsersServiceImpl) publishUserEvent(ctx context.Context, eventType string, user domain.UserResponse, link string, expiresAt time.Time) error {
+	eventID, err := randomToken(16)
+	if err != nil {
+		return err
+	}
+
+	return s.events.PublishUserEvent(ctx, domain.UserEvent{
+		ID:         eventID,
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		User: domain.EventUser{
+			ID:        user.ID,
+			Email:     user.Email,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+		},
+		ActionURL: link,
+		ExpiresAt: &expiresAt,
+	})
+}
+
+// actionLink arma el link del email: la URL del frontend con el token como query param
+func actionLink(baseURL, token string) string {
+	separator := "?"
+	if strings.Contains(baseURL, "?") {
+		separator = "&"
+	}
+	return fmt.Sprintf("%s%stoken=%s", baseURL, separator, url.QueryEscape(token))
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"users-api/internal/domain"
+)
+
+// tokenFromLink saca el token del link de verificación del evento
+func tokenFromLink(t *testing.T, event domain.UserEvent) string {
+	t.Helper()
+	if !strings.HasPrefix(event.ActionURL, testVerificationURL+"?token=") {
+		t.Fatalf("Expected a verification link, got %q", event.ActionURL)
+	}
+	link, _ := url.Parse(event.ActionURL)
+	return link.Query().Get("token")
+}
+
+// TestVerifyEmail_RegistrationFlow verifica que el registro publica user.registered y el link confirma el email
+func TestVerifyEmail_RegistrationFlow(t *testing.T) {
+	events := NewMockUserEventsPublisher()
+	service := newTestUsersService(UsersServiceDeps{Events: events})
+
+	created, err := service.Create(context.Background(), domain.User{Email: "nueva@example.com", Password: "password123", FirstName: "Ana", LastName: "Paz"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created.IsEmailVerified() {
+		t.Error("Expected a new user to start unverified")
+	}
+
+	if len(events.events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events.events))
+	}
+	event := events.events[0]
+	if event.Type != domain.EventUserRegistered || event.User.ID != created.ID || event.User.Email != created.Email || event.ExpiresAt == nil {
+		t.Errorf("Unexpected event %+v", event)
+	}
+	token := tokenFromLink(t, event)
+
+	if _, err := service.VerifyEmail(context.Background(), "not-a-token"); !errors.Is(err, ErrInvalidVerificationToken) {
+		t.Errorf("Expected ErrInvalidVerificationToken, got %v", err)
+	}
+
+	result, err := service.VerifyEmail(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.UserID != created.ID {
+		t.Errorf("Expected user %d, got %+v", created.ID, result)
+	}
+	user, _ := service.GetByID(context.Background(), strconv.Itoa(created.ID))
+	if !user.IsEmailVerified() {
+		t.Error("Expected the email to be verified")
+	}
+
+	// Abrir el link de nuevo no es un error
+	if _, err := service.VerifyEmail(context.Background(), token); err != nil {
+		t.Errorf("Expected the link to be idempotent, got %v", err)
+	}
+
+	// Ya verificado: reenviar no publica nada
+	if err := service.ResendVerification(context.Background(), created.Email, ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := service.ResendVerification(context.Background(), "nadie@example.com", ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(events.events) != 1 {
+		t.Errorf("Expected no new events, got %d", len(events.events))
+	}
+}
+
+// TestVerifyEmail_EmailChangeRequiresNewVerification verifica que cambiar el email lo desverifica y el link viejo deja de valer
+func TestVerifyEmail_EmailChangeRequiresNewVerification(t *testing.T) {
+	events := NewMockUserEventsPublisher()
+	service := newTestUsersService(UsersServiceDeps{Events: events})
+
+	created, _ := service.Create(context.Background(), domain.User{Email: "vieja@example.com", Password: "password123", FirstName: "Ana", LastName: "Paz"})
+	oldToken := tokenFromLink(t, events.events[0])
+	if _, err := service.VerifyEmail(context.Background(), oldToken); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	updated, err := service.Update(context.Background(), domain.TokenClaims{UserID: created.ID}, strconv.Itoa(created.ID), domain.User{Email: "nueva@example.com", FirstName: "Ana", LastName: "Paz"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated.IsEmailVerified() {
+		t.Error("Expected the new email to be unverified")
+	}
+	if len(events.events) != 2 || events.events[1].User.Email != "nueva@example.com" {
+		t.Fatalf("Expected a verification event for the new email, got %+v", events.events)
+	}
+
+	if _, err := service.VerifyEmail(context.Background(), oldToken); !errors.Is(err, ErrInvalidVerificationToken) {
+		t.Errorf("Expected the old link not to verify the new email, got %v", err)
+	}
+	if _, err := service.VerifyEmail(context.Background(), tokenFromLink(t, events.events[1])); err != nil {
+		t.Errorf("Expected the new link to verify, got %v", err)
+	}
+}
+
+// TestResendVerification_RateLimited verifica que el reenvío se corta por email y por IP
+func TestResendVerification_RateLimited(t *testing.T) {
+	events := NewMockUserEventsPublisher()
+	service := newTestUsersService(UsersServiceDeps{Events: events})
+	ctx := context.Background()
+
+	if _, err := service.Create(ctx, domain.User{Email: "ana@example.com", Password: "password123", FirstName: "Ana", LastName: "Paz"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i := 0; i < maxVerificationResendsPerEmail; i++ {
+		if err := service.ResendVerification(ctx, "ana@example.com", "10.0.0.1"); err != nil {
+			t.Fatalf("Expected resend %d to be allowed, got %v", i+1, err)
+		}
+	}
+	err := service.ResendVerification(ctx, " ANA@example.com", "10.0.0.2")
+	var locked *LoginLockedError
+	if !errors.As(err, &locked) || !errors.Is(err, ErrTooManyVerificationRequests) || locked.RetryAfter <= 0 {
+		t.Fatalf("Expected ErrTooManyVerificationRequests with a retry time, got %v", err)
+	}
+	if len(events.events) != 1+maxVerificationResendsPerEmail {
+		t.Errorf("Expected %d events, got %d", 1+maxVerificationResendsPerEmail, len(events.events))
+	}
+
+	// Desde una IP se corta aunque cada pedido sea para un email distinto (existan o no)
+	for i := 0; i < maxVerificationResendsPerIP; i++ {
+		if err := service.ResendVerification(ctx, "nadie"+strconv.Itoa(i)+"@example.com", "10.0.0.3"); err != nil {
+			t.Fatalf("Expected resend %d to be allowed, got %v", i+1, err)
+		}
+	}
+	if err := service.ResendVerification(ctx, "otra@example.com", "10.0.0.3"); !errors.Is(err, ErrTooManyVerificationRequests) {
+		t.Errorf("Expected ErrTooManyVerificationRequests for the IP, got %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"users-api/internal/domain"
+)
+
+// LoginAttemptsStore guarda los logins fallidos por cuenta y por IP
+// RegisterFailure tiene que ser atómico para que varias réplicas cuenten sobre el mismo contador
+type LoginAttemptsStore interface {
+	Get(ctx context.Context, key string) (domain.LoginAttempts, error)
+	RegisterFailure(ctx context.Context, key string, now time.Time, window time.Duration) (domain.LoginAttempts, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+}
+
+// LoginThrottle define cuántos fallos se toleran y cuánto se frena a quien sigue probando
+// Con un máximo en 0 no se bloquea por ese criterio
+type LoginThrottle struct {
+	MaxAccountFailures int           // Fallos de una cuenta dentro de Window antes de bloquearla
+	MaxIPFailures      int           // Fallos desde una IP (en cualquier cuenta) antes de bloquear la IP
+	Window             time.Duration // Ventana en la que se cuentan los fallos
+	Lockout            time.Duration // Duración del bloqueo
+	BaseDelay          time.Duration // Demora después del primer fallo; se duplica con cada fallo siguiente
+	MaxDelay           time.Duration // Tope de la demora
+}
+
+var (
+	ErrAccountLocked        = errors.New("account temporarily locked after too many failed login attempts")
+	ErrTooManyLoginAttempts = errors.New("too many failed login attempts from this address")
+)
+
+// LoginLockedError es el error de un login (o de un reenvío de verificación) rechazado por bloqueo
+// Envuelve ErrAccountLocked, ErrTooManyLoginAttempts o ErrTooManyVerificationRequests y dice cuándo se puede volver a intentar
+type LoginLockedError struct {
+	Reason     error
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string { return e.Reason.Error() }
+
+func (e *LoginLockedError) Unwrap() error { return e.Reason }
+
+// loginKeys arma las claves de los contadores del login: la cuenta (exista o no) y la IP del cliente
+func loginKeys(loginReq domain.LoginRequest) (string, string) {
+	account := "account:" + strings.ToLower(strings.TrimSpace(loginReq.Email))
+	ip := ""
+	if loginReq.ClientIP != "" {
+		ip = "ip:" + loginReq.ClientIP
+	}
+	return account, ip
+}
+
+// checkLoginAllowed rechaza el login si la cuenta o la IP están bloqueadas y si no
+// devuelve la demora que le toca según los fallos recientes de la cuenta
+// Si el store no responde el login sigue sin freno: es preferible a dejar a todos afuera
+func (s *UsersServiceImpl) checkLoginAllowed(ctx context.Context, accountKey, ipKey string, now time.Time) (time.Duration, error) {
+	account, err := s.loginAttempts.Get(ctx, accountKey)
+	if err != nil {
+		log.Printf("Error reading login attempts: %v", err)
+		return 0, nil
+	}
+	if account.LockedAt(now) {
+		return 0, &LoginLockedError{Reason: ErrAccountLocked, RetryAfter: account.LockedUntil.Sub(now)}
+	}
+
+	if ipKey != "" {
+		ip, err := s.loginAttempts.Get(ctx, ipKey)
+		if err != nil {
+			log.Printf("Error reading login attempts: %v", err)
+		} else if ip.LockedAt(now) {
+			return 0, &LoginLockedError{Reason: ErrTooManyLoginAttempts, RetryAfter: ip.LockedUntil.Sub(now)}
+		}
+	}
+
+	// Una ventana vencida ya no cuenta
+	if !account.FirstFailureAt.After(now.Add(-s.throttle.Window)) {
+		return 0, nil
+	}
+	return s.throttle.delay(account.Failures), nil
+}
+
+// loginFailed suma el fallo a la IP y a la cuenta y las bloquea si llegaron al máximo
+// La IP se cuenta siempre, también en el fallo que bloquea la cuenta: si no, rotando de cuenta
+// cada fallo que bloquea una quedaría afuera del contador de la IP
+// Devuelve el error que ve el cliente: credenciales inválidas o el bloqueo recién aplicado
+func (s *UsersServiceImpl) loginFailed(ctx context.Context, accountKey, ipKey string, now time.Time) error {
+	ipLocked := false
+	if ipKey != "" {
+		ipLocked = s.registerLoginFailure(ctx, ipKey, s.throttle.MaxIPFailures, now)
+	}
+	accountLocked := s.registerLoginFailure(ctx, accountKey, s.throttle.MaxAccountFailures, now)
+
+	switch {
+	case accountLocked:
+		log.Printf("🔒 Login locked for %s until %s", accountKey, now.Add(s.throttle.Lockout).Format(time.RFC3339))
+		return &LoginLockedError{Reason: ErrAccountLocked, RetryAfter: s.throttle.Lockout}
+	case ipLocked:
+		log.Printf("🔒 Login locked for %s until %s", ipKey, now.Add(s.throttle.Lockout).Format(time.RFC3339))
+		return &LoginLockedError{Reason: ErrTooManyLoginAttempts, RetryAfter: s.throttle.Lockout}
+	}
+	return ErrInvalidCredentials
+}
+
+// registerLoginFailure suma un fallo a la clave y la bloquea si llegó a maxFailures
+func (s *UsersServiceImpl) registerLoginFailure(ctx context.Context, key string, maxFailures int, now time.Time) bool {
+	attempts, err := s.loginAttempts.RegisterFailure(ctx, key, now, s.throttle.Window)
+	if err != nil {
+		log.Printf("Error registering failed login: %v", err)
+		return false
+	}
+	if maxFailures <= 0 || attempts.Failures < maxFailures {
+		return false
+	}
+	if err := s.loginAttempts.Lock(ctx, key, now.Add(s.throttle.Lockout)); err != nil {
+		log.Printf("Error locking %s: %v", key, err)
+		return false
+	}
+	return true
+}
+
+// loginSucceeded borra los fallos de la cuenta
+// Los de la IP quedan: con una cuenta propia no se puede limpiar el contador de quien prueba contraseñas ajenas
+func (s *UsersServiceImpl) loginSucceeded(ctx context.Context, accountKey string) {
+	if err := s.loginAttempts.Reset(ctx, accountKey); err != nil {
+		log.Printf("Error resetting login attempts: %v", err)
+	}
+}
+
+// delay es BaseDelay después del primer fallo y se duplica con cada uno hasta MaxDelay
+func (t LoginThrottle) delay(failures int) time.Duration {
+	if failures <= 0 || t.BaseDelay <= 0 {
+		return 0
+	}
+	d := t.BaseDelay
+	for i := 1; i < failures && d < t.MaxDelay; i++ {
+		d *= 2
+	}
+	if t.MaxDelay > 0 && d > t.MaxDelay {
+		d = t.MaxDelay
+	}
+	return d
+}
+
+// waitFor espera d o hasta que se cancele el request
+func waitFor(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"users-api/internal/domain"
+	"users-api/internal/repository"
+
+	"github.com/h2non/gock"
+)
+
+// testLoginThrottle bloquea rápido y sin demoras para que los tests no esperen
+var testLoginThrottle = LoginThrottle{
+	MaxAccountFailures: 3,
+	MaxIPFailures:      5,
+	Window:             15 * time.Minute,
+	Lockout:            15 * time.Minute,
+}
+
+// newTestLoginAttempts usa el store en memoria (el mismo que en una sola réplica)
+func newTestLoginAttempts() LoginAttemptsStore {
+	return repository.NewInMemoryLoginAttemptsRepository()
+}
+
+// TestLogin_LocksAccountAfterMaxFailures verifica que la cuenta se bloquea y ni la contraseña correcta entra
+func TestLogin_LocksAccountAfterMaxFailures(t *testing.T) {
+	service := newTestUsersService(UsersServiceDeps{})
+	service.Create(context.Background(), domain.User{Email: "ana@example.com", Password: "password123", FirstName: "Ana", LastName: "Paz"})
+	wrong := domain.LoginRequest{Email: "ana@example.com", Password: "incorrecta", ClientIP: "10.0.0.1"}
+
+	for i := 1; i < testLoginThrottle.MaxAccountFailures; i++ {
+		if _, err := service.Login(context.Background(), wrong); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("Attempt %d: expected ErrInvalidCredentials, got %v", i, err)
+		}
+	}
+
+	// El fallo que llega al máximo ya informa el bloqueo
+	_, err := service.Login(context.Background(), wrong)
+	var locked *LoginLockedError
+	if !errors.As(err, &locked) || !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("Expected the account to be locked, got %v", err)
+	}
+	if locked.RetryAfter != testLoginThrottle.Lockout {
+		t.Errorf("Expected retry after %s, got %s", testLoginThrottle.Lockout, locked.RetryAfter)
+	}
+	if errors.Is(err, ErrInvalidCredentials) {
+		t.Error("Expected the lock error to be distinct from ErrInvalidCredentials")
+	}
+
+	// Bloqueada: ni con la contraseña correcta (ni en mayúsculas, ni desde otra IP)
+	_, err = service.Login(context.Background(), domain.LoginRequest{Email: "ANA@example.com", Password: "password123", ClientIP: "10.0.0.2"})
+	if !errors.Is(err, ErrAccountLocked) {
+		t.Errorf("Expected ErrAccountLocked with the right password, got %v", err)
+	}
+}
+
+// TestLogin_PasswordResetUnlocksAccount verifica que recuperar la contraseña levanta el bloqueo de la cuenta
+func TestLogin_PasswordResetUnlocksAccount(t *testing.T) {
+	defer gock.Off()
+	withoutResetResponseTime(t)
+	events := NewMockUserEventsPublisher()
+	service := newTestUsersService(UsersServiceDeps{Events: events})
+	service.Create(context.Background(), domain.User{Email: "ana@example.com", Password: "password123", FirstName: "Ana", LastName: "Paz"})
+	for i := 0; i < testLoginThrottle.MaxAccountFailures; i++ {
+		service.Login(context.Background(), domain.LoginRequest{Email: "ana@example.com", Password: "incorrecta"})
+	}
+
+	service.RequestPasswordReset(context.Background(), "ana@example.com")
+	if err := service.ConfirmPasswordReset(context.Background(), resetTokenFromEvent(t, events), "nueva-clave-456"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	gock.New(testProductsAPIURL).
+		Get("/cart/1").
+		Reply(200).
+		JSON(map[string]interface{}{"items": []interface{}{}, "item_count": 0})
+	if _, err := service.Login(context.Background(), domain.LoginRequest{Email: "ana@example.com", Password: "nueva-clave-456"}); err != nil {
+		t.Errorf("Expected the account to be unlocked after the reset, got %v", err)
+	}
+}
+
+// TestLogin_SuccessResetsAccountFailures verifica que un login correcto vuelve a contar desde cero
+func TestLogin_SuccessResetsAccountFailures(t *testing.T) {
+	defer gock.Off()
+	service := newTestUsersService(UsersServiceDeps{})
+	created, _ := service.Create(context.Background(), domain.User{Email: "ana@example.com", Password: "password123", FirstName: "Ana", LastName: "Paz"})
+	gock.New(testProductsAPIURL).
+		Get("/cart/1").
+		Reply(200).
+		JSON(map[string]interface{}{"items": []interface{}{}, "item_count": 0})
+
+	wrong := domain.LoginRequest{Email: created.Email, Password: "incorrecta"}
+	for i := 1; i < testLoginThrottle.MaxAccountFailures; i++ {
+		service.Login(context.Background(), wrong)
+	}
+	if _, err := service.Login(context.Background(), domain.LoginRequest{Email: created.Email, Password: "password123"}); err != nil {
+		t.Fatalf("Expected login to succeed before the lock, got %v", err)
+	}
+
+	for i := 1; i < testLoginThrottle.MaxAccountFailures; i++ {
+		if _, err := service.Login(context.Background(), wrong); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("Attempt %d after success: expected ErrInvalidCredentials, got %v", i, err)
+		}
+	}
+}
+
+// TestLogin_LocksClientIPAcrossAccounts verifica que probar muchas cuentas desde una IP bloquea esa IP y no las cuentas
+func TestLogin_LocksClientIPAcrossAccounts(t *testing.T) {
+	defer gock.Off()
+	service := newTestUsersService(UsersServiceDeps{})
+	service.Create(context.Background(), domain.User{Email: "ana@example.com", Password: "password123", FirstName: "Ana", LastName: "Paz"})
+
+	emails := []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com"}
+	var err error
+	for _, email := range emails {
+		_, err = service.Login(context.Background(), domain.LoginRequest{Email: email, Password: "incorrecta", ClientIP: "10.0.0.1"})
+	}
+	if !errors.Is(err, ErrTooManyLoginAttempts) {
+		t.Fatalf("Expected the IP to be locked, got %v", err)
+	}
+
+	if _, err := service.Login(context.Background(), domain.LoginRequest{Email: "ana@example.com", Password: "password123", ClientIP: "10.0.0.1"}); !errors.Is(err, ErrTooManyLoginAttempts) {
+		t.Errorf("Expected ErrTooManyLoginAttempts from the locked IP, got %v", err)
+	}
+
+	// Desde otra IP la cuenta entra
+	gock.New(testProductsAPIURL).
+		Get("/cart/1").
+		Reply(200).
+		JSON(map[string]interface{}{"items": []interface{}{}, "item_count": 0})
+	if _, err := service.Login(context.Background(), domain.LoginRequest{Email: "ana@example.com", Password: "password123", ClientIP: "10.0.0.2"}); err != nil {
+		t.Errorf("Expected login from another IP to succeed, got %v", err)
+	}
+}
+
+// TestLoginThrottle_Delay verifica que la demora se duplica con cada fallo hasta el tope
+func TestLoginThrottle_Delay(t *testing.T) {
+	throttle := LoginThrottle{BaseDelay: 250 * time.Millisecond, MaxDelay: time.Second}
+	expected := map[int]time.Duration{
+		0:  0,
+		1:  250 * time.Millisecond,
+		2:  500 * time.Millisecond,
+		3:  time.Second,
+		4:  time.Second,
+		50: time.Second,
+	}
+	for failures, want := range expected {
+		if got := throttle.delay(failures); got != want {
+			t.Errorf("delay(%d): expected %s, got %s", failures, want, got)
+		}
+	}
+}
+
+// TestLogin_AccountLockStillCountsAgainstIP verifica que el fallo que bloquea una cuenta también suma a la IP
+func TestLogin_AccountLockStillCountsAgainstIP(t *testing.T) {
+	throttle := testLoginThrottle
+	throttle.MaxAccountFailures = 1
+	throttle.MaxIPFailures = 3
+	service := newTestUsersService(UsersServiceDeps{Throttle: throttle})
+
+	// Cada intento bloquea una cuenta distinta; el tercero tiene que bloquear también la IP
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		if _, err := service.Login(context.Background(), domain.LoginRequest{Email: email, Password: "incorrecta", ClientIP: "10.0.0.1"}); !errors.Is(err, ErrAccountLocked) {
+			t.Fatalf("Expected %s to be locked, got %v", email, err)
+		}
+	}
+
+	_, err := service.Login(context.Background(), domain.LoginRequest{Email: "d@example.com", Password: "incorrecta", ClientIP: "10.0.0.1"})
+	if !errors.Is(err, ErrTooManyLoginAttempts) {
+		t.Errorf("Expected the IP to be locked, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"users-api/internal/domain"
+)
+
+// PasswordResetsRepository define las operaciones de datos de los links de recuperación de contraseña
+// Consume tiene que ser atómico: marca el link usado, cambia la contraseña y revoca todos los refresh tokens
+// del usuario juntos, una sola vez por link
+type PasswordResetsRepository interface {
+	Create(ctx context.Context, reset domain.PasswordReset) (domain.PasswordReset, error)
+	GetByHash(ctx context.Context, hash string) (domain.PasswordReset, error)
+	Consume(ctx context.Context, reset domain.PasswordReset, passwordHash string, at time.Time) (bool, error)
+}
+
+// passwordResetTTL es cuánto vale el link de recuperación
+const passwordResetTTL = 30 * time.Minute
+
+// passwordResetResponseTime es lo mínimo que tarda en responder el pedido del link
+// Sin el piso un email desconocido respondería antes (no se guarda ni se publica nada) y se sabría qué cuentas existen
+var passwordResetResponseTime = time.Second
+
+var (
+	ErrInvalidResetToken = errors.New("invalid, expired or already used password reset link")
+)
+
+// RequestPasswordReset manda el email con el link para elegir otra contraseña
+// No dice si el email existe: siempre responde igual y tarda lo mismo (los errores solo se loguean)
+func (s *UsersServiceImpl) RequestPasswordReset(ctx context.Context, email string) {
+	deadline := time.Now().Add(passwordResetResponseTime)
+	defer func() { _ = waitFor(ctx, time.Until(deadline)) }()
+
+	userModel, err := s.repository.GetByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			log.Printf("Error looking up user for password reset: %v", err)
+		}
+		return
+	}
+	user := userModel.ToResponse()
+
+	// El token es aleatorio y de un solo uso: se guarda su hash para poder marcarlo usado
+	token, err := randomToken(32)
+	if err != nil {
+		log.Printf("Error generating password reset token for user %d: %v", user.ID, err)
+		return
+	}
+	expiresAt := time.Now().UTC().Add(passwordResetTTL)
+	if _, err := s.passwordResets.Create(ctx, domain.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: hashRefreshToken(token),
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		log.Printf("Error saving password reset for user %d: %v", user.ID, err)
+		return
+	}
+
+	if err := s.publishUserEvent(ctx, domain.EventPasswordReset, user, actionLink(s.resetURL, token), expiresAt); err != nil {
+		log.Printf("Error publishing %s for user %d: %v", domain.EventPasswordReset, user.ID, err)
+		return
+	}
+	log.Printf("📧 Password reset requested for user %d", user.ID)
+}
+
+// ConfirmPasswordReset cambia la contraseña con el token del link
+// El link se usa en el mismo paso en que cambia la contraseña y se cierran todas las sesiones: si algo falla, sigue sirviendo
+// Después se levanta el bloqueo de la cuenta por logins fallidos
+func (s *UsersServiceImpl) ConfirmPasswordReset(ctx context.Context, token, password string) error {
+	if err := validatePassword(password); err != nil {
+		return err
+	}
+
+	stored, err := s.passwordResets.GetByHash(ctx, hashRefreshToken(token))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	now := time.Now().UTC()
+	if stored.UsedAt != nil || !now.Before(stored.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	user, err := s.repository.GetByID(ctx, stored.UserID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	hash, err := s.passwords.Hash(password)
+	if err != nil {
+		return fmt.Errorf("error hashing password: %w", err)
+	}
+	// Si otro request usó el link entre la lectura y ahora, este ya no vale
+	consumed, err := s.passwordResets.Consume(ctx, stored, hash, now)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return ErrInvalidResetToken
+		}
+		return fmt.Errorf("error updating password: %w", err)
+	}
+	if !consumed {
+		return ErrInvalidResetToken
+	}
+
+	// Quien recibió el link es el dueño: si la cuenta estaba bloqueada por logins fallidos ya puede entrar
+	accountKey, _ := loginKeys(domain.LoginRequest{Email: user.Email})
+	s.loginSucceeded(ctx, accountKey)
+
+	log.Printf("🔐 Password reset for user %d, all sessions closed", stored.UserID)
+	return nil
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"users-api/internal/domain"
+
+	"github.com/h2non/gock"
+)
+
+// testPasswordResetURL es el link de recuperación de contraseña en los tests
+const testPasswordResetURL = "http://localhost:3000/restablecer-password"
+
+// MockPasswordResetsRepository simula la tabla de links de recuperación
+// Como la transacción de MySQL, Consume cambia la contraseña en el repositorio de usuarios y revoca los refresh tokens
+type MockPasswordResetsRepository struct {
+	resets        map[int]domain.PasswordReset
+	nextID        int
+	users         UsersRepository
+	refreshTokens RefreshTokensRepository
+	failUpdate    bool // Para simular que falla el cambio de contraseña
+}
+
+func NewMockPasswordResetsRepository(users UsersRepository, refreshTokens RefreshTokensRepository) *MockPasswordResetsRepository {
+	return &MockPasswordResetsRepository{
+		resets:        make(map[int]domain.PasswordReset),
+		nextID:        1,
+		users:         users,
+		refreshTokens: refreshTokens,
+	}
+}
+
+func (m *MockPasswordResetsRepository) Create(ctx context.Context, reset domain.PasswordReset) (domain.PasswordReset, error) {
+	reset.ID = m.nextID
+	m.nextID++
+	m.resets[reset.ID] = reset
+	return reset, nil
+}
+
+func (m *MockPasswordResetsRepository) GetByHash(ctx context.Context, hash string) (domain.PasswordReset, error) {
+	for _, reset := range m.resets {
+		if reset.TokenHash == hash {
+			return reset, nil
+		}
+	}
+	return domain.PasswordReset{}, errors.New("password reset not found")
+}
+
+func (m *MockPasswordResetsRepository) Consume(ctx context.Context, reset domain.PasswordReset, passwordHash string, at time.Time) (bool, error) {
+	stored := m.resets[reset.ID]
+	if stored.UsedAt != nil || !at.Before(stored.ExpiresAt) {
+		return false, nil
+	}
+	// Si falla el cambio de contraseña la transacción se deshace: el link sigue sin usar
+	if m.failUpdate {
+		return false, errors.New("database error")
+	}
+	if err := m.users.UpdatePasswordHash(ctx, reset.UserID, passwordHash); err != nil {
+		return false, err
+	}
+	if err := m.refreshTokens.RevokeAllForUser(ctx, reset.UserID, at); err != nil {
+		return false, err
+	}
+	for id, r := range m.resets {
+		if r.UserID == reset.UserID && r.UsedAt == nil {
+			r.UsedAt = &at
+			m.resets[id] = r
+		}
+	}
+	return true, nil
+}
+
+// withoutResetResponseTime saca el piso de tiempo del pedido del link para que los tests no esperen
+func withoutResetResponseTime(t *testing.T) {
+	previous := passwordResetResponseTime
+	passwordResetResponseTime = 0
+	t.Cleanup(func() { passwordResetResponseTime = previous })
+}
+
+// resetTokenFromEvent saca el token del link de recuperación del último evento publicado
+func resetTokenFromEvent(t *testing.T, events *MockUserEventsPublisher) string {
+	t.Helper()
+	event := events.events[len(events.events)-1]
+	if event.Type != domain.EventPasswordReset || !strings.HasPrefix(event.ActionURL, testPasswordResetURL+"?token=") {
+		t.Fatalf("Expected a password reset event, got %+v", event)
+	}
+	link, _ := url.Parse(event.ActionURL)
+	return link.Query().Get("token")
+}
+
+// TestPasswordReset_Flow verifica que el link cambia la contraseña una sola vez y cierra las sesiones abiertas
+func TestPasswordReset_Flow(t *testing.T) {
+	defer gock.Off()
+	withoutResetResponseTime(t)
+	events := NewMockUserEventsPublisher()
+	service := newTestUsersService(UsersServiceDeps{Events: events})
+	login := loginForSessionTest(t, service, "olvido@example.com")
+
+	service.RequestPasswordReset(context.Background(), "olvido@example.com")
+	token := resetTokenFromEvent(t, events)
+	if expiresAt := events.events[len(events.events)-1].ExpiresAt; expiresAt == nil || time.Until(*expiresAt) > passwordResetTTL {
+		t.Errorf("Expected the link to expire within %s, got %v", passwordResetTTL, expiresAt)
+	}
+
+	if err := service.ConfirmPasswordReset(context.Background(), token, "nueva-clave-456"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// El link es de un solo uso
+	if err := service.ConfirmPasswordReset(context.Background(), token, "otra-clave-789"); !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("Expected ErrInvalidResetToken on reuse, got %v", err)
+	}
+
+	// La sesión abierta con la contraseña vieja quedó revocada
+	if _, err := service.Refresh(context.Background(), login.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Expected the old session to be revoked, got %v", err)
+	}
+
+	if _, err := service.Login(context.Background(), domain.LoginRequest{Email: "olvido@example.com", Password: "password123"}); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected the old password to stop working, got %v", err)
+	}
+	gock.New(testProductsAPIURL).
+		Get("/cart/1").
+		Reply(200).
+		JSON(map[string]interface{}{"items": []interface{}{}, "item_count": 0})
+	if _, err := service.Login(context.Background(), domain.LoginRequest{Email: "olvido@example.com", Password: "nueva-clave-456"}); err != nil {
+		t.Errorf("Expected login with the new password, got %v", err)
+	}
+}
+
+// TestPasswordReset_UnknownEmail verifica que un email desconocido no publica nada y tarda lo mismo que uno registrado
+func TestPasswordReset_UnknownEmail(t *testing.T) {
+	previous := passwordResetResponseTime
+	passwordResetResponseTime = 50 * time.Millisecond
+	defer func() { passwordResetResponseTime = previous }()
+
+	events := NewMockUserEventsPublisher()
+	service := newTestUsersService(UsersServiceDeps{Events: events})
+	service.Create(context.Background(), domain.User{Email: "ana@example.com", Password: "password123", FirstName: "Ana", LastName: "Paz"})
+	events.events = nil
+
+	for _, email := range []string{"nadie@example.com", "ana@example.com"} {
+		start := time.Now()
+		service.RequestPasswordReset(context.Background(), email)
+		if elapsed := time.Since(start); elapsed < passwordResetResponseTime {
+			t.Errorf("Expected %s to take at least %s, took %s", email, passwordResetResponseTime, elapsed)
+		}
+	}
+	if len(events.events) != 1 || events.events[0].User.Email != "ana@example.com" {
+		t.Errorf("Expected only the registered email to get a link, got %+v", events.events)
+	}
+}
+
+// TestPasswordReset_FailureKeepsLinkUsable verifica que si falla el cambio de contraseña el link no se gasta
+// y que la contraseña nueva cumple las mismas reglas que en el registro
+func TestPasswordReset_FailureKeepsLinkUsable(t *testing.T) {
+	withoutResetResponseTime(t)
+	events := NewMockUserEventsPublisher()
+	repo := NewMockUsersRepository()
+	tokens := NewMockRefreshTokensRepository()
+	resets := NewMockPasswordResetsRepository(repo, tokens)
+	service := newTestUsersService(UsersServiceDeps{Repository: repo, RefreshTokens: tokens, Events: events, PasswordResets: resets})
+	created, _ := service.Create(context.Background(), domain.User{Email: "ana@example.com", Password: "password123", FirstName: "Ana", LastName: "Paz"})
+
+	service.RequestPasswordReset(context.Background(), created.Email)
+	token := resetTokenFromEvent(t, events)
+
+	if err := service.ConfirmPasswordReset(context.Background(), token, "corta"); !errors.Is(err, ErrPasswordTooShort) {
+		t.Errorf("Expected ErrPasswordTooShort, got %v", err)
+	}
+
+	resets.failUpdate = true
+	if err := service.ConfirmPasswordReset(context.Background(), token, "nueva-clave-456"); err == nil || errors.Is(err, ErrInvalidResetToken) {
+		t.Fatalf("Expected a database error, got %v", err)
+	}
+
+	resets.failUpdate = false
+	if err := service.ConfirmPasswordReset(context.Background(), token, "nueva-clave-456"); err != nil {
+		t.Errorf("Expected the link to still work after the failure, got %v", err)
+	}
+}
+
+// TestPasswordReset_InvalidLinks verifica que se rechazan los links vencidos, desconocidos y los anteriores a un reset ya usado
+func TestPasswordReset_InvalidLinks(t *testing.T) {
+	withoutResetResponseTime(t)
+	events := NewMockUserEventsPublisher()
+	repo := NewMockUsersRepository()
+	tokens := NewMockRefreshTokensRepository()
+	resets := NewMockPasswordResetsRepository(repo, tokens)
+	service := newTestUsersService(UsersServiceDeps{Repository: repo, RefreshTokens: tokens, Events: events, PasswordResets: resets})
+	created, _ := service.Create(context.Background(), domain.User{Email: "ana@example.com", Password: "password123", FirstName: "Ana", LastName: "Paz"})
+
+	if err := service.ConfirmPasswordReset(context.Background(), "not-a-token", "nueva-clave-456"); !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("Expected ErrInvalidResetToken, got %v", err)
+	}
+
+	service.RequestPasswordReset(context.Background(), created.Email)
+	first := resetTokenFromEvent(t, events)
+	service.RequestPasswordReset(context.Background(), created.Email)
+	second := resetTokenFromEvent(t, events)
+
+	if err := service.ConfirmPasswordReset(context.Background(), second, " "); !errors.Is(err, ErrPasswordRequired) {
+		t.Errorf("Expected ErrPasswordRequired, got %v", err)
+	}
+	if err := service.ConfirmPasswordReset(context.Background(), second, "nueva-clave-456"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// Usar un link invalida los demás que estaban pendientes
+	if err := service.ConfirmPasswordReset(context.Background(), first, "otra-clave-789"); !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("Expected the older link to be invalidated, got %v", err)
+	}
+
+	// Un link vencido no sirve
+	service.RequestPasswordReset(context.Background(), created.Email)
+	expired := resetTokenFromEvent(t, events)
+	stored, _ := resets.GetByHash(context.Background(), hashRefreshToken(expired))
+	stored.ExpiresAt = time.Now().UTC().Add(-time.Minute)
+	resets.resets[stored.ID] = stored
+	if err := service.ConfirmPasswordReset(context.Background(), expired, "otra-clave-789"); !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("Expected ErrInvalidResetToken for an expired link, got %v", err)
+	}
+}
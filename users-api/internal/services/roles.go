@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"users-api/internal/domain"
+)
+
+var (
+	ErrInvalidRole         = errors.New("invalid role")
+	ErrRoleAssignForbidden = errors.New("not allowed to assign roles")
+	ErrCannotChangeOwnRole = errors.New("you cannot change your own role")
+)
+
+// ListRoles devuelve los roles disponibles con sus permisos
+func (s *UsersServiceImpl) ListRoles() []domain.RoleInfo {
+	return domain.Roles()
+}
+
+// AssignRole cambia el rol de un usuario (solo quien tiene roles:assign)
+// El token del usuario mantiene el rol anterior hasta que vence; el nuevo se aplica en el próximo refresh o login
+func (s *UsersServiceImpl) AssignRole(ctx context.Context, caller domain.TokenClaims, userID int, role string) (domain.UserResponse, error) {
+	if !caller.HasPermission(domain.PermRolesAssign) {
+		return domain.UserResponse{}, ErrRoleAssignForbidden
+	}
+	if !domain.IsValidRole(role) {
+		return domain.UserResponse{}, ErrInvalidRole
+	}
+	// Así un super admin no se quita el acceso por error
+	if caller.UserID == userID {
+		return domain.UserResponse{}, ErrCannotChangeOwnRole
+	}
+
+	user, err := s.repository.UpdateRole(ctx, userID, role, role == domain.RoleSuperAdmin)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return domain.UserResponse{}, ErrUserNotFound
+		}
+		return domain.UserResponse{}, err
+	}
+	log.Printf("🛡️ User %d assigned role %s to user %d", caller.UserID, role, userID)
+	return user, nil
+}
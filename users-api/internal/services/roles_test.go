@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"users-api/internal/domain"
+
+	"github.com/h2non/gock"
+)
+
+// TestAssignRole_PermissionsFollowTheRole verifica que el rol asignado llega al token en el próximo refresh
+func TestAssignRole_PermissionsFollowTheRole(t *testing.T) {
+	defer gock.Off()
+	service := newTestUsersService(UsersServiceDeps{})
+	login := loginForSessionTest(t, service, "catalog@example.com")
+
+	if login.Role != domain.RoleCustomer || len(login.Permissions) != 0 {
+		t.Fatalf("Expected a customer without permissions, got role=%q permissions=%v", login.Role, login.Permissions)
+	}
+
+	superAdmin := domain.TokenClaims{UserID: 999, IsAdmin: true, Role: domain.RoleSuperAdmin}
+	user, err := service.AssignRole(context.Background(), superAdmin, login.CustomerID, domain.RoleCatalogManager)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.Role != domain.RoleCatalogManager || user.IsAdmin {
+		t.Errorf("Expected a catalog manager without admin, got %+v", user)
+	}
+
+	pair, err := service.Refresh(context.Background(), login.RefreshToken)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	claims, err := service.VerifyToken(pair.Token)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if claims.Role != domain.RoleCatalogManager || !claims.HasPermission(domain.PermItemsWrite) || claims.HasPermission(domain.PermSalesRead) {
+		t.Errorf("Expected catalog manager permissions in the token, got %+v", claims)
+	}
+	if _, err := service.VerifyAdminToken(pair.Token); err == nil {
+		t.Error("Expected a catalog manager not to pass the admin check")
+	}
+}
+
+// TestAssignRole_Errors verifica quién puede asignar roles y qué roles existen
+func TestAssignRole_Errors(t *testing.T) {
+	repo := NewMockUsersRepository()
+	service := newTestUsersService(UsersServiceDeps{Repository: repo})
+	created, _ := service.Create(context.Background(), domain.User{Email: "support@example.com", Password: "password123", FirstName: "Ana", LastName: "Paz"})
+
+	support := domain.TokenClaims{UserID: 50, Role: domain.RoleSupport, Permissions: domain.PermissionsFor(domain.RoleSupport)}
+	if _, err := service.AssignRole(context.Background(), support, created.ID, domain.RoleSuperAdmin); !errors.Is(err, ErrRoleAssignForbidden) {
+		t.Errorf("Expected ErrRoleAssignForbidden, got %v", err)
+	}
+
+	superAdmin := domain.TokenClaims{UserID: 999, IsAdmin: true, Role: domain.RoleSuperAdmin}
+	if _, err := service.AssignRole(context.Background(), superAdmin, created.ID, "owner"); !errors.Is(err, ErrInvalidRole) {
+		t.Errorf("Expected ErrInvalidRole, got %v", err)
+	}
+	if _, err := service.AssignRole(context.Background(), superAdmin, superAdmin.UserID, domain.RoleCustomer); !errors.Is(err, ErrCannotChangeOwnRole) {
+		t.Errorf("Expected ErrCannotChangeOwnRole, got %v", err)
+	}
+	if _, err := service.AssignRole(context.Background(), superAdmin, 12345, domain.RoleSupport); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+
+	// Asignar super admin también marca IsAdmin (compatibilidad con quienes solo miran ese flag)
+	user, err := service.AssignRole(context.Background(), superAdmin, created.ID, domain.RoleSuperAdmin)
+	if err != nil || !user.IsAdmin {
+		t.Errorf("Expected a super admin with IsAdmin, got %+v (%v)", user, err)
+	}
+}
+
+// TestUpdate_CannotChangeRole verifica que PUT /users/:id no sube (ni baja) privilegios: el rol solo cambia con AssignRole
+func TestUpdate_CannotChangeRole(t *testing.T) {
+	service := newTestUsersService(UsersServiceDeps{})
+	created, _ := service.Create(context.Background(), domain.User{Email: "ana@example.com", Password: "password123", FirstName: "Ana", LastName: "Paz"})
+	self := domain.TokenClaims{UserID: created.ID, Role: domain.RoleCustomer}
+
+	updated, err := service.Update(context.Background(), self, strconv.Itoa(created.ID), domain.User{
+		Email: created.Email, FirstName: "Ana", LastName: "Paz", IsAdmin: true, Role: domain.RoleSuperAdmin,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated.IsAdmin || updated.Role != domain.RoleCustomer {
+		t.Errorf("Expected the user to stay a customer, got is_admin=%t role=%s", updated.IsAdmin, updated.Role)
+	}
+	if role := domain.EffectiveRole(updated.Role, updated.IsAdmin); role != domain.RoleCustomer {
+		t.Errorf("Expected effective role customer, got %s", role)
+	}
+
+	// Un super admin que se edita a sí mismo con is_admin=false sigue siendo super admin
+	superAdmin := domain.TokenClaims{UserID: 999, IsAdmin: true, Role: domain.RoleSuperAdmin}
+	service.AssignRole(context.Background(), superAdmin, created.ID, domain.RoleSuperAdmin)
+	updated, err = service.Update(context.Background(), domain.TokenClaims{UserID: created.ID}, strconv.Itoa(created.ID), domain.User{
+		Email: created.Email, FirstName: "Ana", LastName: "Paz", IsAdmin: false,
+	})
+	if err != nil || !updated.IsAdmin || updated.Role != domain.RoleSuperAdmin {
+		t.Errorf("Expected the super admin to keep the role, got %+v (%v)", updated, err)
+	}
+}
+
+// TestUpdate_OnlySelfOrUsersWrite verifica que solo el propio usuario o quien tenga users:write puede editarlo o borrarlo
+func TestUpdate_OnlySelfOrUsersWrite(t *testing.T) {
+	service := newTestUsersService(UsersServiceDeps{})
+	created, _ := service.Create(context.Background(), domain.User{Email: "ana@example.com", Password: "password123", FirstName: "Ana", LastName: "Paz"})
+	other := domain.TokenClaims{UserID: created.ID + 1, Role: domain.RoleCustomer}
+	support := domain.TokenClaims{UserID: 50, Role: domain.RoleSupport, Permissions: domain.PermissionsFor(domain.RoleSupport)}
+	change := domain.User{Email: created.Email, Password: "otra-clave-123", FirstName: "Ana", LastName: "Paz"}
+
+	for _, caller := range []domain.TokenClaims{other, support} {
+		if _, err := service.Update(context.Background(), caller, strconv.Itoa(created.ID), change); !errors.Is(err, ErrForbidden) {
+			t.Errorf("Expected ErrForbidden updating for %+v, got %v", caller, err)
+		}
+		if err := service.Delete(context.Background(), caller, strconv.Itoa(created.ID)); !errors.Is(err, ErrForbidden) {
+			t.Errorf("Expected ErrForbidden deleting for %+v, got %v", caller, err)
+		}
+	}
+
+	superAdmin := domain.TokenClaims{UserID: 999, IsAdmin: true, Role: domain.RoleSuperAdmin}
+	if _, err := service.Update(context.Background(), superAdmin, strconv.Itoa(created.ID), change); err != nil {
+		t.Errorf("Expected users:write to update, got %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"users-api/internal/domain"
+	"users-api/internal/utils"
+)
+
+// RefreshTokensRepository define las operaciones de datos de los refresh tokens
+// MarkUsed tiene que ser atómico: solo un request puede rotar cada token
+type RefreshTokensRepository interface {
+	Create(ctx context.Context, token domain.RefreshToken) (domain.RefreshToken, error)
+	GetByHash(ctx context.Context, hash string) (domain.RefreshToken, error)
+	MarkUsed(ctx context.Context, id int, at time.Time) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string, at time.Time) error
+	RevokeAllForUser(ctx context.Context, userID int, at time.Time) error
+}
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used: all tokens of this session were revoked")
+	ErrForbidden           = errors.New("not allowed to manage this user")
+)
+
+// Refresh cambia un refresh token vigente por un access token nuevo y otro refresh token de la misma sesión
+// Si el token ya se había usado alguien lo copió: se revoca la sesión entera (el dueño tiene que volver a loguearse)
+func (s *UsersServiceImpl) Refresh(ctx context.Context, refreshToken string) (domain.TokenPair, error) {
+	stored, err := s.refreshTokens.GetByHash(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return domain.TokenPair{}, ErrInvalidRefreshToken
+		}
+		return domain.TokenPair{}, err
+	}
+
+	now := time.Now().UTC()
+	if stored.RevokedAt != nil || !now.Before(stored.ExpiresAt) {
+		return domain.TokenPair{}, ErrInvalidRefreshToken
+	}
+	if stored.UsedAt != nil {
+		return domain.TokenPair{}, s.revokeReusedFamily(ctx, stored, now)
+	}
+
+	// Si otro request lo rotó entre la lectura y ahora también es un reuso
+	rotated, err := s.refreshTokens.MarkUsed(ctx, stored.ID, now)
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+	if !rotated {
+		return domain.TokenPair{}, s.revokeReusedFamily(ctx, stored, now)
+	}
+
+	user, err := s.repository.GetByID(ctx, stored.UserID)
+	if err != nil {
+		// El usuario se eliminó: la sesión no sirve más
+		_ = s.refreshTokens.RevokeFamily(ctx, stored.FamilyID, now)
+		return domain.TokenPair{}, ErrInvalidRefreshToken
+	}
+
+	// El usuario se vuelve a leer: los cambios de rol y la verificación del email se aplican en el próximo refresh
+	token, _, err := s.accessToken(user)
+	if err != nil {
+		return domain.TokenPair{}, errors.New("failed to generate token")
+	}
+	next, expiresAt, err := s.issueRefreshToken(ctx, user.ID, stored.FamilyID)
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+
+	return domain.TokenPair{Token: token, RefreshToken: next, RefreshExpiresAt: expiresAt}, nil
+}
+
+// Logout revoca la sesión del refresh token
+// Un token desconocido o ya revocado no es un error: el resultado es el mismo
+// El access token sigue valiendo hasta que vence (son de pocos minutos)
+func (s *UsersServiceImpl) Logout(ctx context.Context, refreshToken string) error {
+	stored, err := s.refreshTokens.GetByHash(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil
+		}
+		return err
+	}
+	if err := s.refreshTokens.RevokeFamily(ctx, stored.FamilyID, time.Now().UTC()); err != nil {
+		return fmt.Errorf("error revoking session: %w", err)
+	}
+	log.Printf("👋 Session closed for user %d", stored.UserID)
+	return nil
+}
+
+// LogoutAll revoca todas las sesiones de un usuario (él mismo o quien puede editar usuarios)
+func (s *UsersServiceImpl) LogoutAll(ctx context.Context, caller domain.TokenClaims, userID int) error {
+	if caller.UserID != userID && !caller.HasPermission(domain.PermUsersWrite) {
+		return ErrForbidden
+	}
+	if err := s.refreshTokens.RevokeAllForUser(ctx, userID, time.Now().UTC()); err != nil {
+		return fmt.Errorf("error revoking sessions: %w", err)
+	}
+	log.Printf("👋 All sessions closed for user %d (by user %d)", userID, caller.UserID)
+	return nil
+}
+
+// accessToken emite el access token del usuario con su rol, sus permisos y si verificó el email
+func (s *UsersServiceImpl) accessToken(user domain.UserResponse) (string, domain.RoleInfo, error) {
+	role := domain.RoleInfo{Role: domain.EffectiveRole(user.Role, user.IsAdmin)}
+	role.Permissions = domain.PermissionsFor(role.Role)
+	token, err := s.tokens.Generate(utils.AccessTokenData{
+		UserID:        user.ID,
+		IsAdmin:       user.IsAdmin,
+		Role:          role.Role,
+		Permissions:   role.Permissions,
+		EmailVerified: user.IsEmailVerified(),
+	})
+	return token, role, err
+}
+
+// startSession abre una sesión nueva (familia de refresh tokens) en el login
+func (s *UsersServiceImpl) startSession(ctx context.Context, userID int) (string, time.Time, error) {
+	familyID, err := randomToken(16)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return s.issueRefreshToken(ctx, userID, familyID)
+}
+
+// issueRefreshToken genera un refresh token de la sesión y guarda solo su hash
+func (s *UsersServiceImpl) issueRefreshToken(ctx context.Context, userID int, familyID string) (string, time.Time, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expiresAt := time.Now().UTC().Add(s.refreshTTL)
+	if _, err := s.refreshTokens.Create(ctx, domain.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashRefreshToken(token),
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return "", time.Time{}, fmt.Errorf("error saving refresh token: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+func (s *UsersServiceImpl) revokeReusedFamily(ctx context.Context, stored domain.RefreshToken, now time.Time) error {
+	log.Printf("⚠️ Refresh token reuse detected for user %d, revoking session %s", stored.UserID, stored.FamilyID)
+	if err := s.refreshTokens.RevokeFamily(ctx, stored.FamilyID, now); err != nil {
+		return fmt.Errorf("error revoking session: %w", err)
+	}
+	return ErrRefreshTokenReused
+}
+
+// hashRefreshToken hashea el token para guardarlo: con 256 bits aleatorios alcanza con SHA-256 (no hace falta sal)
+func hashRefreshToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// randomToken genera n bytes aleatorios en base64 url-safe
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+	"users-api/internal/domain"
+
+	"github.com/h2non/gock"
+)
+
+// testRefreshTTL es la vigencia de los refresh tokens en los tests
+const testRefreshTTL = time.Hour
+
+// MockRefreshTokensRepository simula la tabla de refresh tokens
+type MockRefreshTokensRepository struct {
+	tokens map[int]domain.RefreshToken
+	nextID int
+}
+
+func NewMockRefreshTokensRepository() *MockRefreshTokensRepository {
+	return &MockRefreshTokensRepository{
+		tokens: make(map[int]domain.RefreshToken),
+		nextID: 1,
+	}
+}
+
+func (m *MockRefreshTokensRepository) Create(ctx context.Context, token domain.RefreshToken) (domain.RefreshToken, error) {
+	token.ID = m.nextID
+	m.nextID++
+	m.tokens[token.ID] = token
+	return token, nil
+}
+
+func (m *MockRefreshTokensRepository) GetByHash(ctx context.Context, hash string) (domain.RefreshToken, error) {
+	for _, token := range m.tokens {
+		if token.TokenHash == hash {
+			return token, nil
+		}
+	}
+	return domain.RefreshToken{}, errors.New("refresh token not found")
+}
+
+func (m *MockRefreshTokensRepository) MarkUsed(ctx context.Context, id int, at time.Time) (bool, error) {
+	token := m.tokens[id]
+	if token.UsedAt != nil || token.RevokedAt != nil {
+		return false, nil
+	}
+	token.UsedAt = &at
+	m.tokens[id] = token
+	return true, nil
+}
+
+func (m *MockRefreshTokensRepository) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+	for id, token := range m.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &at
+			m.tokens[id] = token
+		}
+	}
+	return nil
+}
+
+func (m *MockRefreshTokensRepository) RevokeAllForUser(ctx context.Context, userID int, at time.Time) error {
+	for id, token := range m.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &at
+			m.tokens[id] = token
+		}
+	}
+	return nil
+}
+
+// loginForSessionTest crea un usuario y devuelve su login (con el carrito interceptado por gock)
+func loginForSessionTest(t *testing.T, service *UsersServiceImpl, email string) domain.LoginResponse {
+	t.Helper()
+	created, err := service.Create(context.Background(), domain.User{Email: email, Password: "password123", FirstName: "Ana", LastName: "Paz"})
+	if err != nil {
+		t.Fatalf("Expected no error creating user, got %v", err)
+	}
+	gock.New(testProductsAPIURL).
+		Get("/cart/" + strconv.Itoa(created.ID)).
+		Reply(200).
+		JSON(map[string]interface{}{"items": []interface{}{}, "item_count": 0})
+
+	login, err := service.Login(context.Background(), domain.LoginRequest{Email: email, Password: "password123"})
+	if err != nil {
+		t.Fatalf("Expected no error on login, got %v", err)
+	}
+	if login.RefreshToken == "" {
+		t.Fatal("Expected a refresh token on login")
+	}
+	return login
+}
+
+// TestRefresh_RotatesToken verifica que cada refresh devuelve un token nuevo y el anterior deja de servir
+func TestRefresh_RotatesToken(t *testing.T) {
+	defer gock.Off()
+	tokens := NewMockRefreshTokensRepository()
+	service := newTestUsersService(UsersServiceDeps{RefreshTokens: tokens})
+	login := loginForSessionTest(t, service, "refresh@example.com")
+
+	pair, err := service.Refresh(context.Background(), login.RefreshToken)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if pair.Token == "" || pair.RefreshToken == "" || pair.RefreshToken == login.RefreshToken {
+		t.Fatalf("Expected a new access token and a rotated refresh token, got %+v", pair)
+	}
+
+	// Solo se guarda el hash
+	for _, stored := range tokens.tokens {
+		if stored.TokenHash == pair.RefreshToken || stored.TokenHash == login.RefreshToken {
+			t.Error("Expected refresh tokens to be stored hashed")
+		}
+	}
+
+	if _, err := service.Refresh(context.Background(), "unknown-token"); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Expected ErrInvalidRefreshToken for an unknown token, got %v", err)
+	}
+}
+
+// TestRefresh_ReuseRevokesFamily verifica que reusar un token revoca toda la sesión
+func TestRefresh_ReuseRevokesFamily(t *testing.T) {
+	defer gock.Off()
+	service := newTestUsersService(UsersServiceDeps{})
+	login := loginForSessionTest(t, service, "reuse@example.com")
+
+	pair, err := service.Refresh(context.Background(), login.RefreshToken)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Alguien presenta el token viejo: se revoca la familia, incluido el token nuevo
+	if _, err := service.Refresh(context.Background(), login.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("Expected ErrRefreshTokenReused, got %v", err)
+	}
+	if _, err := service.Refresh(context.Background(), pair.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Expected the rotated token to be revoked too, got %v", err)
+	}
+}
+
+// TestLogout verifica el logout de una sesión y el de todas las sesiones
+func TestLogout(t *testing.T) {
+	defer gock.Off()
+	service := newTestUsersService(UsersServiceDeps{})
+	first := loginForSessionTest(t, service, "logout@example.com")
+
+	if err := service.Logout(context.Background(), first.RefreshToken); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := service.Refresh(context.Background(), first.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Expected the logged out token to be invalid, got %v", err)
+	}
+
+	// Otro usuario no puede cerrar sesiones ajenas; un admin sí
+	if err := service.LogoutAll(context.Background(), domain.TokenClaims{UserID: 99}, first.CustomerID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+	if err := service.LogoutAll(context.Background(), domain.TokenClaims{UserID: 99, IsAdmin: true}, first.CustomerID); err != nil {
+		t.Errorf("Expected an admin to log out all sessions, got %v", err)
+	}
+}
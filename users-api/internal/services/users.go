@@ -1,6 +1,7 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -10,6 +11,8 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 	"users-api/internal/domain"
 	"users-api/internal/utils"
 )
@@ -22,11 +25,26 @@ type UsersRepository interface {
 	GetByEmail(ctx context.Context, email string) (domain.User, error)
 	Update(ctx context.Context, id int, user domain.User) (domain.UserResponse, error)
 	Delete(ctx context.Context, id int) error
+	UpdatePasswordHash(ctx context.Context, id int, hash string) error
+	UpdateRole(ctx context.Context, id int, role string, isAdmin bool) (domain.UserResponse, error)
+	SetEmailVerifiedAt(ctx context.Context, id int, at *time.Time) error
 }
 
 // UsersServiceImpl implementa UsersService
 type UsersServiceImpl struct {
-	repository UsersRepository
+	repository     UsersRepository
+	productsAPIURL string
+	passwords      *utils.PasswordHasher
+	tokens         *utils.JWTManager
+	refreshTokens  RefreshTokensRepository
+	refreshTTL     time.Duration
+	events         UserEventsPublisher
+	verifyURL      string
+	verifyTTL      time.Duration
+	passwordResets PasswordResetsRepository
+	resetURL       string
+	loginAttempts  LoginAttemptsStore
+	throttle       LoginThrottle
 }
 
 // Definiciones de errores especificos
@@ -39,12 +57,48 @@ var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrFirstNameRequired  = errors.New("first name is required and cannot be empty")
 	ErrLastNameRequired   = errors.New("last name is required and cannot be empty")
+	ErrPasswordTooShort   = fmt.Errorf("password must be at least %d characters", minPasswordLength)
 )
 
+// minPasswordLength es el largo mínimo de una contraseña (registro, edición y recuperación)
+const minPasswordLength = 8
+
+// UsersServiceDeps son las dependencias y la configuración de UsersServiceImpl
+type UsersServiceDeps struct {
+	Repository     UsersRepository
+	ProductsAPIURL string                // Para traer (y fusionar) el carrito del cliente en el login
+	Passwords      *utils.PasswordHasher // Algoritmo y costo con que se hashean las contraseñas
+	Tokens         *utils.JWTManager     // Firma los access tokens con la clave activa y los verifica con cualquiera de las publicadas
+	RefreshTokens  RefreshTokensRepository
+	RefreshTTL     time.Duration // Cuánto vale un refresh token sin usarse
+	// Events publica user.registered con el link de verificación (VerifyURL + token, que vence en VerifyTTL)
+	// y user.password_reset con el link de recuperación (ResetURL + token de un solo uso guardado en PasswordResets)
+	Events         UserEventsPublisher
+	VerifyURL      string
+	VerifyTTL      time.Duration
+	PasswordResets PasswordResetsRepository
+	ResetURL       string
+	// LoginAttempts cuenta los logins fallidos por cuenta y por IP; Throttle define las demoras y los bloqueos
+	LoginAttempts LoginAttemptsStore
+	Throttle      LoginThrottle
+}
+
 // NewUsersService crea una nueva instancia del service
-func NewUsersService(repository UsersRepository) *UsersServiceImpl {
+func NewUsersService(deps UsersServiceDeps) *UsersServiceImpl {
 	return &UsersServiceImpl{
-		repository: repository,
+		repository:     deps.Repository,
+		productsAPIURL: deps.ProductsAPIURL,
+		passwords:      deps.Passwords,
+		tokens:         deps.Tokens,
+		refreshTokens:  deps.RefreshTokens,
+		refreshTTL:     deps.RefreshTTL,
+		events:         deps.Events,
+		verifyURL:      deps.VerifyURL,
+		verifyTTL:      deps.VerifyTTL,
+		passwordResets: deps.PasswordResets,
+		resetURL:       deps.ResetURL,
+		loginAttempts:  deps.LoginAttempts,
+		throttle:       deps.Throttle,
 	}
 }
 
@@ -67,7 +121,16 @@ func (s *UsersServiceImpl) Create(ctx context.Context, user domain.User) (domain
 	}
 
 	// 2. Hash de la contraseña antes de guardar
-	user.Password = utils.HashSHA256(user.Password)
+	hash, err := s.passwords.Hash(user.Password)
+	if err != nil {
+		return domain.UserResponse{}, err
+	}
+	user.Password = hash
+
+	// Los usuarios nuevos son clientes: los roles se asignan con AssignRole
+	user.Role = domain.RoleCustomer
+	// y empiezan sin verificar el email
+	user.EmailVerifiedAt = nil
 
 	// 3. Intento de Persistencia
 	response, err := s.repository.Create(ctx, user)
@@ -90,7 +153,9 @@ func (s *UsersServiceImpl) Create(ctx context.Context, user domain.User) (domain
 		return domain.UserResponse{}, err
 	}
 
-	// 5. Manejo error
+	// 5. Email de bienvenida con el link de verificación
+	s.sendVerificationEmail(ctx, response)
+
 	return response, nil
 }
 
@@ -120,20 +185,39 @@ func (s *UsersServiceImpl) GetByEmail(ctx context.Context, email string) (domain
 	return s.repository.GetByEmail(ctx, email)
 }
 
-// Update actualiza un usuario existente
-func (s *UsersServiceImpl) Update(ctx context.Context, id string, user domain.User) (domain.UserResponse, error) {
+// Update actualiza un usuario existente (él mismo o quien puede editar usuarios)
+// El rol y is_admin del body se ignoran: solo se cambian con AssignRole
+func (s *UsersServiceImpl) Update(ctx context.Context, caller domain.TokenClaims, id string, user domain.User) (domain.UserResponse, error) {
 	userID, err := strconv.Atoi(id)
 	if err != nil {
 		return domain.UserResponse{}, ErrInvalidUserID //400
 	}
+	if caller.UserID != userID && !caller.HasPermission(domain.PermUsersWrite) {
+		return domain.UserResponse{}, ErrForbidden //403
+	}
+	user.IsAdmin = false
+	user.Role = ""
 
 	if err := s.validateUser(user); err != nil {
 		return domain.UserResponse{}, err
 	}
 
-	// Si se envía una nueva contraseña, hashearla
+	// Si se envía una nueva contraseña, validarla y hashearla
 	if user.Password != "" {
-		user.Password = utils.HashSHA256(user.Password)
+		if err := validatePassword(user.Password); err != nil {
+			return domain.UserResponse{}, err
+		}
+		if user.Password, err = s.passwords.Hash(user.Password); err != nil {
+			return domain.UserResponse{}, err
+		}
+	}
+
+	current, err := s.repository.GetByID(ctx, userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return domain.UserResponse{}, ErrUserNotFound // 404
+		}
+		return domain.UserResponse{}, err // 500
 	}
 
 	updatedUser, err := s.repository.Update(ctx, userID, user)
@@ -147,15 +231,27 @@ func (s *UsersServiceImpl) Update(ctx context.Context, id string, user domain.Us
 		return domain.UserResponse{}, err // 500
 	}
 
+	// Un email nuevo hay que volver a verificarlo
+	if !strings.EqualFold(current.Email, updatedUser.Email) {
+		if err := s.repository.SetEmailVerifiedAt(ctx, userID, nil); err != nil {
+			return domain.UserResponse{}, err // 500
+		}
+		updatedUser.EmailVerifiedAt = nil
+		s.sendVerificationEmail(ctx, updatedUser)
+	}
+
 	return updatedUser, nil
 }
 
-// Delete elimina un usuario por ID
-func (s *UsersServiceImpl) Delete(ctx context.Context, id string) error {
+// Delete elimina un usuario por ID (él mismo o quien puede editar usuarios)
+func (s *UsersServiceImpl) Delete(ctx context.Context, caller domain.TokenClaims, id string) error {
 	userID, err := strconv.Atoi(id)
 	if err != nil {
 		return ErrInvalidUserID //400
 	}
+	if caller.UserID != userID && !caller.HasPermission(domain.PermUsersWrite) {
+		return ErrForbidden //403
+	}
 
 	err = s.repository.Delete(ctx, userID)
 	if err != nil {
@@ -169,6 +265,8 @@ func (s *UsersServiceImpl) Delete(ctx context.Context, id string) error {
 }
 
 // Login valida credenciales de usuario
+// Los fallos se cuentan por cuenta y por IP: cada fallo demora más el siguiente intento y al llegar al máximo
+// se bloquea temporalmente (LoginLockedError, distinto de ErrInvalidCredentials)
 func (s *UsersServiceImpl) Login(ctx context.Context, loginReq domain.LoginRequest) (domain.LoginResponse, error) {
 	if strings.TrimSpace(loginReq.Email) == "" {
 		return domain.LoginResponse{}, ErrEmailRequired //400
@@ -177,36 +275,86 @@ func (s *UsersServiceImpl) Login(ctx context.Context, loginReq domain.LoginReque
 		return domain.LoginResponse{}, ErrPasswordRequired //400
 	}
 
+	accountKey, ipKey := loginKeys(loginReq)
+	delay, err := s.checkLoginAllowed(ctx, accountKey, ipKey, time.Now().UTC())
+	if err != nil {
+		return domain.LoginResponse{}, err //429
+	}
+	// La demora va antes de verificar (y también para los intentos correctos) para que no se pueda cortar la espera al ver que falló
+	if err := waitFor(ctx, delay); err != nil {
+		return domain.LoginResponse{}, err
+	}
+
 	// Buscar usuario por email
 	userModel, err := s.repository.GetByEmail(ctx, loginReq.Email)
 	if err != nil {
-		return domain.LoginResponse{}, ErrInvalidCredentials //401
+		return domain.LoginResponse{}, s.loginFailed(ctx, accountKey, ipKey, time.Now().UTC()) //401 o 429
 	}
 
 	// Verificar contraseña
-	if utils.HashSHA256(loginReq.Password) != userModel.Password {
-		return domain.LoginResponse{}, ErrInvalidCredentials //401
+	match, needsRehash := s.passwords.Verify(loginReq.Password, userModel.Password)
+	if !match {
+		return domain.LoginResponse{}, s.loginFailed(ctx, accountKey, ipKey, time.Now().UTC()) //401 o 429
 	}
+	s.loginSucceeded(ctx, accountKey)
 
-	// Generar JWT token
-	token, err := utils.GenerateJWT(userModel.ID, userModel.IsAdmin)
+	// Los hashes legacy (SHA-256) o con otro algoritmo/costo se actualizan ahora que tenemos la contraseña en claro
+	if needsRehash {
+		s.upgradePasswordHash(ctx, userModel.ID, loginReq.Password)
+	}
+
+	// Generar JWT token con el rol y sus permisos
+	token, role, err := s.accessToken(userModel.ToResponse())
 	if err != nil {
 		return domain.LoginResponse{}, errors.New("failed to generate token") //500
 	}
 
+	// Cada login abre una sesión con su propio refresh token
+	refreshToken, refreshExpiresAt, err := s.startSession(ctx, userModel.ID)
+	if err != nil {
+		return domain.LoginResponse{}, err //500
+	}
+
 	// Obtener el carrito desde products-api
-	cart := s.getCartFromProductsAPI(ctx, userModel.ID, token)
+	// Si el usuario venía comprando como invitado, su carrito se fusiona con el del cliente
+	var cart interface{}
+	if loginReq.GuestCartToken != "" {
+		cart = s.mergeGuestCartInProductsAPI(ctx, userModel.ID, token, loginReq.GuestCartToken)
+	}
+	if cart == nil {
+		cart = s.getCartFromProductsAPI(ctx, userModel.ID, token)
+	}
 
 	return domain.LoginResponse{
-		Token:      token,
-		Name:       userModel.FirstName,
-		Surname:    userModel.LastName,
-		CustomerID: userModel.ID,
-		IsAdmin:    userModel.IsAdmin,
-		Cart:       cart,
+		Token:            token,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+		Name:             userModel.FirstName,
+		Surname:          userModel.LastName,
+		CustomerID:       userModel.ID,
+		IsAdmin:          userModel.IsAdmin,
+		Role:             role.Role,
+		Permissions:      role.Permissions,
+		EmailVerified:    userModel.EmailVerifiedAt != nil,
+		Cart:             cart,
 	}, nil
 }
 
+// upgradePasswordHash guarda la contraseña con el algoritmo y costo actuales
+// Si falla el login sigue igual: se vuelve a intentar en el próximo
+func (s *UsersServiceImpl) upgradePasswordHash(ctx context.Context, userID int, password string) {
+	hash, err := s.passwords.Hash(password)
+	if err != nil {
+		log.Printf("Error rehashing password for user %d: %v", userID, err)
+		return
+	}
+	if err := s.repository.UpdatePasswordHash(ctx, userID, hash); err != nil {
+		log.Printf("Error upgrading password hash for user %d: %v", userID, err)
+		return
+	}
+	log.Printf("🔐 Password hash upgraded for user %d", userID)
+}
+
 // validateUser aplica reglas de negocio para validar un usuario
 func (s *UsersServiceImpl) validateUser(user domain.User) error {
 	if strings.TrimSpace(user.Email) == "" {
@@ -227,34 +375,53 @@ func (s *UsersServiceImpl) validateCreateUser(user domain.User) error {
 	if err := s.validateUser(user); err != nil {
 		return err
 	}
-	if strings.TrimSpace(user.Password) == "" {
-		return ErrPasswordRequired
+	return validatePassword(user.Password)
+}
+
+// validatePassword aplica las reglas de las contraseñas nuevas
+func validatePassword(password string) error {
+	if strings.TrimSpace(password) == "" {
+		return ErrPasswordRequired // 400
+	}
+	if utf8.RuneCountInString(password) < minPasswordLength {
+		return ErrPasswordTooShort // 400
 	}
 	return nil
 }
 
-func (s *UsersServiceImpl) VerifyToken(token string) error {
-	err := utils.ValidateJWT(token)
+// VerifyToken valida el token y devuelve sus claims para que products-api sepa quién llama
+func (s *UsersServiceImpl) VerifyToken(token string) (domain.TokenClaims, error) {
+	claims, err := s.tokens.Parse(token)
 	if err != nil {
 		log.Println("Error al verificar el token")
-		return fmt.Errorf("failed to verify token: %w", err)
+		return domain.TokenClaims{}, fmt.Errorf("failed to verify token: %w", err)
 	}
-	return nil
+	return domain.TokenClaims{
+		UserID:        claims.UserID,
+		IsAdmin:       claims.IsAdmin,
+		Role:          claims.Role,
+		Permissions:   claims.Permissions,
+		EmailVerified: claims.EmailVerified,
+	}, nil
 }
 
-func (s *UsersServiceImpl) VerifyAdminToken(token string) error {
-	err := utils.ValidateAdminJWT(token)
+// JWKS devuelve las claves públicas con las que se verifican los access tokens
+func (s *UsersServiceImpl) JWKS() utils.JWKS {
+	return s.tokens.JWKS()
+}
+
+func (s *UsersServiceImpl) VerifyAdminToken(token string) (domain.TokenClaims, error) {
+	err := s.tokens.ValidateAdmin(token)
 	if err != nil {
 		log.Println("Error al verificar el token de admin")
-		return fmt.Errorf("failed to verify admin token: %w", err)
+		return domain.TokenClaims{}, fmt.Errorf("failed to verify admin token: %w", err)
 	}
-	return nil
+	return s.VerifyToken(token)
 }
 
 // getCartFromProductsAPI obtiene el carrito del usuario desde products-api
 func (s *UsersServiceImpl) getCartFromProductsAPI(ctx context.Context, customerID int, token string) interface{} {
-	// URL del products-api (ajustar según tu configuración de docker-compose)
-	url := fmt.Sprintf("http://products-api:8081/carrito/%d", customerID)
+	url := fmt.Sprintf("%s/cart/%d", s.productsAPIURL, customerID)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -291,3 +458,43 @@ func (s *UsersServiceImpl) getCartFromProductsAPI(ctx context.Context, customerI
 
 	return cart
 }
+
+// mergeGuestCartInProductsAPI pasa el carrito de invitado al carrito del cliente
+// Devuelve nil si no se pudo (por ejemplo, el carrito de invitado venció): el login sigue igual
+func (s *UsersServiceImpl) mergeGuestCartInProductsAPI(ctx context.Context, customerID int, token, guestToken string) interface{} {
+	url := fmt.Sprintf("%s/cart/%d/merge", s.productsAPIURL, customerID)
+
+	body, err := json.Marshal(map[string]string{"guest_token": guestToken})
+	if err != nil {
+		log.Printf("Error encoding merge request: %v", err)
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("Error creating merge request: %v", err)
+		return nil
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Printf("Error calling products-api to merge the guest cart: %v", err)
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Products-api returned status %d merging the guest cart", resp.StatusCode)
+		return nil
+	}
+
+	var cart interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&cart); err != nil {
+		log.Printf("Error parsing merged cart: %v", err)
+		return nil
+	}
+	return cart
+}
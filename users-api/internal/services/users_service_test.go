@@ -5,15 +5,79 @@ import (
 	"errors"
 	"strconv"
 	"testing"
+	"time"
 	"users-api/internal/domain"
+	"users-api/internal/utils"
 
 	"github.com/h2non/gock"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // ============================================
 // MOCKS version falsa que simula el repositorio y la bd
 // ============================================
 
+// testProductsAPIURL es la URL de products-api que interceptan los tests de login con gock
+const testProductsAPIURL = "http://products-api:8080"
+
+// testPasswordHasher usa bcrypt con el costo mínimo para que los tests no tarden
+var testPasswordHasher, _ = utils.NewPasswordHasher(utils.PasswordHashConfig{
+	Algorithm:  utils.PasswordAlgorithmBcrypt,
+	BcryptCost: bcrypt.MinCost,
+})
+
+// testVerificationURL y testVerificationTTL son el link y la vigencia de la verificación de email en los tests
+const (
+	testVerificationURL = "http://localhost:3000/verificar-email"
+	testVerificationTTL = time.Hour
+)
+
+// testJWTManager firma los tokens de los tests con una clave Ed25519 efímera
+var testJWTManager = newTestJWTManager()
+
+func newTestJWTManager() *utils.JWTManager {
+	key, err := utils.NewEphemeralSigningKey()
+	if err != nil {
+		panic(err)
+	}
+	manager, err := utils.NewJWTManager([]utils.SigningKey{key}, key.ID)
+	if err != nil {
+		panic(err)
+	}
+	return manager
+}
+
+// newTestUsersService arma el service de los tests: lo que no viene en deps se completa con los mocks
+// y la configuración de test, así cada test solo pasa lo que quiere inspeccionar
+func newTestUsersService(deps UsersServiceDeps) *UsersServiceImpl {
+	if deps.Repository == nil {
+		deps.Repository = NewMockUsersRepository()
+	}
+	if deps.RefreshTokens == nil {
+		deps.RefreshTokens = NewMockRefreshTokensRepository()
+	}
+	if deps.Events == nil {
+		deps.Events = NewMockUserEventsPublisher()
+	}
+	if deps.PasswordResets == nil {
+		deps.PasswordResets = NewMockPasswordResetsRepository(deps.Repository, deps.RefreshTokens)
+	}
+	if deps.LoginAttempts == nil {
+		deps.LoginAttempts = newTestLoginAttempts()
+	}
+	if deps.Throttle == (LoginThrottle{}) {
+		deps.Throttle = testLoginThrottle
+	}
+	deps.ProductsAPIURL = testProductsAPIURL
+	deps.Passwords = testPasswordHasher
+	deps.Tokens = testJWTManager
+	deps.RefreshTTL = testRefreshTTL
+	deps.VerifyURL = testVerificationURL
+	deps.VerifyTTL = testVerificationTTL
+	deps.ResetURL = testPasswordResetURL
+	return NewUsersService(deps)
+}
+
 // MockUsersRepository simula el repositorio de usuarios
 type MockUsersRepository struct {
 	users      map[int]domain.User // Almacena usuarios en memoria (no en MySQL)
@@ -91,6 +155,9 @@ func (m *MockUsersRepository) GetByID(ctx context.Context, id int) (domain.UserR
 		FirstName: user.FirstName,
 		LastName:  user.LastName,
 		IsAdmin:   user.IsAdmin,
+		Role:      user.Role,
+
+		EmailVerifiedAt: user.EmailVerifiedAt,
 	}, nil
 }
 
@@ -114,20 +181,23 @@ func (m *MockUsersRepository) Update(ctx context.Context, id int, user domain.Us
 		return domain.UserResponse{}, errors.New("database error")
 	}
 
-	if _, exists := m.users[id]; !exists {
+	existing, exists := m.users[id]
+	if !exists {
 		return domain.UserResponse{}, errors.New("user not found")
 	}
 
+	// Como en MySQL, el rol, is_admin y la verificación del email no se tocan en el update
+	// y la contraseña solo si viene una nueva
 	user.ID = id
+	user.Role = existing.Role
+	user.IsAdmin = existing.IsAdmin
+	if user.Password == "" {
+		user.Password = existing.Password
+	}
+	user.EmailVerifiedAt = existing.EmailVerifiedAt
 	m.users[id] = user
 
-	return domain.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		IsAdmin:   user.IsAdmin,
-	}, nil
+	return user.ToResponse(), nil
 }
 
 // Simula la eliminación de un usuario y respuesta de error si falla
@@ -144,6 +214,64 @@ func (m *MockUsersRepository) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
+// Simula el reemplazo del hash de la contraseña
+func (m *MockUsersRepository) UpdatePasswordHash(ctx context.Context, id int, hash string) error {
+	if m.shouldFail {
+		return errors.New("database error")
+	}
+
+	user, exists := m.users[id]
+	if !exists {
+		return errors.New("user not found")
+	}
+	user.Password = hash
+	m.users[id] = user
+	return nil
+}
+
+func (m *MockUsersRepository) UpdateRole(ctx context.Context, id int, role string, isAdmin bool) (domain.UserResponse, error) {
+	if m.shouldFail {
+		return domain.UserResponse{}, errors.New("database error")
+	}
+
+	user, exists := m.users[id]
+	if !exists {
+		return domain.UserResponse{}, errors.New("user not found")
+	}
+	user.Role = role
+	user.IsAdmin = isAdmin
+	m.users[id] = user
+	return user.ToResponse(), nil
+}
+
+func (m *MockUsersRepository) SetEmailVerifiedAt(ctx context.Context, id int, at *time.Time) error {
+	if m.shouldFail {
+		return errors.New("database error")
+	}
+
+	user, exists := m.users[id]
+	if !exists {
+		return errors.New("user not found")
+	}
+	user.EmailVerifiedAt = at
+	m.users[id] = user
+	return nil
+}
+
+// MockUserEventsPublisher guarda los eventos publicados en vez de mandarlos a RabbitMQ
+type MockUserEventsPublisher struct {
+	events []domain.UserEvent
+}
+
+func NewMockUserEventsPublisher() *MockUserEventsPublisher {
+	return &MockUserEventsPublisher{}
+}
+
+func (m *MockUserEventsPublisher) PublishUserEvent(ctx context.Context, event domain.UserEvent) error {
+	m.events = append(m.events, event)
+	return nil
+}
+
 // ============================================
 // TESTS patron AAA(Arrange, Act, Assert) (Preparar, Ejecutar, Verificar)
 // ============================================
@@ -153,8 +281,8 @@ func (m *MockUsersRepository) Delete(ctx context.Context, id int) error {
 // TestCreate_Success verifica la creación exitosa de un usuario
 func TestCreate_Success(t *testing.T) {
 	// Preparar
-	mockRepo := NewMockUsersRepository() // Mock en lugar de MySQL
-	service := NewUsersService(mockRepo) // Servicio con el mock
+	mockRepo := NewMockUsersRepository()                                   // Mock en lugar de MySQL
+	service := newTestUsersService(UsersServiceDeps{Repository: mockRepo}) // Servicio con el mock
 
 	user := domain.User{
 		Email:     "test@example.com",
@@ -188,7 +316,7 @@ func TestCreate_Success(t *testing.T) {
 // TestCreate_DuplicateEmail verifica que no se permita email duplicado
 func TestCreate_DuplicateEmail(t *testing.T) {
 	mockRepo := NewMockUsersRepository()
-	service := NewUsersService(mockRepo)
+	service := newTestUsersService(UsersServiceDeps{Repository: mockRepo})
 
 	user1 := domain.User{
 		Email:     "duplicate@example.com",
@@ -223,7 +351,7 @@ func TestCreate_DuplicateEmail(t *testing.T) {
 // TestCreate_EmptyEmail verifica validación de email vacío
 func TestCreate_EmptyEmail(t *testing.T) {
 	mockRepo := NewMockUsersRepository()
-	service := NewUsersService(mockRepo)
+	service := newTestUsersService(UsersServiceDeps{Repository: mockRepo})
 
 	user := domain.User{
 		Email:     "",
@@ -246,7 +374,7 @@ func TestCreate_EmptyEmail(t *testing.T) {
 // TestCreate_EmptyPassword verifica validación de password vacío
 func TestCreate_EmptyPassword(t *testing.T) {
 	mockRepo := NewMockUsersRepository()
-	service := NewUsersService(mockRepo)
+	service := newTestUsersService(UsersServiceDeps{Repository: mockRepo})
 
 	user := domain.User{
 		Email:     "test@example.com",
@@ -269,7 +397,7 @@ func TestCreate_EmptyPassword(t *testing.T) {
 // TestCreate_EmptyFirstName verifica validación de first name vacío
 func TestCreate_EmptyFirstName(t *testing.T) {
 	mockRepo := NewMockUsersRepository()
-	service := NewUsersService(mockRepo)
+	service := newTestUsersService(UsersServiceDeps{Repository: mockRepo})
 
 	user := domain.User{
 		Email:     "test@example.com",
@@ -292,7 +420,7 @@ func TestCreate_EmptyFirstName(t *testing.T) {
 // TestCreate_EmptyLastName verifica validación de last name vacío
 func TestCreate_EmptyLastName(t *testing.T) {
 	mockRepo := NewMockUsersRepository()
-	service := NewUsersService(mockRepo)
+	service := newTestUsersService(UsersServiceDeps{Repository: mockRepo})
 
 	user := domain.User{
 		Email:     "test@example.com",
@@ -319,8 +447,8 @@ func TestLogin_Success(t *testing.T) {
 	defer gock.Off() // Limpiar interceptores después del test
 
 	//  Interceptar llamadas HTTP a products-api
-	gock.New("http://products-api:8081").
-		Get("/carrito/1"). // El customerID será 1 (primer usuario creado)
+	gock.New(testProductsAPIURL).
+		Get("/cart/1"). // El customerID será 1 (primer usuario creado)
 		Reply(200).
 		JSON(map[string]interface{}{
 			"items":      []interface{}{},
@@ -329,7 +457,7 @@ func TestLogin_Success(t *testing.T) {
 		})
 
 	mockRepo := NewMockUsersRepository()
-	service := NewUsersService(mockRepo)
+	service := newTestUsersService(UsersServiceDeps{Repository: mockRepo})
 
 	// Crear un usuario primero
 	user := domain.User{
@@ -367,10 +495,107 @@ func TestLogin_Success(t *testing.T) {
 	}
 }
 
+// TestLogin_MergesGuestCart verifica que el carrito de invitado se fusiona con el del cliente
+func TestLogin_MergesGuestCart(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testProductsAPIURL).
+		Post("/cart/1/merge").
+		MatchType("json").
+		JSON(map[string]string{"guest_token": "guest-123"}).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"items":      []interface{}{map[string]interface{}{"item_id": "mate-1", "quantity": 2}},
+			"item_count": 2,
+		})
+
+	mockRepo := NewMockUsersRepository()
+	service := newTestUsersService(UsersServiceDeps{Repository: mockRepo})
+	_, _ = service.Create(context.Background(), domain.User{
+		Email:     "guest@example.com",
+		Password:  "password123",
+		FirstName: "Ana",
+		LastName:  "Paz",
+	})
+
+	response, err := service.Login(context.Background(), domain.LoginRequest{
+		Email:          "guest@example.com",
+		Password:       "password123",
+		GuestCartToken: "guest-123",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	cart, ok := response.Cart.(map[string]interface{})
+	if !ok || cart["item_count"] != float64(2) {
+		t.Errorf("Expected the merged cart in the response, got %v", response.Cart)
+	}
+	if !gock.IsDone() {
+		t.Error("Expected products-api merge endpoint to be called")
+	}
+}
+
+// TestCreate_HashesPassword verifica que la contraseña no se guarda en claro ni con SHA-256
+func TestCreate_HashesPassword(t *testing.T) {
+	mockRepo := NewMockUsersRepository()
+	service := newTestUsersService(UsersServiceDeps{Repository: mockRepo})
+
+	created, err := service.Create(context.Background(), domain.User{
+		Email:     "hash@example.com",
+		Password:  "password123",
+		FirstName: "Ana",
+		LastName:  "Paz",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	stored := mockRepo.users[created.ID].Password
+	if stored == "password123" || utils.IsLegacySHA256Hash(stored) {
+		t.Fatalf("Expected an adaptive hash, got %q", stored)
+	}
+	if match, needsRehash := testPasswordHasher.Verify("password123", stored); !match || needsRehash {
+		t.Errorf("Expected the stored hash to verify without rehash, got match=%v needsRehash=%v", match, needsRehash)
+	}
+}
+
+// TestLogin_UpgradesLegacyHash verifica que un hash SHA-256 viejo se migra en el login
+func TestLogin_UpgradesLegacyHash(t *testing.T) {
+	defer gock.Off()
+	gock.New(testProductsAPIURL).
+		Get("/cart/1").
+		Reply(200).
+		JSON(map[string]interface{}{"items": []interface{}{}, "total": 0, "item_count": 0})
+
+	mockRepo := NewMockUsersRepository()
+	mockRepo.users[1] = domain.User{
+		ID:        1,
+		Email:     "legacy@example.com",
+		Password:  utils.HashSHA256("password123"),
+		FirstName: "Ana",
+		LastName:  "Paz",
+	}
+	mockRepo.nextID = 2
+	service := newTestUsersService(UsersServiceDeps{Repository: mockRepo})
+
+	if _, err := service.Login(context.Background(), domain.LoginRequest{Email: "legacy@example.com", Password: "password123"}); err != nil {
+		t.Fatalf("Expected legacy login to succeed, got %v", err)
+	}
+
+	stored := mockRepo.users[1].Password
+	if utils.IsLegacySHA256Hash(stored) {
+		t.Fatal("Expected the legacy hash to be upgraded")
+	}
+	if match, _ := testPasswordHasher.Verify("password123", stored); !match {
+		t.Error("Expected the upgraded hash to verify the same password")
+	}
+}
+
 // TestLogin_WrongPassword verifica rechazo de contraseña incorrecta
 func TestLogin_WrongPassword(t *testing.T) {
 	mockRepo := NewMockUsersRepository()
-	service := NewUsersService(mockRepo)
+	service := newTestUsersService(UsersServiceDeps{Repository: mockRepo})
 
 	user := domain.User{
 		Email:     "wrongpass@example.com",
@@ -399,7 +624,7 @@ func TestLogin_WrongPassword(t *testing.T) {
 // TestLogin_UserNotFound verifica rechazo de email inexistente
 func TestLogin_UserNotFound(t *testing.T) {
 	mockRepo := NewMockUsersRepository()
-	service := NewUsersService(mockRepo)
+	service := newTestUsersService(UsersServiceDeps{Repository: mockRepo})
 
 	loginReq := domain.LoginRequest{
 		Email:    "nonexistent@example.com",
@@ -422,7 +647,7 @@ func TestLogin_UserNotFound(t *testing.T) {
 // TestGetByID_Success verifica obtener usuario por ID
 func TestGetByID_Success(t *testing.T) {
 	mockRepo := NewMockUsersRepository()
-	service := NewUsersService(mockRepo)
+	service := newTestUsersService(UsersServiceDeps{Repository: mockRepo})
 
 	user := domain.User{
 		Email:     "getbyid@example.com",
@@ -450,7 +675,7 @@ func TestGetByID_Success(t *testing.T) {
 // TestGetByID_NotFound verifica manejo de usuario no encontrado
 func TestGetByID_NotFound(t *testing.T) {
 	mockRepo := NewMockUsersRepository()
-	service := NewUsersService(mockRepo)
+	service := newTestUsersService(UsersServiceDeps{Repository: mockRepo})
 
 	_, err := service.GetByID(context.Background(), "999")
 
@@ -466,7 +691,7 @@ func TestGetByID_NotFound(t *testing.T) {
 // TestGetByID_InvalidID verifica manejo de ID inválido
 func TestGetByID_InvalidID(t *testing.T) {
 	mockRepo := NewMockUsersRepository()
-	service := NewUsersService(mockRepo)
+	service := newTestUsersService(UsersServiceDeps{Repository: mockRepo})
 
 	_, err := service.GetByID(context.Background(), "invalid")
 
@@ -483,10 +708,10 @@ func TestGetByID_InvalidID(t *testing.T) {
 // TestList_Success verifica obtener todos los usuarios
 func TestList_Success(t *testing.T) {
 	mockRepo := NewMockUsersRepository()
-	service := NewUsersService(mockRepo)
+	service := newTestUsersService(UsersServiceDeps{Repository: mockRepo})
 
-	user1 := domain.User{Email: "user1@example.com", Password: "pass1", FirstName: "User", LastName: "One"}
-	user2 := domain.User{Email: "user2@example.com", Password: "pass2", FirstName: "User", LastName: "Two"}
+	user1 := domain.User{Email: "user1@example.com", Password: "password1", FirstName: "User", LastName: "One"}
+	user2 := domain.User{Email: "user2@example.com", Password: "password2", FirstName: "User", LastName: "Two"}
 	_, _ = service.Create(context.Background(), user1)
 	_, _ = service.Create(context.Background(), user2)
 
@@ -505,7 +730,7 @@ func TestList_Success(t *testing.T) {
 // TestUpdate_Success verifica actualización de usuario
 func TestUpdate_Success(t *testing.T) {
 	mockRepo := NewMockUsersRepository()
-	service := NewUsersService(mockRepo)
+	service := newTestUsersService(UsersServiceDeps{Repository: mockRepo})
 
 	user := domain.User{
 		Email:     "original@example.com",
@@ -522,7 +747,7 @@ func TestUpdate_Success(t *testing.T) {
 		LastName:  "Name",
 	}
 
-	updated, err := service.Update(context.Background(), strconv.Itoa(created.ID), updateReq)
+	updated, err := service.Update(context.Background(), domain.TokenClaims{UserID: created.ID}, strconv.Itoa(created.ID), updateReq)
 
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
@@ -537,7 +762,7 @@ func TestUpdate_Success(t *testing.T) {
 // TestDelete_Success verifica eliminación de usuario
 func TestDelete_Success(t *testing.T) {
 	mockRepo := NewMockUsersRepository()
-	service := NewUsersService(mockRepo)
+	service := newTestUsersService(UsersServiceDeps{Repository: mockRepo})
 
 	user := domain.User{
 		Email:     "delete@example.com",
@@ -550,7 +775,7 @@ func TestDelete_Success(t *testing.T) {
 	created, _ := service.Create(context.Background(), user)
 
 	// Eliminar el usuario
-	err := service.Delete(context.Background(), strconv.Itoa(created.ID))
+	err := service.Delete(context.Background(), domain.TokenClaims{UserID: created.ID}, strconv.Itoa(created.ID))
 
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
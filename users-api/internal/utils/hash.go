@@ -5,6 +5,8 @@ import (
 	"encoding/hex"
 )
 
+// HashSHA256 es el hash de contraseñas de la versión anterior (SHA-256 sin sal)
+// Solo se usa para verificar los hashes legacy antes de migrarlos; las contraseñas nuevas usan PasswordHasher
 func HashSHA256(value string) string {
 	hash := sha256.Sum256([]byte(value))
 	return hex.EncodeToString(hash[:])
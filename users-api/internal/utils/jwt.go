@@ -1,7 +1,21 @@
 package utils
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -11,102 +25,378 @@ const (
 	// TokenExpirationTime is the expiration time for the JWT token
 	jwtDuration = 10 * time.Minute
 	// TokenIssuer is the issuer of the JWT token
-	jwtSecret = "jwtSecret"
+	jwtIssuer = "backend"
+	// jwtAccessSubject es el subject de los access tokens; los tokens de acción (verificar email...) usan otro
+	// para que no se puedan usar como access token
+	jwtAccessSubject = "auth"
+)
+
+// Propósitos de los tokens de acción (van en el subject)
+const (
+	PurposeEmailVerification = "email_verification"
 )
 
 type CustomClaims struct {
 	IsAdmin bool `json:"is_admin"`
 	UserID  int  `json:"user_id"`
+	// Role y Permissions: products-api controla los permisos por ruta sin consultar la base
+	Role          string   `json:"role,omitempty"`
+	Permissions   []string `json:"permissions,omitempty"`
+	EmailVerified bool     `json:"email_verified"`
 	jwt.RegisteredClaims
 }
 
-// UserID associated with each token
-func GenerateJWT(userID int, isAdmin bool) (string, error) {
+// AccessTokenData son los datos del usuario que viajan en el access token
+type AccessTokenData struct {
+	UserID        int
+	IsAdmin       bool
+	Role          string
+	Permissions   []string
+	EmailVerified bool
+}
+
+// ActionClaims son los claims de un token de un solo propósito que se manda por email
+// Lleva el email para que el link deje de valer si el usuario lo cambia
+type ActionClaims struct {
+	UserID int    `json:"user_id"`
+	Email  string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// SigningKey es una clave del JWTManager identificada por su kid
+// Las claves con PrivateKey pueden firmar; las que solo tienen PublicKey quedan para verificar
+// tokens ya emitidos (rotación: la clave vieja se deja hasta que vencen sus tokens)
+type SigningKey struct {
+	ID         string
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+// Method devuelve el algoritmo de firma según el tipo de clave: RS256 (RSA) o EdDSA (Ed25519)
+func (k SigningKey) Method() (jwt.SigningMethod, error) {
+	switch k.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %T for kid %q", k.PublicKey, k.ID)
+}
+
+// JWK es una clave pública en formato JSON Web Key
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA: módulo
+	E   string `json:"e,omitempty"`   // RSA: exponente
+	Crv string `json:"crv,omitempty"` // OKP: curva
+	X   string `json:"x,omitempty"`   // OKP: clave pública
+}
+
+// JWKS es el documento que se publica en /.well-known/jwks.json
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWTManager firma los access tokens con la clave activa y los verifica con cualquiera de las claves
+// El kid va en el header del token para saber con qué clave verificarlo
+type JWTManager struct {
+	active SigningKey
+	keys   map[string]SigningKey
+}
+
+// NewJWTManager crea el manager con las claves de verificación y la clave activa para firmar
+// Si activeKID está vacío y hay una sola clave privada, esa es la activa
+func NewJWTManager(keys []SigningKey, activeKID string) (*JWTManager, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one JWT key is required")
+	}
+
+	m := &JWTManager{keys: make(map[string]SigningKey, len(keys))}
+	var signers []SigningKey
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, errors.New("JWT keys need a kid")
+		}
+		if _, exists := m.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicated JWT kid %q", key.ID)
+		}
+		if _, err := key.Method(); err != nil {
+			return nil, err
+		}
+		m.keys[key.ID] = key
+		if key.PrivateKey != nil {
+			signers = append(signers, key)
+		}
+	}
+
+	switch {
+	case activeKID != "":
+		key, ok := m.keys[activeKID]
+		if !ok || key.PrivateKey == nil {
+			return nil, fmt.Errorf("active JWT kid %q has no private key", activeKID)
+		}
+		m.active = key
+	case len(signers) == 1:
+		m.active = signers[0]
+	default:
+		return nil, errors.New("the active JWT kid must be set when there is not exactly one private key")
+	}
+	return m, nil
+}
+
+// ActiveKID devuelve el kid con el que se firman los tokens nuevos
+func (m *JWTManager) ActiveKID() string {
+	return m.active.ID
+}
+
+// Generate emite un access token para el usuario (UserID associated with each token) con su rol y permisos
+func (m *JWTManager) Generate(data AccessTokenData) (string, error) {
 	// set the expiration time
 	expirationTime := time.Now().Add(jwtDuration)
 	// create the JWT claims (los datos
 	//  que viajan en el token. el mas importante es el user id)
 	claims := CustomClaims{
-		IsAdmin: isAdmin, // set if the user is an admin
-		UserID:  userID,
+		IsAdmin:       data.IsAdmin, // set if the user is an admin
+		UserID:        data.UserID,
+		Role:          data.Role,
+		Permissions:   data.Permissions,
+		EmailVerified: data.EmailVerified,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime), // set the expiration time
 			IssuedAt:  jwt.NewNumericDate(time.Now()),     // set who issued the token
 			NotBefore: jwt.NewNumericDate(time.Now()),     // set when the token is valid
-			Issuer:    "backend",                          // set the issuer of the token
-			Subject:   "auth",                             // set the subject of the token
-			ID:        fmt.Sprintf("%d", userID),
+			Issuer:    jwtIssuer,                          // set the issuer of the token
+			Subject:   jwtAccessSubject,                   // set the subject of the token
+			ID:        fmt.Sprintf("%d", data.UserID),
+		},
+	}
+	return m.sign(claims)
+}
+
+// GenerateAction emite un token de acción (purpose) para el usuario que vence en ttl
+func (m *JWTManager) GenerateAction(purpose string, userID int, email string, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	token, err := m.sign(ActionClaims{
+		UserID: userID,
+		Email:  email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    jwtIssuer,
+			Subject:   purpose,
 		},
+	})
+	return token, expiresAt, err
+}
+
+// ParseAction valida un token de acción y que sea del propósito esperado
+func (m *JWTManager) ParseAction(tokenString, purpose string) (*ActionClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &ActionClaims{}, m.keyFunc, m.parserOptions(purpose)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing token: %w", err)
 	}
 
-	// create the token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	claims, ok := token.Claims.(*ActionClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+	return claims, nil
+}
 
-	// sign the token with the secret key, ecrypted with SHA256
-	tokenString, err := token.SignedString([]byte(jwtSecret))
+// sign firma los claims con la clave activa, con el kid en el header
+func (m *JWTManager) sign(claims jwt.Claims) (string, error) {
+	method, err := m.active.Method()
+	if err != nil {
+		return "", err
+	}
+
+	// create the token, with the key ID in the header
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = m.active.ID
+
+	// sign the token with the active private key
+	tokenString, err := token.SignedString(m.active.PrivateKey)
 	if err != nil {
 		return "", fmt.Errorf("failed generating token: %w", err)
 	}
 	return tokenString, nil
 }
 
-// ValidateJWT validates the JWT token and returns the user ID
-func ValidateJWT(tokenString string) error {
-	// parse the token
-	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// check if the signing method is valid
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(jwtSecret), nil
-	})
+// parserOptions son las validaciones comunes: algoritmos asimétricos, issuer, subject y vencimiento obligatorio
+func (m *JWTManager) parserOptions(subject string) []jwt.ParserOption {
+	return []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(jwtIssuer),
+		jwt.WithSubject(subject),
+		jwt.WithExpirationRequired(),
+	}
+}
 
+// Parse validates the JWT token and returns its claims (user ID and admin flag)
+func (m *JWTManager) Parse(tokenString string) (*CustomClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, m.keyFunc, m.parserOptions(jwtAccessSubject)...)
 	if err != nil {
-		return fmt.Errorf("failed parsing token: %w", err)
+		return nil, fmt.Errorf("failed parsing token: %w", err)
 	}
 
-	// check if the token is valid
 	claims, ok := token.Claims.(*CustomClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+	return claims, nil
+}
+
+// ValidateAdmin validates the JWT token and checks the admin flag
+func (m *JWTManager) ValidateAdmin(tokenString string) error {
+	claims, err := m.Parse(tokenString)
+	if err != nil {
+		return err
+	}
+	// check if user is admin
+	if !claims.IsAdmin {
+		return fmt.Errorf("user is not admin")
+	}
+	return nil
+}
 
-	if ok {
+// JWKS devuelve las claves públicas de verificación, ordenadas por kid
+func (m *JWTManager) JWKS() JWKS {
+	kids := make([]string, 0, len(m.keys))
+	for kid := range m.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
 
-		if claims.ExpiresAt != nil && claims.ExpiresAt.Before(time.Now()) {
-			return fmt.Errorf("token expired at %v", claims.ExpiresAt.Time)
+	jwks := JWKS{Keys: make([]JWK, 0, len(kids))}
+	for _, kid := range kids {
+		switch pub := m.keys[kid].PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kty: "RSA",
+				Kid: kid,
+				Use: "sig",
+				Alg: jwt.SigningMethodRS256.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kty: "OKP",
+				Kid: kid,
+				Use: "sig",
+				Alg: jwt.SigningMethodEdDSA.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(pub),
+			})
 		}
-		return nil
 	}
+	return jwks
+}
 
-	return fmt.Errorf("invalid token")
+// keyFunc elige la clave de verificación por el kid del header y controla que el algoritmo sea el de la clave
+func (m *JWTManager) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := m.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	method, err := key.Method()
+	if err != nil {
+		return nil, err
+	}
+	if token.Method.Alg() != method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %v for key %q", token.Header["alg"], kid)
+	}
+	return key.PublicKey, nil
 }
 
-func ValidateAdminJWT(tokenString string) error {
-	// parse the token
-	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// check if the signing method is valid
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+// ParseSigningKey lee una clave PEM: privada (PKCS#8, o PKCS#1 para RSA) o pública (PKIX)
+func ParseSigningKey(kid string, data []byte) (SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return SigningKey{}, fmt.Errorf("key %q is not PEM encoded", kid)
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY", "RSA PRIVATE KEY":
+		var parsed interface{}
+		var err error
+		if block.Type == "RSA PRIVATE KEY" {
+			parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		} else {
+			parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
 		}
-		return []byte(jwtSecret), nil
-	})
+		if err != nil {
+			return SigningKey{}, fmt.Errorf("invalid private key %q: %w", kid, err)
+		}
+		switch private := parsed.(type) {
+		case *rsa.PrivateKey:
+			if private.N.BitLen() < 2048 {
+				return SigningKey{}, fmt.Errorf("RSA key %q must be at least 2048 bits", kid)
+			}
+			return SigningKey{ID: kid, PrivateKey: private, PublicKey: &private.PublicKey}, nil
+		case ed25519.PrivateKey:
+			return SigningKey{ID: kid, PrivateKey: private, PublicKey: private.Public()}, nil
+		}
+		return SigningKey{}, fmt.Errorf("unsupported private key type %T for %q", parsed, kid)
+
+	case "PUBLIC KEY":
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return SigningKey{}, fmt.Errorf("invalid public key %q: %w", kid, err)
+		}
+		key := SigningKey{ID: kid, PublicKey: parsed}
+		if _, err := key.Method(); err != nil {
+			return SigningKey{}, err
+		}
+		return key, nil
+	}
+	return SigningKey{}, fmt.Errorf("unsupported PEM block %q in key %q", block.Type, kid)
+}
 
+// LoadSigningKeys lee las claves de un directorio: el nombre del archivo es el kid
+// <kid>.pem es una clave privada (firma y verifica), <kid>.pub.pem una pública (solo verifica)
+func LoadSigningKeys(dir string) ([]SigningKey, error) {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return fmt.Errorf("failed parsing token: %w", err)
+		return nil, fmt.Errorf("error reading JWT keys dir: %w", err)
 	}
 
-	// check if the token is valid and cast to CustomClaims
-	if claims, ok := token.Claims.(*CustomClaims); ok && token.Valid {
-		// check expiration
-		if claims.ExpiresAt != nil && claims.ExpiresAt.Before(time.Now()) {
-			return fmt.Errorf("token expired at %v", claims.ExpiresAt.Time)
+	keys := []SigningKey{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".pem") {
+			continue
 		}
-
-		// check if user is admin
-		if !claims.IsAdmin {
-			return fmt.Errorf("user is not admin")
+		kid := strings.TrimSuffix(strings.TrimSuffix(name, ".pem"), ".pub")
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("error reading JWT key %s: %w", name, err)
 		}
-
-		return nil
+		key, err := ParseSigningKey(kid, data)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
 	}
+	return keys, nil
+}
 
-	return fmt.Errorf("invalid token")
+// NewEphemeralSigningKey genera una clave Ed25519 en memoria (solo para desarrollo:
+// los tokens dejan de valer al reiniciar el servicio)
+func NewEphemeralSigningKey() (SigningKey, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("error generating JWT key: %w", err)
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return SigningKey{}, fmt.Errorf("error generating JWT kid: %w", err)
+	}
+	return SigningKey{ID: "ephemeral-" + hex.EncodeToString(suffix), PrivateKey: private, PublicKey: public}, nil
 }
@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newTestRSAKey(t *testing.T, kid string) SigningKey {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(private)
+	key, err := ParseSigningKey(kid, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return key
+}
+
+func TestJWTManager_RotationKeepsOldTokensValid(t *testing.T) {
+	old := newTestRSAKey(t, "2025-01")
+	oldManager, err := NewJWTManager([]SigningKey{old}, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	oldToken, err := oldManager.Generate(AccessTokenData{UserID: 7, IsAdmin: true, Role: "super_admin"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// La clave vieja queda solo para verificar; firma una Ed25519 nueva
+	current, _ := NewEphemeralSigningKey()
+	retired := SigningKey{ID: old.ID, PublicKey: old.PublicKey}
+	manager, err := NewJWTManager([]SigningKey{retired, current}, current.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	claims, err := manager.Parse(oldToken)
+	if err != nil {
+		t.Fatalf("Expected the token of the retired key to verify, got %v", err)
+	}
+	if claims.UserID != 7 || !claims.IsAdmin {
+		t.Errorf("Expected user 7 admin, got %+v", claims)
+	}
+
+	newToken, _ := manager.Generate(AccessTokenData{UserID: 8, Role: "catalog_manager", Permissions: []string{"items:write"}})
+	parsed, _, _ := jwt.NewParser().ParseUnverified(newToken, &CustomClaims{})
+	if parsed.Header["kid"] != current.ID || parsed.Header["alg"] != "EdDSA" {
+		t.Errorf("Expected EdDSA with kid %s, got %v", current.ID, parsed.Header)
+	}
+	newClaims, err := manager.Parse(newToken)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if newClaims.Role != "catalog_manager" || len(newClaims.Permissions) != 1 || newClaims.Permissions[0] != "items:write" {
+		t.Errorf("Expected the role and permissions in the token, got %+v", newClaims)
+	}
+	if err := manager.ValidateAdmin(newToken); err == nil {
+		t.Error("Expected a non admin token to fail the admin check")
+	}
+
+	// El JWKS publica las dos claves
+	jwks := manager.JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("Expected 2 keys in the JWKS, got %d", len(jwks.Keys))
+	}
+	for _, key := range jwks.Keys {
+		switch key.Kid {
+		case old.ID:
+			if key.Kty != "RSA" || key.Alg != "RS256" || key.N == "" || key.E != "AQAB" {
+				t.Errorf("Unexpected RSA JWK %+v", key)
+			}
+		case current.ID:
+			if key.Kty != "OKP" || key.Crv != "Ed25519" || key.Alg != "EdDSA" || key.X == "" {
+				t.Errorf("Unexpected Ed25519 JWK %+v", key)
+			}
+		default:
+			t.Errorf("Unexpected kid %s", key.Kid)
+		}
+	}
+
+	// Una clave sin privada no puede ser la activa
+	if _, err := NewJWTManager([]SigningKey{retired, current}, retired.ID); err == nil {
+		t.Error("Expected an error for an active key without private key")
+	}
+}
+
+func TestJWTManager_RejectsForgedTokens(t *testing.T) {
+	key, _ := NewEphemeralSigningKey()
+	manager, _ := NewJWTManager([]SigningKey{key}, key.ID)
+
+	claims := CustomClaims{
+		IsAdmin: true,
+		UserID:  1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			Issuer:    jwtIssuer,
+			Subject:   jwtAccessSubject,
+		},
+	}
+
+	// HS256 con el secreto viejo, aunque traiga el kid correcto
+	hmac := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	hmac.Header["kid"] = key.ID
+	forged, _ := hmac.SignedString([]byte("jwtSecret"))
+	if _, err := manager.Parse(forged); err == nil {
+		t.Error("Expected an HS256 token to be rejected")
+	}
+
+	// Firmado con otra clave y un kid desconocido
+	other, _ := NewEphemeralSigningKey()
+	otherManager, _ := NewJWTManager([]SigningKey{other}, other.ID)
+	token, _ := otherManager.Generate(AccessTokenData{UserID: 1, IsAdmin: true, Role: "super_admin"})
+	if _, err := manager.Parse(token); err == nil {
+		t.Error("Expected a token with an unknown kid to be rejected")
+	}
+
+	// Firmado con otra clave usando nuestro kid
+	impostor := SigningKey{ID: key.ID, PrivateKey: other.PrivateKey, PublicKey: other.PublicKey}
+	impostorManager, _ := NewJWTManager([]SigningKey{impostor}, key.ID)
+	token, _ = impostorManager.Generate(AccessTokenData{UserID: 1, IsAdmin: true, Role: "super_admin"})
+	if _, err := manager.Parse(token); err == nil {
+		t.Error("Expected a token signed with another key to be rejected")
+	}
+
+	// Vencido
+	expired := claims
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	ed := jwt.NewWithClaims(jwt.SigningMethodEdDSA, expired)
+	ed.Header["kid"] = key.ID
+	token, _ = ed.SignedString(key.PrivateKey)
+	if _, err := manager.Parse(token); err == nil {
+		t.Error("Expected an expired token to be rejected")
+	}
+}
+
+func TestJWTManager_ActionTokens(t *testing.T) {
+	key, _ := NewEphemeralSigningKey()
+	manager, _ := NewJWTManager([]SigningKey{key}, key.ID)
+
+	token, expiresAt, err := manager.GenerateAction(PurposeEmailVerification, 5, "ana@example.com", time.Hour)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if time.Until(expiresAt) <= 0 {
+		t.Errorf("Expected a future expiration, got %v", expiresAt)
+	}
+
+	claims, err := manager.ParseAction(token, PurposeEmailVerification)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if claims.UserID != 5 || claims.Email != "ana@example.com" {
+		t.Errorf("Expected user 5 and its email, got %+v", claims)
+	}
+
+	// Los tokens de acción no sirven como access token ni para otro propósito, y viceversa
+	if _, err := manager.Parse(token); err == nil {
+		t.Error("Expected an action token not to work as an access token")
+	}
+	if _, err := manager.ParseAction(token, "other_purpose"); err == nil {
+		t.Error("Expected an action token not to work for another purpose")
+	}
+	access, _ := manager.Generate(AccessTokenData{UserID: 5})
+	if _, err := manager.ParseAction(access, PurposeEmailVerification); err == nil {
+		t.Error("Expected an access token not to work as an action token")
+	}
+
+	expired, _, _ := manager.GenerateAction(PurposeEmailVerification, 5, "ana@example.com", -time.Minute)
+	if _, err := manager.ParseAction(expired, PurposeEmailVerification); err == nil {
+		t.Error("Expected an expired action token to be rejected")
+	}
+}
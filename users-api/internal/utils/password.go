@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Algoritmos de hash de contraseñas soportados
+const (
+	PasswordAlgorithmArgon2id = "argon2id"
+	PasswordAlgorithmBcrypt   = "bcrypt"
+)
+
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+// PasswordHashConfig define el algoritmo y el costo con que se hashean las contraseñas nuevas
+// Subir el costo no invalida los hashes existentes: se actualizan en el próximo login
+type PasswordHashConfig struct {
+	Algorithm       string
+	BcryptCost      int
+	Argon2Time      uint32
+	Argon2MemoryKiB uint32
+	Argon2Threads   uint8
+}
+
+// PasswordHasher hashea y verifica contraseñas con un formato autodescriptivo:
+// bcrypt ($2a$<costo>$...) o argon2id en formato PHC ($argon2id$v=19$m=...,t=...,p=...$salt$hash)
+// Los hashes SHA-256 sin sal de la versión anterior (64 caracteres hex) se siguen aceptando
+// para poder migrarlos en el login
+type PasswordHasher struct {
+	cfg PasswordHashConfig
+}
+
+// NewPasswordHasher crea un hasher y valida la configuración
+func NewPasswordHasher(cfg PasswordHashConfig) (*PasswordHasher, error) {
+	switch cfg.Algorithm {
+	case PasswordAlgorithmBcrypt:
+		if cfg.BcryptCost < bcrypt.MinCost || cfg.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case PasswordAlgorithmArgon2id:
+		if cfg.Argon2Time < 1 || cfg.Argon2MemoryKiB < 8*uint32(cfg.Argon2Threads) || cfg.Argon2Threads < 1 {
+			return nil, errors.New("argon2id needs time >= 1, threads >= 1 and memory >= 8 KiB per thread")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm %q", cfg.Algorithm)
+	}
+	return &PasswordHasher{cfg: cfg}, nil
+}
+
+// Hash genera el hash de una contraseña con el algoritmo configurado
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	if h.cfg.Algorithm == PasswordAlgorithmBcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cfg.BcryptCost)
+		if err != nil {
+			return "", fmt.Errorf("failed hashing password: %w", err)
+		}
+		return string(hash), nil
+	}
+
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed generating salt: %w", err)
+	}
+	key := argon2.IDKey([]byte(password), salt, h.cfg.Argon2Time, h.cfg.Argon2MemoryKiB, h.cfg.Argon2Threads, argon2KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.cfg.Argon2MemoryKiB, h.cfg.Argon2Time, h.cfg.Argon2Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify compara la contraseña con el hash guardado
+// needsRehash indica que la contraseña es correcta pero el hash es legacy o usa otro algoritmo o costo
+func (h *PasswordHasher) Verify(password, encoded string) (match bool, needsRehash bool) {
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		params, salt, key, err := decodeArgon2id(encoded)
+		if err != nil {
+			return false, false
+		}
+		candidate := argon2.IDKey([]byte(password), salt, params.Argon2Time, params.Argon2MemoryKiB, params.Argon2Threads, uint32(len(key)))
+		if subtle.ConstantTimeCompare(candidate, key) != 1 {
+			return false, false
+		}
+		return true, h.cfg.Algorithm != PasswordAlgorithmArgon2id ||
+			params.Argon2Time != h.cfg.Argon2Time ||
+			params.Argon2MemoryKiB != h.cfg.Argon2MemoryKiB ||
+			params.Argon2Threads != h.cfg.Argon2Threads
+
+	case strings.HasPrefix(encoded, "$2"):
+		if bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password)) != nil {
+			return false, false
+		}
+		cost, err := bcrypt.Cost([]byte(encoded))
+		return true, err != nil || h.cfg.Algorithm != PasswordAlgorithmBcrypt || cost != h.cfg.BcryptCost
+
+	case IsLegacySHA256Hash(encoded):
+		// SHA-256 sin sal: siempre hay que migrarlo
+		legacy := HashSHA256(password)
+		return subtle.ConstantTimeCompare([]byte(legacy), []byte(strings.ToLower(encoded))) == 1, true
+	}
+	return false, false
+}
+
+// IsLegacySHA256Hash indica si el hash guardado es un SHA-256 hex sin sal (formato anterior)
+func IsLegacySHA256Hash(encoded string) bool {
+	if len(encoded) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(encoded)
+	return err == nil
+}
+
+// decodeArgon2id lee los parámetros, la sal y la clave de un hash PHC de argon2id
+func decodeArgon2id(encoded string) (PasswordHashConfig, []byte, []byte, error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return PasswordHashConfig{}, nil, nil, errors.New("invalid argon2id hash format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return PasswordHashConfig{}, nil, nil, errors.New("unsupported argon2id version")
+	}
+
+	params := PasswordHashConfig{Algorithm: PasswordAlgorithmArgon2id}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Argon2MemoryKiB, &params.Argon2Time, &params.Argon2Threads); err != nil {
+		return PasswordHashConfig{}, nil, nil, errors.New("invalid argon2id parameters")
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return PasswordHashConfig{}, nil, nil, errors.New("invalid argon2id salt")
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return PasswordHashConfig{}, nil, nil, errors.New("invalid argon2id key")
+	}
+	return params, salt, key, nil
+}
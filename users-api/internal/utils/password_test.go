@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPasswordHasher_Argon2id(t *testing.T) {
+	hasher, err := NewPasswordHasher(PasswordHashConfig{Algorithm: PasswordAlgorithmArgon2id, Argon2Time: 1, Argon2MemoryKiB: 1024, Argon2Threads: 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	hash, err := hasher.Hash("secreto")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Errorf("Expected a PHC argon2id hash, got %q", hash)
+	}
+
+	// Misma contraseña, distinta sal
+	other, _ := hasher.Hash("secreto")
+	if other == hash {
+		t.Error("Expected different salts for each hash")
+	}
+
+	if match, needsRehash := hasher.Verify("secreto", hash); !match || needsRehash {
+		t.Errorf("Expected match without rehash, got match=%v needsRehash=%v", match, needsRehash)
+	}
+	if match, _ := hasher.Verify("otro", hash); match {
+		t.Error("Expected a wrong password not to match")
+	}
+
+	// Subir el costo pide rehashear los hashes existentes
+	stronger, _ := NewPasswordHasher(PasswordHashConfig{Algorithm: PasswordAlgorithmArgon2id, Argon2Time: 2, Argon2MemoryKiB: 1024, Argon2Threads: 1})
+	if match, needsRehash := stronger.Verify("secreto", hash); !match || !needsRehash {
+		t.Errorf("Expected match with rehash after raising the cost, got match=%v needsRehash=%v", match, needsRehash)
+	}
+}
+
+func TestPasswordHasher_BcryptAndLegacy(t *testing.T) {
+	hasher, err := NewPasswordHasher(PasswordHashConfig{Algorithm: PasswordAlgorithmBcrypt, BcryptCost: 4})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	hash, _ := hasher.Hash("secreto")
+	if match, needsRehash := hasher.Verify("secreto", hash); !match || needsRehash {
+		t.Errorf("Expected bcrypt match without rehash, got match=%v needsRehash=%v", match, needsRehash)
+	}
+
+	legacy := HashSHA256("secreto")
+	if match, needsRehash := hasher.Verify("secreto", legacy); !match || !needsRehash {
+		t.Errorf("Expected legacy SHA-256 to match and need rehash, got match=%v needsRehash=%v", match, needsRehash)
+	}
+	if match, _ := hasher.Verify("otro", legacy); match {
+		t.Error("Expected a wrong password not to match the legacy hash")
+	}
+	if match, _ := hasher.Verify("secreto", "texto-cualquiera"); match {
+		t.Error("Expected an unknown hash format not to match")
+	}
+
+	if _, err := NewPasswordHasher(PasswordHashConfig{Algorithm: "md5"}); err == nil {
+		t.Error("Expected an error for an unsupported algorithm")
+	}
+}